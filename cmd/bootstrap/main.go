@@ -0,0 +1,53 @@
+// Command bootstrap rebuilds a manager on a new host out of a recovery bundle exported by POST
+// /server/recovery-bundle. It restores the bundle into the empty database, then starts the manager, which writes the
+// configs of named and reloads it. The passphrase of the bundle is read from RECOVERY_PASSPHRASE. Once restored, the
+// database holding zones, the restore is skipped, the container restarting with the same command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"github.com/anantadwi13/dns-server-manager/internal"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"os"
+)
+
+const (
+	BindFolderPath = "/etc/bind/"
+
+	DataPath = "/data/"
+	DBName   = "service.sqlite.db"
+)
+
+func main() {
+	bundlePath := flag.String("bundle", "", "path of the recovery bundle")
+	flag.Parse()
+	if *bundlePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	zoneGroupPaths, err := domain.ParseZoneGroupPaths(os.Getenv("ZONE_GROUP_PATHS"))
+	if err != nil {
+		log.Fatalln("ZONE_GROUP_PATHS:", err)
+	}
+	config := domain.NewConfig(BindFolderPath, DataPath, DBName, os.Getenv("DB_READ_DSN"), zoneGroupPaths, false)
+	bundle, err := os.Open(*bundlePath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = internal.RestoreRecoveryBundle(context.Background(), config, bundle, os.Getenv("RECOVERY_PASSPHRASE"))
+	bundle.Close()
+	switch {
+	case errors.Is(err, domain.ErrorRecoveryTargetNotEmpty):
+		log.Println("Recovery bundle is not restored, the database holds zones already")
+	case err != nil:
+		log.Fatalln("restoring the recovery bundle:", err)
+	default:
+		log.Println("Recovery bundle is restored")
+	}
+
+	internal.NewService(config).Start()
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"github.com/anantadwi13/dns-server-manager/internal"
 	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"os"
 )
 
 const (
@@ -13,8 +15,15 @@ const (
 )
 
 func main() {
+	zoneGroupPaths, err := domain.ParseZoneGroupPaths(os.Getenv("ZONE_GROUP_PATHS"))
+	if err != nil {
+		log.Fatalln("ZONE_GROUP_PATHS:", err)
+	}
 	service := internal.NewService(
-		domain.NewConfig(BindFolderPath, DataPath, DBName),
+		domain.NewConfig(
+			BindFolderPath, DataPath, DBName, os.Getenv("DB_READ_DSN"), zoneGroupPaths,
+			os.Getenv("CHAOS_MODE") == "true",
+		),
 	)
 	service.Start()
 }
@@ -0,0 +1,101 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/net/dns/dnsmessage"
+	"io"
+	"net"
+	"time"
+)
+
+const dnsTimeout = 5 * time.Second
+
+// lookupHost asks the DNS server at address for the addresses of name.
+func lookupHost(address string, name string) ([]string, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: dnsTimeout}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(address, "53"))
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+	return resolver.LookupHost(ctx, name)
+}
+
+// transferZone runs an AXFR of the zone from the DNS server at address, returning the owner names of its records.
+func transferZone(ctx context.Context, address string, zone string) (map[string]bool, error) {
+	dialer := net.Dialer{Timeout: dnsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, "53"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = conn.SetDeadline(time.Now().Add(dnsTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := (&dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 1},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(zone + "."),
+			Type:  dnsmessage.TypeAXFR,
+			Class: dnsmessage.ClassINET,
+		}},
+	}).Pack()
+	if err != nil {
+		return nil, err
+	}
+	// Messages over TCP are prefixed with their length.
+	err = binary.Write(conn, binary.BigEndian, uint16(len(query)))
+	if err != nil {
+		return nil, err
+	}
+	_, err = conn.Write(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// The transfer starts and ends with the SOA record, spanning as many messages as needed.
+	names := map[string]bool{}
+	soaRecords := 0
+	for soaRecords < 2 {
+		var length uint16
+		err = binary.Read(conn, binary.BigEndian, &length)
+		if err != nil {
+			return nil, err
+		}
+		response := make([]byte, length)
+		_, err = io.ReadFull(conn, response)
+		if err != nil {
+			return nil, err
+		}
+
+		var message dnsmessage.Message
+		err = message.Unpack(response)
+		if err != nil {
+			return nil, err
+		}
+		if message.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("transfer refused with %v", message.RCode)
+		}
+		if len(message.Answers) == 0 {
+			return nil, fmt.Errorf("transfer ended before its closing SOA record")
+		}
+		for _, answer := range message.Answers {
+			if answer.Header.Type == dnsmessage.TypeSOA {
+				soaRecords++
+			}
+			names[answer.Header.Name.String()] = true
+		}
+	}
+	return names, nil
+}
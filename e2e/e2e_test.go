@@ -0,0 +1,191 @@
+//go:build e2e
+// +build e2e
+
+// Package e2e holds the end-to-end tests of the manager, run against a running deployment, see docker-compose.yml, with
+// go test -tags e2e ./e2e/...
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/external"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	zoneDomain   = "e2e.test"
+	waitTimeout  = time.Minute
+	waitInterval = time.Second
+)
+
+type checks struct {
+	apiUrl    string
+	primary   string
+	secondary string
+	client    *http.Client
+}
+
+func newChecks() *checks {
+	return &checks{
+		apiUrl:    strings.TrimSuffix(env("E2E_API_URL", "http://127.0.0.1:5555"), "/"),
+		primary:   env("E2E_PRIMARY_ADDRESS", "127.0.0.1"),
+		secondary: env("E2E_SECONDARY_ADDRESS", ""),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// TestZoneLifecycle creates a zone through the API, checks that the primary named serves and transfers it and that the
+// secondary follows its changes, then deletes the zone.
+func TestZoneLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := newChecks()
+	waitFor(t, "the API", func() error {
+		return c.call(ctx, http.MethodGet, "/features", nil, nil)
+	})
+
+	// A zone left over by an aborted run is dropped first.
+	_ = c.call(ctx, http.MethodDelete, "/zones/"+zoneDomain, nil, nil)
+	err := c.call(ctx, http.MethodPost, "/zones", &external.CreateZoneJSONRequestBody{
+		Domain:    zoneDomain,
+		PrimaryNs: "ns1." + zoneDomain + ".",
+		MailAddr:  "hostmaster@" + zoneDomain,
+	}, nil)
+	if err != nil {
+		t.Fatalf("creating the zone: %v", err)
+	}
+	t.Cleanup(func() {
+		err := c.call(ctx, http.MethodDelete, "/zones/"+zoneDomain, nil, nil)
+		if err != nil {
+			t.Errorf("deleting the zone: %v", err)
+		}
+	})
+
+	records := []external.RecordReq{
+		{Name: "ns1", Type: "A", Value: c.primary},
+		{Name: "www", Type: "A", Value: "192.0.2.10"},
+	}
+	if c.secondary != "" {
+		// The secondary is notified of the changes once it is one of the name servers of the zone.
+		records = append(records,
+			external.RecordReq{Name: "ns2", Type: "A", Value: c.secondary},
+			external.RecordReq{Name: "@", Type: "NS", Value: "ns2." + zoneDomain + "."},
+		)
+	} else {
+		t.Log("E2E_SECONDARY_ADDRESS is not set, the secondary is not checked")
+	}
+	var www external.RecordRes
+	for i := range records {
+		var record external.RecordRes
+		err = c.call(ctx, http.MethodPost, "/records/"+zoneDomain, &records[i], &record)
+		if err != nil {
+			t.Fatalf("creating the %v record %v: %v", records[i].Type, records[i].Name, err)
+		}
+		if record.Name == "www" {
+			www = record
+		}
+	}
+
+	c.checkServed(t, "www."+zoneDomain, "192.0.2.10")
+
+	waitFor(t, "the transfer of the zone from the primary", func() error {
+		names, err := transferZone(ctx, c.primary, zoneDomain)
+		if err != nil {
+			return err
+		}
+		if !names["www."+zoneDomain+"."] {
+			return fmt.Errorf("www is missing from the transfer")
+		}
+		return nil
+	})
+
+	err = c.call(ctx, http.MethodPut, "/records/"+zoneDomain+"/"+www.Id, &external.RecordReq{Value: "192.0.2.20"}, nil)
+	if err != nil {
+		t.Fatalf("updating the www record: %v", err)
+	}
+	c.checkServed(t, "www."+zoneDomain, "192.0.2.20")
+}
+
+// checkServed waits until the primary, and the secondary when there is one, answer address for name.
+func (c *checks) checkServed(t *testing.T, name, address string) {
+	t.Helper()
+	servers := []string{c.primary}
+	if c.secondary != "" {
+		servers = append(servers, c.secondary)
+	}
+	for _, server := range servers {
+		server := server
+		waitFor(t, fmt.Sprintf("%v to answer %v for %v", server, address, name), func() error {
+			addresses, err := lookupHost(server, name)
+			if err != nil {
+				return err
+			}
+			if len(addresses) != 1 || addresses[0] != address {
+				return fmt.Errorf("got %v", addresses)
+			}
+			return nil
+		})
+	}
+}
+
+// call sends req as JSON to the API and decodes the response into res when it is set, failing on error statuses.
+func (c *checks) call(ctx context.Context, method, path string, req interface{}, res interface{}) error {
+	var body bytes.Buffer
+	if req != nil {
+		err := json.NewEncoder(&body).Encode(req)
+		if err != nil {
+			return err
+		}
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.apiUrl+path, &body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpRes, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode >= 300 {
+		var generalRes external.GeneralRes
+		_ = json.NewDecoder(httpRes.Body).Decode(&generalRes)
+		return fmt.Errorf("%v %v responded with status %v: %v", method, path, httpRes.Status, generalRes.Message)
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(httpRes.Body).Decode(res)
+}
+
+// waitFor retries check until it succeeds, failing the test once waitTimeout is over, the reloads of named and the
+// transfers to the secondary being asynchronous.
+func waitFor(t *testing.T, what string, check func() error) {
+	t.Helper()
+	t.Log("Waiting for", what)
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %v: %v", what, err)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
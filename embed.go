@@ -0,0 +1,9 @@
+package dnsservermanager
+
+import "embed"
+
+// Assets holds the files served by the API server, they are compiled into the binary so the service does not
+// depend on its working directory.
+//
+//go:embed specification.yaml web
+var Assets embed.FS
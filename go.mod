@@ -6,6 +6,9 @@ require (
 	github.com/deepmap/oapi-codegen v1.8.2
 	github.com/google/uuid v1.3.0
 	github.com/labstack/echo/v4 v4.5.0
+	github.com/labstack/gommon v0.3.0
 	github.com/mattn/go-sqlite3 v1.14.8
 	github.com/pkg/errors v0.9.1
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
 )
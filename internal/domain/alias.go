@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+)
+
+// IsAliasRecord reports whether the record type is the ALIAS pseudo-record. An ALIAS record points a name, usually the
+// apex where a CNAME is not allowed, to a host name whose addresses are written in the zone file as A and AAAA
+// records instead of the ALIAS record itself.
+func IsAliasRecord(recordType string) bool {
+	return strings.ToUpper(recordType) == "ALIAS"
+}
+
+// AliasTarget returns the host name an ALIAS record points to, normalized. The value is always fully qualified,
+// whether it ends with a dot or not.
+func AliasTarget(record *Record) string {
+	return NormalizeDomain(record.Value)
+}
+
+// AliasTargets returns the targets of the ALIAS records of the zones, sorted and without duplicates.
+func AliasTargets(zones []*Zone) []string {
+	known := map[string]bool{}
+	var targets []string
+	for _, zone := range zones {
+		for _, record := range zone.Records {
+			if record.Disabled || !IsAliasRecord(record.Type) {
+				continue
+			}
+			expanded, err := zone.ExpandedRecord(record)
+			if err != nil {
+				continue
+			}
+			target := AliasTarget(expanded)
+			if known[target] {
+				continue
+			}
+			known[target] = true
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// MaterializeAlias returns the A and AAAA records an ALIAS record stands for given the addresses of its target.
+func MaterializeAlias(record *Record, addresses []string) []*Record {
+	var records []*Record
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		recordType := "AAAA"
+		if ip.To4() != nil {
+			recordType = "A"
+		}
+		records = append(records, &Record{Name: record.Name, Type: recordType, Value: ip.String(), TTL: record.TTL})
+	}
+	return records
+}
+
+type AliasResolver interface {
+	// Start resolves the targets of the ALIAS records every interval until Shutdown.
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	// Addresses returns the addresses of target, sorted. A target not resolved yet is resolved right away, the last
+	// known addresses are kept while the target fails to resolve.
+	Addresses(ctx context.Context, target string) []string
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrorSOARecord   = errors.New("the SOA record is managed along with the zone, it can not be added or removed")
+	ErrorCNAMEAtApex = errors.New("a CNAME record is not allowed at the zone apex, an ALIAS record can be used instead")
+)
+
+const (
+	ErrorCodeLastNameServer = "last_ns_record"
+	ErrorCodeSOARecord      = "soa_record"
+	ErrorCodeCNAMEAtApex    = "cname_at_apex"
+)
+
+// apexErrorCodes tells the clients which apex rule refused a change, so they can explain it.
+var apexErrorCodes = map[error]string{
+	ErrorLastNameServer: ErrorCodeLastNameServer,
+	ErrorSOARecord:      ErrorCodeSOARecord,
+	ErrorCNAMEAtApex:    ErrorCodeCNAMEAtApex,
+}
+
+// ErrorCode returns the code of the apex rule refusing a change, empty for the other errors.
+func ErrorCode(err error) string {
+	for ruleErr, code := range apexErrorCodes {
+		if errors.Is(err, ruleErr) {
+			return code
+		}
+	}
+	return ""
+}
+
+// CheckApexRecord refuses the records breaking the apex of the zone: SOA records, the SOA of the zone being
+// managed with the zone itself, and CNAME records at the apex, which can not coexist with its SOA and NS records.
+func (z *Zone) CheckApexRecord(record *Record) error {
+	switch strings.ToUpper(record.Type) {
+	case "SOA":
+		return ErrorSOARecord
+	case "CNAME":
+		if z.NormalizeRecordName(record.Name) == "@" {
+			return ErrorCNAMEAtApex
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// zoneArchiveBundleVersion is bumped whenever the bundle changes, older bundles being upgraded when decoded. Bundles
+// of version 1 hold the priority of MX and SRV records in their value.
+const zoneArchiveBundleVersion = 2
+
+// ZoneArchive is a zone removed from serving, its records and audit log being kept in a compressed bundle.
+type ZoneArchive struct {
+	Id          string
+	Domain      string
+	ArchivedAt  time.Time
+	RecordCount int
+	// Size is the size of the compressed bundle in bytes.
+	Size int64
+}
+
+type ZoneArchiveBundle struct {
+	Version    int            `json:"version"`
+	ArchivedAt time.Time      `json:"archived_at"`
+	Zone       *Zone          `json:"zone"`
+	AuditLogs  []*ChangeEvent `json:"audit_logs"`
+}
+
+func NewZoneArchive(zone *Zone) *ZoneArchive {
+	return &ZoneArchive{Domain: zone.Domain, ArchivedAt: time.Now(), RecordCount: len(zone.Records)}
+}
+
+func NewZoneArchiveBundle(archive *ZoneArchive, zone *Zone, auditLogs []*ChangeEvent) *ZoneArchiveBundle {
+	return &ZoneArchiveBundle{
+		Version:    zoneArchiveBundleVersion,
+		ArchivedAt: archive.ArchivedAt,
+		Zone:       zone,
+		AuditLogs:  auditLogs,
+	}
+}
+
+// Encode returns the gzip compressed JSON document of the bundle.
+func (b *ZoneArchiveBundle) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	err := json.NewEncoder(writer).Encode(b)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func DecodeZoneArchiveBundle(reader io.Reader) (*ZoneArchiveBundle, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	bundle := &ZoneArchiveBundle{}
+	err = json.NewDecoder(gzipReader).Decode(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Version < 1 || bundle.Version > zoneArchiveBundleVersion || bundle.Zone == nil {
+		return nil, ErrorInvalidZoneArchive
+	}
+	// Bundles archived before serial strategies existed used the date strategy.
+	if bundle.Zone.SOA != nil && bundle.Zone.SOA.SerialStrategy == "" {
+		bundle.Zone.SOA.SerialStrategy = SerialStrategyDate
+	}
+	if bundle.Version == 1 {
+		for _, record := range bundle.Zone.Records {
+			record.ExtractPriority()
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	AuditExporterTypeSyslog = "syslog"
+	AuditExporterTypeHttp   = "http"
+
+	AuditExporterProtocolUdp = "udp"
+	AuditExporterProtocolTcp = "tcp"
+)
+
+var ErrorChangeMetadataRequired = errors.New("zone is regulated, make sure ticket id, reason and requested by are set")
+
+// ChangeMetadata documents why a mutation happened, it is stored in the audit log and sent along with events.
+type ChangeMetadata struct {
+	TicketId    string
+	Reason      string
+	RequestedBy string
+}
+
+// NewChangeMetadata returns nil when no field is set.
+func NewChangeMetadata(ticketId, reason, requestedBy string) *ChangeMetadata {
+	metadata := &ChangeMetadata{
+		TicketId:    strings.TrimSpace(ticketId),
+		Reason:      strings.TrimSpace(reason),
+		RequestedBy: strings.TrimSpace(requestedBy),
+	}
+	if metadata.TicketId == "" && metadata.Reason == "" && metadata.RequestedBy == "" {
+		return nil
+	}
+	return metadata
+}
+
+func (m *ChangeMetadata) IsComplete() bool {
+	return m != nil && m.TicketId != "" && m.Reason != "" && m.RequestedBy != ""
+}
+
+type AuditLogFilter struct {
+	Zone string
+	// RecordId restricts the events to the changes of a record.
+	RecordId string
+	Limit    int
+	// All lists every event, ignoring Limit.
+	All bool
+	// From and To restrict the events to a period when set, From being inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+}
+
+// AuditExporter streams the audit log to an external collector, either as RFC 5424 syslog messages sent to a
+// host:port address or as JSON documents POSTed to an http(s) URL.
+type AuditExporter struct {
+	Id      string
+	Type    string
+	Address string
+	// Protocol is the syslog transport, udp or tcp.
+	Protocol string
+	Enabled  bool
+}
+
+func NewAuditExporter(exporterType, address string) *AuditExporter {
+	exporter := &AuditExporter{Type: exporterType, Address: address, Enabled: true}
+	if exporterType == AuditExporterTypeSyslog {
+		exporter.Protocol = AuditExporterProtocolUdp
+	}
+	return exporter
+}
+
+func (e *AuditExporter) IsValid() bool {
+	switch e.Type {
+	case AuditExporterTypeSyslog:
+		_, port, err := net.SplitHostPort(e.Address)
+		if err != nil || port == "" {
+			return false
+		}
+		return e.Protocol == AuditExporterProtocolUdp || e.Protocol == AuditExporterProtocolTcp
+	case AuditExporterTypeHttp:
+		parsedUrl, err := url.Parse(e.Address)
+		return err == nil && (parsedUrl.Scheme == "http" || parsedUrl.Scheme == "https") && parsedUrl.Host != ""
+	default:
+		return false
+	}
+}
+
+// ChangeEventListener is notified of every change event relayed out of the outbox.
+type ChangeEventListener interface {
+	OnChangeEvent(ctx context.Context, event *ChangeEvent)
+}
+
+// AuditLogExporter streams the change events to the audit exporters, queued for the relay of the outbox not to wait on
+// the collectors.
+type AuditLogExporter interface {
+	ChangeEventListener
+
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+}
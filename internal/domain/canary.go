@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueriedCanaries returns the canary records owning the queried name, whatever the type of the query, the disabled
+// ones being left out as named does not serve them.
+func (z *Zone) QueriedCanaries(name string) []*Record {
+	owner, inZone := z.relativeName(NormalizeDomain(name) + ".")
+	if !inZone {
+		return nil
+	}
+	var records []*Record
+	for _, record := range z.Records {
+		if !record.Canary || record.Disabled {
+			continue
+		}
+		if recordName, _ := z.relativeName(record.Name); recordName == owner {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// NewCanaryEvent creates the event raised by a query for a canary record of the zone.
+func NewCanaryEvent(zone *Zone, record *Record, entry *QueryLogEntry) *ChangeEvent {
+	event := NewRecordEvent(EventCanaryQueried, zone, record, nil)
+	event.Detail = fmt.Sprintf("queried for %v by %v", strings.ToUpper(entry.Type), entry.ClientIP)
+	if entry.ClientSubnet != "" {
+		event.Detail += fmt.Sprintf(" on behalf of %v", entry.ClientSubnet)
+	}
+	return event
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	DefaultChangeBurstWindow       = 10 * time.Minute
+	DefaultChangeBurstBaselineDays = 14
+	DefaultChangeBurstSensitivity  = 3
+	DefaultChangeBurstMinChanges   = 20
+)
+
+// ChangeBurstSettings flags the zones changed much more often than they usually are, e.g. by a leaked credential,
+// the changes of every zone being counted by window and compared to the windows of the baseline period.
+type ChangeBurstSettings struct {
+	// WindowSeconds is the length of the windows the changes are counted in, DefaultChangeBurstWindow when 0.
+	WindowSeconds int `json:"window_seconds"`
+	// BaselineDays is the history the windows are compared to, DefaultChangeBurstBaselineDays when 0.
+	BaselineDays int `json:"baseline_days"`
+	// Sensitivity is the number of standard deviations above the mean of the baseline a window has to go for a
+	// burst, lower values flagging more bursts, DefaultChangeBurstSensitivity when 0.
+	Sensitivity float64 `json:"sensitivity"`
+	// MinChanges is the number of changes within a window below which no burst is flagged, so that the zones hardly
+	// ever changed are not flagged by a handful of changes, DefaultChangeBurstMinChanges when 0.
+	MinChanges int `json:"min_changes"`
+}
+
+func (s *ChangeBurstSettings) IsValid() bool {
+	return s.WindowSeconds >= 0 && s.BaselineDays >= 0 && s.Sensitivity >= 0 && s.MinChanges >= 0 &&
+		time.Duration(s.baselineDays())*24*time.Hour > s.Window()
+}
+
+func (s *ChangeBurstSettings) Window() time.Duration {
+	if s.WindowSeconds == 0 {
+		return DefaultChangeBurstWindow
+	}
+	return time.Duration(s.WindowSeconds) * time.Second
+}
+
+// Windows returns the number of windows counted, the latest one followed by the ones of the baseline.
+func (s *ChangeBurstSettings) Windows() int {
+	return int(time.Duration(s.baselineDays()) * 24 * time.Hour / s.Window())
+}
+
+func (s *ChangeBurstSettings) baselineDays() int {
+	if s.BaselineDays == 0 {
+		return DefaultChangeBurstBaselineDays
+	}
+	return s.BaselineDays
+}
+
+// ChangeBurst is a window a zone was changed in much more often than in the windows of its baseline.
+type ChangeBurst struct {
+	Zone    string
+	Window  time.Duration
+	Changes int
+	// Threshold is the number of changes the window had to go over, out of the mean and the deviation of the
+	// baseline.
+	Threshold float64
+}
+
+// DetectChangeBurst compares the changes of the latest window, counts[0], to the ones of the baseline, the windows
+// before it. nil is returned unless the latest window goes over both MinChanges and the threshold of the baseline.
+func (s *ChangeBurstSettings) DetectChangeBurst(zone string, counts []int) *ChangeBurst {
+	if len(counts) == 0 {
+		return nil
+	}
+	sensitivity := s.Sensitivity
+	if sensitivity == 0 {
+		sensitivity = DefaultChangeBurstSensitivity
+	}
+	minChanges := s.MinChanges
+	if minChanges == 0 {
+		minChanges = DefaultChangeBurstMinChanges
+	}
+
+	mean, deviation := 0.0, 0.0
+	if baseline := counts[1:]; len(baseline) > 0 {
+		for _, count := range baseline {
+			mean += float64(count)
+		}
+		mean /= float64(len(baseline))
+		for _, count := range baseline {
+			deviation += (float64(count) - mean) * (float64(count) - mean)
+		}
+		deviation = math.Sqrt(deviation / float64(len(baseline)))
+	}
+	threshold := math.Max(mean+sensitivity*deviation, float64(minChanges))
+	if float64(counts[0]) <= threshold {
+		return nil
+	}
+	return &ChangeBurst{Zone: zone, Window: s.Window(), Changes: counts[0], Threshold: threshold}
+}
+
+// Event returns the event flagging the burst in the audit log and to the webhooks.
+func (b *ChangeBurst) Event() *ChangeEvent {
+	return &ChangeEvent{
+		Type:   EventChangeBurst,
+		Time:   time.Now(),
+		Zone:   b.Zone,
+		Detail: fmt.Sprintf("%v changes within %v, %.1f expected at most", b.Changes, b.Window, b.Threshold),
+	}
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Clone returns a copy of the zone under domainName, to be persisted as a new zone, e.g. a staging copy or a parked
+// domain. The SOA timers, the records, the generators and the settings are copied, the names and the targets under
+// the domain of the zone being moved under domainName. What is tied to the domain itself is left out: its external
+// ids, registration and expiration. PTR sync is left off, the reverse zones pointing to the zone being cloned.
+func (z *Zone) Clone(domainName string) (*Zone, error) {
+	if z.SOA == nil {
+		return nil, errors.New("zone has no SOA record to clone")
+	}
+	clone := NewZone(domainName)
+	clone.Regulated = z.Regulated
+	clone.StrictValidation = z.StrictValidation
+	clone.SyncPrimaryNS = z.SyncPrimaryNS
+	clone.NotifyInterval = z.NotifyInterval
+	clone.AllowTransfer = append([]string(nil), z.AllowTransfer...)
+	clone.TransferKeys = append([]*TSIGKey(nil), z.TransferKeys...)
+	clone.AlsoNotify = append([]string(nil), z.AlsoNotify...)
+	clone.Fragments = append([]string(nil), z.Fragments...)
+	clone.Views = append([]string(nil), z.Views...)
+	clone.Group = z.Group
+	clone.DnssecPolicy = z.DnssecPolicy
+	clone.Notes = z.Notes
+	clone.TechnicalContact = z.TechnicalContact
+	for name, value := range z.Variables {
+		if clone.Variables == nil {
+			clone.Variables = map[string]string{}
+		}
+		clone.Variables[name] = value
+	}
+
+	soa := *z.SOA
+	soa.Id = ""
+	soa.PrimaryNameServer = z.moveName(soa.PrimaryNameServer, domainName)
+	if at := strings.LastIndex(soa.MailAddress, "@"); at >= 0 {
+		soa.MailAddress = soa.MailAddress[:at+1] + z.moveName(soa.MailAddress[at+1:], domainName)
+	} else {
+		soa.MailAddress = z.moveName(soa.MailAddress, domainName)
+	}
+	// The clone is a new zone, its first serial comes from the strategy.
+	soa.Serial, soa.SerialCounter = "", 0
+	soa.SerialStepAt, soa.PublishedAt = time.Time{}, time.Time{}
+	soa.UpdateSerial()
+	err := clone.RegisterSOA(&soa)
+	if err != nil {
+		return nil, err
+	}
+
+	generators := map[*RecordGenerator]*RecordGenerator{}
+	for _, generator := range z.Generators {
+		generatorCopy := *generator
+		generatorCopy.Id = ""
+		generators[generator] = &generatorCopy
+		clone.Generators = append(clone.Generators, &generatorCopy)
+	}
+	for _, record := range z.Records {
+		recordCopy := *record
+		recordCopy.Id, recordCopy.ExternalId = "", ""
+		if strings.HasSuffix(recordCopy.Name, ".") {
+			recordCopy.Name = z.moveName(recordCopy.Name, domainName)
+		}
+		if index, ok := recordTargetFields[strings.ToUpper(recordCopy.Type)]; ok {
+			fields := strings.Fields(recordCopy.Value)
+			if index < len(fields) {
+				fields[index] = z.moveName(fields[index], domainName)
+				recordCopy.Value = strings.Join(fields, " ")
+			}
+		}
+		if record.Labels != nil {
+			recordCopy.Labels = map[string]string{}
+			for key, value := range record.Labels {
+				recordCopy.Labels[key] = value
+			}
+		}
+		for original, generatorCopy := range generators {
+			if record.IsGeneratedBy(original) {
+				recordCopy.Generator = generatorCopy
+			}
+		}
+		err = clone.AddRecord(&recordCopy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// moveName moves a name under the domain of the zone under domainName, keeping its trailing dot, e.g.
+// mail.example.com. becomes mail.example.net. for a clone of example.com under example.net. Other names are returned
+// as is.
+func (z *Zone) moveName(name string, domainName string) string {
+	fqdn := strings.TrimSuffix(name, ".")
+	zoneDomain, target := NormalizeDomain(z.Domain), NormalizeDomain(domainName)
+	lowerFqdn := strings.ToLower(fqdn)
+	if lowerFqdn != zoneDomain && !strings.HasSuffix(lowerFqdn, "."+zoneDomain) {
+		return name
+	}
+	moved := fqdn[:len(fqdn)-len(zoneDomain)] + target
+	if strings.HasSuffix(name, ".") {
+		moved += "."
+	}
+	return moved
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Version is the version of the manager, set at build time with
+// -ldflags "-X github.com/anantadwi13/dns-server-manager/internal/domain.Version=v1.2.3".
+var Version = "dev"
+
+const (
+	InstanceRoleLeader   = "leader"
+	InstanceRoleFollower = "follower"
+
+	InstanceSyncStatusInSync         = "in_sync"
+	InstanceSyncStatusSchemaMismatch = "schema_mismatch"
+	InstanceSyncStatusStale          = "stale"
+)
+
+// Instance is a manager sharing the store with the others, it is registered as long as it sends its heartbeat.
+type Instance struct {
+	Id            string
+	Hostname      string
+	Version       string
+	SchemaVersion int
+	StartedAt     time.Time
+	LastSeenAt    time.Time
+
+	// Role and SyncStatus are derived from the other instances by ResolveInstances.
+	Role       string
+	SyncStatus string
+}
+
+// ResolveInstances assigns the roles and the sync statuses of the instances. An instance is stale once it missed its
+// heartbeat for staleAfter, the leader is the live instance running for the longest time. Live instances not running
+// the newest schema version are reported as schema_mismatch.
+func ResolveInstances(instances []*Instance, staleAfter time.Duration, now time.Time) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		if instances[i].StartedAt.Equal(instances[j].StartedAt) {
+			return instances[i].Id < instances[j].Id
+		}
+		return instances[i].StartedAt.Before(instances[j].StartedAt)
+	})
+
+	schemaVersion := 0
+	for _, instance := range instances {
+		if instance.SchemaVersion > schemaVersion {
+			schemaVersion = instance.SchemaVersion
+		}
+	}
+
+	hasLeader := false
+	for _, instance := range instances {
+		instance.Role = InstanceRoleFollower
+		switch {
+		case now.Sub(instance.LastSeenAt) > staleAfter:
+			instance.SyncStatus = InstanceSyncStatusStale
+			continue
+		case instance.SchemaVersion < schemaVersion:
+			instance.SyncStatus = InstanceSyncStatusSchemaMismatch
+		default:
+			instance.SyncStatus = InstanceSyncStatusInSync
+		}
+		if !hasLeader {
+			instance.Role = InstanceRoleLeader
+			hasLeader = true
+		}
+	}
+}
+
+type ClusterRegistry interface {
+	// Start registers this instance and keeps sending its heartbeat until Shutdown unregisters it.
+	Start(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+
+	// InstanceId is the id this instance is registered with.
+	InstanceId() string
+	// Instances returns every registered instance resolved by ResolveInstances, oldest first.
+	Instances(ctx context.Context) ([]*Instance, error)
+}
@@ -9,19 +9,53 @@ type Config interface {
 	DataFolderPath() string
 	DBName() string
 	DBPath() string
+	// DBReadDSN is the data source name of a read replica serving the read only queries, empty when every query goes
+	// to the primary database.
+	DBReadDSN() string
+	RpzFolderPath() string
+	// ZoneFolderPath is the folder the zone files of the zone group are written to: the bind folder for the zones of no
+	// group, the folder configured for the group, or its folder in ZoneGroupsFolderPath otherwise.
+	ZoneFolderPath(group string) string
+	// ZoneGroupsFolderPath holds a folder per zone group having no folder configured.
+	ZoneGroupsFolderPath() string
+	// KeysFolderPath holds a key directory per signed zone file, named generating the DNSSEC keys there.
+	KeysFolderPath() string
+	// RndcKeyPath is the key the manager controls named with through rndc, generated on the first start.
+	RndcKeyPath() string
+	ArchiveFolderPath() string
+	SettingsPath() string
+	// ChaosMode lets the operators inject faults through the API, never set on a production manager.
+	ChaosMode() bool
 }
 
 type config struct {
 	bindFolderPath string
 	dataFolderPath string
 	dbName         string
+	dbReadDSN      string
+	zoneGroupPaths map[string]string
+	chaosMode      bool
 }
 
-func NewConfig(bindFolderPath string, dataFolderPath string, dbName string) Config {
+// NewConfig returns the config of the manager, zoneGroupPaths being the folders configured for the zone groups, see
+// ParseZoneGroupPaths.
+func NewConfig(
+	bindFolderPath string, dataFolderPath string, dbName string, dbReadDSN string, zoneGroupPaths map[string]string,
+	chaosMode bool,
+) Config {
 	conf := &config{
 		bindFolderPath: path(bindFolderPath),
 		dataFolderPath: path(dataFolderPath),
 		dbName:         dbName,
+		dbReadDSN:      dbReadDSN,
+		zoneGroupPaths: map[string]string{},
+		chaosMode:      chaosMode,
+	}
+	for group, groupPath := range zoneGroupPaths {
+		if !filepath.IsAbs(groupPath) {
+			groupPath = filepath.Join(conf.bindFolderPath, groupPath)
+		}
+		conf.zoneGroupPaths[group] = path(groupPath)
 	}
 	return conf
 }
@@ -46,6 +80,48 @@ func (c *config) DBPath() string {
 	return path(c.dataFolderPath, c.dbName)
 }
 
+func (c *config) DBReadDSN() string {
+	return c.dbReadDSN
+}
+
+func (c *config) RpzFolderPath() string {
+	return path(c.dataFolderPath, "rpz")
+}
+
+func (c *config) ZoneFolderPath(group string) string {
+	if group == "" {
+		return c.bindFolderPath
+	}
+	if groupPath, ok := c.zoneGroupPaths[group]; ok {
+		return groupPath
+	}
+	return path(c.ZoneGroupsFolderPath(), group)
+}
+
+func (c *config) ZoneGroupsFolderPath() string {
+	return path(c.bindFolderPath, "zones")
+}
+
+func (c *config) KeysFolderPath() string {
+	return path(c.dataFolderPath, "keys")
+}
+
+func (c *config) RndcKeyPath() string {
+	return path(c.bindFolderPath, "rndc.managed.key")
+}
+
+func (c *config) ArchiveFolderPath() string {
+	return path(c.dataFolderPath, "archives")
+}
+
+func (c *config) SettingsPath() string {
+	return path(c.dataFolderPath, "config.json")
+}
+
+func (c *config) ChaosMode() bool {
+	return c.chaosMode
+}
+
 func path(paths ...string) string {
 	cleanPath := ""
 	if len(paths) > 0 {
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrorInvalidDelegation = errors.New(
+		"delegations need a name below the apex and name servers given by their host name, once each")
+	ErrorDelegationNotFound = errors.New("delegation is not found")
+)
+
+// Delegation hands a subdomain of the zone over to other name servers: the NS records of the subdomain along with the
+// A and AAAA records of the name servers in the zone, their glue.
+type Delegation struct {
+	Name        string
+	NameServers []*DelegatedNameServer
+}
+
+type DelegatedNameServer struct {
+	Host string
+	// Addresses are the glue of the name servers in the zone, set from the A and AAAA records of the host.
+	Addresses []string
+}
+
+// Delegations returns the subdomains of the zone owning NS records, by name. The disabled records are left out.
+func (z *Zone) Delegations() []*Delegation {
+	var names []string
+	for _, record := range z.Records {
+		name, _ := z.relativeName(record.Name)
+		if !record.Disabled && strings.ToUpper(record.Type) == "NS" && name != "@" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var delegations []*Delegation
+	for _, name := range names {
+		delegations = append(delegations, z.FindDelegation(name))
+	}
+	return delegations
+}
+
+// FindDelegation returns nil when name owns no NS record.
+func (z *Zone) FindDelegation(name string) *Delegation {
+	owner, inZone := z.relativeName(z.NormalizeRecordName(name))
+	if !inZone || owner == "@" {
+		return nil
+	}
+	delegation := &Delegation{Name: owner}
+	for _, record := range z.delegationRecords(owner) {
+		delegation.NameServers = append(delegation.NameServers, &DelegatedNameServer{
+			Host:      record.Value,
+			Addresses: z.glueAddresses(record.Value),
+		})
+	}
+	if len(delegation.NameServers) == 0 {
+		return nil
+	}
+	return delegation
+}
+
+// SetDelegation replaces the name servers of a subdomain. The name servers in the zone need glue: the addresses given
+// replace the A and AAAA records of their host, the host keeping its records otherwise, and having none is refused.
+// The glue under the subdomain no longer pointed to is removed. The zone is left partly changed on error.
+func (z *Zone) SetDelegation(delegation *Delegation) (added []*Record, removed []*Record, err error) {
+	name := z.NormalizeRecordName(delegation.Name)
+	err = z.CheckRecordName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	owner, _ := z.relativeName(name)
+	if !isValidHostName(name) || owner == "@" || len(delegation.NameServers) == 0 {
+		return nil, nil, ErrorInvalidDelegation
+	}
+
+	var (
+		records []*Record
+		hosts   []string
+		// inZoneHosts and glued hold the name servers in the zone, by relative name, glued those given addresses.
+		inZoneHosts = map[string]bool{}
+		glued       = map[string]bool{}
+	)
+	for _, nameServer := range delegation.NameServers {
+		host := strings.TrimSpace(nameServer.Host)
+		qualifiedHost := strings.ToLower(QualifyName(host))
+		if !isValidHostName(strings.TrimSuffix(host, ".")) || containsString(hosts, qualifiedHost) {
+			return nil, nil, ErrorInvalidDelegation
+		}
+		hosts = append(hosts, qualifiedHost)
+		records = append(records, NewRecord(owner, "NS", host))
+		hostOwner, inZone := z.relativeName(qualifiedHost)
+		if !inZone {
+			if len(nameServer.Addresses) > 0 {
+				return nil, nil, fmt.Errorf("%v is outside of the zone, its glue is served by its own zone", host)
+			}
+			continue
+		}
+		inZoneHosts[hostOwner] = true
+		if len(nameServer.Addresses) == 0 {
+			if len(z.glueAddresses(host)) == 0 {
+				return nil, nil, fmt.Errorf("%v is in the zone, its glue addresses are required", host)
+			}
+			continue
+		}
+		glued[hostOwner] = true
+		for _, address := range nameServer.Addresses {
+			ip := net.ParseIP(strings.TrimSpace(address))
+			if ip == nil {
+				return nil, nil, fmt.Errorf("%v is not a valid glue address of %v", address, host)
+			}
+			recordType := "AAAA"
+			if ip.To4() != nil {
+				recordType = "A"
+			}
+			records = append(records, NewRecord(hostOwner, recordType, ip.String()))
+		}
+	}
+
+	// The records of the delegation kept as they are, by name, type and value.
+	kept := map[string]bool{}
+	for _, record := range z.Records {
+		name, _ := z.relativeName(record.Name)
+		recordType := strings.ToUpper(record.Type)
+		switch {
+		case recordType == "NS" && name == owner:
+		case (recordType == "A" || recordType == "AAAA") && glued[name]:
+		case (recordType == "A" || recordType == "AAAA") && isBelow(name, owner) && !inZoneHosts[name]:
+			// Glue of a name server no longer pointed to.
+		default:
+			continue
+		}
+		key := name + " " + recordType + " " + strings.ToLower(record.Value)
+		if !kept[key] && containsRecord(records, name, recordType, record.Value) {
+			kept[key] = true
+			continue
+		}
+		err = record.CheckEditable()
+		if err != nil {
+			return nil, nil, err
+		}
+		removed = append(removed, record)
+	}
+	for _, record := range removed {
+		err = z.DeleteRecord(record)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, record := range records {
+		key := record.Name + " " + record.Type + " " + strings.ToLower(record.Value)
+		if kept[key] {
+			continue
+		}
+		kept[key] = true
+		err = z.AddRecord(record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v %v", err, record.Name, record.Value)
+		}
+		added = append(added, record)
+	}
+	return added, removed, nil
+}
+
+// RemoveDelegation removes the NS records of a subdomain along with the glue under it, which are returned.
+func (z *Zone) RemoveDelegation(name string) ([]*Record, error) {
+	delegation := z.FindDelegation(name)
+	if delegation == nil {
+		return nil, ErrorDelegationNotFound
+	}
+	var removed []*Record
+	for _, record := range z.Records {
+		recordName, _ := z.relativeName(record.Name)
+		recordType := strings.ToUpper(record.Type)
+		if recordType == "NS" && recordName == delegation.Name ||
+			(recordType == "A" || recordType == "AAAA") && isBelow(recordName, delegation.Name) {
+			err := record.CheckEditable()
+			if err != nil {
+				return nil, err
+			}
+			removed = append(removed, record)
+		}
+	}
+	for _, record := range removed {
+		_ = z.DeleteRecord(record)
+	}
+	return removed, nil
+}
+
+func (z *Zone) delegationRecords(owner string) []*Record {
+	var records []*Record
+	for _, record := range z.Records {
+		name, _ := z.relativeName(record.Name)
+		if !record.Disabled && strings.ToUpper(record.Type) == "NS" && name == owner {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// glueAddresses returns the addresses of the A and AAAA records of host, nil when it is outside of the zone.
+func (z *Zone) glueAddresses(host string) []string {
+	hostOwner, inZone := z.relativeName(QualifyName(host))
+	if !inZone {
+		return nil
+	}
+	var addresses []string
+	for _, record := range z.Records {
+		name, _ := z.relativeName(record.Name)
+		recordType := strings.ToUpper(record.Type)
+		if !record.Disabled && name == hostOwner && (recordType == "A" || recordType == "AAAA") {
+			addresses = append(addresses, record.Value)
+		}
+	}
+	return addresses
+}
+
+// isBelow reports whether the relative name is owner or a name under it.
+func isBelow(name string, owner string) bool {
+	return name == owner || strings.HasSuffix(name, "."+owner)
+}
+
+func containsRecord(records []*Record, name string, recordType string, value string) bool {
+	for _, record := range records {
+		if record.Name == name && record.Type == recordType && strings.EqualFold(record.Value, value) {
+			return true
+		}
+	}
+	return false
+}
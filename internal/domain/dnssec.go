@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The dnssec-policy of the signed zones, named generating and rolling their keys on its own.
+const (
+	// DnssecPolicyDefault signs the zone with a single ECDSAP256SHA256 key never rolled, BIND's default policy.
+	DnssecPolicyDefault = "default"
+	// DnssecPolicyInsecure unsigns a signed zone, keeping its signatures until the DS records were removed from the
+	// parent zone and dropped from the caches.
+	DnssecPolicyInsecure = "insecure"
+)
+
+var DnssecPolicies = []string{DnssecPolicyDefault, DnssecPolicyInsecure}
+
+var ErrorInvalidDnssecPolicy = errors.New("dnssec policy is either default or insecure, empty for unsigned zones")
+
+// dnskeySecureEntryPoint is the flag of the keys the DS records of the parent zone point to.
+const dnskeySecureEntryPoint = 1
+
+// The digest types of the DS records, SHA-1 being no longer used for new DS records (RFC 8624).
+const (
+	DSDigestSHA256 = 2
+	DSDigestSHA384 = 4
+)
+
+var DSDigestTypes = []int{DSDigestSHA256, DSDigestSHA384}
+
+// DnssecKey is a key named signs a zone with, as found in its key directory.
+type DnssecKey struct {
+	Tag       int
+	Algorithm int
+	Flags     int
+	// DNSKEY is the DNSKEY record of the key.
+	DNSKEY string
+	// PublicKey is the public key of the DNSKEY record in base64.
+	PublicKey string
+	// DS are the DS records to publish in the parent zone, one by digest type of DSDigestTypes, for the key signing
+	// keys only.
+	DS []*DSRecord
+	// View is the view serving the zone from a zone file of its own the key signs, empty for the zone file the other
+	// views share.
+	View string
+}
+
+// DSRecord points the parent zone to a key signing key of the zone.
+type DSRecord struct {
+	Owner      string
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	// Digest is in hexadecimal.
+	Digest string
+}
+
+func (r *DSRecord) String() string {
+	return fmt.Sprintf("%v IN DS %v %v %v %v", r.Owner, r.KeyTag, r.Algorithm, r.DigestType, r.Digest)
+}
+
+// FindDS returns the DS record of the digest type, nil for the keys which are not key signing keys.
+func (k *DnssecKey) FindDS(digestType int) *DSRecord {
+	for _, ds := range k.DS {
+		if ds.DigestType == digestType {
+			return ds
+		}
+	}
+	return nil
+}
+
+// DnssecStatus is the signing state of a zone.
+type DnssecStatus struct {
+	Policy string
+	// Signing is false while the zone is served unsigned, e.g. the dnssec feature being disabled.
+	Signing bool
+	Keys    []*DnssecKey
+	// Status is the report of named on the keys and their next events, empty when the zone is not signed.
+	Status string
+}
+
+func IsValidDnssecPolicy(policy string) bool {
+	return policy == "" || containsString(DnssecPolicies, policy)
+}
+
+// IsSigned reports whether named signs the zone, or unsigns it gradually, once the dnssec feature is enabled.
+func (z *Zone) IsSigned() bool {
+	return z.DnssecPolicy != ""
+}
+
+// ParseDnssecKey parses the public key file of a key, e.g. Kexample.com.+013+12345.key, named writes to the key
+// directory of the zone.
+func ParseDnssecKey(contents string) (*DnssecKey, error) {
+	for _, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		for i, field := range fields {
+			if strings.ToUpper(field) != "DNSKEY" {
+				continue
+			}
+			if len(fields) < i+5 {
+				return nil, errors.New("DNSKEY records need flags, a protocol, an algorithm and a key")
+			}
+			return newDnssecKey(fields[0], fields[i+1:])
+		}
+	}
+	return nil, errors.New("key file has no DNSKEY record")
+}
+
+func newDnssecKey(owner string, fields []string) (*DnssecKey, error) {
+	flags, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	protocol, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := strings.Join(fields[3:], "")
+	keyData, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	rdata := appendUint16(nil, uint16(flags))
+	rdata = append(append(rdata, byte(protocol), byte(algorithm)), keyData...)
+
+	owner = QualifyName(strings.ToLower(owner))
+	key := &DnssecKey{
+		Tag:       dnskeyTag(rdata),
+		Algorithm: int(algorithm),
+		Flags:     int(flags),
+		DNSKEY:    fmt.Sprintf("%v IN DNSKEY %v %v %v %v", owner, flags, protocol, algorithm, publicKey),
+		PublicKey: publicKey,
+	}
+	if flags&dnskeySecureEntryPoint == 0 {
+		return key, nil
+	}
+	digestInput := append(wireName(owner), rdata...)
+	for _, digestType := range DSDigestTypes {
+		var digest []byte
+		switch digestType {
+		case DSDigestSHA256:
+			sum := sha256.Sum256(digestInput)
+			digest = sum[:]
+		case DSDigestSHA384:
+			sum := sha512.Sum384(digestInput)
+			digest = sum[:]
+		}
+		key.DS = append(key.DS, &DSRecord{
+			Owner:      owner,
+			KeyTag:     key.Tag,
+			Algorithm:  key.Algorithm,
+			DigestType: digestType,
+			Digest:     fmt.Sprintf("%X", digest),
+		})
+	}
+	return key, nil
+}
+
+// dnskeyTag computes the key tag of the DNSKEY record data, see RFC 4034 appendix B.
+func dnskeyTag(rdata []byte) int {
+	sum := 0
+	for i, b := range rdata {
+		if i&1 == 0 {
+			sum += int(b) << 8
+		} else {
+			sum += int(b)
+		}
+	}
+	sum += sum >> 16 & 0xFFFF
+	return sum & 0xFFFF
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParseDnssecKey(t *testing.T) {
+	// The keys of RFC 6605 section 6 and their DS records.
+	tests := []struct {
+		contents   string
+		tag        int
+		algorithm  int
+		digestType int
+		digest     string
+	}{
+		{
+			contents: "; This is a key-signing key, keyid 55648, for example.net.\n" +
+				"example.net. 3600 IN DNSKEY 257 3 13 GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edb " +
+				"krSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==\n",
+			tag:        55648,
+			algorithm:  13,
+			digestType: DSDigestSHA256,
+			digest:     "B4C8C1FE2E7477127B27115656AD6256F424625BF5C1E2770CE6D6E37DF61D17",
+		},
+		{
+			contents: "example.net. 3600 IN DNSKEY 257 3 14 xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1 " +
+				"w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8 /uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40",
+			tag:        10771,
+			algorithm:  14,
+			digestType: DSDigestSHA384,
+			digest: "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A94" +
+				"6DF983D6",
+		},
+	}
+	for _, test := range tests {
+		key, err := ParseDnssecKey(test.contents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Tag != test.tag || key.Algorithm != test.algorithm || key.Flags != 257 {
+			t.Fatalf("expected the key %v of algorithm %v, got %v %v %v", test.tag, test.algorithm, key.Tag,
+				key.Algorithm, key.Flags)
+		}
+		ds := key.FindDS(test.digestType)
+		if ds == nil || ds.Digest != test.digest || ds.KeyTag != test.tag || ds.Owner != "example.net." {
+			t.Fatalf("expected the DS record %v of digest type %v, got %v", test.digest, test.digestType, ds)
+		}
+	}
+
+	_, err := ParseDnssecKey("; no key\n")
+	if err == nil {
+		t.Fatal("expected a file without DNSKEY record to be refused")
+	}
+}
+
+func TestParseDnssecKeyOfAZoneSigningKey(t *testing.T) {
+	// The key of RFC 4034 section 5.4, a zone signing key having no DS record.
+	key, err := ParseDnssecKey("dskey.example.com. 86400 IN DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz " +
+		"fwJr1AYtsmx3TGkJaNXVbfi/ 2pHm822aJ5iI9BMzNXxeYCmZ DRD99WYwYqUSdjMmmAphXdvx egXd/M5+X7OrzKBaMbCVdFLU " +
+		"Uh6DhweJBjEVv5f2wwjM9Xzc nOf+EPbtG9DMBmADjFDc2w/r ljwvFw==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Tag != 60485 || len(key.DS) != 0 {
+		t.Fatalf("expected the key 60485 without DS record, got %v with %v", key.Tag, len(key.DS))
+	}
+}
+
+func TestDnskeyTag(t *testing.T) {
+	publicKey, err := base64.StdEncoding.DecodeString(strings.Join([]string{
+		"GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edb", "krSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==",
+	}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rdata := append([]byte{0x01, 0x01, 3, 13}, publicKey...)
+	if tag := dnskeyTag(rdata); tag != 55648 {
+		t.Fatalf("expected the key tag 55648, got %v", tag)
+	}
+}
@@ -1,10 +1,141 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrorZoneCheckFailed is returned along with the output of named-checkzone when it rejects a zone file.
+var ErrorZoneCheckFailed = errors.New("zone file is rejected by named-checkzone")
+
+// ErrorConfigCheckFailed is returned along with the output of named-checkconf when it rejects a generated named.conf,
+// named keeping the named.conf it was serving.
+var ErrorConfigCheckFailed = errors.New("config is rejected by named-checkconf")
+
+// ErrorZoneWithoutSOA is returned when a zone file is rendered for a zone without valid SOA record, named refusing to
+// load it.
+var ErrorZoneWithoutSOA = errors.New("zone has no valid SOA record")
 
 type DNSServer interface {
 	UpdateConfigs(ctx context.Context) error
 	Reload(ctx context.Context) error
+	// UpdateAndReload generates the configs, checks them and reloads named, then marks the zones it serves applied.
+	// The zones are left pending when any step fails, the configs named was serving being restored, and reloaded again
+	// when named fails to reload the new ones or does not answer once reloaded.
 	UpdateAndReload(ctx context.Context) error
+	// UpdateAndReloadZone generates the zone files of the zone named domain alone and reloads that zone, then marks it
+	// applied. Every zone is updated and reloaded like UpdateAndReload when named.conf has to change as well.
+	UpdateAndReloadZone(ctx context.Context, domain string) error
+	// RepairConsistency updates the configs like UpdateConfigs, reporting the files which were missing and the zone
+	// files no zone refers to. Unknown zone files are removed when removeUnknownFiles is set.
+	RepairConsistency(ctx context.Context, removeUnknownFiles bool) (*ConsistencyReport, error)
+	Shutdown(ctx context.Context) error
+
+	AddNegativeTrustAnchor(ctx context.Context, nta *NegativeTrustAnchor) error
+	RemoveNegativeTrustAnchor(ctx context.Context, nta *NegativeTrustAnchor) error
+
+	// FlushCache purges the whole cache when name is empty, a single name otherwise, or every name below it when
+	// tree is set.
+	FlushCache(ctx context.Context, name string, tree bool) error
+	DumpCache(ctx context.Context) ([]byte, error)
+
+	// ZoneFile returns the zone file of the zone as named serves it, at its current serial and along with its origin
+	// so it can be loaded by another DNS server.
+	ZoneFile(ctx context.Context, zone *Zone) (string, error)
+	// ServedRecords returns the records named serves for the zone: its records and the ones of the fragments it
+	// includes, their variables expanded and the ALIAS records materialized as A and AAAA records.
+	ServedRecords(ctx context.Context, zone *Zone) ([]*Record, error)
+	// CheckZone loads the zone file of the zone the way named would, without writing it, so a change named would fail
+	// to load the zone with is rejected before being saved. Zones without valid SOA record are not checked.
+	CheckZone(ctx context.Context, zone *Zone) error
+	// CheckZoneWithFragment checks the zone like CheckZone, fragment taking the place of the fragment of its name.
+	CheckZoneWithFragment(ctx context.Context, zone *Zone, fragment *RecordFragment) error
+	// DnssecKeys returns the keys the zone is signed with, as found in the key directories of its zone file and of the
+	// zone files of the views having their own, along with the DS records to publish in the parent zone.
+	DnssecKeys(ctx context.Context, zone *Zone) ([]*DnssecKey, error)
+	// DnssecStatus returns the keys the zone is signed with and the signing state named reports.
+	DnssecStatus(ctx context.Context, zone *Zone) (*DnssecStatus, error)
+	// TransferZone transfers the zone of domainName from primary, an IP address optionally followed by "port" and a
+	// port, and returns it as a zone file. The transfer is signed with key unless it is nil.
+	TransferZone(ctx context.Context, primary string, domainName string, key *TSIGKey) (string, error)
+
+	// SubscribeQueryLog registers a listener receiving every query logged by the DNS server.
+	SubscribeQueryLog(listener QueryLogListener)
+
+	// Ping checks that the DNS server is running and answering its control channel.
+	Ping(ctx context.Context) error
+	// LastReload returns the outcome of the last Reload, UpdateAndReload or UpdateAndReloadZone, nil before the first
+	// one.
+	LastReload() *ReloadResult
+	// LastCrash returns the last time named exited unexpectedly, nil when it never did. named is restarted after each
+	// crash, waiting longer after each crash in a row.
+	LastCrash() *NamedCrash
+}
+
+const (
+	ConsistencyActionRecreated = "recreated"
+	ConsistencyActionMissing   = "missing"
+	ConsistencyActionUnknown   = "unknown"
+	ConsistencyActionRemoved   = "removed"
+	ConsistencyActionRenamed   = "renamed"
+	ConsistencyActionSkipped   = "skipped"
+	ConsistencyActionMigrated  = "migrated"
+)
+
+type ConsistencyAction struct {
+	Action string
+	Path   string
+	// Zone is the domain the file belongs to, empty for files no zone refers to.
+	Zone    string
+	Message string
+}
+
+type ConsistencyReport struct {
+	Time    time.Time
+	Actions []*ConsistencyAction
+}
+
+func NewConsistencyReport() *ConsistencyReport {
+	return &ConsistencyReport{Time: time.Now()}
+}
+
+func (r *ConsistencyReport) Add(action, path, zone, message string) {
+	r.Actions = append(r.Actions, &ConsistencyAction{Action: action, Path: path, Zone: zone, Message: message})
+}
+
+type ForwarderMonitor interface {
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	// Statuses returns the latest probe result of every configured forwarder.
+	Statuses() []*ForwarderStatus
+	// HealthyForwarders filters out forwarders known to be dead. When none of them is healthy, all of them are
+	// returned so that named keeps trying instead of silently losing its upstreams.
+	HealthyForwarders(forwarders []string) []string
+}
+
+// NotifyScheduler publishes the changes deferred by the notify window of their zone once the window is over.
+type NotifyScheduler interface {
+	Start(ctx context.Context)
 	Shutdown(ctx context.Context) error
 }
+
+// RootHintsUpdater downloads the root hints once they are due when they are refreshed, see RootHintsRefresh.
+type RootHintsUpdater interface {
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	// Refresh downloads the root hints right away and regenerates the configs.
+	Refresh(ctx context.Context) error
+}
+
+type RpzFeedUpdater interface {
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	// Refresh downloads the feed right away and regenerates the response policy zone.
+	Refresh(ctx context.Context, feed *RpzFeed) error
+	// Remove drops the domains downloaded for the feed.
+	Remove(ctx context.Context, feed *RpzFeed) error
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	FailoverRolePrimary = "primary"
+	FailoverRoleStandby = "standby"
+
+	DefaultFailoverFailThreshold = 3
+)
+
+var FailoverRoles = []string{FailoverRolePrimary, FailoverRoleStandby}
+
+var ErrorFailoverNotStandby = errors.New("this manager is not configured as a standby")
+
+// FailoverSettings pairs this manager and its named with another pair. The standby watches the primary pair and
+// takes over once the primary failed FailThreshold probes in a row.
+type FailoverSettings struct {
+	Role string `json:"role"`
+	// PrimaryApiUrl is the base URL of the API of the primary manager, e.g. http://10.0.0.1:5555.
+	PrimaryApiUrl string `json:"primary_api_url"`
+	// PrimaryDnsAddress is the address the named of the primary pair listens on.
+	PrimaryDnsAddress string `json:"primary_dns_address"`
+	// ProbeName is resolved through the primary named, it should belong to a zone the primary serves.
+	ProbeName     string `json:"probe_name"`
+	FailThreshold int    `json:"fail_threshold"`
+	// PromoteCommand is run once the standby takes over, e.g. a keepalived notify script or a script moving the
+	// floating IP to this host.
+	PromoteCommand []string `json:"promote_command"`
+}
+
+func (f *FailoverSettings) IsValid() bool {
+	if !containsString(FailoverRoles, f.Role) || f.FailThreshold < 0 {
+		return false
+	}
+	if f.Role == FailoverRolePrimary {
+		return true
+	}
+	apiUrl, err := url.Parse(f.PrimaryApiUrl)
+	if err != nil || (apiUrl.Scheme != "http" && apiUrl.Scheme != "https") || apiUrl.Host == "" {
+		return false
+	}
+	return f.PrimaryDnsAddress != "" && f.ProbeName != "" && len(f.PromoteCommand) > 0
+}
+
+// Threshold returns the number of failed probes in a row after which the standby takes over.
+func (f *FailoverSettings) Threshold() int {
+	if f.FailThreshold == 0 {
+		return DefaultFailoverFailThreshold
+	}
+	return f.FailThreshold
+}
+
+type FailoverStatus struct {
+	// Role is empty when failover is not configured.
+	Role                string
+	PrimaryHealthy      bool
+	ConsecutiveFailures int
+	LastChecked         time.Time
+	LastError           string
+	// Promoted stays set until the manager restarts, the standby never hands the service back on its own so both
+	// pairs do not end up flapping.
+	Promoted   bool
+	PromotedAt time.Time
+}
+
+type FailoverWatchdog interface {
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	Status() *FailoverStatus
+	// Promote takes over right away, e.g. before a planned maintenance of the primary. It fails with
+	// ErrorFailoverNotStandby unless this manager is a standby.
+	Promote(ctx context.Context) error
+}
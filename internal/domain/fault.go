@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	FaultRepositoryError = "repository_error"
+	FaultSlowReload      = "slow_reload"
+	FaultNamedCrash      = "named_crash"
+)
+
+var FaultTypes = []string{FaultRepositoryError, FaultSlowReload, FaultNamedCrash}
+
+var (
+	ErrorInjectedFault = errors.New("injected repository error")
+	ErrorInvalidFault  = errors.New(
+		"fault type must be repository_error, slow_reload or named_crash, a slow_reload needs a positive delay")
+	ErrorChaosModeDisabled = errors.New("chaos mode is disabled, start the manager with CHAOS_MODE=true")
+)
+
+// Fault is a failure injected on purpose in chaos mode, rehearsing the monitoring and the runbooks of the operators.
+type Fault struct {
+	Type string
+	// Delay slows every reload down, slow_reload only.
+	Delay time.Duration
+	// Remaining is the number of calls the fault still applies to, 0 applying it until the faults are cleared.
+	Remaining  int
+	InjectedAt time.Time
+}
+
+func (f *Fault) IsValid() bool {
+	if !containsString(FaultTypes, f.Type) || f.Remaining < 0 {
+		return false
+	}
+	return f.Type != FaultSlowReload || f.Delay > 0
+}
+
+// FaultInjector holds the faults injected in chaos mode, the zone repository and the DNS server decorated by it
+// consuming them.
+type FaultInjector interface {
+	// Inject replaces the fault of the same type. A named_crash stops named right away instead of being kept, named
+	// starting again on the next reload.
+	Inject(ctx context.Context, fault *Fault) error
+	// Faults returns the faults still applying, in the order they were injected.
+	Faults() []*Fault
+	Clear()
+
+	// Take returns the fault of the type applying to the current call, using it up, nil when none is injected.
+	Take(faultType string) *Fault
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var fragmentNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+var (
+	ErrorInvalidFragmentName = errors.New("fragment names are made of lowercase letters, digits and '-'")
+	ErrorUnknownFragment     = errors.New("fragment is not found")
+	ErrorFragmentInUse       = errors.New("fragment is included by zones")
+)
+
+// RecordFragment is a shared collection of records, e.g. the TXT and CAA records every zone of the company publishes.
+// The zones including it serve its records along with their own, so a change of the fragment reaches each of them on
+// the next reload. The names of its records are relative to the zone including it.
+type RecordFragment struct {
+	Name    string
+	Records []*Record
+}
+
+func NewRecordFragment(name string) *RecordFragment {
+	return &RecordFragment{Name: name}
+}
+
+func IsValidFragmentName(name string) bool {
+	return fragmentNamePattern.MatchString(name)
+}
+
+// SetRecords replaces the records of the fragment, each of them having to be valid on its own and of one of
+// RecordTypes. ALIAS records are refused, their addresses being resolved per zone.
+func (f *RecordFragment) SetRecords(records []*Record) error {
+	seen := map[string]bool{}
+	for _, record := range records {
+		if !IsValidRecordType(record.Type) {
+			return fmt.Errorf("record type %v is not supported", record.Type)
+		}
+		if IsAliasRecord(record.Type) {
+			return fmt.Errorf("%v records cannot be shared by fragments", record.Type)
+		}
+		if record.Name == "" {
+			record.Name = "@"
+		}
+		if !IsValidOwnerName(record.Name) || record.Type == "" || record.Value == "" {
+			return fmt.Errorf("record %v %v is not valid", record.Name, record.Type)
+		}
+		// The values referring to variables are checked against the variables of each zone including the fragment.
+		if !HasVariables(record.Value) {
+			err := record.CheckValue()
+			if err != nil {
+				return err
+			}
+			if !record.IsValid() {
+				return fmt.Errorf("record %v %v is not valid", record.Name, record.Type)
+			}
+		}
+		key := record.Name + " " + record.Type + " " + record.Value
+		if seen[key] {
+			return errors.New("duplication of record")
+		}
+		seen[key] = true
+	}
+	f.Records = records
+	return nil
+}
+
+// SetFragments replaces the names of the fragments the zone includes, the caller checking they exist.
+func (z *Zone) SetFragments(names []string) error {
+	var fragments []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !IsValidFragmentName(name) {
+			return ErrorInvalidFragmentName
+		}
+		if !seen[name] {
+			seen[name] = true
+			fragments = append(fragments, name)
+		}
+	}
+	z.Fragments = fragments
+	return nil
+}
+
+// IncludesFragment reports whether the zone includes the fragment named name.
+func (z *Zone) IncludesFragment(name string) bool {
+	return containsString(z.Fragments, name)
+}
+
+// IncludedRecords returns the records of the zone followed by the ones of the fragments it includes, among
+// fragments, the records the zone holds already being left out.
+func (z *Zone) IncludedRecords(fragments []*RecordFragment) []*Record {
+	records := append([]*Record{}, z.Records...)
+	seen := map[string]bool{}
+	for _, record := range z.Records {
+		seen[z.NormalizeRecordName(record.Name)+" "+record.Type+" "+record.Value] = true
+	}
+	for _, fragment := range fragments {
+		if !z.IncludesFragment(fragment.Name) {
+			continue
+		}
+		for _, record := range fragment.Records {
+			key := z.NormalizeRecordName(record.Name) + " " + record.Type + " " + record.Value
+			if !seen[key] {
+				seen[key] = true
+				records = append(records, record)
+			}
+		}
+	}
+	return records
+}
+
+// CheckFragment fails when a record of the fragment breaks the apex of the zone, see CheckApexRecord, refers to a
+// variable the zone does not define, or expands to a value not valid for its type.
+func (z *Zone) CheckFragment(fragment *RecordFragment) error {
+	for _, record := range fragment.Records {
+		err := z.CheckApexRecord(record)
+		if err != nil {
+			return fmt.Errorf("%w (fragment %v)", err, fragment.Name)
+		}
+		if !HasVariables(record.Value) {
+			continue
+		}
+		err = z.CheckRecordValue(record)
+		if err != nil {
+			return fmt.Errorf("%w (fragment %v)", err, fragment.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecordFragmentSetRecords(t *testing.T) {
+	fragment := NewRecordFragment("common")
+	err := fragment.SetRecords([]*Record{NewRecord("@", "CAA", `0 issue "letsencrypt.org"`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range []*Record{
+		NewRecord("@", "SOA", "ns1.example.com. admin.example.com. 1 3600 600 86400 300"),
+		NewRecord("@", "HINFO", `"PC" "Linux"`),
+		NewRecord("www", "ALIAS", "example.net."),
+	} {
+		if fragment.SetRecords([]*Record{record}) == nil {
+			t.Fatalf("expected the %v record to be refused", record.Type)
+		}
+	}
+}
+
+func TestZoneCheckFragmentRefusesACNAMEAtTheApex(t *testing.T) {
+	zone := NewZone("example.com")
+	fragment := NewRecordFragment("common")
+	err := fragment.SetRecords([]*Record{NewRecord("@", "CNAME", "example.net.")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zone.CheckFragment(fragment); !errors.Is(err, ErrorCNAMEAtApex) {
+		t.Fatalf("expected %v, got %v", ErrorCNAMEAtApex, err)
+	}
+
+	err = fragment.SetRecords([]*Record{NewRecord("example.com.", "CNAME", "example.net.")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zone.CheckFragment(fragment); !errors.Is(err, ErrorCNAMEAtApex) {
+		t.Fatalf("expected %v for the absolute apex name, got %v", ErrorCNAMEAtApex, err)
+	}
+}
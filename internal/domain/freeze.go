@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrorZoneFrozen    = errors.New("zone is frozen, thaw it first")
+	ErrorZoneNotFrozen = errors.New("zone is not frozen")
+)
+
+func (z *Zone) IsFrozen() bool {
+	return !z.FrozenAt.IsZero()
+}
+
+// Freeze makes the zone read-only, e.g. during a registrar transfer or an incident: its changes are refused and its
+// zone file is left as it is by the reloads, named serving it at the same serial until it is thawed.
+func (z *Zone) Freeze(reason string, now time.Time) error {
+	if z.IsFrozen() {
+		return ErrorZoneFrozen
+	}
+	z.FrozenAt = now
+	z.FreezeReason = strings.TrimSpace(reason)
+	return nil
+}
+
+// Thaw lets the zone be changed again, its zone file being written by the next reload.
+func (z *Zone) Thaw() error {
+	if !z.IsFrozen() {
+		return ErrorZoneNotFrozen
+	}
+	z.FrozenAt = time.Time{}
+	z.FreezeReason = ""
+	return nil
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// MaxGeneratedRecords bounds the records of a generator, a generator being meant for pools of hosts rather than for
+// whole IPv6 networks.
+const MaxGeneratedRecords = 1024
+
+var (
+	ErrorInvalidRecordGenerator = errors.New(
+		"generators need a name containing $ and a range given as a CIDR or as the first and the last address")
+	ErrorRecordGeneratorTooLarge = fmt.Errorf("generators hold at most %v records", MaxGeneratedRecords)
+	ErrorGeneratedRecord         = errors.New("generated records are only changed through their generator")
+)
+
+// RecordGenerator keeps a set of sequential A or AAAA records in line with a range of addresses, the way $GENERATE
+// does in zone files, e.g. host-1 to host-14 for 192.0.2.0/28. Its records are stored along with the other records of
+// the zone, they are changed and removed through the generator only.
+type RecordGenerator struct {
+	Id string
+	// Name is the owner name of the records, $ being replaced by their index starting at 1, e.g. host-$.
+	Name string
+	// Range is either a CIDR, its network and broadcast addresses being left out for IPv4, e.g. 192.0.2.0/28, or the
+	// first and the last address, e.g. 192.0.2.10-192.0.2.20.
+	Range string
+}
+
+// Records returns the records the generator keeps in the zone, the addresses of its range in order.
+func (g *RecordGenerator) Records() ([]*Record, error) {
+	if !strings.Contains(g.Name, "$") {
+		return nil, ErrorInvalidRecordGenerator
+	}
+	addresses, err := g.addresses()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(addresses))
+	for i, address := range addresses {
+		recordType := "AAAA"
+		if address.To4() != nil {
+			recordType = "A"
+		}
+		record := NewRecord(strings.ReplaceAll(g.Name, "$", strconv.Itoa(i+1)), recordType, address.String())
+		record.Generator = g
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// isSame tells whether both are the same generator, its records pointing to a copy of it once the zone is loaded.
+func (g *RecordGenerator) isSame(other *RecordGenerator) bool {
+	return g == other || (g.Id != "" && g.Id == other.Id)
+}
+
+func (g *RecordGenerator) addresses() ([]net.IP, error) {
+	var first, last net.IP
+	if strings.Contains(g.Range, "/") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(g.Range))
+		if err != nil {
+			return nil, ErrorInvalidRecordGenerator
+		}
+		first = network.IP
+		last = make(net.IP, len(first))
+		for i := range first {
+			last[i] = first[i] | ^network.Mask[i]
+		}
+		if ones, bits := network.Mask.Size(); bits == 32 && ones < 31 {
+			first, last = nextAddress(first), previousAddress(last)
+		}
+	} else {
+		bounds := strings.SplitN(g.Range, "-", 2)
+		if len(bounds) != 2 {
+			return nil, ErrorInvalidRecordGenerator
+		}
+		first, last = net.ParseIP(strings.TrimSpace(bounds[0])), net.ParseIP(strings.TrimSpace(bounds[1]))
+		if first == nil || last == nil || (first.To4() == nil) != (last.To4() == nil) {
+			return nil, ErrorInvalidRecordGenerator
+		}
+		if first.To4() != nil {
+			first, last = first.To4(), last.To4()
+		}
+	}
+	if bytes.Compare(first, last) > 0 {
+		return nil, ErrorInvalidRecordGenerator
+	}
+
+	var addresses []net.IP
+	for address := first; ; address = nextAddress(address) {
+		if len(addresses) == MaxGeneratedRecords {
+			return nil, ErrorRecordGeneratorTooLarge
+		}
+		addresses = append(addresses, address)
+		if address.Equal(last) {
+			return addresses, nil
+		}
+	}
+}
+
+func nextAddress(address net.IP) net.IP {
+	next := make(net.IP, len(address))
+	copy(next, address)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func previousAddress(address net.IP) net.IP {
+	previous := make(net.IP, len(address))
+	copy(previous, address)
+	for i := len(previous) - 1; i >= 0; i-- {
+		previous[i]--
+		if previous[i] != 0xff {
+			break
+		}
+	}
+	return previous
+}
+
+// FindRecordGenerator returns nil when no generator of the zone has the id.
+func (z *Zone) FindRecordGenerator(generatorId string) *RecordGenerator {
+	for _, generator := range z.Generators {
+		if generator.Id == generatorId {
+			return generator
+		}
+	}
+	return nil
+}
+
+// GeneratedRecords returns the records of the zone kept by the generator.
+func (z *Zone) GeneratedRecords(generator *RecordGenerator) []*Record {
+	var records []*Record
+	for _, record := range z.Records {
+		if record.IsGeneratedBy(generator) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// ApplyRecordGenerator adds the generator to the zone, or replaces the one having its id, and brings its records in
+// line: the records still generated are kept, the others are removed and the missing ones are added. The zone is left
+// partly changed on error.
+func (z *Zone) ApplyRecordGenerator(generator *RecordGenerator) (added []*Record, removed []*Record, err error) {
+	records, err := generator.Records()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, record := range records {
+		if !IsValidOwnerName(record.Name) {
+			return nil, nil, fmt.Errorf("%w: %v", ErrorInvalidRecordGenerator, record.Name)
+		}
+		err = z.CheckRecordName(record.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		record.Name = z.NormalizeRecordName(record.Name)
+	}
+
+	kept := map[string]*Record{}
+	for _, record := range z.GeneratedRecords(generator) {
+		kept[record.Name+" "+record.Value] = record
+	}
+	for _, record := range records {
+		if existing, ok := kept[record.Name+" "+record.Value]; ok {
+			existing.Generator = generator
+			delete(kept, record.Name+" "+record.Value)
+			continue
+		}
+		added = append(added, record)
+	}
+	for _, record := range kept {
+		err = z.DeleteRecord(record)
+		if err != nil {
+			return nil, nil, err
+		}
+		removed = append(removed, record)
+	}
+	for _, record := range added {
+		err = z.AddRecord(record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v %v", err, record.Name, record.Value)
+		}
+	}
+
+	for i, existing := range z.Generators {
+		if existing.isSame(generator) {
+			z.Generators[i] = generator
+			return added, removed, nil
+		}
+	}
+	z.Generators = append(z.Generators, generator)
+	return added, removed, nil
+}
+
+// RemoveRecordGenerator removes the generator from the zone along with its records, which are returned.
+func (z *Zone) RemoveRecordGenerator(generator *RecordGenerator) []*Record {
+	removed := z.GeneratedRecords(generator)
+	for _, record := range removed {
+		_ = z.DeleteRecord(record)
+	}
+	var generators []*RecordGenerator
+	for _, existing := range z.Generators {
+		if !existing.isSame(generator) {
+			generators = append(generators, existing)
+		}
+	}
+	z.Generators = generators
+	return removed
+}
+
+// IsGeneratedBy reports whether the record is kept by the generator.
+func (r *Record) IsGeneratedBy(generator *RecordGenerator) bool {
+	return r.Generator != nil && r.Generator.isSame(generator)
+}
+
+// CheckEditable fails for the generated records, which are only changed through their generator.
+func (r *Record) CheckEditable() error {
+	if r.Generator != nil {
+		return ErrorGeneratedRecord
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// MaxHostsFileSize bounds the hosts files imported at once.
+const MaxHostsFileSize = 4 << 20
+
+var ErrorInvalidHostsFile = errors.New("hosts file is not valid")
+
+// ImportHostsFile adds an A or AAAA record for every name of a file in the /etc/hosts format, an address followed by
+// a host name and its aliases per line, e.g. one from a lab being migrated. The names are those of the zone, fully
+// qualified without trailing dot or without any dot, the latter being relative to the zone. The other names, the
+// loopback, link-local and multicast addresses, e.g. the localhost lines, and the records the zone has already are
+// skipped, being returned as "address name". The zone is left partly changed on error.
+func (z *Zone) ImportHostsFile(reader io.Reader) (added []*Record, skipped []string, err error) {
+	content, err := io.ReadAll(io.LimitReader(reader, MaxHostsFileSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(content) > MaxHostsFileSize {
+		return nil, nil, fmt.Errorf("%w: hosts files hold at most %v bytes", ErrorInvalidHostsFile, MaxHostsFileSize)
+	}
+
+	zoneDomain := NormalizeDomain(z.Domain)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		if ip == nil || len(fields) == 1 {
+			return nil, nil, fmt.Errorf("%w: line %v: expected an address followed by host names",
+				ErrorInvalidHostsFile, number)
+		}
+		recordType := "AAAA"
+		if ip.To4() != nil {
+			recordType = "A"
+		}
+		for _, host := range fields[1:] {
+			host = strings.ToLower(strings.TrimSuffix(host, "."))
+			if !isValidHostName(host) {
+				return nil, nil, fmt.Errorf("%w: line %v: %v is not a valid host name", ErrorInvalidHostsFile,
+					number, host)
+			}
+			name := host
+			switch {
+			case host == zoneDomain:
+				name = "@"
+			case strings.HasSuffix(host, "."+zoneDomain):
+				name = strings.TrimSuffix(host, "."+zoneDomain)
+			case strings.Contains(host, "."):
+				name = ""
+			}
+			value := ip.String()
+			if name == "" || ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() ||
+				len(z.FindRecordyByCriteria(name, recordType, value)) > 0 {
+				skipped = append(skipped, fields[0]+" "+host)
+				continue
+			}
+			record := NewRecord(name, recordType, value)
+			err = z.AddRecord(record)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidHostsFile, number, err)
+			}
+			added = append(added, record)
+		}
+	}
+	return added, skipped, scanner.Err()
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxMissingNames bounds the missing names tracked at once, so the random names of a flood of queries do not
+	// grow the statistics without end. The names queried the least are forgotten for the new ones.
+	MaxMissingNames = 10000
+
+	DefaultMissingNamesLimit = 20
+)
+
+// MissingName is a name under a managed zone which was queried while the zone has no record for it, named answering
+// NXDOMAIN.
+type MissingName struct {
+	Zone string
+	// Name is the queried name relative to the zone.
+	Name    string
+	Queries int
+	// Types counts the queries by type.
+	Types         map[string]int
+	LastQueriedAt time.Time
+}
+
+// IsMissingName reports whether named answers NXDOMAIN to the queries for name: name is below the apex of the zone,
+// neither it nor a name below it owns a record, no wildcard covers it and it is not delegated. The disabled records
+// are left out.
+func (z *Zone) IsMissingName(name string) bool {
+	owner, inZone := z.relativeName(NormalizeDomain(name) + ".")
+	if !inZone || owner == "@" {
+		return false
+	}
+	for _, record := range z.Records {
+		if record.Disabled {
+			continue
+		}
+		recordName, _ := z.relativeName(record.Name)
+		if recordName == owner || strings.HasSuffix(recordName, "."+owner) {
+			return false
+		}
+		if strings.ToUpper(record.Type) == "NS" && strings.HasSuffix(owner, "."+recordName) {
+			return false
+		}
+	}
+	return len(z.RecordsAnswering(name, "ANY")) == 0
+}
+
+// wellKnownMailNames are the names the mail clients and the users look the mail server up with.
+var wellKnownMailNames = []string{"mail", "smtp", "imap", "pop", "pop3", "webmail", "autoconfig", "autodiscover"}
+
+// SuggestRecords returns the records to create for a missing name, for the well known names the zone has what they
+// point to: www pointing to the apex when it has addresses, the mail names pointing to the mail server of the apex
+// and _dmarc holding a policy only monitoring the mail. Other names get no suggestion.
+func (z *Zone) SuggestRecords(missing *MissingName) []*Record {
+	name := strings.ToLower(missing.Name)
+	switch {
+	case name == "www":
+		if len(z.RecordsAnswering(z.Domain, "A")) > 0 || len(z.RecordsAnswering(z.Domain, "AAAA")) > 0 {
+			return []*Record{NewRecord(name, "CNAME", NormalizeDomain(z.Domain)+".")}
+		}
+	case containsString(wellKnownMailNames, name):
+		mailServer := z.mailServer()
+		if name == "autodiscover" && strings.HasSuffix(mailServer, ".mail.protection.outlook.com.") {
+			return []*Record{NewRecord(name, "CNAME", "autodiscover.outlook.com.")}
+		}
+		owner, inZone := z.relativeName(mailServer)
+		if mailServer != "" && (!inZone || owner != name) {
+			return []*Record{NewRecord(name, "CNAME", mailServer)}
+		}
+	case name == "_dmarc":
+		return []*Record{NewRecord(name, "TXT", `"v=DMARC1; p=none"`)}
+	}
+	return nil
+}
+
+// mailServer returns the target of the preferred MX record of the apex with a trailing dot, empty without any.
+func (z *Zone) mailServer() string {
+	var mxRecords []*Record
+	for _, record := range z.RecordsAnswering(z.Domain, "MX") {
+		if strings.ToUpper(record.Type) == "MX" {
+			mxRecords = append(mxRecords, record)
+		}
+	}
+	if len(mxRecords) == 0 {
+		return ""
+	}
+	sort.SliceStable(mxRecords, func(i, j int) bool {
+		return mxRecords[i].Priority < mxRecords[j].Priority
+	})
+	target := strings.TrimSpace(mxRecords[0].Value)
+	if target == "" || target == "." {
+		return ""
+	}
+	if !strings.HasSuffix(target, ".") {
+		if target == "@" {
+			return NormalizeDomain(z.Domain) + "."
+		}
+		return target + "." + NormalizeDomain(z.Domain) + "."
+	}
+	return target
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// InventoryHost is a name of a zone owning A or AAAA records, a host for the configuration management.
+type InventoryHost struct {
+	// Name is fully qualified without trailing dot, e.g. web1.example.com.
+	Name string
+	Zone string
+	// Addresses are the values of the A records of the name followed by the AAAA ones.
+	Addresses []string
+	// Labels are the labels of the address records of the name, the first record setting a key winning.
+	Labels map[string]string
+}
+
+// InventoryHosts returns the hosts of the zone, by name, the address records being selected by every selector. The
+// disabled records, the wildcards and the records whose variables can not be expanded are left out.
+func (z *Zone) InventoryHosts(selectors []*LabelSelector) []*InventoryHost {
+	hosts := map[string]*InventoryHost{}
+	var names []string
+	for _, recordType := range []string{"A", "AAAA"} {
+		for _, record := range z.Records {
+			if record.Disabled || strings.ToUpper(record.Type) != recordType || !record.MatchesLabels(selectors) {
+				continue
+			}
+			name := z.QualifiedRecordName(z.NormalizeRecordName(record.Name))
+			if strings.HasPrefix(name, "*") {
+				continue
+			}
+			expanded, err := z.ExpandedRecord(record)
+			if err != nil {
+				continue
+			}
+			host, ok := hosts[name]
+			if !ok {
+				host = &InventoryHost{Name: name, Zone: NormalizeDomain(z.Domain), Labels: map[string]string{}}
+				hosts[name] = host
+				names = append(names, name)
+			}
+			address := strings.TrimSpace(expanded.Value)
+			if !containsString(host.Addresses, address) {
+				host.Addresses = append(host.Addresses, address)
+			}
+			for key, value := range record.Labels {
+				if _, ok := host.Labels[key]; !ok {
+					host.Labels[key] = value
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var inventory []*InventoryHost
+	for _, name := range names {
+		inventory = append(inventory, hosts[name])
+	}
+	return inventory
+}
+
+// AnsibleInventory returns the hosts in the JSON format of the Ansible dynamic inventories: a group per zone, e.g.
+// zone_example_com, and a group per label, e.g. label_env_prod, or label_env when the value is empty, the group names
+// being made of letters, digits and underscores. The hosts get their first address as ansible_host along with the
+// dns_zone, dns_addresses and dns_labels variables.
+func AnsibleInventory(hosts []*InventoryHost) map[string]interface{} {
+	hostVars := map[string]interface{}{}
+	groups := map[string][]string{}
+	for _, host := range hosts {
+		hostVars[host.Name] = map[string]interface{}{
+			"ansible_host":  host.Addresses[0],
+			"dns_zone":      host.Zone,
+			"dns_addresses": host.Addresses,
+			"dns_labels":    host.Labels,
+		}
+		zoneGroup := identifierName("zone_" + host.Zone)
+		groups[zoneGroup] = append(groups[zoneGroup], host.Name)
+		for key, value := range host.Labels {
+			labelGroup := "label_" + key
+			if value != "" {
+				labelGroup += "_" + value
+			}
+			labelGroup = identifierName(labelGroup)
+			if !containsString(groups[labelGroup], host.Name) {
+				groups[labelGroup] = append(groups[labelGroup], host.Name)
+			}
+		}
+	}
+
+	children := make([]string, 0, len(groups))
+	inventory := map[string]interface{}{"_meta": map[string]interface{}{"hostvars": hostVars}}
+	for group, groupHosts := range groups {
+		sort.Strings(groupHosts)
+		inventory[group] = map[string]interface{}{"hosts": groupHosts}
+		children = append(children, group)
+	}
+	sort.Strings(children)
+	inventory["all"] = map[string]interface{}{"children": children}
+	return inventory
+}
+
+// identifierName replaces the characters other than letters, digits and underscores with underscores, for the names
+// Ansible and Prometheus take, e.g. group and label names.
+func identifierName(name string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' {
+			return c
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	maxRecordLabelKeyLength   = 63
+	maxRecordLabelValueLength = 255
+)
+
+var ErrorInvalidRecordLabel = errors.New(
+	"label keys must be made of letters, digits, '-', '_', '.' and '/', values must be single lines of 255 characters at most")
+
+// IsValidRecordLabel reports whether a label can be set on a record, e.g. env=prod or team=payments.
+func IsValidRecordLabel(key, value string) bool {
+	if key == "" || len(key) > maxRecordLabelKeyLength || len(value) > maxRecordLabelValueLength {
+		return false
+	}
+	for _, c := range key {
+		isAlphanumeric := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !isAlphanumeric && c != '-' && c != '_' && c != '.' && c != '/' {
+			return false
+		}
+	}
+	return !strings.ContainsAny(value, "\r\n")
+}
+
+// SetLabels replaces the labels of the record, none when labels is empty.
+func (r *Record) SetLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if !IsValidRecordLabel(key, value) {
+			return ErrorInvalidRecordLabel
+		}
+	}
+	r.Labels = nil
+	for key, value := range labels {
+		if r.Labels == nil {
+			r.Labels = map[string]string{}
+		}
+		r.Labels[key] = value
+	}
+	return nil
+}
+
+// LabelSelector selects the records having a label, with any value when Value is nil.
+type LabelSelector struct {
+	Key   string
+	Value *string
+}
+
+// ParseLabelSelector parses either key=value or key, the latter selecting the records having the label whatever its
+// value.
+func ParseLabelSelector(selector string) (*LabelSelector, error) {
+	key, value := selector, ""
+	hasValue := false
+	if i := strings.Index(selector, "="); i >= 0 {
+		key, value, hasValue = selector[:i], selector[i+1:], true
+	}
+	if !IsValidRecordLabel(key, value) {
+		return nil, ErrorInvalidRecordLabel
+	}
+	parsed := &LabelSelector{Key: key}
+	if hasValue {
+		parsed.Value = &value
+	}
+	return parsed, nil
+}
+
+// MatchesLabels reports whether the record is selected by every selector.
+func (r *Record) MatchesLabels(selectors []*LabelSelector) bool {
+	for _, selector := range selectors {
+		value, ok := r.Labels[selector.Key]
+		if !ok || selector.Value != nil && value != *selector.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+// Localizer translates the messages of the API and the strings of the UI. Catalogs map the English message, or the
+// key of a UI string such as "ui.docs.title", to its translation.
+type Localizer interface {
+	Languages() []string
+	// Catalog returns nil when the language is not available.
+	Catalog(language string) map[string]string
+	// Translate returns the message as is when it has no translation. Messages followed by details, e.g.
+	// "zone has strict validation enabled: ...", are translated up to the details.
+	Translate(language, message string) string
+}
+
+// NegotiateLanguage picks the available language preferred by an Accept-Language header, e.g. "id-ID,id;q=0.9",
+// falling back to DefaultLanguage.
+func NegotiateLanguage(acceptLanguage string, available []string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, preference := range preferences {
+		if preference.tag == "*" {
+			return DefaultLanguage
+		}
+		primary := strings.SplitN(preference.tag, "-", 2)[0]
+		for _, language := range available {
+			if language == preference.tag || language == primary {
+				return language
+			}
+		}
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	OperationGet     = "get"
+	OperationPersist = "persist"
+	OperationDelete  = "delete"
+)
+
+type Metrics interface {
+	// ObserveRepositoryOperation records the outcome and latency of a repository call.
+	ObserveRepositoryOperation(repository, operation string, duration time.Duration, err error)
+	// Write renders every metric in the Prometheus text exposition format.
+	Write(ctx context.Context, w io.Writer) error
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrorLastNameServer = errors.New("the last NS record of the zone apex can not be removed")
+
+// CheckNameServers refuses a change removing the last name server of the apex, previous being the name servers
+// before the change.
+func (z *Zone) CheckNameServers(previous []string) error {
+	if len(previous) > 0 && len(z.NameServers()) == 0 {
+		return ErrorLastNameServer
+	}
+	return nil
+}
+
+// SetPrimaryNameServer changes the primary name server of the SOA. With SyncPrimaryNS, the apex NS record pointing to
+// the previous primary name server points to the new one. It returns that record and the record as it was before,
+// nils when no record changed.
+func (z *Zone) SetPrimaryNameServer(nameServer string) (*Record, *Record) {
+	record := z.primaryNameServerRecord()
+	z.SOA.PrimaryNameServer = nameServer
+	if !z.SyncPrimaryNS || record == nil || z.nameServerOf(record.Value) == z.nameServerOf(nameServer) {
+		return nil, nil
+	}
+	previous := *record
+	record.Value = nameServer
+	return record, &previous
+}
+
+// FollowNameServerChange moves the primary name server of the SOA along with the apex NS record pointing to it when
+// the zone has SyncPrimaryNS, previous being the record before the change and record nil when deleted. The first
+// remaining apex name server becomes the primary one when the record no longer is an apex NS record. It reports
+// whether the SOA changed.
+func (z *Zone) FollowNameServerChange(previous, record *Record) bool {
+	if !z.SyncPrimaryNS || z.SOA == nil || previous == nil || !z.isApexNameServer(previous) ||
+		z.nameServerOf(previous.Value) != z.nameServerOf(z.SOA.PrimaryNameServer) {
+		return false
+	}
+
+	next := record
+	if next == nil || !z.isApexNameServer(next) {
+		next = nil
+		for _, candidate := range z.Records {
+			if z.isApexNameServer(candidate) {
+				next = candidate
+				break
+			}
+		}
+	}
+	if next == nil || z.nameServerOf(next.Value) == z.nameServerOf(z.SOA.PrimaryNameServer) {
+		return false
+	}
+	z.SOA.PrimaryNameServer = z.nameServerOf(next.Value)
+	return true
+}
+
+// primaryNameServerRecord returns the apex NS record pointing to the primary name server of the SOA, nil when none
+// does.
+func (z *Zone) primaryNameServerRecord() *Record {
+	if z.SOA == nil {
+		return nil
+	}
+	for _, record := range z.Records {
+		if z.isApexNameServer(record) && z.nameServerOf(record.Value) == z.nameServerOf(z.SOA.PrimaryNameServer) {
+			return record
+		}
+	}
+	return nil
+}
+
+func (z *Zone) isApexNameServer(record *Record) bool {
+	return !record.Disabled && strings.ToUpper(record.Type) == "NS" && z.NormalizeRecordName(record.Name) == "@"
+}
+
+// nameServerOf returns the name server an NS value or a primary name server names, normalized. Relative names are in
+// the zone.
+func (z *Zone) nameServerOf(value string) string {
+	target := QualifyName(strings.TrimSpace(value))
+	switch {
+	case target == "@":
+		target = z.Domain
+	case !strings.HasSuffix(target, "."):
+		target = target + "." + z.Domain
+	}
+	return NormalizeDomain(target)
+}
@@ -3,9 +3,16 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
+var (
+	ErrorZoneExternalIdTaken   = errors.New("external_id is already used by another zone")
+	ErrorRecordExternalIdTaken = errors.New("external_id is already used by another record of the zone")
+)
+
 type Validation interface {
 	IsValid() bool
 }
@@ -16,12 +23,87 @@ type Zone struct {
 	FilePath string
 	SOA      *SOARecord
 	Records  []*Record
+	// Regulated zones only accept changes documented with complete ChangeMetadata.
+	Regulated bool
+	// StrictValidation turns the validation warnings introduced by a change into errors.
+	StrictValidation bool
+	// FrozenAt is when the zone was made read-only, see Freeze, zero while it can be changed. FreezeReason tells why.
+	FrozenAt     time.Time
+	FreezeReason string
+	// Notes, TechnicalContact and ExpiresAt are kept for bookkeeping only, ExpiresAt being the date the registration
+	// of the domain expires or has to be renewed, zero when unknown.
+	Notes            string
+	TechnicalContact string
+	ExpiresAt        time.Time
+	// Registrar is the name of the registrar account, as configured in the settings, the delegation is published
+	// through. The delegation is not published when empty.
+	Registrar string
+	// SyncPTR keeps the PTR records of the A and AAAA records of the zone in line, in the reverse zones managed here.
+	SyncPTR bool
+	// SyncPrimaryNS keeps the primary name server of the SOA and the apex NS record pointing to it in line.
+	SyncPrimaryNS bool
+	// NotifyInterval batches the changes of zones changing very often, the zone being published, and its secondaries
+	// notified, at most once per interval. Zero publishes every change right away.
+	NotifyInterval time.Duration
+	// AllowTransfer and TransferKeys are the secondaries allowed to transfer the zone, by address or by TSIG key, see
+	// SetAllowTransfer.
+	AllowTransfer []string
+	TransferKeys  []*TSIGKey
+	// AlsoNotify are the secondaries notified of the changes on top of the name servers of the zone, see
+	// SetAlsoNotify.
+	AlsoNotify []string
+	// Variables are referenced by the record values as ${NAME}, see SetVariables.
+	Variables map[string]string
+	// Generators keep sets of sequential records in line with ranges of addresses, see ApplyRecordGenerator.
+	Generators []*RecordGenerator
+	// Fragments are the names of the shared record fragments the zone serves along with its own records.
+	Fragments []string
+	// Views are the names of the views serving the zone, every view serving it when empty.
+	Views []string
+	// Group is the zone group, e.g. a tenant, whose folder holds the zone files of the zone, see
+	// Config.ZoneFolderPath. The zone files are kept in the bind folder when empty.
+	Group string
+	// DnssecPolicy is the dnssec-policy named signs the zone with, see DnssecPolicies, empty for unsigned zones.
+	DnssecPolicy string
+	// ExternalId is the id of the zone in the system of a client syncing it, e.g. a CRM, unique among the zones.
+	ExternalId string
+	// Revision is incremented every time the zone is persisted, AppliedRevision is the last revision named serves.
+	Revision        int
+	AppliedRevision int
+
+	events []*ChangeEvent
 }
 
+const (
+	ZoneStatusApplied = "applied"
+	ZoneStatusPending = "pending"
+)
+
 func NewZone(domain string) *Zone {
 	return &Zone{Domain: domain}
 }
 
+// Status tells whether named serves the latest revision of the zone, or whether its changes are saved but still
+// waiting for a successful reload.
+func (z *Zone) Status() string {
+	if z.AppliedRevision < z.Revision {
+		return ZoneStatusPending
+	}
+	return ZoneStatusApplied
+}
+
+// PublishDeferred reports whether the changes of the zone wait for the end of its notify window, the zone file and
+// the serial being left as they were last published until then.
+func (z *Zone) PublishDeferred(now time.Time) bool {
+	return z.NotifyInterval > 0 && z.SOA != nil && !z.SOA.PublishedAt.IsZero() &&
+		now.Before(z.SOA.PublishedAt.Add(z.NotifyInterval))
+}
+
+// PublishDue reports whether the zone has changes deferred by its notify window which can be published now.
+func (z *Zone) PublishDue(now time.Time) bool {
+	return z.NotifyInterval > 0 && z.Status() == ZoneStatusPending && !z.PublishDeferred(now)
+}
+
 func (z *Zone) RegisterSOA(soa *SOARecord) error {
 	if !soa.IsValid() {
 		return errors.New("invalid SOA")
@@ -42,6 +124,8 @@ func (z *Zone) FindRecordyById(recordId string) *Record {
 	return nil
 }
 
+// FindRecordyByCriteria returns the records matching every non empty criteria, "@" and the fully qualified zone
+// domain both matching the apex records.
 func (z *Zone) FindRecordyByCriteria(name, recordType, value string) []*Record {
 	if name == "" && recordType == "" && value == "" {
 		return nil
@@ -49,7 +133,7 @@ func (z *Zone) FindRecordyByCriteria(name, recordType, value string) []*Record {
 	var records []*Record
 	for _, record := range z.Records {
 		isMatch := true
-		if name != "" && record.Name != name {
+		if name != "" && z.NormalizeRecordName(record.Name) != z.NormalizeRecordName(name) {
 			isMatch = false
 		}
 		if recordType != "" && record.Type != recordType {
@@ -66,23 +150,41 @@ func (z *Zone) FindRecordyByCriteria(name, recordType, value string) []*Record {
 }
 
 func (z *Zone) AddRecord(record *Record) error {
+	if err := z.CheckRecordName(record.Name); err != nil {
+		return err
+	}
+	record.Name = z.NormalizeRecordName(record.Name)
 	if z.Records != nil {
 		for _, r := range z.Records {
 			if r == record {
 				return errors.New("duplication of record")
 			}
-			if r.Id == record.Id {
+			if record.Id != "" && r.Id == record.Id {
 				return errors.New("duplication of record")
 			}
-			if r.Name == record.Name && r.Type == record.Type && r.Value == record.Value {
+			if z.NormalizeRecordName(r.Name) == record.Name && r.Type == record.Type && r.Value == record.Value &&
+				r.View == record.View {
 				return errors.New("duplication of record")
 			}
+			if record.ExternalId != "" && r.ExternalId == record.ExternalId {
+				return ErrorRecordExternalIdTaken
+			}
 		}
 	}
 	z.Records = append(z.Records, record)
 	return nil
 }
 
+// FindRecordByExternalId returns nil when no record has the external id.
+func (z *Zone) FindRecordByExternalId(externalId string) *Record {
+	for _, record := range z.Records {
+		if record.ExternalId == externalId {
+			return record
+		}
+	}
+	return nil
+}
+
 func (z *Zone) DeleteRecord(record *Record) error {
 	if record == nil {
 		return errors.New("record is not found")
@@ -102,15 +204,155 @@ func (z *Zone) DeleteRecord(record *Record) error {
 	return nil
 }
 
+// CheckChange verifies a change to the zone is allowed with the given metadata, the frozen zones not being changed.
+func (z *Zone) CheckChange(metadata *ChangeMetadata) error {
+	if z.IsFrozen() {
+		return ErrorZoneFrozen
+	}
+	return z.CheckChangeMetadata(metadata)
+}
+
+// CheckChangeMetadata verifies the metadata documents the change well enough for the zone.
+func (z *Zone) CheckChangeMetadata(metadata *ChangeMetadata) error {
+	if z.Regulated && !metadata.IsComplete() {
+		return ErrorChangeMetadataRequired
+	}
+	return nil
+}
+
+// AddEvent records a change to be written to the outbox along with the zone by the repository.
+func (z *Zone) AddEvent(event *ChangeEvent) {
+	z.events = append(z.events, event)
+}
+
+func (z *Zone) PendingEvents() []*ChangeEvent {
+	return z.events
+}
+
+func (z *Zone) ClearEvents() {
+	z.events = nil
+}
+
+// NormalizeRecordName stores the apex as "@", whether it is given as an empty name or as the fully qualified zone
+// domain.
+func (z *Zone) NormalizeRecordName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || (strings.HasSuffix(name, ".") && NormalizeDomain(name) == NormalizeDomain(z.Domain)) {
+		return "@"
+	}
+	return name
+}
+
+// CheckRecordName rejects the names which would not end up where they are meant to be, a name ending with the zone
+// domain without trailing dot being rendered relative to the zone a second time.
+func (z *Zone) CheckRecordName(name string) error {
+	zoneDomain := NormalizeDomain(z.Domain)
+	lowerName := strings.ToLower(name)
+	if strings.HasSuffix(lowerName, ".") {
+		fqdn := strings.TrimSuffix(lowerName, ".")
+		if fqdn != zoneDomain && !strings.HasSuffix(fqdn, "."+zoneDomain) {
+			return fmt.Errorf("%v is outside of the zone %v", name, zoneDomain)
+		}
+		return nil
+	}
+	if lowerName == zoneDomain || strings.HasSuffix(lowerName, "."+zoneDomain) {
+		return fmt.Errorf("%v would be rendered as %v.%v, make it relative or add a trailing dot", name, name,
+			zoneDomain)
+	}
+	return nil
+}
+
 func (z *Zone) IsValid() bool {
 	return z.Domain != "" && z.FilePath != ""
 }
 
+const ZoneExpirationDateLayout = "2006-01-02"
+
+// ParseZoneExpirationDate parses a date in the YYYY-MM-DD format, an empty value being the zero time.
+func ParseZoneExpirationDate(value string) (time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(ZoneExpirationDateLayout, strings.TrimSpace(value))
+}
+
+// ZoneFilter selects zones, ZoneRepository.FindZones applying the same conditions as Matches.
+type ZoneFilter struct {
+	// ExpiringWithin matches the zones expiring before now plus the duration, including the expired ones.
+	ExpiringWithin time.Duration
+	// TechnicalContact matches the zones whose technical contact contains the value, case insensitively.
+	TechnicalContact string
+	// ExternalId matches the zone having exactly this external id.
+	ExternalId string
+	// Group matches the zones of this zone group.
+	Group string
+}
+
+func (f *ZoneFilter) Matches(zone *Zone, now time.Time) bool {
+	if f.ExpiringWithin > 0 && (zone.ExpiresAt.IsZero() || !zone.ExpiresAt.Before(now.Add(f.ExpiringWithin))) {
+		return false
+	}
+	if f.TechnicalContact != "" &&
+		!strings.Contains(strings.ToLower(zone.TechnicalContact), strings.ToLower(f.TechnicalContact)) {
+		return false
+	}
+	if f.ExternalId != "" && zone.ExternalId != f.ExternalId {
+		return false
+	}
+	if f.Group != "" && zone.Group != f.Group {
+		return false
+	}
+	return true
+}
+
+// Page selects a slice of a listing, a zero Limit selecting every item from Offset.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p Page) IsValid() bool {
+	return p.Limit >= 0 && p.Offset >= 0
+}
+
+// Bounds returns the start and the end of the page within a listing of total items.
+func (p Page) Bounds(total int) (int, int) {
+	start := p.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if p.Limit > 0 && start+p.Limit < total {
+		end = start + p.Limit
+	}
+	return start, end
+}
+
 type Record struct {
 	Id    string
 	Name  string
 	Type  string
 	Value string
+	// Priority is the preference of MX records and the priority of SRV records, it is not part of Value.
+	Priority int
+	// ExternalId is the id of the record in the system of a client syncing it, unique among the records of the zone.
+	ExternalId string
+	// Comment documents why the record exists, it is written in the zone file above the record.
+	Comment string
+	// Disabled records are kept but left out of the zone file, e.g. during a maintenance.
+	Disabled bool
+	// Canary records are served like the others but should never be queried, e.g. a tempting name in an internal
+	// zone, every query for their name raising a record.canary_queried event while the query log is enabled.
+	Canary bool
+	// TTL is the time to live of the record in seconds, the default TTL of the zone files when 0.
+	TTL int
+	// View is the name of the only view serving the record, overriding the records of the same name and type in that
+	// view, the record being served by every view when empty.
+	View string
+	// Labels are free-form key/value pairs used to organize the records, e.g. env=prod, nil without any.
+	Labels map[string]string
+	// Generator is the generator keeping the record, nil for the records managed one by one.
+	Generator *RecordGenerator
 }
 
 func NewRecord(name string, recordType string, value string) *Record {
@@ -121,10 +363,183 @@ func NewNSRecord(name string, value string) *Record {
 	return &Record{Name: name, Type: "NS", Value: value}
 }
 
+// RecordTypes are the types of the records the API manages.
+var RecordTypes = []string{
+	"A", "AAAA", "ALIAS", "NS", "CNAME", "MX", "TXT", "SRV", "DNSKEY", "KEY", "IPSECKEY", "PTR", "SPF", "TLSA", "CAA",
+}
+
+func IsValidRecordType(recordType string) bool {
+	return containsString(RecordTypes, strings.ToUpper(recordType))
+}
+
+const MaxRecordPriority = 65535
+
+// MaxRecordTTL is the largest TTL, RFC 2181 limiting TTLs to 31 bits.
+const MaxRecordTTL = 2147483647
+
+var ErrorInvalidRecordTTL = errors.New("ttl must be between 0 and 2147483647 seconds")
+
+func IsValidRecordTTL(ttl int) bool {
+	return ttl >= 0 && ttl <= MaxRecordTTL
+}
+
+// recordPriorityFields holds the number of fields of the value of the record types having a priority, e.g. the
+// weight, port and target of SRV records.
+var recordPriorityFields = map[string]int{"MX": 1, "SRV": 3}
+
+// recordTargetFields holds the index of the field naming another host in the value of the record types pointing to
+// one, e.g. the target of the SRV value "5 5060 sip.example.com".
+var recordTargetFields = map[string]int{"NS": 0, "CNAME": 0, "DNAME": 0, "PTR": 0, "MX": 0, "SRV": 2}
+
+func HasRecordPriority(recordType string) bool {
+	_, ok := recordPriorityFields[strings.ToUpper(recordType)]
+	return ok
+}
+
+// ExtractPriority moves the priority heading the value of MX and SRV records into Priority, the way values were
+// written before the priority had its own field, e.g. "10 mail.example.com". It reports whether the value held a
+// priority.
+func (r *Record) ExtractPriority() bool {
+	count, ok := recordPriorityFields[strings.ToUpper(r.Type)]
+	fields := strings.Fields(r.Value)
+	if !ok || len(fields) != count+1 {
+		return false
+	}
+	priority, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+	r.Priority = priority
+	r.Value = strings.Join(fields[1:], " ")
+	return true
+}
+
+// RenderedValue returns the value written to the zone file, the target host of the record types pointing to one
+// being qualified, the priority heading the value of the record types having one and the texts of TXT and SPF
+// records being quoted and split into strings of 255 bytes.
+func (r *Record) RenderedValue() string {
+	value := r.Value
+	if index, ok := recordTargetFields[strings.ToUpper(r.Type)]; ok {
+		fields := strings.Fields(r.Value)
+		if index < len(fields) {
+			fields[index] = QualifyName(fields[index])
+			value = strings.Join(fields, " ")
+		}
+	}
+	if HasRecordPriority(r.Type) {
+		value = fmt.Sprintf("%v %v", r.Priority, value)
+	}
+	if IsTextRecord(r.Type) {
+		if texts, err := TextStrings(r.Value); err == nil {
+			value = renderTextValue(texts)
+		}
+	}
+	return value
+}
+
+// RenderedTTL returns the TTL the record is served with, DefaultTTL when it has none of its own.
+func (r *Record) RenderedTTL() int {
+	if r.TTL > 0 {
+		return r.TTL
+	}
+	return DefaultTTL
+}
+
+// RenderedComment returns the comment as zone file comment lines, each ending with a newline, empty without comment.
+func (r *Record) RenderedComment() string {
+	comment := strings.TrimSpace(r.Comment)
+	if comment == "" {
+		return ""
+	}
+	var rendered strings.Builder
+	for _, line := range strings.Split(comment, "\n") {
+		rendered.WriteString(strings.TrimRight("; "+strings.TrimSpace(line), " ") + "\n")
+	}
+	return rendered.String()
+}
+
 func (r *Record) IsValid() bool {
-	return r.Name != "" && r.Type != "" && r.Value != ""
+	if HasRecordPriority(r.Type) && (r.Priority < 0 || r.Priority > MaxRecordPriority) || !IsValidRecordTTL(r.TTL) {
+		return false
+	}
+	if IsTextRecord(r.Type) {
+		texts, err := TextStrings(r.Value)
+		if err != nil || textDataLength(texts) > maxRecordDataLength {
+			return false
+		}
+	}
+	if r.CheckValue() != nil {
+		return false
+	}
+	return IsValidOwnerName(r.Name) && r.Type != "" && r.Value != ""
 }
 
+const (
+	maxDomainNameLength = 253
+	maxLabelLength      = 63
+)
+
+// IsValidOwnerName reports whether name can own a record: @ for the origin, or labels of letters, digits, hyphens and
+// underscores, e.g. _dmarc or _acme-challenge, relative to the origin or absolute with a trailing dot. A wildcard * is
+// only allowed as the leftmost label, and / as in the RFC 2317 names of classless reverse zones, e.g. 0/26.
+func IsValidOwnerName(name string) bool {
+	if name == "@" {
+		return true
+	}
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > maxDomainNameLength {
+		return false
+	}
+	for i, label := range strings.Split(name, ".") {
+		if label == "*" && i == 0 {
+			continue
+		}
+		if label == "" || len(label) > maxLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			isAlphanumeric := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+			if !isAlphanumeric && c != '-' && c != '_' && c != '/' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// InvalidOwnerNameRecords returns the records of the zone whose owner name is not valid, e.g. stored before the owner
+// names were validated. They are left out of the zone file, named refusing to load it otherwise.
+func (z *Zone) InvalidOwnerNameRecords() []*Record {
+	var records []*Record
+	for _, record := range z.Records {
+		if !IsValidOwnerName(record.Name) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// isValidHostName reports whether name can be resolved to addresses, an owner name without wildcard.
+func isValidHostName(name string) bool {
+	return name != "@" && !strings.HasPrefix(name, "*") && IsValidOwnerName(name)
+}
+
+const (
+	SerialStrategyDate      = "date"
+	SerialStrategyUnix      = "unix"
+	SerialStrategyIncrement = "increment"
+
+	// serialCatchUpDistance is how far behind the serial a strategy may be and still be caught up by incrementing,
+	// e.g. after more than 100 changes a day with the date strategy. Falling further behind means the strategy was
+	// changed for one producing lower serials.
+	serialCatchUpDistance = 1 << 20
+	// serialStep is the largest jump made at once when moving to a lower serial, small enough for the serial to
+	// keep being incremented until the next step without going past what secondaries consider newer.
+	serialStep = 1 << 30
+)
+
+var SerialStrategies = []string{SerialStrategyDate, SerialStrategyUnix, SerialStrategyIncrement}
+
 type SOARecord struct {
 	Id                string
 	Name              string
@@ -132,10 +547,16 @@ type SOARecord struct {
 	MailAddress       string
 	Serial            string
 	SerialCounter     int
-	Refresh           int
-	Retry             int
-	Expire            int
-	CacheTTL          int
+	// SerialStrategy decides the serial of the next version of the zone, date (YYYYMMDDnn), unix or increment.
+	SerialStrategy string
+	// SerialStepAt is set while moving to a lower serial, see UpdateSerial.
+	SerialStepAt time.Time
+	// PublishedAt is when the serial was last written to the zone file.
+	PublishedAt time.Time
+	Refresh     int
+	Retry       int
+	Expire      int
+	CacheTTL    int
 }
 
 func NewDefaultSOARecord(primaryNS, mailAddress string) *SOARecord {
@@ -144,6 +565,7 @@ func NewDefaultSOARecord(primaryNS, mailAddress string) *SOARecord {
 		PrimaryNameServer: primaryNS,
 		MailAddress:       mailAddress,
 		SerialCounter:     0,
+		SerialStrategy:    SerialStrategyDate,
 		Refresh:           7200,
 		Retry:             3600,
 		Expire:            1209600,
@@ -153,14 +575,117 @@ func NewDefaultSOARecord(primaryNS, mailAddress string) *SOARecord {
 	return soa
 }
 
+// UpdateSerial moves the serial to the next version of the zone. Serials compare using the serial number arithmetic
+// of RFC 1982, a serial being newer when it is less than 2^31 ahead, so the serial never goes backwards: it is
+// incremented while the strategy is behind. When the strategy was changed for one producing lower serials, the
+// serial wraps around in steps of 2^30, each step waiting for the refresh interval of the secondaries so they pick
+// the previous one up.
 func (s *SOARecord) UpdateSerial() {
-	counter := (s.SerialCounter + 1) % 100
-	serial := fmt.Sprintf("%v%02d", time.Now().Format("20060102"), counter)
-	s.SerialCounter = counter
-	s.Serial = serial
+	now := time.Now()
+	parsed, err := strconv.ParseUint(s.Serial, 10, 32)
+	if err != nil {
+		s.setSerial(s.targetSerial(0, now))
+		s.SerialStepAt = time.Time{}
+		return
+	}
+
+	current := uint32(parsed)
+	next := current + 1
+	target := s.targetSerial(current, now)
+	switch {
+	case !s.SerialStepAt.IsZero() && now.Before(s.SerialStepAt.Add(time.Duration(s.Refresh)*time.Second)):
+		// Secondaries may not have the last step yet, anything further ahead would look older to them.
+	case isSerialAhead(target, current):
+		next = target
+		s.SerialStepAt = time.Time{}
+	case current-target >= serialCatchUpDistance:
+		next = current + serialStep
+		s.SerialStepAt = now
+	}
+	s.setSerial(next)
+}
+
+func (s *SOARecord) targetSerial(current uint32, now time.Time) uint32 {
+	switch s.SerialStrategy {
+	case SerialStrategyUnix:
+		return uint32(now.Unix())
+	case SerialStrategyIncrement:
+		return current + 1
+	default:
+		date, _ := strconv.ParseUint(now.Format("20060102"), 10, 32)
+		return uint32(date) * 100
+	}
+}
+
+func (s *SOARecord) setSerial(serial uint32) {
+	s.Serial = strconv.FormatUint(uint64(serial), 10)
+	s.SerialCounter = 0
+	if s.SerialStrategy == SerialStrategyDate || s.SerialStrategy == "" {
+		s.SerialCounter = int(serial % 100)
+	}
+}
+
+// isSerialAhead reports whether serial is newer than current in serial number arithmetic.
+func isSerialAhead(serial, current uint32) bool {
+	return serial != current && serial-current < 1<<31
+}
+
+func IsValidSerialStrategy(strategy string) bool {
+	return containsString(SerialStrategies, strategy)
+}
+
+func (s *SOARecord) RenderedPrimaryNameServer() string {
+	return QualifyName(s.PrimaryNameServer)
+}
+
+// RenderedMailAddress returns the mail address in the SOA format.
+func (s *SOARecord) RenderedMailAddress() string {
+	return SOAMailAddress(s.MailAddress)
 }
 
 func (s *SOARecord) IsValid() bool {
-	return s.Name != "" && s.PrimaryNameServer != "" && s.MailAddress != "" &&
-		len(s.Serial) == 10 && s.Refresh > 0 && s.Retry > 0 && s.Expire > 0 && s.CacheTTL > 0
+	return s.Name != "" && s.PrimaryNameServer != "" && IsValidSOAMailAddress(s.MailAddress) &&
+		isValidSerial(s.Serial) && IsValidSerialStrategy(s.SerialStrategy) && s.Refresh > 0 && s.Retry > 0 && s.Expire > 0 && s.CacheTTL > 0
+}
+
+func isValidSerial(serial string) bool {
+	_, err := strconv.ParseUint(serial, 10, 32)
+	return err == nil
+}
+
+// QualifyName appends the trailing dot to a fully qualified name. Names without any dot are relative to the zone and
+// left intact, e.g. "mail" rather than "mail.example.com".
+func QualifyName(name string) string {
+	if name == "" || name == "@" || strings.HasSuffix(name, ".") || !strings.Contains(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// SOAMailAddress converts an email address into the SOA format, the dots of the local part being escaped, e.g.
+// john.doe@example.com becomes john\.doe.example.com. Addresses already in the SOA format are returned as is.
+func SOAMailAddress(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return address
+	}
+	local := strings.ReplaceAll(address[:at], ".", "\\.")
+	mailDomain := address[at+1:]
+	if !strings.HasSuffix(mailDomain, ".") {
+		mailDomain += "."
+	}
+	return local + "." + mailDomain
+}
+
+// IsValidSOAMailAddress accepts either an email address or a mail address already in the SOA format.
+func IsValidSOAMailAddress(address string) bool {
+	if address == "" || strings.ContainsAny(address, " \t;()\"") {
+		return false
+	}
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return true
+	}
+	local, mailDomain := address[:at], address[at+1:]
+	return local != "" && !strings.Contains(local, "@") && mailDomain != "" && !strings.HasPrefix(mailDomain, ".")
 }
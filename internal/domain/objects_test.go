@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValidOwnerName(t *testing.T) {
+	label63 := strings.Repeat("a", 63)
+	// 4 labels of 63 characters and their separating dots make 255 characters, trimmed to 253 and 254 below.
+	name255 := strings.Repeat(label63+".", 3) + label63
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "@", valid: true},
+		{name: "www", valid: true},
+		{name: "www.example.com.", valid: true},
+		{name: "*", valid: true},
+		{name: "*.dev", valid: true},
+		{name: "a.*", valid: false},
+		{name: "a.*.b", valid: false},
+		{name: "*a", valid: false},
+		{name: "_dmarc", valid: true},
+		{name: "_acme-challenge", valid: true},
+		{name: "_sip._tcp", valid: true},
+		{name: "-a", valid: false},
+		{name: "a-", valid: false},
+		{name: "a-b", valid: true},
+		{name: label63, valid: true},
+		{name: label63 + "a", valid: false},
+		{name: name255[:253], valid: true},
+		{name: name255[:254], valid: false},
+		{name: "0/26", valid: true},
+		{name: "65.0/26", valid: true},
+		{name: "a..b", valid: false},
+		{name: "a b", valid: false},
+		{name: "", valid: false},
+	}
+	for _, test := range tests {
+		if valid := IsValidOwnerName(test.name); valid != test.valid {
+			t.Errorf("IsValidOwnerName(%q) = %v, want %v", test.name, valid, test.valid)
+		}
+	}
+}
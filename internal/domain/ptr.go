@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// IsAddressRecord reports whether the record type maps a name to an address, the records PTR records mirror.
+func IsAddressRecord(recordType string) bool {
+	recordType = strings.ToUpper(recordType)
+	return recordType == "A" || recordType == "AAAA"
+}
+
+// ReverseName returns the name of the PTR record of an address, e.g. 4.3.2.1.in-addr.arpa for 1.2.3.4, false when
+// the value is not an address.
+func ReverseName(address string) (string, bool) {
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return "", false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%v.%v.%v.%v.in-addr.arpa", ip4[3], ip4[2], ip4[1], ip4[0]), true
+	}
+	var labels []string
+	for i := len(ip) - 1; i >= 0; i-- {
+		labels = append(labels, fmt.Sprintf("%x", ip[i]&0x0f), fmt.Sprintf("%x", ip[i]>>4))
+	}
+	return strings.Join(labels, ".") + ".ip6.arpa", true
+}
+
+// PTROf returns the reverse name of the address of an A or AAAA record of the zone and the name its PTR record points
+// to, false for the other records and the disabled ones.
+func (z *Zone) PTROf(record *Record) (string, string, bool) {
+	if record == nil || record.Disabled || !IsAddressRecord(record.Type) {
+		return "", "", false
+	}
+	record, err := z.ExpandedRecord(record)
+	if err != nil {
+		return "", "", false
+	}
+	reverseName, ok := ReverseName(record.Value)
+	if !ok {
+		return "", "", false
+	}
+	return reverseName, z.QualifiedRecordName(record.Name), true
+}
+
+// QualifiedRecordName returns the fully qualified name of a record of the zone, normalized.
+func (z *Zone) QualifiedRecordName(name string) string {
+	relative, inZone := z.relativeName(name)
+	switch {
+	case !inZone:
+		return NormalizeDomain(name)
+	case relative == "@":
+		return NormalizeDomain(z.Domain)
+	}
+	return relative + "." + NormalizeDomain(z.Domain)
+}
+
+// FindReverseZone returns the zone the PTR record of a reverse name belongs to, the most specific one when the
+// zones are nested, nil when none of the zones holds it. A classless zone is preferred to the zone delegating it.
+func FindReverseZone(zones []*Zone, reverseName string) *Zone {
+	var found *Zone
+	for _, zone := range zones {
+		zoneDomain := NormalizeDomain(zone.Domain)
+		if _, ok := ptrName(zoneDomain, reverseName); !ok {
+			continue
+		}
+		if found == nil || len(zoneDomain) > len(NormalizeDomain(found.Domain)) {
+			found = zone
+		}
+	}
+	return found
+}
+
+// SetPTR points the PTR record of the reverse name to target, adding the record when the zone has none. It returns
+// the record and the record as it was before, nil when added, or a nil record when the PTR was up to date already.
+func (z *Zone) SetPTR(reverseName string, target string) (*Record, *Record, error) {
+	if name, ok := ptrName(NormalizeDomain(z.Domain), reverseName); ok {
+		reverseName = name
+	}
+	value := NormalizeDomain(target) + "."
+	for _, record := range z.Records {
+		if strings.ToUpper(record.Type) != "PTR" || z.QualifiedRecordName(record.Name) != reverseName {
+			continue
+		}
+		if NormalizeDomain(record.Value) == NormalizeDomain(target) {
+			return nil, nil, nil
+		}
+		previous := *record
+		record.Value = value
+		return record, &previous, nil
+	}
+
+	record := NewRecord(strings.TrimSuffix(reverseName, "."+NormalizeDomain(z.Domain)), "PTR", value)
+	err := z.AddRecord(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, nil, nil
+}
+
+// RemovePTR deletes the PTR record of the reverse name pointing to target, returning nil when there is none.
+func (z *Zone) RemovePTR(reverseName string, target string) *Record {
+	if name, ok := ptrName(NormalizeDomain(z.Domain), reverseName); ok {
+		reverseName = name
+	}
+	for _, record := range z.Records {
+		if strings.ToUpper(record.Type) != "PTR" || z.QualifiedRecordName(record.Name) != reverseName ||
+			NormalizeDomain(record.Value) != NormalizeDomain(target) {
+			continue
+		}
+		if z.DeleteRecord(record) != nil {
+			return nil
+		}
+		return record
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/publicsuffix"
+	"strings"
+)
+
+var ErrorPublicSuffix = errors.New("zone domain is a public suffix, e.g. co.uk, set force to create it anyway")
+
+// IsPublicSuffix reports whether the domain is listed in the Public Suffix List, e.g. com or co.uk, the TLDs missing
+// from the list, e.g. internal ones like corp, not being public suffixes.
+func IsPublicSuffix(domainName string) bool {
+	domainName = NormalizeDomain(domainName)
+	suffix, icann := publicsuffix.PublicSuffix(domainName)
+	// The TLDs missing from the list fall back to a suffix of a single label outside of the ICANN section.
+	return suffix == domainName && (icann || strings.Contains(suffix, "."))
+}
+
+// SimilarDomains returns the registrable domains of the zones the domain is a near typo of, a character being added,
+// removed or replaced, or two adjacent characters being swapped, e.g. example.com for examp1e.com. The registrable
+// domains, e.g. example.com for dc1.example.com, are compared so that the subzones of a domain are not reported.
+func SimilarDomains(domainName string, zoneDomains []string) []string {
+	registrable := registrableDomain(domainName)
+	var similar []string
+	for _, zoneDomain := range zoneDomains {
+		other := registrableDomain(zoneDomain)
+		if other != registrable && !containsString(similar, other) && typoDistance(registrable, other) == 1 {
+			similar = append(similar, other)
+		}
+	}
+	return similar
+}
+
+// SimilarZoneWarnings warns about the registrable domains of the zones the domain of a new zone is a near typo of,
+// see SimilarDomains.
+func SimilarZoneWarnings(domainName string, zoneDomains []string) []*ValidationWarning {
+	var warnings []*ValidationWarning
+	for _, similar := range SimilarDomains(domainName, zoneDomains) {
+		warnings = append(warnings, &ValidationWarning{
+			Code:    WarningSimilarZone,
+			Message: fmt.Sprintf("%v is a near typo of the managed domain %v", NormalizeDomain(domainName), similar),
+		})
+	}
+	return warnings
+}
+
+// registrableDomain returns the public suffix of the domain along with the label before it, the domain itself when
+// it is a public suffix.
+func registrableDomain(domainName string) string {
+	domainName = NormalizeDomain(domainName)
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domainName)
+	if err != nil {
+		return domainName
+	}
+	return registrable
+}
+
+// typoDistance returns the optimal string alignment distance of a and b, the number of characters added, removed or
+// replaced and of adjacent characters swapped to turn a into b.
+func typoDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			distance := minInt(rows[i-1][j]+1, minInt(rows[i][j-1]+1, rows[i-1][j-1]+cost))
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				distance = minInt(distance, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = distance
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
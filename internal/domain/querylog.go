@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// MaxQueryStatsNetworks bounds the client networks counted at once, so the spoofed addresses of a flood of queries do
+// not grow the statistics without end. The queries of the networks coming later are left out of the statistics.
+const MaxQueryStatsNetworks = 10000
+
+type QueryLogEntry struct {
+	Time     time.Time
+	ClientIP string
+	Name     string
+	Type     string
+	// ClientSubnet is the EDNS Client Subnet sent along with the query, empty when absent.
+	ClientSubnet string
+}
+
+type QueryLogListener interface {
+	OnQuery(entry *QueryLogEntry)
+}
+
+type NetworkQueryStats struct {
+	Network          string
+	FromClientSubnet bool
+	Queries          int
+	Zones            map[string]int
+}
+
+type QueryStatistics interface {
+	QueryLogListener
+	ChangeEventListener
+
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	Since() time.Time
+	Networks() []*NetworkQueryStats
+	// MissingNames returns the names of the managed zones queried since Since which are still missing.
+	MissingNames() []*MissingName
+}
+
+// CanaryMonitor raises an event for the queries of the canary records, see Record.Canary.
+type CanaryMonitor interface {
+	QueryLogListener
+
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+}
+
+// ClientNetwork returns the network a client address belongs to for statistics purposes, /24 for IPv4 and /56
+// for IPv6, so individual hosts of the same network are grouped together.
+func ClientNetwork(clientIP string) string {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return clientIP
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(56, 128)), Mask: net.CIDRMask(56, 128)}).String()
+}
+
+// FindZoneOfName returns the most specific zone domain that name belongs to, or an empty string.
+func FindZoneOfName(name string, zoneDomains []string) string {
+	name = NormalizeDomain(name)
+	found := ""
+	for _, zoneDomain := range zoneDomains {
+		zoneDomain = NormalizeDomain(zoneDomain)
+		if name != zoneDomain && !strings.HasSuffix(name, "."+zoneDomain) {
+			continue
+		}
+		if len(zoneDomain) > len(found) {
+			found = zoneDomain
+		}
+	}
+	return found
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrorInvalidRecordGroupChange = errors.New(
+		"record group changes need a label selector and at least one of ttl, value_suffix and disabled")
+	ErrorInvalidValueSuffix = errors.New("value suffix changes need the suffix to replace")
+)
+
+// RecordGroupChange is applied at once to the records selected by their labels across the zones, e.g. lowering the
+// TTL of the records of a service ahead of its migration.
+type RecordGroupChange struct {
+	// TTL replaces the TTL of the records when set, 0 restoring the default TTL of the zone files.
+	TTL *int
+	// ValueSuffix replaces the end of the values ending with its From, e.g. pointing the CNAME records to the new load
+	// balancer, the other values being left as they are.
+	ValueSuffix *ValueSuffixChange
+	Disabled    *bool
+}
+
+// ValueSuffixChange replaces the suffix From of the record values by To, From being matched case-insensitively.
+type ValueSuffixChange struct {
+	From string
+	To   string
+}
+
+func (c *RecordGroupChange) Check() error {
+	if c.TTL == nil && c.ValueSuffix == nil && c.Disabled == nil {
+		return ErrorInvalidRecordGroupChange
+	}
+	if c.TTL != nil && !IsValidRecordTTL(*c.TTL) {
+		return ErrorInvalidRecordTTL
+	}
+	if c.ValueSuffix != nil && c.ValueSuffix.From == "" {
+		return ErrorInvalidValueSuffix
+	}
+	return nil
+}
+
+// FindRecordGroup returns the records of the zone selected by every selector, none without selector.
+func (z *Zone) FindRecordGroup(selectors []*LabelSelector) []*Record {
+	if len(selectors) == 0 {
+		return nil
+	}
+	var records []*Record
+	for _, record := range z.Records {
+		if record.MatchesLabels(selectors) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// ChangeRecordGroup applies the change to the records of the zone selected by every selector. The records it
+// changed are returned along with copies of them as they were, the records the change leaves as they are being left
+// out. The generated records can not be changed. The zone is left partly changed on error.
+func (z *Zone) ChangeRecordGroup(
+	selectors []*LabelSelector, change *RecordGroupChange,
+) (changed []*Record, previous []*Record, err error) {
+	err = change.Check()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, record := range z.FindRecordGroup(selectors) {
+		previousRecord := *record
+		if change.TTL != nil {
+			record.TTL = *change.TTL
+		}
+		if suffix := change.ValueSuffix; suffix != nil &&
+			strings.HasSuffix(strings.ToLower(record.Value), strings.ToLower(suffix.From)) {
+			record.Value = record.Value[:len(record.Value)-len(suffix.From)] + suffix.To
+		}
+		if change.Disabled != nil {
+			record.Disabled = *change.Disabled
+		}
+		if record.TTL == previousRecord.TTL && record.Value == previousRecord.Value &&
+			record.Disabled == previousRecord.Disabled {
+			continue
+		}
+
+		err = record.CheckEditable()
+		if err == nil {
+			err = z.CheckRecordValue(record)
+		}
+		if err == nil && !z.IsValidRecord(record) {
+			err = errors.New("record is not valid")
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v %v %v", err, z.QualifiedRecordName(record.Name), record.Type,
+				previousRecord.Value)
+		}
+		changed = append(changed, record)
+		previous = append(previous, &previousRecord)
+	}
+	return changed, previous, nil
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrorInvalidIPv4Value   = errors.New("the value of an A record must be an IPv4 address")
+	ErrorInvalidIPv6Value   = errors.New("the value of an AAAA record must be an IPv6 address")
+	ErrorInvalidTargetValue = errors.New("the value of the record must be a host name")
+	ErrorInvalidSRVValue    = errors.New("the value of an SRV record must be a weight, a port and a target host name")
+	ErrorInvalidCAAValue    = errors.New("the value of a CAA record must be a flag, a tag and a value")
+	ErrorInvalidTLSAValue   = errors.New(
+		"the value of a TLSA record must be a usage, a selector, a matching type and hexadecimal data")
+)
+
+// CheckValue checks the value of the record against its type, e.g. an A record holding an IPv4 address or an MX
+// record naming a host, so that named does not refuse to load the zone. The values of the other types are written to
+// the zone file as they are.
+func (r *Record) CheckValue() error {
+	value := strings.TrimSpace(r.Value)
+	switch strings.ToUpper(r.Type) {
+	case "A":
+		ip := net.ParseIP(value)
+		if ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+			return ErrorInvalidIPv4Value
+		}
+	case "AAAA":
+		if net.ParseIP(value) == nil || !strings.Contains(value, ":") {
+			return ErrorInvalidIPv6Value
+		}
+	case "ALIAS":
+		if !isValidHostName(value) || net.ParseIP(value) != nil {
+			return ErrorInvalidTargetValue
+		}
+	case "NS", "CNAME", "DNAME", "PTR":
+		if !isValidTargetName(value) {
+			return ErrorInvalidTargetValue
+		}
+	case "MX":
+		// A single dot is the null MX of the domains receiving no mail.
+		if value != "." && !isValidTargetName(value) {
+			return ErrorInvalidTargetValue
+		}
+	case "SRV":
+		fields := strings.Fields(value)
+		if len(fields) != 3 || !isUintField(fields[0], 65535) || !isUintField(fields[1], 65535) ||
+			fields[2] != "." && !isValidTargetName(fields[2]) {
+			return ErrorInvalidSRVValue
+		}
+	case "CAA":
+		fields := strings.SplitN(value, " ", 3)
+		if len(fields) != 3 || !isUintField(fields[0], 255) || !isAlphanumeric(fields[1]) {
+			return ErrorInvalidCAAValue
+		}
+		if _, err := TextStrings(fields[2]); err != nil || strings.TrimSpace(fields[2]) == "" {
+			return ErrorInvalidCAAValue
+		}
+	case "TLSA":
+		fields := strings.Fields(value)
+		if len(fields) < 4 || !isUintField(fields[0], 3) || !isUintField(fields[1], 1) || !isUintField(fields[2], 2) ||
+			!isHex(strings.Join(fields[3:], "")) {
+			return ErrorInvalidTLSAValue
+		}
+	}
+	return nil
+}
+
+// isValidTargetName reports whether name can be the host a record points to, @ being the origin of the zone. Unlike
+// owner names, a target can not be an address.
+func isValidTargetName(name string) bool {
+	return name == "@" || isValidHostName(name) && net.ParseIP(name) == nil
+}
+
+func isUintField(field string, max uint64) bool {
+	value, err := strconv.ParseUint(field, 10, 64)
+	return err == nil && value <= max
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"time"
+)
+
+// recoveryBundleVersion is bumped whenever the bundle changes, older bundles being upgraded when decoded.
+const recoveryBundleVersion = 1
+
+// MinRecoveryPassphraseLength keeps the passphrases encrypting the secrets of the bundles out of easy guessing.
+const MinRecoveryPassphraseLength = 12
+
+var (
+	ErrorInvalidRecoveryBundle   = errors.New("recovery bundle is not valid")
+	ErrorRecoveryPassphrase      = errors.New("recovery bundle can not be decrypted with the passphrase")
+	ErrorShortRecoveryPassphrase = errors.New("recovery passphrases need at least 12 characters")
+	ErrorRecoveryBundleSchema    = errors.New("recovery bundle comes from a newer manager, upgrade this one first")
+	ErrorRecoveryTargetNotEmpty  = errors.New("recovery bundles are only restored into a manager without zones")
+)
+
+// RecoveryBundle holds everything needed to rebuild a manager from scratch after a disaster: the zones, the shared
+// configuration and the server options. The TSIG keys, the managed ones and the ones of the zones, are encrypted
+// with a passphrase, the rest of the bundle being readable as is.
+type RecoveryBundle struct {
+	Version int `json:"version"`
+	// SchemaVersion is the database schema version of the manager which exported the bundle.
+	SchemaVersion int               `json:"schema_version"`
+	ExportedAt    time.Time         `json:"exported_at"`
+	Options       *ServerOptions    `json:"options"`
+	Zones         []*Zone           `json:"zones"`
+	Fragments     []*RecordFragment `json:"fragments"`
+	RpzFeeds      []*RpzFeed        `json:"rpz_feeds"`
+	RpzProfiles   []*RpzProfile     `json:"rpz_profiles"`
+	TSIGKeys      []*TSIGKey        `json:"-"`
+	// Secrets is the encryption of the TSIG keys, see recoverySecrets.
+	Secrets []byte `json:"secrets"`
+}
+
+type recoverySecrets struct {
+	TSIGKeys []*TSIGKey `json:"tsig_keys"`
+	// ZoneKeys are the transfer keys of the zones by domain, the zones of the bundle holding their name only.
+	ZoneKeys map[string][]*TSIGKey `json:"zone_keys"`
+}
+
+func NewRecoveryBundle(schemaVersion int) *RecoveryBundle {
+	return &RecoveryBundle{Version: recoveryBundleVersion, SchemaVersion: schemaVersion, ExportedAt: time.Now()}
+}
+
+// Encode returns the gzip compressed JSON document of the bundle, its TSIG keys being encrypted with the passphrase.
+func (b *RecoveryBundle) Encode(passphrase string) ([]byte, error) {
+	if len(passphrase) < MinRecoveryPassphraseLength {
+		return nil, ErrorShortRecoveryPassphrase
+	}
+
+	secrets := recoverySecrets{TSIGKeys: b.TSIGKeys, ZoneKeys: map[string][]*TSIGKey{}}
+	encoded := *b
+	encoded.Zones = nil
+	for _, zone := range b.Zones {
+		zoneCopy := *zone
+		zoneCopy.TransferKeys = nil
+		for _, key := range zone.TransferKeys {
+			zoneCopy.TransferKeys = append(zoneCopy.TransferKeys, &TSIGKey{Name: key.Name})
+			if !key.IsManaged() {
+				secrets.ZoneKeys[zone.Domain] = append(secrets.ZoneKeys[zone.Domain], key)
+			}
+		}
+		encoded.Zones = append(encoded.Zones, &zoneCopy)
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	encoded.Secrets, err = encryptRecoverySecrets(plaintext, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	err = json.NewEncoder(writer).Encode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeRecoveryBundle reads a bundle written by Encode, decrypting its TSIG keys with the passphrase.
+func DecodeRecoveryBundle(reader io.Reader, passphrase string) (*RecoveryBundle, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+	defer gzipReader.Close()
+
+	bundle := &RecoveryBundle{}
+	err = json.NewDecoder(gzipReader).Decode(bundle)
+	if err != nil {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+	if bundle.Version < 1 || bundle.Version > recoveryBundleVersion || bundle.Options == nil {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+
+	plaintext, err := decryptRecoverySecrets(bundle.Secrets, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	secrets := recoverySecrets{}
+	err = json.Unmarshal(plaintext, &secrets)
+	if err != nil {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+	bundle.TSIGKeys = secrets.TSIGKeys
+	for _, zone := range bundle.Zones {
+		for i, key := range zone.TransferKeys {
+			for _, secretKey := range secrets.ZoneKeys[zone.Domain] {
+				if secretKey.Name == key.Name {
+					zone.TransferKeys[i] = secretKey
+				}
+			}
+		}
+	}
+	return bundle, nil
+}
+
+// The secrets are sealed with AES-256-GCM, the key being derived from the passphrase with scrypt. The salt and the
+// nonce lead the ciphertext.
+const (
+	recoverySaltSize = 16
+	recoveryKeySize  = 32
+)
+
+func recoveryKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, recoveryKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptRecoverySecrets(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, recoverySaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := recoveryKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	sealed := append(salt, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+func decryptRecoverySecrets(sealed []byte, passphrase string) ([]byte, error) {
+	if len(sealed) < recoverySaltSize {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+	aead, err := recoveryKey(passphrase, sealed[:recoverySaltSize])
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[recoverySaltSize:]
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrorInvalidRecoveryBundle
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrorRecoveryPassphrase
+	}
+	return plaintext, nil
+}
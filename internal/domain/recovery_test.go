@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryBundleSealsTheTSIGSecrets(t *testing.T) {
+	managedKey, err := NewTSIGKey("shared-key", "hmac-sha256", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transferKey, err := NewTSIGKey("transfer-key", "hmac-sha256", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zone := NewZone("example.com")
+	zone.TransferKeys = []*TSIGKey{transferKey}
+
+	bundle := NewRecoveryBundle(1)
+	bundle.Options = NewDefaultServerOptions()
+	bundle.TSIGKeys = []*TSIGKey{managedKey}
+	bundle.Zones = []*Zone{zone}
+	encoded, err := bundle.Encode("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []*TSIGKey{managedKey, transferKey} {
+		if strings.Contains(string(document), key.Secret) {
+			t.Errorf("expected the secret of %v to be encrypted", key.Name)
+		}
+	}
+
+	_, err = DecodeRecoveryBundle(bytes.NewReader(encoded), "wrong horse battery staple")
+	if !errors.Is(err, ErrorRecoveryPassphrase) {
+		t.Fatalf("expected the wrong passphrase to be refused, got %v", err)
+	}
+	decoded, err := DecodeRecoveryBundle(bytes.NewReader(encoded), "correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.TSIGKeys) != 1 || decoded.TSIGKeys[0].Secret != managedKey.Secret {
+		t.Errorf("expected the managed key to be decrypted")
+	}
+	if keys := decoded.Zones[0].TransferKeys; len(keys) != 1 || keys[0].Secret != transferKey.Secret {
+		t.Errorf("expected the transfer key of the zone to be decrypted")
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	RegistrarTypeGandi     = "gandi"
+	RegistrarTypeNamecheap = "namecheap"
+	RegistrarTypeOpenSRS   = "opensrs"
+)
+
+var RegistrarTypes = []string{RegistrarTypeGandi, RegistrarTypeNamecheap, RegistrarTypeOpenSRS}
+
+var ErrorRegistrarUnsupported = errors.New("operation is not supported by the registrar")
+
+// RegistrarAccount holds the credentials of an account at a registrar. Accounts are configured in the settings file
+// only, zones refer to them by name.
+type RegistrarAccount struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// ApiKey is the Gandi personal access token, the Namecheap API key or the OpenSRS private key.
+	ApiKey string `json:"api_key"`
+	// Username is the Namecheap or OpenSRS reseller user name.
+	Username string `json:"username"`
+	// ClientIP is the whitelisted address Namecheap requires along with every request.
+	ClientIP string `json:"client_ip"`
+	// Endpoint overrides the API URL of the registrar, e.g. to use its sandbox.
+	Endpoint string `json:"endpoint"`
+}
+
+func (a *RegistrarAccount) IsValid() bool {
+	if a.Name == "" || a.ApiKey == "" || !containsString(RegistrarTypes, a.Type) {
+		return false
+	}
+	switch a.Type {
+	case RegistrarTypeNamecheap:
+		return a.Username != "" && a.ClientIP != ""
+	case RegistrarTypeOpenSRS:
+		return a.Username != ""
+	}
+	return true
+}
+
+// DelegationSigner is a DS record to publish in the parent zone. Flags and PublicKey describe the DNSKEY it digests,
+// for the registrars taking the key rather than the digest.
+type DelegationSigner struct {
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	Digest     string
+	Flags      int
+	PublicKey  string
+}
+
+// RegistrarConnector publishes the delegation of a domain at its registrar. The operations a registrar does not
+// offer through its API fail with ErrorRegistrarUnsupported.
+type RegistrarConnector interface {
+	SetNameServers(ctx context.Context, domainName string, nameServers []string) error
+	SetDelegationSigners(ctx context.Context, domainName string, signers []*DelegationSigner) error
+}
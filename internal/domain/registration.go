@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrorRegistrationNotFound = errors.New("registration of the domain is not found")
+
+// Registration is what the registry publishes about a domain.
+type Registration struct {
+	Domain    string
+	Registrar string
+	// ExpiresAt is zero when the registry does not publish it.
+	ExpiresAt time.Time
+	// NameServers are the delegated name servers, normalized.
+	NameServers []string
+}
+
+type RegistrationLookup interface {
+	// Lookup fails with ErrorRegistrationNotFound when the registry does not know the domain.
+	Lookup(ctx context.Context, domainName string) (*Registration, error)
+}
+
+// NameServerComparison compares the name servers delegated by the registry with the apex NS records of a zone.
+type NameServerComparison struct {
+	Registry []string
+	Zone     []string
+	// MissingAtRegistry are the name servers of the zone the domain is not delegated to.
+	MissingAtRegistry []string
+	// UnknownToZone are the delegated name servers the zone has no NS record for.
+	UnknownToZone []string
+}
+
+func CompareNameServers(registry []string, zone *Zone) *NameServerComparison {
+	comparison := &NameServerComparison{Registry: normalizeNames(registry), Zone: zone.NameServers()}
+	for _, nameServer := range comparison.Zone {
+		if !containsString(comparison.Registry, nameServer) {
+			comparison.MissingAtRegistry = append(comparison.MissingAtRegistry, nameServer)
+		}
+	}
+	for _, nameServer := range comparison.Registry {
+		if !containsString(comparison.Zone, nameServer) {
+			comparison.UnknownToZone = append(comparison.UnknownToZone, nameServer)
+		}
+	}
+	return comparison
+}
+
+func (c *NameServerComparison) Matches() bool {
+	return len(c.MissingAtRegistry) == 0 && len(c.UnknownToZone) == 0
+}
+
+// NameServers returns the targets of the apex NS records, fully qualified and normalized.
+func (z *Zone) NameServers() []string {
+	var nameServers []string
+	for _, record := range z.Records {
+		if z.isApexNameServer(record) {
+			nameServers = append(nameServers, z.nameServerOf(record.Value))
+		}
+	}
+	return normalizeNames(nameServers)
+}
+
+// normalizeNames normalizes, deduplicates and sorts names.
+func normalizeNames(names []string) []string {
+	var normalized []string
+	for _, name := range names {
+		name = NormalizeDomain(name)
+		if name != "" && !containsString(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,256 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"text/template"
+	"time"
+)
+
+const (
+	ReportFormatMarkdown = "markdown"
+	ReportFormatHtml     = "html"
+
+	DefaultReportPeriod   = 30 * 24 * time.Hour
+	reportTopNetworkCount = 10
+)
+
+// ZoneReport summarizes the changes, the health and the traffic of a zone over a period, meant to be forwarded to
+// the owner of the zone.
+type ZoneReport struct {
+	Domain      string
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	RecordCount int
+	Serial      string
+	// Changes are sorted oldest first.
+	Changes []*ChangeEvent
+	Checks  []*ReportCheck
+	// QueriesSince is when the query statistics started, they are not bound to the period of the report.
+	QueriesSince time.Time
+	Queries      int
+	TopNetworks  []*NetworkQueryCount
+}
+
+type ReportCheck struct {
+	Name   string
+	Passed bool
+	Detail string
+}
+
+type NetworkQueryCount struct {
+	Network string
+	Queries int
+}
+
+func NewZoneReport(zone *Zone, from, to time.Time) *ZoneReport {
+	report := &ZoneReport{
+		Domain:      zone.Domain,
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now(),
+		RecordCount: len(zone.Records),
+	}
+	if zone.SOA != nil {
+		report.Serial = zone.SOA.Serial
+	}
+	report.checkZone(zone)
+	return report
+}
+
+func (r *ZoneReport) checkZone(zone *Zone) {
+	r.Checks = append(r.Checks, &ReportCheck{
+		Name:   "SOA record",
+		Passed: zone.SOA != nil && zone.SOA.IsValid(),
+	})
+
+	hasNS := false
+	invalid := 0
+	for _, record := range zone.Records {
+		if !zone.IsValidRecord(record) {
+			invalid++
+		}
+		if record.Type == "NS" && record.Name == "@" {
+			hasNS = true
+		}
+	}
+	r.Checks = append(r.Checks, &ReportCheck{Name: "Apex NS records", Passed: hasNS})
+	check := &ReportCheck{Name: "Record validity", Passed: invalid == 0}
+	if invalid > 0 {
+		check.Detail = fmt.Sprintf("%v invalid records are not served", invalid)
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// SetChanges sets the changes of the period, given newest first as read from the audit log.
+func (r *ZoneReport) SetChanges(events []*ChangeEvent) {
+	r.Changes = nil
+	for i := len(events) - 1; i >= 0; i-- {
+		r.Changes = append(r.Changes, events[i])
+	}
+}
+
+func (r *ZoneReport) SetQueryStats(since time.Time, networks []*NetworkQueryStats) {
+	r.QueriesSince = since
+	r.Queries = 0
+	r.TopNetworks = nil
+	for _, stats := range networks {
+		queries := stats.Zones[NormalizeDomain(r.Domain)]
+		if queries == 0 {
+			continue
+		}
+		r.Queries += queries
+		r.TopNetworks = append(r.TopNetworks, &NetworkQueryCount{Network: stats.Network, Queries: queries})
+	}
+	sort.Slice(r.TopNetworks, func(i, j int) bool {
+		if r.TopNetworks[i].Queries != r.TopNetworks[j].Queries {
+			return r.TopNetworks[i].Queries > r.TopNetworks[j].Queries
+		}
+		return r.TopNetworks[i].Network < r.TopNetworks[j].Network
+	})
+	if len(r.TopNetworks) > reportTopNetworkCount {
+		r.TopNetworks = r.TopNetworks[:reportTopNetworkCount]
+	}
+}
+
+func IsValidReportFormat(format string) bool {
+	return format == ReportFormatMarkdown || format == ReportFormatHtml
+}
+
+func (r *ZoneReport) Render(format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == ReportFormatHtml {
+		err = htmlReportTemplate.Execute(&buf, r)
+	} else {
+		err = markdownReportTemplate.Execute(&buf, r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var reportFuncs = map[string]interface{}{
+	"date":     formatDate,
+	"describe": describeChange,
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// describeChange returns a one line description of a change, e.g. "A record www updated to 192.0.2.1".
+func describeChange(event *ChangeEvent) string {
+	switch event.Type {
+	case EventZoneCreated:
+		return "Zone created"
+	case EventZoneUpdated:
+		return "Zone settings updated"
+	case EventZoneDeleted:
+		return "Zone deleted"
+	case EventZoneArchived:
+		return "Zone archived"
+	case EventZoneRestored:
+		return "Zone restored"
+	case EventZoneFrozen:
+		return "Zone frozen"
+	case EventZoneThawed:
+		return "Zone thawed"
+	case EventChangeBurst:
+		return "Unusual burst of changes: " + event.Detail
+	}
+	if event.Record == nil {
+		return event.Type
+	}
+	record := event.Record
+	switch event.Type {
+	case EventRecordCreated:
+		return fmt.Sprintf("%v record %v added with %v", record.Type, record.Name, record.Value)
+	case EventRecordUpdated:
+		return fmt.Sprintf("%v record %v updated to %v", record.Type, record.Name, record.Value)
+	case EventRecordDeleted:
+		return fmt.Sprintf("%v record %v removed", record.Type, record.Name)
+	case EventCanaryQueried:
+		return fmt.Sprintf("Canary %v record %v %v", record.Type, record.Name, event.Detail)
+	}
+	return event.Type
+}
+
+var markdownReportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(
+	`# DNS report for {{.Domain}}
+
+Period: {{date .From}} to {{date .To}}
+Generated: {{date .GeneratedAt}}
+
+## Summary
+
+- Records: {{.RecordCount}}
+- SOA serial: {{if .Serial}}{{.Serial}}{{else}}none{{end}}
+- Changes in the period: {{len .Changes}}
+
+## Changes
+{{if .Changes}}
+| Time | Change | Ticket | Requested by |
+| --- | --- | --- | --- |
+{{range .Changes}}| {{date .Time}} | {{describe .}} | {{if .Change}}{{.Change.TicketId}}{{end}} | {{if .Change}}{{.Change.RequestedBy}}{{end}} |
+{{end}}{{else}}
+No changes were made in the period.
+{{end}}
+## Health checks
+
+{{range .Checks}}- {{if .Passed}}[PASS]{{else}}[FAIL]{{end}} {{.Name}}{{if .Detail}}: {{.Detail}}{{end}}
+{{end}}
+## Queries
+
+{{.Queries}} queries since {{date .QueriesSince}}.
+{{if .TopNetworks}}
+| Network | Queries |
+| --- | --- |
+{{range .TopNetworks}}| {{.Network}} | {{.Queries}} |
+{{end}}{{end}}`))
+
+var htmlReportTemplate = htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8"/>
+<title>DNS report for {{.Domain}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.pass { color: #2e7d32; }
+.fail { color: #c62828; }
+</style>
+</head>
+<body>
+<h1>DNS report for {{.Domain}}</h1>
+<p>Period: {{date .From}} to {{date .To}}<br/>Generated: {{date .GeneratedAt}}</p>
+<h2>Summary</h2>
+<ul>
+<li>Records: {{.RecordCount}}</li>
+<li>SOA serial: {{if .Serial}}{{.Serial}}{{else}}none{{end}}</li>
+<li>Changes in the period: {{len .Changes}}</li>
+</ul>
+<h2>Changes</h2>
+{{if .Changes}}<table>
+<tr><th>Time</th><th>Change</th><th>Ticket</th><th>Requested by</th></tr>
+{{range .Changes}}<tr><td>{{date .Time}}</td><td>{{describe .}}</td><td>{{if .Change}}{{.Change.TicketId}}{{end}}</td><td>{{if .Change}}{{.Change.RequestedBy}}{{end}}</td></tr>
+{{end}}</table>{{else}}<p>No changes were made in the period.</p>{{end}}
+<h2>Health checks</h2>
+<ul>
+{{range .Checks}}<li class="{{if .Passed}}pass{{else}}fail{{end}}">{{if .Passed}}PASS{{else}}FAIL{{end}} {{.Name}}{{if .Detail}}: {{.Detail}}{{end}}</li>
+{{end}}</ul>
+<h2>Queries</h2>
+<p>{{.Queries}} queries since {{date .QueriesSince}}.</p>
+{{if .TopNetworks}}<table>
+<tr><th>Network</th><th>Queries</th></tr>
+{{range .TopNetworks}}<tr><td>{{.Network}}</td><td>{{.Queries}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
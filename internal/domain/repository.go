@@ -3,19 +3,183 @@ package domain
 import (
 	"context"
 	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"time"
 )
 
+type readOnlyKey struct{}
+
+// WithReadOnly marks the queries made with ctx as read only, allowing the repositories to serve them from a read
+// replica. Replicas may lag behind, a read followed by a write must not use such a context.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func IsReadOnly(ctx context.Context) bool {
+	readOnly, _ := ctx.Value(readOnlyKey{}).(bool)
+	return readOnly
+}
+
 type ZoneRepository interface {
 	GetAllZones(ctx context.Context) ([]*Zone, error)
+	// FindZones returns the page of the zones matching the filter ordered by domain, along with the number of zones
+	// matching the filter.
+	FindZones(ctx context.Context, filter ZoneFilter, page Page) ([]*Zone, int, error)
 	GetZoneById(ctx context.Context, zoneId string) (*Zone, error)
 	GetZoneByDomain(ctx context.Context, domain string) (*Zone, error)
 
 	Persist(ctx context.Context, zone *Zone) error
+	// PersistAll saves the zones in a single transaction, none of them being saved on error.
+	PersistAll(ctx context.Context, zones []*Zone) error
+	// PersistSerial stores the serial of the SOA record of the zone and when it was published only, the rest of the
+	// zone being left as it is in the database.
+	PersistSerial(ctx context.Context, zone *Zone) error
+	// PersistFilePath stores the path of the zone file of the zone only, e.g. once the file was renamed.
+	PersistFilePath(ctx context.Context, zone *Zone) error
+	// MarkApplied records that named serves the zone at its revision, a newer applied revision being kept.
+	MarkApplied(ctx context.Context, zone *Zone) error
 	Delete(ctx context.Context, zone *Zone) error
 }
 
-var ErrorZoneNotFound = errors.New("zone is not found")
+// ZoneLocker serializes the changes of a zone, each one reading the zone from the repository, changing it and
+// persisting it back, so concurrent changes do not overwrite each other.
+//
+// A change locking several zones must lock them in the order of SortZoneLockOrder: the forward zones first, then the
+// reverse zones, each class sorted by domain. A change of a forward zone syncing its PTR records locks the reverse
+// zones while holding the forward zone, any other order would deadlock against it.
+type ZoneLocker interface {
+	// Lock blocks until no other change of the zone is in progress, the returned function ends the change.
+	Lock(domainName string) (unlock func())
+}
+
+// SortZoneLockOrder sorts the domains in the order a ZoneLocker must lock them in, the forward zones before the
+// reverse zones under in-addr.arpa or ip6.arpa.
+func SortZoneLockOrder(domains []string) {
+	sort.SliceStable(domains, func(i, j int) bool {
+		reverseI, reverseJ := isReverseDomain(domains[i]), isReverseDomain(domains[j])
+		if reverseI != reverseJ {
+			return !reverseI
+		}
+		return NormalizeDomain(domains[i]) < NormalizeDomain(domains[j])
+	})
+}
+
+func isReverseDomain(domainName string) bool {
+	return strings.HasSuffix("."+NormalizeDomain(domainName), ".arpa")
+}
+
+type ServerRepository interface {
+	GetOptions(ctx context.Context) (*ServerOptions, error)
+	PersistOptions(ctx context.Context, options *ServerOptions) error
+	// PersistRootHintsRefresh persists the outcome of a refresh of the root hints, leaving the other options as they
+	// are.
+	PersistRootHintsRefresh(ctx context.Context, options *ServerOptions) error
+
+	GetAllNegativeTrustAnchors(ctx context.Context) ([]*NegativeTrustAnchor, error)
+	GetNegativeTrustAnchorByDomain(ctx context.Context, domain string) (*NegativeTrustAnchor, error)
+	PersistNegativeTrustAnchor(ctx context.Context, nta *NegativeTrustAnchor) error
+	DeleteNegativeTrustAnchor(ctx context.Context, nta *NegativeTrustAnchor) error
+}
+
+type RpzRepository interface {
+	GetAllFeeds(ctx context.Context) ([]*RpzFeed, error)
+	GetFeedById(ctx context.Context, feedId string) (*RpzFeed, error)
+
+	PersistFeed(ctx context.Context, feed *RpzFeed) error
+	DeleteFeed(ctx context.Context, feed *RpzFeed) error
+
+	// GetProfiles returns the profiles whose enabled flag has been stored.
+	GetProfiles(ctx context.Context) ([]*RpzProfile, error)
+	PersistProfile(ctx context.Context, profile *RpzProfile) error
+}
+
+type FragmentRepository interface {
+	GetAllFragments(ctx context.Context) ([]*RecordFragment, error)
+	GetFragmentByName(ctx context.Context, name string) (*RecordFragment, error)
+
+	PersistFragment(ctx context.Context, fragment *RecordFragment) error
+	DeleteFragment(ctx context.Context, fragment *RecordFragment) error
+}
+
+// TSIGKeyRepository holds the managed TSIG keys, see TSIGKey.
+type TSIGKeyRepository interface {
+	GetAllTSIGKeys(ctx context.Context) ([]*TSIGKey, error)
+	GetTSIGKeyByName(ctx context.Context, name string) (*TSIGKey, error)
+
+	PersistTSIGKey(ctx context.Context, key *TSIGKey) error
+	DeleteTSIGKey(ctx context.Context, key *TSIGKey) error
+}
+
+// RecordUsageRepository holds when the records last answered a query, as seen in the query log.
+type RecordUsageRepository interface {
+	// GetLastQueries returns when the records of the zone last answered a query by record id, the records which did
+	// not yet being left out.
+	GetLastQueries(ctx context.Context, zone *Zone) (map[string]time.Time, error)
+	// PersistLastQueries stores when the records last answered a query by record id, the deleted records being
+	// forgotten.
+	PersistLastQueries(ctx context.Context, lastQueries map[string]time.Time) error
+}
+
+type WebhookRepository interface {
+	GetAllWebhooks(ctx context.Context) ([]*Webhook, error)
+	GetWebhookById(ctx context.Context, webhookId string) (*Webhook, error)
+
+	PersistWebhook(ctx context.Context, webhook *Webhook) error
+	DeleteWebhook(ctx context.Context, webhook *Webhook) error
+}
+
+// OutboxRepository holds the change events written along with zone mutations and their webhook deliveries.
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]*ChangeEvent, error)
+	// DispatchEvent marks an event as dispatched and queues its deliveries at once.
+	DispatchEvent(ctx context.Context, event *ChangeEvent, deliveries []*WebhookDelivery) error
+
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*WebhookDelivery, error)
+	PersistDelivery(ctx context.Context, delivery *WebhookDelivery) error
+
+	// Prune removes the dispatched events and finished deliveries older than before.
+	Prune(ctx context.Context, before time.Time) error
+	// AddEvents writes events made by no zone mutation, e.g. the alerts, to the outbox and the audit log.
+	AddEvents(ctx context.Context, events []*ChangeEvent) error
+}
+
+// AuditLogRepository reads the change events recorded along with every zone mutation, newest first.
+type AuditLogRepository interface {
+	GetAuditLogs(ctx context.Context, filter AuditLogFilter) ([]*ChangeEvent, error)
+	// CountChanges returns the number of changes of the zone within each of the windows of length window before to,
+	// the latest first, the events flagging the zone being left out.
+	CountChanges(ctx context.Context, zone string, to time.Time, window time.Duration, windows int) ([]int, error)
+
+	GetAllExporters(ctx context.Context) ([]*AuditExporter, error)
+	GetExporterById(ctx context.Context, exporterId string) (*AuditExporter, error)
+	PersistExporter(ctx context.Context, exporter *AuditExporter) error
+	DeleteExporter(ctx context.Context, exporter *AuditExporter) error
+}
+
+// ZoneArchiveRepository stores the archived zones along with their bundle.
+type ZoneArchiveRepository interface {
+	GetAllArchives(ctx context.Context) ([]*ZoneArchive, error)
+	GetArchiveById(ctx context.Context, archiveId string) (*ZoneArchive, error)
+	// GetBundle returns the encoded bundle of an archive.
+	GetBundle(ctx context.Context, archive *ZoneArchive) ([]byte, error)
+
+	PersistArchive(ctx context.Context, archive *ZoneArchive, bundle []byte) error
+	DeleteArchive(ctx context.Context, archive *ZoneArchive) error
+}
+
+var (
+	ErrorZoneNotFound       = errors.New("zone is not found")
+	ErrorInvalidZoneArchive = errors.New("zone archive bundle is not valid")
+	ErrorSchemaOutdated     = errors.New("database schema is newer than this instance, upgrade it to make changes")
+)
 
+// Migration brings the schema of the database shared by the instances to the version of this instance. Instances
+// migrate one at a time and never downgrade the schema.
 type Migration interface {
 	Migrate(ctx context.Context) error
+	// CheckSchema fails with ErrorSchemaOutdated once a newer instance migrated the database.
+	CheckSchema(ctx context.Context) error
+	// SchemaVersion is the version Migrate brings the database schema to.
+	SchemaVersion() int
 }
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrorInvalidReverseNetwork = errors.New(
+	"cidr must be an IPv4 network of /8, /16, /24 or longer, or an IPv6 network ending on a nibble")
+
+// classlessZonePattern matches the domains of the classless reverse zones of RFC 2317, e.g.
+// 64-26.2.0.192.in-addr.arpa for 192.0.2.64/26.
+var classlessZonePattern = regexp.MustCompile(`^(\d{1,3})-(\d{1,2})\.((?:\d{1,3}\.){3}in-addr\.arpa)$`)
+
+// ReverseZone is the reverse zone of a network, holding the PTR records of its addresses.
+type ReverseZone struct {
+	Network *net.IPNet
+	// Domain is the domain of the zone, e.g. 2.0.192.in-addr.arpa for 192.0.2.0/24. The IPv4 networks longer than /24
+	// get a classless zone of RFC 2317, e.g. 64-26.2.0.192.in-addr.arpa for 192.0.2.64/26.
+	Domain string
+	// Parent is the domain of the zone delegating a classless zone, e.g. 2.0.192.in-addr.arpa, empty for the others.
+	Parent string
+}
+
+// NewReverseZone returns the reverse zone of the network in the CIDR notation, e.g. 192.0.2.0/24 or 2001:db8::/32. The
+// address is taken as the network it is part of.
+func NewReverseZone(cidr string) (*ReverseZone, error) {
+	_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidReverseNetwork, strings.TrimSpace(cidr))
+	}
+	ones, bits := network.Mask.Size()
+	// The IPv4-mapped IPv6 networks, e.g. ::ffff:192.0.2.0/120, would be reversed as IPv4 addresses with an IPv6 mask.
+	if bits == net.IPv6len*8 && network.IP.To4() != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidReverseNetwork, strings.TrimSpace(cidr))
+	}
+	reverseName, _ := ReverseName(network.IP.String())
+	labels := strings.Split(reverseName, ".")
+	zone := &ReverseZone{Network: network}
+
+	switch {
+	case bits == net.IPv4len*8 && ones > 24:
+		zone.Parent = strings.Join(labels[1:], ".")
+		zone.Domain = fmt.Sprintf("%v-%v.%v", labels[0], ones, zone.Parent)
+	case bits == net.IPv4len*8 && ones >= 8 && ones%8 == 0:
+		zone.Domain = strings.Join(labels[4-ones/8:], ".")
+	case bits == net.IPv6len*8 && ones >= 4 && ones%4 == 0:
+		zone.Domain = strings.Join(labels[32-ones/4:], ".")
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidReverseNetwork, network)
+	}
+	return zone, nil
+}
+
+// DelegationRecords returns the records the parent zone of a classless zone delegates it with, relative to the parent:
+// the NS record of the zone pointing to nameServer and a CNAME record per address pointing to its PTR record in the
+// zone, e.g. 65 CNAME 65.64-26.2.0.192.in-addr.arpa. None are returned for the other zones.
+func (r *ReverseZone) DelegationRecords(nameServer string) []*Record {
+	if r.Parent == "" {
+		return nil
+	}
+	records := []*Record{NewNSRecord(strings.TrimSuffix(r.Domain, "."+r.Parent), nameServer)}
+	ones, bits := r.Network.Mask.Size()
+	first := int(r.Network.IP.To4()[3])
+	for address := first; address < first+1<<(bits-ones); address++ {
+		records = append(records, NewRecord(strconv.Itoa(address), "CNAME", fmt.Sprintf("%v.%v.", address, r.Domain)))
+	}
+	return records
+}
+
+// ptrName returns the name the PTR record of a reverse name has in the reverse zone of zoneDomain, false when the zone
+// does not hold it. The name is the reverse name itself, but in the classless zones where the address is moved below
+// the zone domain, e.g. 65.64-26.2.0.192.in-addr.arpa for 65.2.0.192.in-addr.arpa.
+func ptrName(zoneDomain, reverseName string) (string, bool) {
+	if !strings.HasSuffix(zoneDomain, ".arpa") {
+		return "", false
+	}
+	if strings.HasSuffix(reverseName, "."+zoneDomain) {
+		return reverseName, true
+	}
+	match := classlessZonePattern.FindStringSubmatch(zoneDomain)
+	if match == nil || !strings.HasSuffix(reverseName, "."+match[3]) {
+		return "", false
+	}
+	address, err := strconv.Atoi(strings.TrimSuffix(reverseName, "."+match[3]))
+	if err != nil {
+		return "", false
+	}
+	first, _ := strconv.Atoi(match[1])
+	ones, _ := strconv.Atoi(match[2])
+	if ones <= 24 || ones > 32 || address < first || address >= first+1<<(32-ones) {
+		return "", false
+	}
+	return fmt.Sprintf("%v.%v", address, zoneDomain), true
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewReverseZone(t *testing.T) {
+	tests := []struct {
+		cidr   string
+		domain string
+		parent string
+		err    bool
+	}{
+		{cidr: "10.0.0.0/8", domain: "10.in-addr.arpa"},
+		{cidr: "172.16.0.0/16", domain: "16.172.in-addr.arpa"},
+		{cidr: "192.0.2.0/24", domain: "2.0.192.in-addr.arpa"},
+		{cidr: "192.0.2.77/24", domain: "2.0.192.in-addr.arpa"},
+		{cidr: "192.0.2.64/26", domain: "64-26.2.0.192.in-addr.arpa", parent: "2.0.192.in-addr.arpa"},
+		{cidr: "192.0.2.7/32", domain: "7-32.2.0.192.in-addr.arpa", parent: "2.0.192.in-addr.arpa"},
+		{cidr: "10.0.0.0/20", err: true},
+		{cidr: "0.0.0.0/0", err: true},
+		{cidr: "2001:db8::/32", domain: "8.b.d.0.1.0.0.2.ip6.arpa"},
+		{cidr: "2001:db8:1::/52", domain: "0.1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+		{cidr: "2001:db8::/30", err: true},
+		{cidr: "2001:db8::/0", err: true},
+		{cidr: "::ffff:0.0.0.0/100", err: true},
+		{cidr: "::ffff:0.0.0.0/104", err: true},
+		{cidr: "::ffff:1.2.3.0/120", err: true},
+		{cidr: "192.0.2.0", err: true},
+		{cidr: "", err: true},
+	}
+	for _, test := range tests {
+		zone, err := NewReverseZone(test.cidr)
+		if test.err {
+			if !errors.Is(err, ErrorInvalidReverseNetwork) {
+				t.Errorf("%q: expected ErrorInvalidReverseNetwork, got %v", test.cidr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.cidr, err)
+			continue
+		}
+		if zone.Domain != test.domain || zone.Parent != test.parent {
+			t.Errorf("%q: got %q (parent %q), expected %q (parent %q)", test.cidr, zone.Domain, zone.Parent,
+				test.domain, test.parent)
+		}
+	}
+}
+
+func TestReverseZoneDelegationRecords(t *testing.T) {
+	zone, err := NewReverseZone("192.0.2.64/26")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := zone.DelegationRecords("ns1.example.com.")
+	if len(records) != 1+64 {
+		t.Fatalf("got %v records, expected 65", len(records))
+	}
+	if records[0].Name != "64-26" || records[0].Type != "NS" {
+		t.Errorf("got %v %v, expected the NS record of 64-26", records[0].Name, records[0].Type)
+	}
+	if last := records[len(records)-1]; last.Name != "127" || last.Value != "127.64-26.2.0.192.in-addr.arpa." {
+		t.Errorf("got %v CNAME %v, expected 127 CNAME 127.64-26.2.0.192.in-addr.arpa.", last.Name, last.Value)
+	}
+
+	zone, err = NewReverseZone("192.0.2.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records := zone.DelegationRecords("ns1.example.com."); len(records) != 0 {
+		t.Errorf("got %v records for a /24, expected none", len(records))
+	}
+}
+
+func TestFindReverseZoneClassless(t *testing.T) {
+	parent, classless := NewZone("2.0.192.in-addr.arpa"), NewZone("64-26.2.0.192.in-addr.arpa")
+	zones := []*Zone{parent, classless}
+	tests := map[string]*Zone{
+		"65.2.0.192.in-addr.arpa":  classless,
+		"127.2.0.192.in-addr.arpa": classless,
+		"63.2.0.192.in-addr.arpa":  parent,
+		"128.2.0.192.in-addr.arpa": parent,
+		"1.3.0.192.in-addr.arpa":   nil,
+	}
+	for reverseName, expected := range tests {
+		if found := FindReverseZone(zones, reverseName); found != expected {
+			t.Errorf("%v: got %v, expected %v", reverseName, found, expected)
+		}
+	}
+
+	record, _, err := classless.SetPTR("65.2.0.192.in-addr.arpa", "www.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Name != "65" || record.Value != "www.example.com." {
+		t.Errorf("got %v PTR %v, expected 65 PTR www.example.com.", record.Name, record.Value)
+	}
+	if classless.RemovePTR("65.2.0.192.in-addr.arpa", "www.example.com") == nil {
+		t.Error("expected the PTR record to be removed")
+	}
+}
+
+func TestSortZoneLockOrder(t *testing.T) {
+	domains := []string{"2.0.192.in-addr.arpa", "example.org", "8.b.d.0.1.0.0.2.ip6.arpa", "Example.com."}
+	SortZoneLockOrder(domains)
+	expected := []string{"Example.com.", "example.org", "2.0.192.in-addr.arpa", "8.b.d.0.1.0.0.2.ip6.arpa"}
+	if strings.Join(domains, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected %v, got %v", expected, domains)
+	}
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultZonesImage includes named.conf.default-zones as shipped by the image.
+	DefaultZonesImage    = "image"
+	DefaultZonesDisabled = "disabled"
+	DefaultZonesCustom   = "custom"
+
+	// RootHintsImage leaves the root hints to the default zones, or to the ones built in named when they are disabled.
+	RootHintsImage   = "image"
+	RootHintsCustom  = "custom"
+	RootHintsRefresh = "refresh"
+
+	DefaultRootHintsUrl             = "https://www.internic.net/domain/named.root"
+	DefaultRootHintsRefreshInterval = 30 * 24 * time.Hour
+	MinRootHintsRefreshInterval     = 24 * time.Hour
+)
+
+var rootZonePattern = regexp.MustCompile(`zone\s+"\."`)
+
+var (
+	ErrorInvalidDefaultZones = errors.New("default zones are image, disabled or custom, custom ones needing a content")
+	ErrorInvalidRootHints    = errors.New(
+		"root hints are image, custom or refresh, custom ones needing the NS records of the root zone and the " +
+			"addresses of the root servers, refreshed ones an http url refreshed at most daily")
+	ErrorRootHintsConflict = errors.New("root hints are only managed while the default zones leave the root zone out")
+)
+
+func (o *ServerOptions) SetDefaultZones(mode string, content string) error {
+	switch mode {
+	case DefaultZonesImage, DefaultZonesDisabled:
+	case DefaultZonesCustom:
+		if strings.TrimSpace(content) == "" {
+			return ErrorInvalidDefaultZones
+		}
+	default:
+		return ErrorInvalidDefaultZones
+	}
+	if mode != DefaultZonesCustom {
+		content = ""
+	}
+	if o.RootHints != RootHintsImage && declaresRootZone(mode, content) {
+		return ErrorRootHintsConflict
+	}
+	o.DefaultZones = mode
+	o.DefaultZonesContent = content
+	return nil
+}
+
+// SetRootHints replaces the root hints of named. Custom ones are given by content, refreshed ones are downloaded from
+// rootHintsUrl every interval, see RootHintsUpdater. named declaring a single root zone, the default zones have to
+// leave it out for the root hints to be managed.
+func (o *ServerOptions) SetRootHints(mode string, content string, rootHintsUrl string, interval time.Duration) error {
+	switch mode {
+	case RootHintsImage:
+		content = ""
+	case RootHintsCustom:
+		if CheckRootHints(content) != nil {
+			return ErrorInvalidRootHints
+		}
+	case RootHintsRefresh:
+		parsed, err := url.Parse(rootHintsUrl)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			interval < MinRootHintsRefreshInterval {
+			return ErrorInvalidRootHints
+		}
+		// The hints downloaded from the same url are kept until the next refresh.
+		content = ""
+		if o.RootHints == RootHintsRefresh && o.RootHintsUrl == rootHintsUrl {
+			content = o.RootHintsContent
+		}
+	default:
+		return ErrorInvalidRootHints
+	}
+	if mode != RootHintsImage && declaresRootZone(o.DefaultZones, o.DefaultZonesContent) {
+		return ErrorRootHintsConflict
+	}
+	if mode == RootHintsRefresh && (o.RootHints != RootHintsRefresh || o.RootHintsUrl != rootHintsUrl) {
+		o.RootHintsRefreshedAt = time.Time{}
+		o.RootHintsLastError = ""
+	}
+	o.RootHints = mode
+	o.RootHintsContent = content
+	o.RootHintsUrl = rootHintsUrl
+	o.RootHintsRefreshInterval = interval
+	return nil
+}
+
+// ManagedRootHints returns the root hints named is given on top of the default zones, empty when there are none yet.
+func (o *ServerOptions) ManagedRootHints() string {
+	if o.RootHints == RootHintsImage {
+		return ""
+	}
+	return o.RootHintsContent
+}
+
+// IsRootHintsRefreshDue reports whether the root hints are refreshed and the last successful refresh is older than
+// their interval.
+func (o *ServerOptions) IsRootHintsRefreshDue(now time.Time) bool {
+	return o.RootHints == RootHintsRefresh && !now.Before(o.RootHintsRefreshedAt.Add(o.RootHintsRefreshInterval))
+}
+
+// declaresRootZone reports whether the default zones declare the root zone, the ones of the image declaring its
+// hints.
+func declaresRootZone(mode string, content string) bool {
+	switch mode {
+	case DefaultZonesImage:
+		return true
+	case DefaultZonesCustom:
+		return rootZonePattern.MatchString(content)
+	}
+	return false
+}
+
+// CheckRootHints fails unless content is a zone file holding NS records of the root zone along with addresses, like
+// named.root does.
+func CheckRootHints(content string) error {
+	hasNs, hasAddress := false, false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(strings.ToUpper(line))
+		for _, field := range fields {
+			switch field {
+			case "NS":
+				hasNs = hasNs || fields[0] == "."
+			case "A", "AAAA":
+				hasAddress = true
+			}
+		}
+	}
+	if scanner.Err() != nil || !hasNs || !hasAddress {
+		return ErrorInvalidRootHints
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	RpzZoneName = "rpz.local"
+
+	DefaultRpzFeedRefreshInterval = 24 * time.Hour
+	MinRpzFeedRefreshInterval     = 5 * time.Minute
+)
+
+var (
+	blocklistDomainPattern = regexp.MustCompile(`^([a-z0-9_]([a-z0-9_-]*[a-z0-9_])?\.)+[a-z0-9-]+$`)
+	rpzProfileNamePattern  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+)
+
+type RpzProfile struct {
+	Name        string
+	Description string
+	Enabled     bool
+	// Views are the names of the views applying the profile, every view when empty.
+	Views []string
+	// DefaultFeedUrls are subscribed when a built-in profile is enabled without any feed of its own.
+	DefaultFeedUrls []string
+}
+
+// SetViews replaces the names of the views applying the profile, every view applying it when empty. The caller checks
+// they exist.
+func (p *RpzProfile) SetViews(names []string) error {
+	var views []string
+	for _, name := range names {
+		if !IsValidViewName(name) {
+			return ErrorInvalidViewName
+		}
+		if !containsString(views, name) {
+			views = append(views, name)
+		}
+	}
+	p.Views = views
+	return nil
+}
+
+// UsesView reports whether the profile refers to the view named name.
+func (p *RpzProfile) UsesView(name string) bool {
+	return containsString(p.Views, name)
+}
+
+// AppliedInView reports whether the view named name applies the profile.
+func (p *RpzProfile) AppliedInView(name string) bool {
+	return len(p.Views) == 0 || containsString(p.Views, name)
+}
+
+var BuiltinRpzProfiles = []*RpzProfile{
+	{
+		Name:            "ads",
+		Description:     "Advertising and tracking domains",
+		DefaultFeedUrls: []string{"https://raw.githubusercontent.com/StevenBlack/hosts/master/hosts"},
+	},
+	{
+		Name:            "malware",
+		Description:     "Domains distributing malware",
+		DefaultFeedUrls: []string{"https://urlhaus.abuse.ch/downloads/hostfile/"},
+	},
+	{
+		Name:            "adult",
+		Description:     "Adult content",
+		DefaultFeedUrls: []string{"https://raw.githubusercontent.com/StevenBlack/hosts/master/alternates/porn-only/hosts"},
+	},
+}
+
+// MergeRpzProfiles lists the built-in profiles along with every profile referenced by a feed, applying the
+// stored enabled flags and views. Profiles are enabled in every view unless stored otherwise.
+func MergeRpzProfiles(stored []*RpzProfile, feeds []*RpzFeed) []*RpzProfile {
+	storedByName := map[string]*RpzProfile{}
+	for _, profile := range stored {
+		storedByName[profile.Name] = profile
+	}
+
+	var profiles []*RpzProfile
+	seen := map[string]bool{}
+	add := func(profile RpzProfile) {
+		if seen[profile.Name] {
+			return
+		}
+		seen[profile.Name] = true
+		profile.Enabled = true
+		if storedProfile, ok := storedByName[profile.Name]; ok {
+			profile.Enabled = storedProfile.Enabled
+			profile.Views = storedProfile.Views
+		}
+		profiles = append(profiles, &profile)
+	}
+	for _, profile := range BuiltinRpzProfiles {
+		add(*profile)
+	}
+	for _, feed := range feeds {
+		if feed.Profile != "" {
+			add(RpzProfile{Name: feed.Profile})
+		}
+	}
+	return profiles
+}
+
+func FindRpzProfile(profiles []*RpzProfile, name string) *RpzProfile {
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile
+		}
+	}
+	return nil
+}
+
+func IsValidRpzProfileName(name string) bool {
+	return name == "" || rpzProfileNamePattern.MatchString(name)
+}
+
+// RpzZoneNameOfProfile returns the response policy zone serving a profile, feeds without profile share the
+// top level zone.
+func RpzZoneNameOfProfile(profile string) string {
+	if profile == "" {
+		return RpzZoneName
+	}
+	return profile + "." + RpzZoneName
+}
+
+// RpzZonesOfView returns the response policy zones out of rpzZones the view named name applies, a zone whose profile
+// is not stored being applied in every view.
+func RpzZonesOfView(profiles []*RpzProfile, rpzZones []string, name string) []string {
+	var zones []string
+	for _, rpzZone := range rpzZones {
+		profileName := strings.TrimSuffix(strings.TrimSuffix(rpzZone, RpzZoneName), ".")
+		if profile := FindRpzProfile(profiles, profileName); profile == nil || profile.AppliedInView(name) {
+			zones = append(zones, rpzZone)
+		}
+	}
+	return zones
+}
+
+// IsRpzZoneName reports whether name is reserved for response policy zones.
+func IsRpzZoneName(name string) bool {
+	name = NormalizeDomain(name)
+	return name == RpzZoneName || strings.HasSuffix(name, "."+RpzZoneName)
+}
+
+type RpzFeed struct {
+	Id              string
+	Name            string
+	Profile         string
+	Url             string
+	RefreshInterval time.Duration
+	Enabled         bool
+	// LastRefreshed is the time the feed was last downloaded, LastAttempted the time of the last download attempt, which
+	// the feed is due after, failed or not.
+	LastRefreshed time.Time
+	LastAttempted time.Time
+	LastError     string
+	DomainCount   int
+}
+
+func NewRpzFeed(name, feedUrl string, refreshInterval time.Duration) *RpzFeed {
+	return &RpzFeed{Name: name, Url: feedUrl, RefreshInterval: refreshInterval, Enabled: true}
+}
+
+func (f *RpzFeed) IsDue(now time.Time) bool {
+	return f.Enabled && !now.Before(f.LastAttempted.Add(f.RefreshInterval))
+}
+
+func (f *RpzFeed) IsValid() bool {
+	parsedUrl, err := url.Parse(f.Url)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+		return false
+	}
+	return f.Name != "" && f.RefreshInterval >= MinRpzFeedRefreshInterval && IsValidRpzProfileName(f.Profile)
+}
+
+// ParseBlocklist extracts the blocked domains out of a feed, accepting hosts files ("0.0.0.0 ads.example.com"),
+// adblock style rules ("||ads.example.com^") and plain domain lists. Comments, IP addresses and anything that does
+// not look like a domain name are skipped.
+func ParseBlocklist(reader io.Reader) ([]string, error) {
+	seen := map[string]bool{}
+	var domains []string
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		candidate := line
+		if strings.HasPrefix(line, "||") {
+			candidate = strings.TrimSuffix(strings.TrimPrefix(line, "||"), "^")
+		} else if fields := strings.Fields(line); len(fields) > 1 && net.ParseIP(fields[0]) != nil {
+			candidate = fields[1]
+		} else if len(fields) > 1 {
+			continue
+		}
+
+		candidate = NormalizeDomain(candidate)
+		if net.ParseIP(candidate) != nil || !blocklistDomainPattern.MatchString(candidate) || seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		domains = append(domains, candidate)
+	}
+	return domains, scanner.Err()
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRpzZonesOfView(t *testing.T) {
+	stored := []*RpzProfile{
+		{Name: "ads", Enabled: true},
+		{Name: "adult", Enabled: true, Views: []string{"guest"}},
+		{Name: "malware", Enabled: true, Views: []string{"guest", "internal"}},
+	}
+	profiles := MergeRpzProfiles(stored, []*RpzFeed{{Profile: "custom"}})
+	rpzZones := []string{"ads.rpz.local", "adult.rpz.local", "custom.rpz.local", "malware.rpz.local", "rpz.local"}
+
+	tests := []struct {
+		view  string
+		zones []string
+	}{
+		{view: "guest", zones: rpzZones},
+		{view: "internal", zones: []string{"ads.rpz.local", "custom.rpz.local", "malware.rpz.local", "rpz.local"}},
+		{view: "external", zones: []string{"ads.rpz.local", "custom.rpz.local", "rpz.local"}},
+	}
+	for _, test := range tests {
+		zones := RpzZonesOfView(profiles, rpzZones, test.view)
+		if fmt.Sprint(zones) != fmt.Sprint(test.zones) {
+			t.Errorf("RpzZonesOfView(%v) = %v, want %v", test.view, zones, test.zones)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+const DefaultSerialCheckAlertAfter = 5 * time.Minute
+
+// SerialCheckSettings lists the DNS servers serving the managed zones, e.g. the anycast nodes or the managers sharing
+// the database, whose SOA serials are compared for every zone.
+type SerialCheckSettings struct {
+	Nodes []*SerialCheckNode `json:"nodes"`
+	// AlertAfterSeconds is how long a node may serve another serial than the newest one before an alert is raised,
+	// DefaultSerialCheckAlertAfter when 0.
+	AlertAfterSeconds int `json:"alert_after_seconds"`
+}
+
+type SerialCheckNode struct {
+	Name string `json:"name"`
+	// Address is the host named listens on, optionally followed by a port, e.g. 192.0.2.53 or 192.0.2.53:5353.
+	Address string `json:"address"`
+}
+
+func (s *SerialCheckSettings) IsValid() bool {
+	if len(s.Nodes) == 0 || s.AlertAfterSeconds < 0 {
+		return false
+	}
+	names := map[string]bool{}
+	for _, node := range s.Nodes {
+		if node == nil || node.Name == "" || names[node.Name] || node.Address == "" {
+			return false
+		}
+		names[node.Name] = true
+		if host, port, err := net.SplitHostPort(node.Address); err == nil {
+			number, err := strconv.Atoi(port)
+			if host == "" || err != nil || number <= 0 || number > 65535 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *SerialCheckSettings) AlertAfter() time.Duration {
+	if s.AlertAfterSeconds == 0 {
+		return DefaultSerialCheckAlertAfter
+	}
+	return time.Duration(s.AlertAfterSeconds) * time.Second
+}
+
+// NodeSerial is the SOA serial a node serves for a zone.
+type NodeSerial struct {
+	Node string
+	// Serial is empty when the node did not answer with the SOA record of the zone, Error telling why.
+	Serial string
+	Error  string
+	InSync bool
+	// DivergedSince is when the node stopped serving the newest serial, zero while it is in sync.
+	DivergedSince time.Time
+	// Alerting is set once the node diverged for longer than the alert delay.
+	Alerting bool
+}
+
+// ZoneSerials compares the serials the nodes serve for a zone.
+type ZoneSerials struct {
+	Zone string
+	// Serial is the newest serial served by a node, empty when none answered.
+	Serial string
+	Nodes  []*NodeSerial
+}
+
+// ResolveSerials sets the newest serial of the zone, in serial number arithmetic, and marks the nodes serving it in
+// sync. The nodes which did not answer are not in sync.
+func (z *ZoneSerials) ResolveSerials() {
+	var newest uint32
+	z.Serial = ""
+	for _, node := range z.Nodes {
+		serial, err := strconv.ParseUint(node.Serial, 10, 32)
+		if err != nil {
+			continue
+		}
+		if z.Serial == "" || isSerialAhead(uint32(serial), newest) {
+			newest = uint32(serial)
+			z.Serial = node.Serial
+		}
+	}
+	for _, node := range z.Nodes {
+		node.InSync = z.Serial != "" && node.Serial == z.Serial
+	}
+}
+
+// SerialConsistency is the outcome of the last comparison of the serials served by the nodes, the matrix of the
+// zones by node.
+type SerialConsistency struct {
+	CheckedAt  time.Time
+	AlertAfter time.Duration
+	Nodes      []string
+	Zones      []*ZoneSerials
+}
+
+// AlertingZones returns the zones a node diverged from for longer than the alert delay.
+func (c *SerialConsistency) AlertingZones() []*ZoneSerials {
+	var zones []*ZoneSerials
+	for _, zone := range c.Zones {
+		for _, node := range zone.Nodes {
+			if node.Alerting {
+				zones = append(zones, zone)
+				break
+			}
+		}
+	}
+	return zones
+}
+
+// AddSerialCheck adds the consistency of the serials to the checks of the status page, the number of zones only
+// being shown. consistency is nil while the serials are not compared.
+func (s *ServiceStatus) AddSerialCheck(consistency *SerialConsistency) {
+	if consistency == nil {
+		return
+	}
+	alerting := len(consistency.AlertingZones())
+	check := &ReportCheck{Name: "Serial consistency", Passed: alerting == 0}
+	switch {
+	case consistency.CheckedAt.IsZero():
+		check.Detail = "not checked yet"
+	case alerting > 0:
+		check.Detail = fmt.Sprintf("%v zones diverged across the nodes", alerting)
+	default:
+		check.Detail = fmt.Sprintf("%v zones in sync on %v nodes", len(consistency.Zones), len(consistency.Nodes))
+	}
+	s.Checks = append(s.Checks, check)
+}
+
+type SerialChecker interface {
+	Start(ctx context.Context)
+	Shutdown(ctx context.Context) error
+
+	// Consistency returns the outcome of the last check, nil while no node is configured.
+	Consistency() *SerialConsistency
+}
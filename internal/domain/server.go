@@ -0,0 +1,326 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultNegativeTrustAnchorLifetime = time.Hour
+	MaxNegativeTrustAnchorLifetime     = 7 * 24 * time.Hour
+
+	RecursionModeAuthoritative = "authoritative"
+	RecursionModeRecursive     = "recursive"
+)
+
+var ErrorOpenResolver = errors.New("allow-recursion would make this server an open resolver, set allow_open_resolver to override")
+
+type ServerOptions struct {
+	ValidateExcept []string
+
+	RecursionMode     string
+	AllowRecursion    []string
+	AllowOpenResolver bool
+
+	Forwarders []string
+
+	QueryLog          bool
+	ClientSubnetStats bool
+	// QueryLogSince is when the query log was last enabled, the records answering no query since being unused.
+	QueryLogSince time.Time
+
+	BlackholePresets  []string
+	BlackholeNetworks []string
+
+	// RpzAllowlist holds the domains exempted from every response policy feed.
+	RpzAllowlist []string
+
+	// Views are the BIND views, in the order named matches the clients against them, served while the views feature
+	// is enabled.
+	Views []*View
+
+	// DefaultZones tells how named.conf.default-zones is included, see DefaultZonesImage.
+	DefaultZones        string
+	DefaultZonesContent string
+
+	// RootHints tells where the root hints come from, see RootHintsImage. RootHintsContent holds the custom hints,
+	// or the last ones downloaded from RootHintsUrl.
+	RootHints                string
+	RootHintsContent         string
+	RootHintsUrl             string
+	RootHintsRefreshInterval time.Duration
+	RootHintsRefreshedAt     time.Time
+	RootHintsLastError       string
+}
+
+type BlackholePreset struct {
+	Name        string
+	Description string
+	Networks    []string
+}
+
+var BlackholePresets = []*BlackholePreset{
+	{
+		Name:        "bogons-v4",
+		Description: "IPv4 ranges that must never source queries on the public internet (RFC 6890)",
+		Networks: []string{
+			"0.0.0.0/8", "100.64.0.0/10", "169.254.0.0/16", "192.0.0.0/24", "192.0.2.0/24", "198.18.0.0/15",
+			"198.51.100.0/24", "203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+		},
+	},
+	{
+		Name:        "bogons-v6",
+		Description: "IPv6 documentation, discard, deprecated and multicast ranges",
+		Networks: []string{
+			"100::/64", "2001:db8::/32", "3ffe::/16", "fec0::/10", "ff00::/8",
+		},
+	},
+	{
+		Name:        "private",
+		Description: "RFC 1918 and unique local ranges, for servers that only face the public internet",
+		Networks: []string{
+			"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7",
+		},
+	},
+}
+
+func FindBlackholePreset(name string) *BlackholePreset {
+	for _, preset := range BlackholePresets {
+		if preset.Name == name {
+			return preset
+		}
+	}
+	return nil
+}
+
+// NewDefaultServerOptions returns the options matching BIND's own defaults, so an unconfigured server behaves
+// exactly like the stock image did.
+func NewDefaultServerOptions() *ServerOptions {
+	return &ServerOptions{
+		RecursionMode:            RecursionModeRecursive,
+		AllowRecursion:           []string{"localhost", "localnets"},
+		DefaultZones:             DefaultZonesImage,
+		RootHints:                RootHintsImage,
+		RootHintsUrl:             DefaultRootHintsUrl,
+		RootHintsRefreshInterval: DefaultRootHintsRefreshInterval,
+	}
+}
+
+func (o *ServerOptions) SetRecursion(mode string, allowRecursion []string, allowOpenResolver bool) error {
+	if mode != RecursionModeAuthoritative && mode != RecursionModeRecursive {
+		return fmt.Errorf("recursion mode must be %v or %v", RecursionModeAuthoritative, RecursionModeRecursive)
+	}
+	if mode == RecursionModeRecursive {
+		if len(allowRecursion) == 0 {
+			return errors.New("allow_recursion must not be empty in recursive mode")
+		}
+		for _, element := range allowRecursion {
+			if !IsValidAddressMatchElement(element) {
+				return fmt.Errorf("%v is not a valid address match element", element)
+			}
+		}
+		if IsOpenAddressMatchList(allowRecursion) && !allowOpenResolver {
+			return ErrorOpenResolver
+		}
+	}
+	o.RecursionMode = mode
+	o.AllowRecursion = allowRecursion
+	o.AllowOpenResolver = allowOpenResolver
+	return nil
+}
+
+func (o *ServerOptions) IsRecursive() bool {
+	return o.RecursionMode == RecursionModeRecursive
+}
+
+func (o *ServerOptions) SetForwarders(forwarders []string) error {
+	for _, forwarder := range forwarders {
+		if net.ParseIP(forwarder) == nil {
+			return fmt.Errorf("forwarder %v is not a valid IP address", forwarder)
+		}
+	}
+	o.Forwarders = forwarders
+	return nil
+}
+
+func (o *ServerOptions) SetBlackhole(presets []string, networks []string) error {
+	for _, preset := range presets {
+		if FindBlackholePreset(preset) == nil {
+			return fmt.Errorf("blackhole preset %v is not found", preset)
+		}
+	}
+	for _, network := range networks {
+		if !IsValidAddressMatchElement(network) {
+			return fmt.Errorf("%v is not a valid address match element", network)
+		}
+	}
+	if IsOpenAddressMatchList(networks) {
+		return errors.New("blackhole networks would blackhole every client")
+	}
+	o.BlackholePresets = presets
+	o.BlackholeNetworks = networks
+	return nil
+}
+
+// Blackhole returns every network to ignore, presets expanded first followed by the custom networks.
+func (o *ServerOptions) Blackhole() []string {
+	var networks []string
+	for _, name := range o.BlackholePresets {
+		if preset := FindBlackholePreset(name); preset != nil {
+			networks = append(networks, preset.Networks...)
+		}
+	}
+	return append(networks, o.BlackholeNetworks...)
+}
+
+func (o *ServerOptions) SetRpzAllowlist(domains []string) error {
+	var allowlist []string
+	for _, domainName := range domains {
+		domainName = NormalizeDomain(domainName)
+		if !blocklistDomainPattern.MatchString(domainName) {
+			return fmt.Errorf("%v is not a valid domain", domainName)
+		}
+		allowlist = append(allowlist, domainName)
+	}
+	o.RpzAllowlist = allowlist
+	return nil
+}
+
+func (o *ServerOptions) AddValidationException(domainName string) error {
+	domainName = NormalizeDomain(domainName)
+	if domainName == "" {
+		return errors.New("domain is not valid")
+	}
+	for _, d := range o.ValidateExcept {
+		if d == domainName {
+			return errors.New("validation exception already exists")
+		}
+	}
+	o.ValidateExcept = append(o.ValidateExcept, domainName)
+	return nil
+}
+
+func (o *ServerOptions) DeleteValidationException(domainName string) error {
+	domainName = NormalizeDomain(domainName)
+	for i, d := range o.ValidateExcept {
+		if d == domainName {
+			o.ValidateExcept = append(o.ValidateExcept[:i], o.ValidateExcept[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("validation exception is not found")
+}
+
+type ForwarderStatus struct {
+	Address             string
+	Healthy             bool
+	Latency             time.Duration
+	ConsecutiveFailures int
+	LastChecked         time.Time
+	LastError           string
+}
+
+type NegativeTrustAnchor struct {
+	Id        string
+	Domain    string
+	ExpiresAt time.Time
+}
+
+func NewNegativeTrustAnchor(domainName string, lifetime time.Duration) *NegativeTrustAnchor {
+	return &NegativeTrustAnchor{
+		Domain:    NormalizeDomain(domainName),
+		ExpiresAt: time.Now().Add(lifetime).Truncate(time.Second),
+	}
+}
+
+func (n *NegativeTrustAnchor) IsExpired() bool {
+	return !time.Now().Before(n.ExpiresAt)
+}
+
+// Lifetime returns the remaining lifetime of the anchor, rounded down to whole seconds as expected by rndc.
+func (n *NegativeTrustAnchor) Lifetime() time.Duration {
+	return time.Until(n.ExpiresAt).Truncate(time.Second)
+}
+
+func (n *NegativeTrustAnchor) IsValid() bool {
+	return n.Domain != "" && !n.ExpiresAt.IsZero()
+}
+
+// IsValidAddressMatchElement reports whether element can be used in a BIND address match list: a built-in ACL name,
+// an IP address or a CIDR block, optionally negated with "!".
+func IsValidAddressMatchElement(element string) bool {
+	element = strings.TrimPrefix(strings.TrimSpace(element), "!")
+	switch element {
+	case "any", "none", "localhost", "localnets":
+		return true
+	}
+	if net.ParseIP(element) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(element)
+	return err == nil
+}
+
+// IsOpenAddressMatchList reports whether the elements together match every client on the internet, the ranges they
+// allow covering the whole IPv4 or IPv6 address space. The negated elements are left out, bind matching the first
+// element a client falls in.
+func IsOpenAddressMatchList(elements []string) bool {
+	var ranges []*net.IPNet
+	for _, element := range elements {
+		element = strings.TrimSpace(element)
+		if element == "any" {
+			return true
+		}
+		if ip := net.ParseIP(element); ip != nil {
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, net.IPv4len*8
+			}
+			ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(element)
+		if err == nil {
+			ranges = append(ranges, ipNet)
+		}
+	}
+	ipv4Space := &net.IPNet{IP: make(net.IP, net.IPv4len), Mask: net.CIDRMask(0, net.IPv4len*8)}
+	ipv6Space := &net.IPNet{IP: make(net.IP, net.IPv6len), Mask: net.CIDRMask(0, net.IPv6len*8)}
+	return coversAddressRange(ranges, ipv4Space) || coversAddressRange(ranges, ipv6Space)
+}
+
+// coversAddressRange reports whether the union of ranges covers the whole of target, target being split in halves
+// as long as ranges narrower than it are found in it.
+func coversAddressRange(ranges []*net.IPNet, target *net.IPNet) bool {
+	ones, bits := target.Mask.Size()
+	narrower := false
+	for _, ipRange := range ranges {
+		rangeOnes, rangeBits := ipRange.Mask.Size()
+		if rangeBits != bits {
+			continue
+		}
+		if rangeOnes <= ones && ipRange.Contains(target.IP) {
+			return true
+		}
+		if rangeOnes > ones && target.Contains(ipRange.IP) {
+			narrower = true
+		}
+	}
+	if !narrower {
+		return false
+	}
+	mask := net.CIDRMask(ones+1, bits)
+	upper := make(net.IP, len(target.IP))
+	copy(upper, target.IP)
+	upper[ones/8] |= 0x80 >> (ones % 8)
+	return coversAddressRange(ranges, &net.IPNet{IP: target.IP, Mask: mask}) &&
+		coversAddressRange(ranges, &net.IPNet{IP: upper, Mask: mask})
+}
+
+// NormalizeDomain lower-cases a domain name and strips its trailing dot.
+func NormalizeDomain(domainName string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domainName)), ".")
+}
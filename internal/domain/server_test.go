@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestIsOpenAddressMatchList(t *testing.T) {
+	tests := []struct {
+		elements []string
+		open     bool
+	}{
+		{elements: []string{"localhost", "localnets"}, open: false},
+		{elements: []string{"any"}, open: true},
+		{elements: []string{"0.0.0.0/0"}, open: true},
+		{elements: []string{"::/0"}, open: true},
+		{elements: []string{"0.0.0.0/1", "128.0.0.0/1"}, open: true},
+		{elements: []string{"0.0.0.0/2", "64.0.0.0/2", "128.0.0.0/1"}, open: true},
+		{elements: []string{"::/1", "8000::/1"}, open: true},
+		{elements: []string{"0.0.0.0/1", "128.0.0.0/2"}, open: false},
+		{elements: []string{"0.0.0.0/1", "8000::/1"}, open: false},
+		{elements: []string{"10.0.0.0/8", "192.168.0.0/16", "2001:db8::/32", "192.0.2.1"}, open: false},
+		{elements: []string{"!0.0.0.0/1", "128.0.0.0/1"}, open: false},
+	}
+	for _, test := range tests {
+		if open := IsOpenAddressMatchList(test.elements); open != test.open {
+			t.Errorf("IsOpenAddressMatchList(%v) = %v, want %v", test.elements, open, test.open)
+		}
+	}
+}
+
+func TestSetRecursionRefusesTheSplitOpenRanges(t *testing.T) {
+	options := NewDefaultServerOptions()
+	err := options.SetRecursion(RecursionModeRecursive, []string{"0.0.0.0/1", "128.0.0.0/1"}, false)
+	if err != ErrorOpenResolver {
+		t.Fatalf("expected the split ranges to be refused, got %v", err)
+	}
+	err = options.SetRecursion(RecursionModeRecursive, []string{"0.0.0.0/1", "128.0.0.0/1"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
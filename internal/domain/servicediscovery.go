@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The labels of the records scraped by Prometheus, following the annotations of the Kubernetes pods.
+const (
+	// ScrapeLabel set to true makes the name of the record a scrape target.
+	ScrapeLabel = "prometheus.io/scrape"
+	// ScrapePortLabel is the port of the target, the one of the scheme by default.
+	ScrapePortLabel = "prometheus.io/port"
+	// ScrapePathLabel is the metrics path of the target, /metrics by default.
+	ScrapePathLabel = "prometheus.io/path"
+	// ScrapeSchemeLabel is either http, the default, or https.
+	ScrapeSchemeLabel = "prometheus.io/scheme"
+)
+
+// scrapeRecordTypes are the types of the records whose name resolves to the target.
+var scrapeRecordTypes = []string{"A", "AAAA", "CNAME", "ALIAS"}
+
+// ScrapeTarget is a host Prometheus scrapes, along with the labels of its target group.
+type ScrapeTarget struct {
+	// Target is the fully qualified name of the record without trailing dot, followed by the port when it is set.
+	Target string
+	// Labels are the meta labels of the target, __meta_dns_zone, __meta_dns_name and __meta_dns_label_<key> for the
+	// labels of the record, along with __metrics_path__ and __scheme__ when they are set.
+	Labels map[string]string
+}
+
+// ScrapeTargets returns the targets of the records of the zone labelled prometheus.io/scrape=true, by target, the
+// records being selected by every selector as well. The disabled records, the wildcards and the records whose port
+// or scheme is not valid are left out. A name owning several records is a single target, the labels of its first
+// record being kept.
+func (z *Zone) ScrapeTargets(selectors []*LabelSelector) []*ScrapeTarget {
+	targets := map[string]*ScrapeTarget{}
+	var names []string
+	for _, record := range z.Records {
+		if record.Disabled || !containsString(scrapeRecordTypes, strings.ToUpper(record.Type)) ||
+			record.Labels[ScrapeLabel] != "true" || !record.MatchesLabels(selectors) {
+			continue
+		}
+		name := z.QualifiedRecordName(z.NormalizeRecordName(record.Name))
+		if strings.HasPrefix(name, "*") {
+			continue
+		}
+		target := name
+		if port, ok := record.Labels[ScrapePortLabel]; ok {
+			number, err := strconv.Atoi(port)
+			if err != nil || number <= 0 || number > 65535 {
+				continue
+			}
+			target += ":" + port
+		}
+		scheme, ok := record.Labels[ScrapeSchemeLabel]
+		if ok && scheme != "http" && scheme != "https" {
+			continue
+		}
+		if _, ok := targets[target]; ok {
+			continue
+		}
+
+		labels := map[string]string{
+			"__meta_dns_zone": NormalizeDomain(z.Domain),
+			"__meta_dns_name": name,
+		}
+		for key, value := range record.Labels {
+			labels["__meta_dns_label_"+identifierName(key)] = value
+		}
+		if path, ok := record.Labels[ScrapePathLabel]; ok {
+			labels["__metrics_path__"] = path
+		}
+		if scheme != "" {
+			labels["__scheme__"] = scheme
+		}
+		targets[target] = &ScrapeTarget{Target: target, Labels: labels}
+		names = append(names, target)
+	}
+	sort.Strings(names)
+
+	var scrapeTargets []*ScrapeTarget
+	for _, target := range names {
+		scrapeTargets = append(scrapeTargets, targets[target])
+	}
+	return scrapeTargets
+}
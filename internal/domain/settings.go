@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var LogLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+const (
+	FeatureDnssec       = "dnssec"
+	FeatureViews        = "views"
+	FeaturePureGoServer = "pure_go_server"
+)
+
+// Feature is an experimental subsystem, disabled unless its flag is set in the settings.
+type Feature struct {
+	Name        string
+	Description string
+	// Available is false while the subsystem is not part of this build, enabling it has no effect.
+	Available bool
+}
+
+var Features = []*Feature{
+	{Name: FeatureDnssec, Description: "DNSSEC signing of the managed zones", Available: true},
+	{Name: FeatureViews, Description: "BIND views serving different answers per client network", Available: true},
+	{Name: FeaturePureGoServer, Description: "Built-in Go DNS server replacing Bind9"},
+}
+
+// Settings is the manager's own configuration, read from the settings file and reloadable at runtime without
+// restarting the service or the DNS server.
+type Settings struct {
+	// LogLevel is the minimum level logged by the API server, requests are logged at the debug level.
+	LogLevel  string            `json:"log_level"`
+	RateLimit RateLimitSettings `json:"rate_limit"`
+	// Webhooks are delivered along with the webhooks registered through the API.
+	Webhooks []*WebhookTarget `json:"webhooks"`
+	// RemoveUnknownZoneFiles removes the zone files no zone refers to when the service starts, they are only
+	// reported otherwise.
+	RemoveUnknownZoneFiles bool `json:"remove_unknown_zone_files"`
+	// Features holds the feature flags by feature name.
+	Features map[string]bool `json:"features"`
+	// Registrars are the registrar accounts zones can publish their delegation through.
+	Registrars []*RegistrarAccount `json:"registrars"`
+	// Failover is nil when this manager is not paired with another one.
+	Failover   *FailoverSettings  `json:"failover"`
+	StatusPage StatusPageSettings `json:"status_page"`
+	// SerialCheck is nil when the serials served by the nodes are not compared.
+	SerialCheck *SerialCheckSettings `json:"serial_check"`
+	// DefaultSOAPreset sets the SOA timers of the zones created without a preset, the built-in timers being kept when
+	// empty.
+	DefaultSOAPreset string `json:"default_soa_preset"`
+	// ChangeBurst is nil when the bursts of changes of the zones are not flagged.
+	ChangeBurst *ChangeBurstSettings `json:"change_burst"`
+	// WarnSimilarZones warns when a zone is created with a near typo of the domain of a managed zone.
+	WarnSimilarZones bool `json:"warn_similar_zones"`
+	// ZoneLimits warns when a change grows a zone close to its limits, the zones being sized by ZoneSize.
+	ZoneLimits ZoneLimitSettings `json:"zone_limits"`
+}
+
+// RateLimitSettings limits the API requests of every client address, a zero RequestsPerSecond disables the limit.
+type RateLimitSettings struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+}
+
+type WebhookTarget struct {
+	Url             string   `json:"url"`
+	Events          []string `json:"events"`
+	Zones           []string `json:"zones"`
+	RecordTypes     []string `json:"record_types"`
+	PayloadTemplate string   `json:"payload_template"`
+	ContentType     string   `json:"content_type"`
+}
+
+func DefaultSettings() *Settings {
+	return &Settings{LogLevel: LogLevelInfo}
+}
+
+// Webhook converts the target, webhookId is expected to stay the same across reloads so pending deliveries are
+// still delivered.
+func (t *WebhookTarget) Webhook(webhookId string) *Webhook {
+	webhook := NewWebhook(t.Url)
+	webhook.Id = webhookId
+	webhook.Events = t.Events
+	webhook.PayloadTemplate = t.PayloadTemplate
+	if t.ContentType != "" {
+		webhook.ContentType = t.ContentType
+	}
+	for _, zone := range t.Zones {
+		webhook.Zones = append(webhook.Zones, NormalizeDomain(zone))
+	}
+	for _, recordType := range t.RecordTypes {
+		webhook.RecordTypes = append(webhook.RecordTypes, strings.ToUpper(recordType))
+	}
+	return webhook
+}
+
+func (s *Settings) IsValid() bool {
+	if !containsString(LogLevels, s.LogLevel) {
+		return false
+	}
+	if s.RateLimit.RequestsPerSecond < 0 || s.RateLimit.Burst < 0 {
+		return false
+	}
+	if s.RateLimit.RequestsPerSecond > 0 && s.RateLimit.Burst == 0 {
+		return false
+	}
+	for name := range s.Features {
+		if FindFeature(name) == nil {
+			return false
+		}
+	}
+	for _, target := range s.Webhooks {
+		if target == nil || !target.Webhook("").IsValid() {
+			return false
+		}
+	}
+	names := map[string]bool{}
+	for _, account := range s.Registrars {
+		if account == nil || !account.IsValid() || names[account.Name] {
+			return false
+		}
+		names[account.Name] = true
+	}
+	if s.Failover != nil && !s.Failover.IsValid() {
+		return false
+	}
+	if s.SerialCheck != nil && !s.SerialCheck.IsValid() {
+		return false
+	}
+	if s.DefaultSOAPreset != "" && FindSOAPreset(s.DefaultSOAPreset) == nil {
+		return false
+	}
+	if s.ChangeBurst != nil && !s.ChangeBurst.IsValid() {
+		return false
+	}
+	if !s.ZoneLimits.IsValid() {
+		return false
+	}
+	return true
+}
+
+func (s *Settings) FindRegistrar(name string) *RegistrarAccount {
+	for _, account := range s.Registrars {
+		if account.Name == name {
+			return account
+		}
+	}
+	return nil
+}
+
+func (s *Settings) IsFeatureEnabled(name string) bool {
+	return s.Features[name]
+}
+
+func FindFeature(name string) *Feature {
+	for _, feature := range Features {
+		if feature.Name == name {
+			return feature
+		}
+	}
+	return nil
+}
+
+type SettingsListener interface {
+	OnSettingsChanged(settings *Settings)
+}
+
+type SettingsProvider interface {
+	Settings() *Settings
+	// Reload reads the settings again and notifies the listeners, the current settings are kept when the new ones
+	// are invalid.
+	Reload(ctx context.Context) (*Settings, error)
+	Subscribe(listener SettingsListener)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "errors"
+
+var ErrorUnknownSOAPreset = errors.New("soa preset is not found")
+
+// SOAPreset sets the timers of the SOA record together, following the usual recommendations rather than picking each
+// timer on its own.
+type SOAPreset struct {
+	Name string
+	// Description explains what the timers trade, shown to the operators picking a preset.
+	Description string
+	Refresh     int
+	Retry       int
+	Expire      int
+	// CacheTTL is the negative caching TTL of RFC 2308.
+	CacheTTL int
+}
+
+var SOAPresets = []*SOAPreset{
+	{
+		Name: "fast-failover",
+		Description: "Secondaries check for changes every 5 minutes and missing names are cached for a minute, so " +
+			"records moved during a failover are picked up quickly, at the cost of more SOA queries. Secondaries " +
+			"stop answering after a week without the primary.",
+		Refresh:  300,
+		Retry:    60,
+		Expire:   604800,
+		CacheTTL: 60,
+	},
+	{
+		Name: "standard",
+		Description: "The RFC 1912 timers, secondaries checking for changes every 2 hours and serving the zone for " +
+			"2 weeks without the primary, missing names being cached for an hour (RFC 2308).",
+		Refresh:  7200,
+		Retry:    3600,
+		Expire:   1209600,
+		CacheTTL: 3600,
+	},
+	{
+		Name: "conservative",
+		Description: "The RIPE-203 timers for stable zones relying on NOTIFY, secondaries checking for changes " +
+			"daily and serving the zone for 6 weeks without the primary, missing names being cached for 3 hours.",
+		Refresh:  86400,
+		Retry:    7200,
+		Expire:   3600000,
+		CacheTTL: 10800,
+	},
+}
+
+func FindSOAPreset(name string) *SOAPreset {
+	for _, preset := range SOAPresets {
+		if preset.Name == name {
+			return preset
+		}
+	}
+	return nil
+}
+
+// ApplyPreset sets the timers of the SOA record to the ones of the preset.
+func (s *SOARecord) ApplyPreset(preset *SOAPreset) {
+	s.Refresh = preset.Refresh
+	s.Retry = preset.Retry
+	s.Expire = preset.Expire
+	s.CacheTTL = preset.CacheTTL
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"time"
+)
+
+const defaultStatusPageTitle = "DNS service status"
+
+// namedCrashWindow is how long the status page reports the last crash of named once it runs again.
+const namedCrashWindow = time.Hour
+
+// StatusPageSettings enables the status page, an unauthenticated summary of the health of the service meant for
+// wallboards. It never shows zone data.
+type StatusPageSettings struct {
+	Enabled bool `json:"enabled"`
+	// Title defaults to defaultStatusPageTitle.
+	Title string `json:"title"`
+}
+
+// ReloadResult is the outcome of the last time named was given new configs.
+type ReloadResult struct {
+	Time time.Time
+	// Error is empty when the reload succeeded.
+	Error string
+	// LeftOut lists the zones left out of the reload, e.g. "example.com: <reason>", their zone file failing to load.
+	// named serves the zone file they were last written to, if any.
+	LeftOut []string
+}
+
+// LeftOutError returns why the zone of domainName was left out of the reload, nil when it was not.
+func (r *ReloadResult) LeftOutError(domainName string) error {
+	if r == nil {
+		return nil
+	}
+	for _, leftOut := range r.LeftOut {
+		if strings.HasPrefix(leftOut, domainName+": ") {
+			return errors.New("zone is left out of the reload, " + leftOut)
+		}
+	}
+	return nil
+}
+
+// NamedCrash is the last time named exited while the service was not shutting it down.
+type NamedCrash struct {
+	Time time.Time
+	// Error is how named exited, e.g. its exit status.
+	Error string
+	// Crashes counts the crashes since the service started, named being restarted after each of them.
+	Crashes int
+	// Running is set once named was started again.
+	Running bool
+}
+
+// ServiceStatus is the health of the service shown on the status page.
+type ServiceStatus struct {
+	Title       string
+	GeneratedAt time.Time
+	Checks      []*ReportCheck
+}
+
+// NewServiceStatus summarizes the health of the service, namedErr being the outcome of asking named for its status
+// and lastReload nil until named is reloaded once.
+func NewServiceStatus(settings StatusPageSettings, namedErr error, lastReload *ReloadResult) *ServiceStatus {
+	status := &ServiceStatus{Title: settings.Title, GeneratedAt: time.Now()}
+	if status.Title == "" {
+		status.Title = defaultStatusPageTitle
+	}
+
+	status.Checks = append(status.Checks, &ReportCheck{Name: "API", Passed: true})
+
+	named := &ReportCheck{Name: "Named", Passed: namedErr == nil}
+	if namedErr != nil {
+		named.Detail = "not responding"
+	}
+	status.Checks = append(status.Checks, named)
+
+	reload := &ReportCheck{Name: "Last reload", Passed: lastReload == nil || lastReload.Error == ""}
+	switch {
+	case lastReload == nil:
+		reload.Detail = "not reloaded since the service started"
+	case lastReload.Error != "":
+		reload.Detail = "failed at " + formatDate(lastReload.Time)
+	default:
+		reload.Detail = formatDate(lastReload.Time)
+	}
+	// The zones are left out, not named, the status page being shown to anyone.
+	if lastReload != nil && len(lastReload.LeftOut) > 0 {
+		reload.Detail += fmt.Sprintf(", %v zones left out", len(lastReload.LeftOut))
+	}
+	status.Checks = append(status.Checks, reload)
+	return status
+}
+
+// AddNamedCrash reports the last crash of named, crash being nil when named never crashed. The check fails until named
+// runs again for namedCrashWindow.
+func (s *ServiceStatus) AddNamedCrash(crash *NamedCrash) {
+	check := &ReportCheck{Name: "Named crashes", Passed: true}
+	if crash == nil {
+		check.Detail = "none since the service started"
+		s.Checks = append(s.Checks, check)
+		return
+	}
+	check.Passed = crash.Running && s.GeneratedAt.Sub(crash.Time) > namedCrashWindow
+	check.Detail = fmt.Sprintf("%v since the service started, last at %v: %v", crash.Crashes,
+		formatDate(crash.Time), crash.Error)
+	if !crash.Running {
+		check.Detail += ", restarting"
+	}
+	s.Checks = append(s.Checks, check)
+}
+
+func (s *ServiceStatus) Healthy() bool {
+	for _, check := range s.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderHtml renders the status page, refreshing itself every minute.
+func (s *ServiceStatus) RenderHtml() ([]byte, error) {
+	var buf bytes.Buffer
+	err := statusPageTemplate.Execute(&buf, s)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var statusPageTemplate = htmltemplate.Must(htmltemplate.New("status").Funcs(reportFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8"/>
+<meta http-equiv="refresh" content="60"/>
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.pass { color: #2e7d32; }
+.fail { color: #c62828; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="{{if .Healthy}}pass{{else}}fail{{end}}">{{if .Healthy}}All systems operational{{else}}Degraded{{end}}</p>
+<ul>
+{{range .Checks}}<li class="{{if .Passed}}pass{{else}}fail{{end}}">{{if .Passed}}OK{{else}}FAIL{{end}} {{.Name}}{{if .Detail}}: {{.Detail}}{{end}}</li>
+{{end}}</ul>
+<p>Updated: {{date .GeneratedAt}}</p>
+</body>
+</html>
+`))
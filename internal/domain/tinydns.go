@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// tinydnsRecordTypes are the numbers of the record types written as generic tinydns-data lines, the types tinydns
+// has no line of its own for.
+var tinydnsRecordTypes = map[string]uint16{
+	"AAAA": 28, "MX": 15, "TXT": 16, "SPF": 99, "SRV": 33, "CAA": 257, "TLSA": 52, "DNSKEY": 48, "KEY": 25,
+	"DNAME": 39,
+}
+
+// TinydnsData returns the zone in the tinydns-data format of djbdns, its SOA record followed by the records named
+// serves, e.g. as returned by DNSServer.ServedRecords, every line having the TTL the record is served with. The
+// records tinydns has no line of its own for are written as generic lines, and the ones which can not be, e.g.
+// IPSECKEY, are left as comments.
+func (z *Zone) TinydnsData(records []*Record) string {
+	var data strings.Builder
+	soa := z.SOA
+	data.WriteString(fmt.Sprintf("Z%v:%v:%v:%v:%v:%v:%v:%v:%v\n", tinydnsName(z.Domain),
+		tinydnsName(z.targetName(soa.PrimaryNameServer)), tinydnsMailbox(soa.RenderedMailAddress()),
+		soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.CacheTTL, DefaultTTL))
+
+	for _, record := range records {
+		data.WriteString(z.tinydnsLine(record))
+	}
+	return data.String()
+}
+
+func (z *Zone) tinydnsLine(record *Record) string {
+	name := tinydnsName(z.QualifiedRecordName(record.Name))
+	value := strings.TrimSpace(record.Value)
+	ttl := record.RenderedTTL()
+	switch recordType := strings.ToUpper(record.Type); recordType {
+	case "A":
+		return fmt.Sprintf("+%v:%v:%v\n", name, value, ttl)
+	case "NS":
+		return fmt.Sprintf("&%v::%v:%v\n", name, tinydnsName(z.targetName(value)), ttl)
+	case "CNAME":
+		return fmt.Sprintf("C%v:%v:%v\n", name, tinydnsName(z.targetName(value)), ttl)
+	case "PTR":
+		return fmt.Sprintf("^%v:%v:%v\n", name, tinydnsName(z.targetName(value)), ttl)
+	case "MX":
+		// The null MX has no host name tinydns-data would take.
+		if value != "." {
+			return fmt.Sprintf("@%v::%v:%v:%v\n", name, tinydnsName(z.targetName(value)), record.Priority, ttl)
+		}
+	case "TXT":
+		if texts, err := TextStrings(value); err == nil && len(texts) == 1 {
+			// tinydns-data splits the text into strings itself.
+			return fmt.Sprintf("'%v:%v:%v\n", name, tinydnsEscape(texts[0], false), ttl)
+		}
+	}
+	rdata, err := z.tinydnsRecordData(record)
+	if err != nil {
+		return fmt.Sprintf("# %v %v %v: %v\n", record.Name, record.Type, value, err)
+	}
+	return fmt.Sprintf(":%v:%v:%v:%v\n", name, tinydnsRecordTypes[strings.ToUpper(record.Type)],
+		tinydnsEscape(string(rdata), false), ttl)
+}
+
+// tinydnsRecordData returns the record data of a generic tinydns-data line in the wire format.
+func (z *Zone) tinydnsRecordData(record *Record) ([]byte, error) {
+	value := strings.TrimSpace(record.Value)
+	fields := strings.Fields(value)
+	var rdata []byte
+	switch recordType := strings.ToUpper(record.Type); recordType {
+	case "AAAA":
+		return net.ParseIP(value).To16(), nil
+	case "TXT", "SPF":
+		texts, err := TextStrings(value)
+		if err != nil {
+			return nil, err
+		}
+		for _, text := range texts {
+			for _, chunk := range splitText(text) {
+				rdata = append(append(rdata, byte(len(chunk))), chunk...)
+			}
+		}
+		return rdata, nil
+	case "MX":
+		rdata = appendUint16(rdata, uint16(record.Priority))
+		return append(rdata, 0), nil
+	case "SRV":
+		rdata = appendUint16(rdata, uint16(record.Priority))
+		for _, field := range fields[:2] {
+			number, _ := strconv.ParseUint(field, 10, 16)
+			rdata = appendUint16(rdata, uint16(number))
+		}
+		if fields[2] == "." {
+			return append(rdata, 0), nil
+		}
+		return append(rdata, wireName(z.targetName(fields[2]))...), nil
+	case "DNAME":
+		return wireName(z.targetName(value)), nil
+	case "CAA":
+		caa := strings.SplitN(value, " ", 3)
+		flags, _ := strconv.ParseUint(caa[0], 10, 8)
+		texts, err := TextStrings(caa[2])
+		if err != nil {
+			return nil, err
+		}
+		rdata = append(rdata, byte(flags), byte(len(caa[1])))
+		return append(append(rdata, caa[1]...), strings.Join(texts, "")...), nil
+	case "TLSA":
+		for _, field := range fields[:3] {
+			number, _ := strconv.ParseUint(field, 10, 8)
+			rdata = append(rdata, byte(number))
+		}
+		data, err := hex.DecodeString(strings.Join(fields[3:], ""))
+		if err != nil {
+			return nil, err
+		}
+		return append(rdata, data...), nil
+	case "DNSKEY", "KEY":
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("%v records need flags, a protocol, an algorithm and a key", recordType)
+		}
+		flags, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		protocol, err := strconv.ParseUint(fields[1], 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		algorithm, err := strconv.ParseUint(fields[2], 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.Join(fields[3:], ""))
+		if err != nil {
+			return nil, err
+		}
+		rdata = appendUint16(rdata, uint16(flags))
+		return append(append(rdata, byte(protocol), byte(algorithm)), key...), nil
+	}
+	return nil, fmt.Errorf("%v records are not supported by tinydns-data", record.Type)
+}
+
+// targetName returns the fully qualified name of the host a record of the zone points to, the names without any dot
+// being relative to the zone.
+func (z *Zone) targetName(target string) string {
+	return z.QualifiedRecordName(QualifyName(target))
+}
+
+// tinydnsName returns a domain name for a tinydns-data line, without trailing dot.
+func tinydnsName(name string) string {
+	var labels []string
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		labels = append(labels, tinydnsEscape(label, false))
+	}
+	return strings.Join(labels, ".")
+}
+
+// tinydnsMailbox returns a mailbox in the SOA format, e.g. john\.doe.example.com, for a tinydns-data line, the
+// escaped dots of the local part being kept in their label.
+func tinydnsMailbox(mailbox string) string {
+	var labels []string
+	label := ""
+	mailbox = strings.TrimSuffix(mailbox, ".")
+	for i := 0; i < len(mailbox); i++ {
+		switch {
+		case mailbox[i] == '\\' && i+1 < len(mailbox):
+			i++
+			label += string(mailbox[i])
+		case mailbox[i] == '.':
+			labels = append(labels, tinydnsEscape(label, true))
+			label = ""
+		default:
+			label += string(mailbox[i])
+		}
+	}
+	return strings.Join(append(labels, tinydnsEscape(label, true)), ".")
+}
+
+// tinydnsEscape escapes the bytes of a tinydns-data field which are not printable, the colon separating the fields
+// and the backslash as octal \ooo, along with the dots when escapeDots is set.
+func tinydnsEscape(field string, escapeDots bool) string {
+	var escaped strings.Builder
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c <= ' ' || c > '~' || c == ':' || c == '\\' || escapeDots && c == '.' {
+			escaped.WriteString(fmt.Sprintf("\\%03o", c))
+			continue
+		}
+		escaped.WriteByte(c)
+	}
+	return escaped.String()
+}
+
+func appendUint16(data []byte, value uint16) []byte {
+	return append(data, byte(value>>8), byte(value))
+}
+
+// wireName returns a fully qualified domain name in the uncompressed wire format.
+func wireName(name string) []byte {
+	var wire []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label != "" {
+			wire = append(append(wire, byte(len(label))), label...)
+		}
+	}
+	return append(wire, 0)
+}
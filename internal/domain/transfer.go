@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var TSIGKeyAlgorithms = []string{
+	"hmac-md5", "hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512",
+}
+
+// tsigSecretSizes are the sizes of the generated secrets, the output size of the hash of each algorithm.
+var tsigSecretSizes = map[string]int{
+	"hmac-md5": 16, "hmac-sha1": 20, "hmac-sha224": 28, "hmac-sha256": 32, "hmac-sha384": 48, "hmac-sha512": 64,
+}
+
+var tsigKeyNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var (
+	ErrorInvalidTSIGKey = errors.New(
+		"TSIG keys need a name made of letters, digits, '.', '_' and '-', a known hmac algorithm and a base64 secret")
+	ErrorTSIGKeyConflict = errors.New("TSIG key is defined differently by another zone")
+	ErrorUnknownTSIGKey  = errors.New("TSIG key is not found")
+	ErrorTSIGKeyInUse    = errors.New("TSIG key is used by zones")
+
+	ErrorZoneTransferFailed = errors.New("zone transfer failed")
+)
+
+// TSIGKey authenticates a secondary transferring a zone, its name, algorithm and secret being configured on the
+// secondary as well. The transfer keys of a zone without secret refer to the managed key of the same name, managed
+// keys being stored on their own and shared by the zones.
+type TSIGKey struct {
+	Name      string
+	Algorithm string
+	Secret    string
+}
+
+// NewTSIGKey returns a key with a random secret when secret is empty.
+func NewTSIGKey(name, algorithm, secret string) (*TSIGKey, error) {
+	key := &TSIGKey{Name: name, Algorithm: strings.ToLower(strings.TrimSpace(algorithm)), Secret: secret}
+	if key.Secret == "" {
+		size, ok := tsigSecretSizes[key.Algorithm]
+		if !ok {
+			return nil, ErrorInvalidTSIGKey
+		}
+		random := make([]byte, size)
+		_, err := rand.Read(random)
+		if err != nil {
+			return nil, err
+		}
+		key.Secret = base64.StdEncoding.EncodeToString(random)
+	}
+	if !key.IsValid() {
+		return nil, ErrorInvalidTSIGKey
+	}
+	return key, nil
+}
+
+// IsManaged reports whether the key refers to the managed key of its name.
+func (k *TSIGKey) IsManaged() bool {
+	return k.Secret == ""
+}
+
+func (k *TSIGKey) IsValid() bool {
+	if !tsigKeyNamePattern.MatchString(k.Name) || !containsString(TSIGKeyAlgorithms, k.Algorithm) {
+		return false
+	}
+	secret, err := base64.StdEncoding.DecodeString(k.Secret)
+	return err == nil && len(secret) > 0
+}
+
+// SetAllowTransfer replaces the secondaries allowed to transfer the zone, the address match elements of addresses
+// or the holders of one of the keys. named's default applies when both are empty.
+func (z *Zone) SetAllowTransfer(addresses []string, keys []*TSIGKey) error {
+	for _, address := range addresses {
+		if !IsValidAddressMatchElement(address) {
+			return fmt.Errorf("%v is not a valid address match element", address)
+		}
+	}
+	names := map[string]bool{}
+	for _, key := range keys {
+		key.Algorithm = strings.ToLower(strings.TrimSpace(key.Algorithm))
+		if key.IsManaged() && !tsigKeyNamePattern.MatchString(key.Name) || !key.IsManaged() && !key.IsValid() {
+			return ErrorInvalidTSIGKey
+		}
+		if names[key.Name] {
+			return fmt.Errorf("TSIG key %v is given twice", key.Name)
+		}
+		names[key.Name] = true
+	}
+	z.AllowTransfer = addresses
+	z.TransferKeys = keys
+	return nil
+}
+
+// SetAlsoNotify replaces the secondaries notified of the changes of the zone on top of its name servers, each target
+// being an IP address optionally followed by a port, e.g. "192.0.2.53 port 5353".
+func (z *Zone) SetAlsoNotify(targets []string) error {
+	var alsoNotify []string
+	for _, target := range targets {
+		fields := strings.Fields(target)
+		if !IsValidNotifyTarget(fields) {
+			return fmt.Errorf("%v is not a valid notify target", target)
+		}
+		alsoNotify = append(alsoNotify, strings.Join(fields, " "))
+	}
+	z.AlsoNotify = alsoNotify
+	return nil
+}
+
+// IsValidNotifyTarget reports whether the fields are an IP address, optionally followed by "port" and a port.
+func IsValidNotifyTarget(fields []string) bool {
+	if len(fields) != 1 && len(fields) != 3 || net.ParseIP(fields[0]) == nil {
+		return false
+	}
+	if len(fields) == 1 {
+		return true
+	}
+	port, err := strconv.Atoi(fields[2])
+	return fields[1] == "port" && err == nil && port > 0 && port <= 65535
+}
+
+// CheckTransferKeys fails when another zone, or a managed key, defines a key of the zone with another algorithm or
+// secret, named holding a single key per name. The zones may share a key.
+func (z *Zone) CheckTransferKeys(zones []*Zone, managedKeys []*TSIGKey) error {
+	for _, key := range z.TransferKeys {
+		if key.IsManaged() {
+			continue
+		}
+		for _, managedKey := range managedKeys {
+			if key.conflictsWith(managedKey) {
+				return fmt.Errorf("%w: %v", ErrorTSIGKeyConflict, key.Name)
+			}
+		}
+		for _, other := range zones {
+			if other.Id != z.Id && other.DefinesConflictingKey(key) {
+				return fmt.Errorf("%w: %v (%v)", ErrorTSIGKeyConflict, key.Name, other.Domain)
+			}
+		}
+	}
+	return nil
+}
+
+// DefinesConflictingKey reports whether the zone defines a key named like key with another algorithm or secret.
+func (z *Zone) DefinesConflictingKey(key *TSIGKey) bool {
+	for _, zoneKey := range z.TransferKeys {
+		if !zoneKey.IsManaged() && zoneKey.conflictsWith(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesManagedKey reports whether a transfer key of the zone refers to the managed key named name.
+func (z *Zone) UsesManagedKey(name string) bool {
+	for _, key := range z.TransferKeys {
+		if key.IsManaged() && key.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *TSIGKey) conflictsWith(other *TSIGKey) bool {
+	return k.Name == other.Name && (k.Algorithm != other.Algorithm || k.Secret != other.Secret)
+}
+
+// UniqueTransferKeys returns the managed keys followed by the keys defined by the zones, once per name, in the order
+// they appear.
+func UniqueTransferKeys(zones []*Zone, managedKeys []*TSIGKey) []*TSIGKey {
+	var keys []*TSIGKey
+	names := map[string]bool{}
+	add := func(key *TSIGKey) {
+		if !key.IsManaged() && !names[key.Name] {
+			names[key.Name] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range managedKeys {
+		add(key)
+	}
+	for _, zone := range zones {
+		for _, key := range zone.TransferKeys {
+			add(key)
+		}
+	}
+	return keys
+}
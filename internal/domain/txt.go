@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxCharacterStringLength is the length limit of every string of TXT and SPF records, longer texts such as DKIM
+	// keys are split into several strings.
+	maxCharacterStringLength = 255
+	// maxRecordDataLength is the limit of the record data, each string taking its length plus a length byte.
+	maxRecordDataLength = 65535
+)
+
+// IsTextRecord reports whether the value of the record type is made of character strings.
+func IsTextRecord(recordType string) bool {
+	recordType = strings.ToUpper(recordType)
+	return recordType == "TXT" || recordType == "SPF"
+}
+
+// TextStrings returns the strings of the value of a TXT or SPF record, unescaped. A value made of quoted strings,
+// e.g. `"v=DKIM1; k=rsa; " "p=MIGf..."`, holds one string per quoted string, any other value is a single string.
+func TextStrings(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return []string{value}, nil
+	}
+
+	var texts []string
+	for value != "" {
+		if value[0] != '"' {
+			return nil, fmt.Errorf("text %q is not quoted", value)
+		}
+		var text strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(value); i++ {
+			c := value[i]
+			if c == '"' {
+				closed = true
+				break
+			}
+			if c != '\\' {
+				text.WriteByte(c)
+				continue
+			}
+			if i+3 < len(value) && isDigits(value[i+1:i+4]) {
+				code, _ := strconv.Atoi(value[i+1 : i+4])
+				if code > 255 {
+					return nil, fmt.Errorf("escape \\%v is not a byte", value[i+1:i+4])
+				}
+				text.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+			if i+1 < len(value) {
+				i++
+				text.WriteByte(value[i])
+			}
+		}
+		if !closed {
+			return nil, fmt.Errorf("text %q is missing its closing quote", value)
+		}
+		texts = append(texts, text.String())
+		value = strings.TrimSpace(value[i+1:])
+	}
+	return texts, nil
+}
+
+// renderTextValue quotes and escapes the strings of a TXT or SPF value for the zone file, the ones longer than
+// maxCharacterStringLength being split.
+func renderTextValue(texts []string) string {
+	var quoted []string
+	for _, text := range texts {
+		for _, chunk := range splitText(text) {
+			quoted = append(quoted, quoteText(chunk))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// splitText splits a text into strings of maxCharacterStringLength bytes at most, without cutting a UTF-8 character
+// in half.
+func splitText(text string) []string {
+	chunks := []string{}
+	for len(text) > maxCharacterStringLength {
+		end := maxCharacterStringLength
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == 0 {
+			end = maxCharacterStringLength
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return append(chunks, text)
+}
+
+func quoteText(text string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"' || c == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(c)
+		case c < ' ' || c == 0x7f:
+			quoted.WriteString(fmt.Sprintf("\\%03d", c))
+		default:
+			quoted.WriteByte(c)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+// textDataLength returns the length of the record data of the strings once split.
+func textDataLength(texts []string) int {
+	length := 0
+	for _, text := range texts {
+		for _, chunk := range splitText(text) {
+			length += len(chunk) + 1
+		}
+	}
+	return length
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnboundConfig returns the zone as the local-zone and local-data clauses of an Unbound server section, for the
+// internal resolvers to answer the queries for the zone themselves without transferring it. The records are the ones
+// named serves, e.g. as returned by DNSServer.ServedRecords, their names and targets fully qualified. The zone is
+// static: the names it has no record for are answered NXDOMAIN rather than resolved.
+func (z *Zone) UnboundConfig(records []*Record) string {
+	var config strings.Builder
+	zoneDomain := NormalizeDomain(z.Domain) + "."
+	config.WriteString(fmt.Sprintf("local-zone: \"%v\" static\n", zoneDomain))
+
+	soa := z.SOA
+	mailAddress := soa.RenderedMailAddress()
+	if !strings.HasSuffix(mailAddress, ".") {
+		mailAddress += "."
+	}
+	config.WriteString(unboundLocalData(fmt.Sprintf("%v %v IN SOA %v. %v %v %v %v %v %v", zoneDomain, DefaultTTL,
+		z.targetName(soa.PrimaryNameServer), mailAddress, soa.Serial, soa.Refresh, soa.Retry, soa.Expire,
+		soa.CacheTTL)))
+
+	for _, record := range records {
+		record = z.qualifiedRecord(record)
+		config.WriteString(unboundLocalData(fmt.Sprintf("%v %v IN %v %v", record.Name, record.RenderedTTL(),
+			strings.ToUpper(record.Type), record.RenderedValue())))
+	}
+	return config.String()
+}
+
+// qualifiedRecord returns a copy of the record whose name and target, if any, are fully qualified with a trailing
+// dot, for the formats without origin.
+func (z *Zone) qualifiedRecord(record *Record) *Record {
+	qualified := *record
+	qualified.Name = z.QualifiedRecordName(record.Name) + "."
+	if index, ok := recordTargetFields[strings.ToUpper(record.Type)]; ok {
+		fields := strings.Fields(record.Value)
+		if index < len(fields) && fields[index] != "." {
+			fields[index] = z.targetName(fields[index]) + "."
+			qualified.Value = strings.Join(fields, " ")
+		}
+	}
+	return &qualified
+}
+
+// unboundLocalData returns the local-data clause of a record in the zone file format, single quoted when it holds
+// double quotes, e.g. TXT records, the single quotes of the data being escaped the zone file way.
+func unboundLocalData(data string) string {
+	if strings.Contains(data, `"`) {
+		return fmt.Sprintf("local-data: '%v'\n", strings.ReplaceAll(data, "'", `\039`))
+	}
+	return fmt.Sprintf("local-data: \"%v\"\n", data)
+}
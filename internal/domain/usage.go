@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultUnusedRecordDays is how long a record goes without answering a query before being reported as unused.
+const DefaultUnusedRecordDays = 30
+
+// RecordsAnswering returns the records of the zone answering a query for name and queryType: the records of the name
+// having the type, or its CNAME record, and the wildcard records covering the name when it owns no record. The
+// disabled records are left out.
+func (z *Zone) RecordsAnswering(name string, queryType string) []*Record {
+	owner, inZone := z.relativeName(NormalizeDomain(name) + ".")
+	if !inZone {
+		return nil
+	}
+	queryType = strings.ToUpper(queryType)
+	if z.ownsName(owner) {
+		return z.recordsAnswering(owner, queryType)
+	}
+	for owner != "@" {
+		parent := "@"
+		if i := strings.Index(owner, "."); i >= 0 {
+			parent = owner[i+1:]
+		}
+		wildcard := "*"
+		if parent != "@" {
+			wildcard = "*." + parent
+		}
+		if z.ownsName(wildcard) {
+			return z.recordsAnswering(wildcard, queryType)
+		}
+		owner = parent
+	}
+	return nil
+}
+
+func (z *Zone) recordsAnswering(owner string, queryType string) []*Record {
+	var records []*Record
+	for _, record := range z.Records {
+		name, _ := z.relativeName(record.Name)
+		if record.Disabled || name != owner {
+			continue
+		}
+		recordType := strings.ToUpper(record.Type)
+		if queryType == "ANY" || recordType == queryType || recordType == "CNAME" ||
+			IsAliasRecord(recordType) && (queryType == "A" || queryType == "AAAA") {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (z *Zone) ownsName(owner string) bool {
+	for _, record := range z.Records {
+		if name, _ := z.relativeName(record.Name); name == owner && !record.Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// UnusedRecords returns the records which answered no query since since, lastQueries holding when each record last
+// answered one by id. The disabled records, which are not served, and the NS records of the apex, which delegate the
+// zone, are left out.
+func (z *Zone) UnusedRecords(lastQueries map[string]time.Time, since time.Time) []*Record {
+	var records []*Record
+	for _, record := range z.Records {
+		if record.Disabled || strings.ToUpper(record.Type) == "NS" && z.NormalizeRecordName(record.Name) == "@" {
+			continue
+		}
+		if lastQuery, ok := lastQueries[record.Id]; ok && !lastQuery.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	WarningLowNegativeCacheTTL = "low_negative_cache_ttl"
+	WarningMissingGlue         = "missing_glue"
+	WarningCNAMEWithOtherData  = "cname_with_other_data"
+	WarningTargetIsAlias       = "target_is_alias"
+	WarningAliasWithAddresses  = "alias_with_addresses"
+	// WarningRegistrarPublishFailed is returned when the zone changed but its delegation could not be published.
+	WarningRegistrarPublishFailed = "registrar_publish_failed"
+	// WarningPTRSyncFailed is returned when the zone changed but the PTR records could not be kept in line.
+	WarningPTRSyncFailed = "ptr_sync_failed"
+	// WarningNotApplied is returned when the change is saved but named could not be reloaded, the zone is pending.
+	WarningNotApplied = "not_applied"
+	// WarningSimilarZone is returned when a zone is created with a near typo of a managed domain, e.g. a typo-squatting
+	// domain, while Settings.WarnSimilarZones is set.
+	WarningSimilarZone = "similar_zone"
+	// WarningZoneSize is returned when a change grows the zone close to one of the limits of Settings.ZoneLimits.
+	WarningZoneSize = "zone_size"
+	// WarningReverseDelegation is returned when a classless reverse zone is created but the zone delegating it is not
+	// managed here, or holds other records at the names of the delegation.
+	WarningReverseDelegation = "reverse_delegation"
+	// WarningInvalidOwnerName is returned while the zone has records whose owner name is not valid, those records
+	// being left out of the zone file.
+	WarningInvalidOwnerName = "invalid_owner_name"
+
+	MinAdvisedTTL = 60
+)
+
+var ErrorStrictValidation = errors.New("zone has strict validation enabled")
+
+// ValidationWarning is an advisory about a zone, it does not prevent the zone from being changed unless the zone
+// has strict validation enabled.
+type ValidationWarning struct {
+	Code    string
+	Message string
+}
+
+// Warnings lists the advisories about the current content of the zone.
+func (z *Zone) Warnings() []*ValidationWarning {
+	var warnings []*ValidationWarning
+	add := func(code string, format string, args ...interface{}) {
+		warnings = append(warnings, &ValidationWarning{Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if z.SOA != nil && z.SOA.CacheTTL < MinAdvisedTTL {
+		add(WarningLowNegativeCacheTTL, "negative caching TTL of %vs is below %vs", z.SOA.CacheTTL, MinAdvisedTTL)
+	}
+	for _, record := range z.InvalidOwnerNameRecords() {
+		if !record.Disabled {
+			add(WarningInvalidOwnerName, "%q is not a valid owner name, its %v record is left out of the zone file",
+				record.Name, record.Type)
+		}
+	}
+
+	types := map[string][]string{}
+	for _, record := range z.Records {
+		if record.Disabled {
+			continue
+		}
+		if name, ok := z.relativeName(record.Name); ok {
+			types[name] = append(types[name], strings.ToUpper(record.Type))
+		}
+	}
+
+	for _, record := range z.Records {
+		if record.Disabled {
+			continue
+		}
+		recordType := strings.ToUpper(record.Type)
+		name, _ := z.relativeName(record.Name)
+		if recordType == "CNAME" && len(types[name]) > 1 {
+			add(WarningCNAMEWithOtherData, "%v has a CNAME record along with other records", record.Name)
+		}
+		if IsAliasRecord(recordType) && (containsString(types[name], "A") || containsString(types[name], "AAAA")) {
+			add(WarningAliasWithAddresses, "%v has an ALIAS record along with A or AAAA records", record.Name)
+		}
+		if recordType != "NS" && recordType != "MX" && recordType != "SRV" {
+			continue
+		}
+		expanded, err := z.ExpandedRecord(record)
+		if err != nil {
+			continue
+		}
+		fields := strings.Fields(expanded.Value)
+		index := recordTargetFields[recordType]
+		if index >= len(fields) {
+			continue
+		}
+		target := fields[index]
+		targetName, inZone := z.relativeName(QualifyName(target))
+		if !inZone {
+			continue
+		}
+		if recordType == "NS" && !containsString(types[targetName], "A") && !containsString(types[targetName], "AAAA") {
+			add(WarningMissingGlue, "NS target %v has no A or AAAA record in the zone", target)
+		}
+		if recordType != "NS" && containsString(types[targetName], "CNAME") {
+			add(WarningTargetIsAlias, "%v target %v is a CNAME, it should point to a host having an address",
+				recordType, target)
+		}
+	}
+	return warnings
+}
+
+// CheckWarnings returns the warnings of the zone. When the zone has strict validation enabled, the warnings which
+// are not part of previous, the warnings before the change, are reported as an error instead.
+func (z *Zone) CheckWarnings(previous []*ValidationWarning) ([]*ValidationWarning, error) {
+	warnings := z.Warnings()
+	if !z.StrictValidation {
+		return warnings, nil
+	}
+
+	known := map[ValidationWarning]bool{}
+	for _, warning := range previous {
+		known[*warning] = true
+	}
+	var messages []string
+	for _, warning := range warnings {
+		if !known[*warning] {
+			messages = append(messages, warning.Message)
+		}
+	}
+	if len(messages) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrorStrictValidation, strings.Join(messages, "; "))
+	}
+	return warnings, nil
+}
+
+// relativeName returns a name as written in the zone file relative to the zone, in lower case, false when the name
+// is outside of the zone. Names without trailing dot are relative already.
+func (z *Zone) relativeName(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "@" {
+		return "@", true
+	}
+	if !strings.HasSuffix(name, ".") {
+		return name, true
+	}
+	fqdn := strings.TrimSuffix(name, ".")
+	zoneDomain := NormalizeDomain(z.Domain)
+	if fqdn == zoneDomain {
+		return "@", true
+	}
+	if strings.HasSuffix(fqdn, "."+zoneDomain) {
+		return strings.TrimSuffix(fqdn, "."+zoneDomain), true
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	variableNamePattern      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableReferencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+var (
+	ErrorInvalidVariable = errors.New(
+		"variable names must be made of letters, digits and '_' without leading digit, values must be single lines")
+	ErrorUnknownVariable       = errors.New("record value refers to a variable the zone does not define")
+	ErrorVariableBreaksRecords = errors.New("records referring to the variables would not be valid anymore")
+)
+
+// SetVariables replaces the variables the record values of the zone refer to as ${NAME}, e.g. ${LB_IP}. Every
+// record referring to a variable has to expand to a valid value with the new variables.
+func (z *Zone) SetVariables(variables map[string]string) error {
+	for name, value := range variables {
+		if !variableNamePattern.MatchString(name) || strings.ContainsAny(value, "\r\n") {
+			return ErrorInvalidVariable
+		}
+	}
+
+	previous := z.Variables
+	z.Variables = nil
+	for name, value := range variables {
+		if z.Variables == nil {
+			z.Variables = map[string]string{}
+		}
+		z.Variables[name] = value
+	}
+	for _, record := range z.Records {
+		if !HasVariables(record.Value) {
+			continue
+		}
+		if z.CheckRecordValue(record) != nil {
+			z.Variables = previous
+			return fmt.Errorf("%w: %v %v", ErrorVariableBreaksRecords, record.Name, record.Type)
+		}
+	}
+	return nil
+}
+
+// HasVariables reports whether the value refers to at least one variable.
+func HasVariables(value string) bool {
+	return variableReferencePattern.MatchString(value)
+}
+
+// ExpandedRecord returns a copy of the record with the variables of its value replaced by their value, the record
+// itself when it refers to none.
+func (z *Zone) ExpandedRecord(record *Record) (*Record, error) {
+	if !HasVariables(record.Value) {
+		return record, nil
+	}
+	var err error
+	value := variableReferencePattern.ReplaceAllStringFunc(record.Value, func(reference string) string {
+		name := variableReferencePattern.FindStringSubmatch(reference)[1]
+		value, ok := z.Variables[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("%w: %v", ErrorUnknownVariable, name)
+		}
+		return value
+	})
+	if err != nil {
+		return nil, err
+	}
+	expanded := *record
+	expanded.Value = value
+	return &expanded, nil
+}
+
+// CheckRecordValue checks the value of the record against its type once its variables are expanded.
+func (z *Zone) CheckRecordValue(record *Record) error {
+	expanded, err := z.ExpandedRecord(record)
+	if err != nil {
+		return err
+	}
+	return expanded.CheckValue()
+}
+
+// IsValidRecord tells whether the record is valid once its variables are expanded.
+func (z *Zone) IsValidRecord(record *Record) bool {
+	expanded, err := z.ExpandedRecord(record)
+	return err == nil && expanded.IsValid()
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var viewNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+var (
+	ErrorInvalidViewName = errors.New("view names are made of lowercase letters, digits and '-'")
+	ErrorUnknownView     = errors.New("view is not found")
+	ErrorViewInUse       = errors.New("view is used by zones, records or response policy profiles")
+)
+
+// View serves its own answers to the clients matched by MatchClients, e.g. the private addresses of the hosts to the
+// internal clients and their public ones to the others. named picks the first view matching the client, the clients
+// no view matches being refused.
+type View struct {
+	Name string
+	// MatchClients is the address match list of the clients of the view, e.g. 10.0.0.0/8 or any.
+	MatchClients []string
+}
+
+func IsValidViewName(name string) bool {
+	return viewNamePattern.MatchString(name)
+}
+
+// SetViews replaces the views, in the order named matches the clients against them. The caller checks the views
+// removed are used by no zone.
+func (o *ServerOptions) SetViews(views []*View) error {
+	seen := map[string]bool{}
+	for _, view := range views {
+		if !IsValidViewName(view.Name) {
+			return ErrorInvalidViewName
+		}
+		if len(view.MatchClients) == 0 {
+			return fmt.Errorf("view %v needs the clients it matches", view.Name)
+		}
+		for _, element := range view.MatchClients {
+			if !IsValidAddressMatchElement(element) {
+				return fmt.Errorf("%v is not a valid address match element", element)
+			}
+		}
+		if seen[view.Name] {
+			return fmt.Errorf("view %v is defined twice", view.Name)
+		}
+		seen[view.Name] = true
+	}
+	o.Views = views
+	return nil
+}
+
+func (o *ServerOptions) FindView(name string) *View {
+	for _, view := range o.Views {
+		if view.Name == name {
+			return view
+		}
+	}
+	return nil
+}
+
+// SetViews replaces the names of the views serving the zone, every view serving it when empty. The caller checks
+// they exist.
+func (z *Zone) SetViews(names []string) error {
+	var views []string
+	for _, name := range names {
+		if !IsValidViewName(name) {
+			return ErrorInvalidViewName
+		}
+		if !containsString(views, name) {
+			views = append(views, name)
+		}
+	}
+	z.Views = views
+	return nil
+}
+
+// ServedInView reports whether the view named name serves the zone.
+func (z *Zone) ServedInView(name string) bool {
+	return len(z.Views) == 0 || containsString(z.Views, name)
+}
+
+// UsesView reports whether the zone or one of its records refers to the view named name.
+func (z *Zone) UsesView(name string) bool {
+	return containsString(z.Views, name) || z.HasViewRecords(name)
+}
+
+// HasViewRecords reports whether records of the zone override the others in the view named name.
+func (z *Zone) HasViewRecords(name string) bool {
+	for _, record := range z.Records {
+		if record.View == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordViews returns the names of the views the records of the zone override the others in, sorted.
+func (z *Zone) RecordViews() []string {
+	var names []string
+	for _, record := range z.Records {
+		if record.View != "" && !containsString(names, record.View) {
+			names = append(names, record.View)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ViewRecords returns the records the view named name serves out of records: the records of the view along with
+// the records of every view, the names and types the view has records of being left to the records of the view. The
+// records of every view only are returned when name is empty.
+func (z *Zone) ViewRecords(records []*Record, name string) []*Record {
+	overridden := map[string]bool{}
+	for _, record := range records {
+		if name != "" && record.View == name {
+			overridden[z.NormalizeRecordName(record.Name)+" "+strings.ToUpper(record.Type)] = true
+		}
+	}
+	var viewRecords []*Record
+	for _, record := range records {
+		switch record.View {
+		case "":
+			if !overridden[z.NormalizeRecordName(record.Name)+" "+strings.ToUpper(record.Type)] {
+				viewRecords = append(viewRecords, record)
+			}
+		case name:
+			viewRecords = append(viewRecords, record)
+		}
+	}
+	return viewRecords
+}
@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+const (
+	EventZoneCreated   = "zone.created"
+	EventZoneUpdated   = "zone.updated"
+	EventZoneDeleted   = "zone.deleted"
+	EventZoneArchived  = "zone.archived"
+	EventZoneRestored  = "zone.restored"
+	EventZoneFrozen    = "zone.frozen"
+	EventZoneThawed    = "zone.thawed"
+	EventChangeBurst   = "zone.change_burst"
+	EventRecordCreated = "record.created"
+	EventRecordUpdated = "record.updated"
+	EventRecordDeleted = "record.deleted"
+	EventCanaryQueried = "record.canary_queried"
+
+	DefaultWebhookContentType = "application/json"
+)
+
+var EventTypes = []string{
+	EventZoneCreated, EventZoneUpdated, EventZoneDeleted, EventZoneArchived, EventZoneRestored, EventZoneFrozen,
+	EventZoneThawed, EventChangeBurst, EventRecordCreated, EventRecordUpdated, EventRecordDeleted, EventCanaryQueried,
+}
+
+// ChangeEvent describes a change made to a zone or to one of its records.
+type ChangeEvent struct {
+	// Id is assigned when the event is written to the outbox.
+	Id   string
+	Type string
+	Time time.Time
+	Zone string
+	// Record is the record after the change, or the removed record, nil for zone events.
+	Record *Record
+	// PreviousRecord is the record before the change, set for record updates only.
+	PreviousRecord *Record
+	Change         *ChangeMetadata
+	// Detail describes the events flagging a zone, e.g. the changes of a burst or the query of a canary record, empty
+	// for the changes.
+	Detail string
+}
+
+func NewZoneEvent(eventType string, zone *Zone) *ChangeEvent {
+	return &ChangeEvent{Type: eventType, Time: time.Now(), Zone: zone.Domain}
+}
+
+// IsAlert reports whether the event flags a zone, e.g. a burst of changes, rather than describing a change.
+func (e *ChangeEvent) IsAlert() bool {
+	return e.Type == EventChangeBurst || e.Type == EventCanaryQueried
+}
+
+func (e *ChangeEvent) WithChange(metadata *ChangeMetadata) *ChangeEvent {
+	e.Change = metadata
+	return e
+}
+
+// NewRecordEvent creates a record event, record is referenced rather than copied so the id assigned when the zone
+// is persisted ends up in the event.
+func NewRecordEvent(eventType string, zone *Zone, record, previousRecord *Record) *ChangeEvent {
+	event := NewZoneEvent(eventType, zone)
+	event.Record = record
+	event.PreviousRecord = previousRecord
+	return event
+}
+
+// EventPublisher delivers the events written to the outbox.
+type EventPublisher interface {
+	Start(ctx context.Context)
+	// Notify wakes the publisher up after events have been written to the outbox.
+	Notify()
+	Shutdown(ctx context.Context) error
+	Subscribe(listener ChangeEventListener)
+}
+
+type Webhook struct {
+	Id  string
+	Url string
+	// Events, Zones and RecordTypes filter the delivered events, an empty filter matches everything. Setting
+	// RecordTypes restricts the webhook to record events.
+	Events      []string
+	Zones       []string
+	RecordTypes []string
+	// PayloadTemplate is a Go template rendered with the ChangeEvent, the default JSON payload is sent when empty.
+	PayloadTemplate string
+	ContentType     string
+	Enabled         bool
+}
+
+func NewWebhook(webhookUrl string) *Webhook {
+	return &Webhook{Url: webhookUrl, ContentType: DefaultWebhookContentType, Enabled: true}
+}
+
+func (w *Webhook) Matches(event *ChangeEvent) bool {
+	if !w.Enabled {
+		return false
+	}
+	if len(w.Events) > 0 && !containsString(w.Events, event.Type) {
+		return false
+	}
+	if len(w.Zones) > 0 && !containsString(w.Zones, NormalizeDomain(event.Zone)) {
+		return false
+	}
+	if len(w.RecordTypes) > 0 && (event.Record == nil || !containsString(w.RecordTypes, event.Record.Type)) {
+		return false
+	}
+	return true
+}
+
+// RenderPayload renders the body sent for an event, the json function is available to templates for escaping
+// values, e.g. {"text": {{json .Record.Value}}}.
+func (w *Webhook) RenderPayload(event *ChangeEvent) ([]byte, error) {
+	if w.PayloadTemplate == "" {
+		return json.Marshal(event.Payload())
+	}
+	tmpl, err := w.template()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, event)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *Webhook) template() (*template.Template, error) {
+	return template.New("payload").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+	}).Option("missingkey=error").Parse(w.PayloadTemplate)
+}
+
+func (w *Webhook) IsValid() bool {
+	parsedUrl, err := url.Parse(w.Url)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+		return false
+	}
+	for _, event := range w.Events {
+		if !containsString(EventTypes, event) {
+			return false
+		}
+	}
+	if w.PayloadTemplate != "" {
+		if _, err := w.template(); err != nil {
+			return false
+		}
+	}
+	return w.ContentType != ""
+}
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+
+	MaxDeliveryAttempts   = 10
+	minDeliveryRetryDelay = 10 * time.Second
+	maxDeliveryRetryDelay = time.Hour
+)
+
+// WebhookDelivery tracks the delivery of an event to a webhook, it is retried with an exponential backoff until it
+// succeeds or MaxDeliveryAttempts is reached.
+type WebhookDelivery struct {
+	Id            string
+	WebhookId     string
+	Event         *ChangeEvent
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+func NewWebhookDelivery(webhook *Webhook, event *ChangeEvent) *WebhookDelivery {
+	return &WebhookDelivery{
+		WebhookId:     webhook.Id,
+		Event:         event,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: event.Time,
+	}
+}
+
+func (d *WebhookDelivery) MarkDelivered() {
+	d.Attempts++
+	d.Status = DeliveryStatusDelivered
+	d.LastError = ""
+}
+
+func (d *WebhookDelivery) MarkFailed(err error, now time.Time) {
+	d.Attempts++
+	d.LastError = err.Error()
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+	delay := minDeliveryRetryDelay << uint(d.Attempts-1)
+	if delay > maxDeliveryRetryDelay {
+		delay = maxDeliveryRetryDelay
+	}
+	d.NextAttemptAt = now.Add(delay)
+}
+
+// Abandon gives up on a delivery which can no longer succeed.
+func (d *WebhookDelivery) Abandon(err error) {
+	d.Status = DeliveryStatusFailed
+	d.LastError = err.Error()
+}
+
+// Payload returns the JSON document describing the event, as sent to webhooks without template.
+func (e *ChangeEvent) Payload() map[string]interface{} {
+	payload := map[string]interface{}{
+		"id":   e.Id,
+		"type": e.Type,
+		"time": e.Time.UTC().Format(time.RFC3339),
+		"zone": e.Zone,
+	}
+	if e.Record != nil {
+		payload["record"] = recordPayload(e.Record)
+	}
+	if e.PreviousRecord != nil {
+		payload["previous_record"] = recordPayload(e.PreviousRecord)
+	}
+	if e.Detail != "" {
+		payload["detail"] = e.Detail
+	}
+	if e.Change != nil {
+		payload["change"] = map[string]string{
+			"ticket_id":    e.Change.TicketId,
+			"reason":       e.Change.Reason,
+			"requested_by": e.Change.RequestedBy,
+		}
+	}
+	return payload
+}
+
+func recordPayload(record *Record) map[string]interface{} {
+	payload := map[string]interface{}{"id": record.Id, "name": record.Name, "type": record.Type, "value": record.Value}
+	if HasRecordPriority(record.Type) {
+		payload["priority"] = record.Priority
+	}
+	if record.ExternalId != "" {
+		payload["external_id"] = record.ExternalId
+	}
+	return payload
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,392 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxZoneFileSize bounds the zone files imported at once.
+	MaxZoneFileSize = 16 << 20
+	// DefaultTTL is the TTL of the records of the zone files written here without TTL of their own, in seconds.
+	DefaultTTL = 14400
+)
+
+// The formats the zones are exported in, see Zone.TinydnsData and Zone.UnboundConfig.
+const (
+	ExportFormatZoneFile = "zone-file"
+	ExportFormatTinydns  = "tinydns"
+	ExportFormatUnbound  = "unbound"
+)
+
+func IsValidExportFormat(format string) bool {
+	return format == ExportFormatZoneFile || format == ExportFormatTinydns || format == ExportFormatUnbound
+}
+
+// zoneFileRecordTypes are the record types imported from zone files, the types managed through the API.
+var zoneFileRecordTypes = []string{
+	"A", "AAAA", "CAA", "CNAME", "DNSKEY", "IPSECKEY", "KEY", "MX", "NS", "PTR", "SPF", "SRV", "TLSA", "TXT",
+}
+
+// zoneFileSignatureTypes are left out of imported zone files, signing being named's job once the zone is managed.
+var zoneFileSignatureTypes = []string{"RRSIG", "NSEC", "NSEC3", "NSEC3PARAM"}
+
+var zoneFileTTLPattern = regexp.MustCompile(`^([0-9]+[smhdwSMHDW]?)+$`)
+
+var ErrorInvalidZoneFile = errors.New("zone file is not valid")
+
+// zoneFileLine is a logical line of a zone file, the lines continued by parentheses being joined.
+type zoneFileLine struct {
+	number int
+	// indented lines leave the owner out, the owner of the previous record applying.
+	indented bool
+	tokens   []string
+}
+
+// zoneFileEntry is a resource record of a zone file, its owner being fully qualified.
+type zoneFileEntry struct {
+	line       int
+	owner      string
+	origin     string
+	recordType string
+	data       []string
+}
+
+// ParseZoneFile reads a zone file in the master file format of RFC 1035 into a new zone, e.g. one exported by
+// another DNS server. domainName is the domain of the zone, the owner of the SOA record when empty. The TTLs are
+// left out, the zone files written here having a single default TTL, and so are the DNSSEC signatures. $INCLUDE and
+// $GENERATE are not supported.
+func ParseZoneFile(reader io.Reader, domainName string) (*Zone, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, MaxZoneFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > MaxZoneFileSize {
+		return nil, fmt.Errorf("%w: zone files hold at most %v bytes", ErrorInvalidZoneFile, MaxZoneFileSize)
+	}
+	lines, err := splitZoneFile(string(content))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := zoneFileEntries(lines, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var soaEntry *zoneFileEntry
+	for _, entry := range entries {
+		if entry.recordType != "SOA" {
+			continue
+		}
+		if soaEntry != nil {
+			return nil, fmt.Errorf("%w: line %v: zone files hold a single SOA record", ErrorInvalidZoneFile, entry.line)
+		}
+		soaEntry = entry
+	}
+	if soaEntry == nil {
+		return nil, fmt.Errorf("%w: SOA record is missing", ErrorInvalidZoneFile)
+	}
+	if domainName == "" {
+		domainName = soaEntry.owner
+	}
+	zoneDomain := NormalizeDomain(domainName)
+	if NormalizeDomain(soaEntry.owner) != zoneDomain {
+		return nil, fmt.Errorf("%w: line %v: SOA record is not at the apex of %v", ErrorInvalidZoneFile,
+			soaEntry.line, zoneDomain)
+	}
+
+	zone := NewZone(zoneDomain)
+	soa, err := zoneFileSOA(soaEntry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidZoneFile, soaEntry.line, err)
+	}
+	err = zone.RegisterSOA(soa)
+	if err != nil {
+		return nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidZoneFile, soaEntry.line, err)
+	}
+
+	for _, entry := range entries {
+		if entry.recordType == "SOA" || containsString(zoneFileSignatureTypes, entry.recordType) {
+			continue
+		}
+		err = zone.addZoneFileRecord(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidZoneFile, entry.line, err)
+		}
+	}
+	return zone, nil
+}
+
+func (z *Zone) addZoneFileRecord(entry *zoneFileEntry) error {
+	if !containsString(zoneFileRecordTypes, entry.recordType) {
+		return fmt.Errorf("%v records are not supported", entry.recordType)
+	}
+	data := append([]string(nil), entry.data...)
+	if index, ok := recordTargetFields[entry.recordType]; ok {
+		if HasRecordPriority(entry.recordType) {
+			index++
+		}
+		if index < len(data) && data[index] != "." {
+			target, err := qualifyZoneFileName(data[index], entry.origin)
+			if err != nil {
+				return err
+			}
+			data[index] = z.zoneFileTarget(target)
+		}
+	}
+
+	record := NewRecord(z.zoneFileName(entry.owner), entry.recordType, strings.Join(data, " "))
+	if HasRecordPriority(record.Type) && !record.ExtractPriority() {
+		return fmt.Errorf("%v record has no valid priority", record.Type)
+	}
+	err := z.CheckRecordValue(record)
+	if err != nil {
+		return err
+	}
+	if !z.IsValidRecord(record) {
+		return fmt.Errorf("%v record is not valid", record.Type)
+	}
+	err = z.CheckApexRecord(record)
+	if err != nil {
+		return err
+	}
+	return z.AddRecord(record)
+}
+
+// zoneFileName returns a fully qualified name the way the records hold it: @ for the apex, relative to the zone
+// under it and fully qualified outside of it.
+func (z *Zone) zoneFileName(fqdn string) string {
+	name := strings.TrimSuffix(fqdn, ".")
+	zoneDomain := NormalizeDomain(z.Domain)
+	lowerName := strings.ToLower(name)
+	if lowerName == zoneDomain {
+		return "@"
+	}
+	if strings.HasSuffix(lowerName, "."+zoneDomain) {
+		return name[:len(name)-len(zoneDomain)-1]
+	}
+	return fqdn
+}
+
+// zoneFileTarget returns a fully qualified target like zoneFileName, the targets relative to the zone being kept
+// fully qualified when they hold dots as QualifyName would take them for fully qualified ones.
+func (z *Zone) zoneFileTarget(fqdn string) string {
+	name := z.zoneFileName(fqdn)
+	if strings.Contains(name, ".") && !strings.HasSuffix(name, ".") {
+		return fqdn
+	}
+	return name
+}
+
+func zoneFileSOA(entry *zoneFileEntry) (*SOARecord, error) {
+	if len(entry.data) != 7 {
+		return nil, errors.New("SOA record needs a primary name server, a mail address, a serial and 4 timers")
+	}
+	primaryNS, err := qualifyZoneFileName(entry.data[0], entry.origin)
+	if err != nil {
+		return nil, err
+	}
+	mailAddress, err := qualifyZoneFileName(entry.data[1], entry.origin)
+	if err != nil {
+		return nil, err
+	}
+	soa := NewDefaultSOARecord(primaryNS, mailAddress)
+	// The serial is kept for the secondaries, it is moved ahead by the strategy the next time the zone is written.
+	soa.Serial = entry.data[2]
+	timers := []*int{&soa.Refresh, &soa.Retry, &soa.Expire, &soa.CacheTTL}
+	for i, timer := range timers {
+		*timer, err = parseZoneFileTTL(entry.data[3+i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !soa.IsValid() {
+		return nil, errors.New("invalid SOA")
+	}
+	return soa, nil
+}
+
+// zoneFileEntries resolves the directives and the owners of the lines, the owners being qualified with the origin
+// of their line, domainName until a $ORIGIN.
+func zoneFileEntries(lines []*zoneFileLine, domainName string) ([]*zoneFileEntry, error) {
+	origin := ""
+	if domainName != "" {
+		origin = NormalizeDomain(domainName) + "."
+	}
+	owner := ""
+	var entries []*zoneFileEntry
+	for _, line := range lines {
+		tokens := line.tokens
+		if !line.indented && strings.HasPrefix(tokens[0], "$") {
+			switch strings.ToUpper(tokens[0]) {
+			case "$ORIGIN":
+				if len(tokens) < 2 {
+					return nil, fmt.Errorf("%w: line %v: $ORIGIN needs a domain", ErrorInvalidZoneFile, line.number)
+				}
+				qualified, err := qualifyZoneFileName(tokens[1], origin)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidZoneFile, line.number, err)
+				}
+				origin = qualified
+			case "$TTL":
+			default:
+				return nil, fmt.Errorf("%w: line %v: %v is not supported", ErrorInvalidZoneFile, line.number,
+					tokens[0])
+			}
+			continue
+		}
+
+		if !line.indented {
+			qualified, err := qualifyZoneFileName(tokens[0], origin)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %v: %v", ErrorInvalidZoneFile, line.number, err)
+			}
+			owner = qualified
+			tokens = tokens[1:]
+		} else if owner == "" {
+			return nil, fmt.Errorf("%w: line %v: record has no owner", ErrorInvalidZoneFile, line.number)
+		}
+		// The TTL and the class come in any order before the type.
+		for len(tokens) > 0 {
+			upper := strings.ToUpper(tokens[0])
+			if upper == "CH" || upper == "HS" || upper == "CS" {
+				return nil, fmt.Errorf("%w: line %v: only IN records are supported", ErrorInvalidZoneFile,
+					line.number)
+			}
+			if upper != "IN" && !zoneFileTTLPattern.MatchString(upper) {
+				break
+			}
+			tokens = tokens[1:]
+		}
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("%w: line %v: record needs a type and a value", ErrorInvalidZoneFile, line.number)
+		}
+		entries = append(entries, &zoneFileEntry{
+			line:       line.number,
+			owner:      owner,
+			origin:     origin,
+			recordType: strings.ToUpper(tokens[0]),
+			data:       tokens[1:],
+		})
+	}
+	return entries, nil
+}
+
+// qualifyZoneFileName returns the name fully qualified with origin, @ standing for origin.
+func qualifyZoneFileName(name string, origin string) (string, error) {
+	if strings.HasSuffix(name, ".") {
+		return name, nil
+	}
+	if origin == "" {
+		return "", fmt.Errorf("%v is relative while the origin is unknown", name)
+	}
+	if name == "@" {
+		return origin, nil
+	}
+	return name + "." + origin, nil
+}
+
+// parseZoneFileTTL parses a TTL in seconds, or made of units like 1h30m.
+func parseZoneFileTTL(value string) (int, error) {
+	if !zoneFileTTLPattern.MatchString(value) {
+		return 0, fmt.Errorf("%v is not a valid TTL", value)
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, number := 0, 0
+	for _, c := range []byte(strings.ToLower(value)) {
+		if c >= '0' && c <= '9' {
+			number = number*10 + int(c-'0')
+			continue
+		}
+		total += number * units[c]
+		number = 0
+	}
+	return total + number, nil
+}
+
+// splitZoneFile splits a zone file into its logical lines. The comments are left out, the lines continued by
+// parentheses are joined and the quoted strings are kept as single tokens, quotes included.
+func splitZoneFile(content string) ([]*zoneFileLine, error) {
+	var lines []*zoneFileLine
+	line := &zoneFileLine{number: 1}
+	var token strings.Builder
+	number, depth, atLineStart, quoted := 1, 0, true, false
+	flush := func() {
+		if token.Len() > 0 {
+			line.tokens = append(line.tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if quoted {
+			token.WriteByte(c)
+			switch {
+			case c == '\\' && i+1 < len(content):
+				i++
+				token.WriteByte(content[i])
+			case c == '"':
+				quoted = false
+			case c == '\n':
+				number++
+			}
+			continue
+		}
+		switch c {
+		case ';':
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+		case '"':
+			quoted = true
+			token.WriteByte(c)
+		case '\\':
+			token.WriteByte(c)
+			if i+1 < len(content) {
+				i++
+				token.WriteByte(content[i])
+			}
+		case '(':
+			flush()
+			depth++
+		case ')':
+			flush()
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%w: line %v: unbalanced parentheses", ErrorInvalidZoneFile, number)
+			}
+		case ' ', '\t', '\r':
+			flush()
+			if atLineStart && len(line.tokens) == 0 {
+				line.indented = true
+			}
+		case '\n':
+			flush()
+			number++
+			if depth > 0 {
+				continue
+			}
+			if len(line.tokens) > 0 {
+				lines = append(lines, line)
+			}
+			line = &zoneFileLine{number: number}
+			atLineStart = true
+			continue
+		default:
+			token.WriteByte(c)
+		}
+		atLineStart = false
+	}
+	if quoted || depth > 0 {
+		return nil, fmt.Errorf("%w: line %v: unterminated quote or parenthesis", ErrorInvalidZoneFile, number)
+	}
+	flush()
+	if len(line.tokens) > 0 {
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
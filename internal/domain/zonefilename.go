@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// zoneFileDomainLength bounds the part of a zone file name taken from the domain, file names being limited to 255
+// bytes, the suffixes of the views and of the journals of named included.
+const zoneFileDomainLength = 100
+
+// ZoneFileName returns the name the zone file of the zone of domainName is given: the domain lowercased, the
+// characters unsafe in file names, e.g. those of internationalized names, replaced by '_' and cut to
+// zoneFileDomainLength bytes, followed by a hash of the domain. The hash keeps apart the domains only differing by the
+// characters replaced or by the end cut.
+func ZoneFileName(domainName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(domainName))
+	if len(name) > zoneFileDomainLength {
+		name = name[:zoneFileDomainLength]
+	}
+	sum := sha256.Sum256([]byte(domainName))
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
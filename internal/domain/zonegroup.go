@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var zoneGroupPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+var ErrorInvalidZoneGroup = errors.New("zone groups are made of lowercase letters, digits and '-'")
+
+// IsValidZoneGroup tells whether group may group zones, e.g. a tenant or a view, the zone files of a group being kept
+// in a folder of their own, see Config.ZoneFolderPath. The empty group keeps the zone files in the bind folder.
+func IsValidZoneGroup(group string) bool {
+	return group == "" || zoneGroupPattern.MatchString(group)
+}
+
+// ParseZoneGroupPaths parses the folders of the zone groups, e.g. "tenant-a=/srv/zones/a,tenant-b=tenants/b", the
+// relative ones being relative to the bind folder.
+func ParseZoneGroupPaths(value string) (map[string]string, error) {
+	paths := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		group, path := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			group, path = pair[:i], pair[i+1:]
+		}
+		group, path = strings.TrimSpace(group), strings.TrimSpace(path)
+		if group == "" || !IsValidZoneGroup(group) {
+			return nil, fmt.Errorf("%v: %w", pair, ErrorInvalidZoneGroup)
+		}
+		if path == "" {
+			return nil, fmt.Errorf("%v: zone group needs a folder", pair)
+		}
+		if _, ok := paths[group]; ok {
+			return nil, fmt.Errorf("zone group %v is defined twice", group)
+		}
+		paths[group] = path
+	}
+	return paths, nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "fmt"
+
+// ZoneLimitWarnRatio is the share of a zone limit past which the changes of the zone are warned about.
+const ZoneLimitWarnRatio = 0.8
+
+// ZoneLimitSettings are the sizes the zones are meant to stay below, e.g. to plan splitting the largest ones before
+// their transfers and reloads slow down. A zero limit leaves the size unlimited. No change is refused, the changes
+// growing a zone past ZoneLimitWarnRatio of a limit are warned about.
+type ZoneLimitSettings struct {
+	MaxRecords int `json:"max_records"`
+	// MaxFileSize is in bytes, the zone file being the one named serves.
+	MaxFileSize int64 `json:"max_file_size"`
+}
+
+func (l *ZoneLimitSettings) IsValid() bool {
+	return l.MaxRecords >= 0 && l.MaxFileSize >= 0
+}
+
+// ZoneSize is the size of a zone: its own records, those of its fragments left out, and its zone file, 0 while it is
+// not written.
+type ZoneSize struct {
+	Records  int
+	FileSize int64
+}
+
+// Warnings returns a WarningZoneSize per limit the size is past ZoneLimitWarnRatio of.
+func (l *ZoneLimitSettings) Warnings(size *ZoneSize) []*ValidationWarning {
+	var warnings []*ValidationWarning
+	if l.MaxRecords > 0 && float64(size.Records) >= ZoneLimitWarnRatio*float64(l.MaxRecords) {
+		warnings = append(warnings, &ValidationWarning{
+			Code: WarningZoneSize,
+			Message: fmt.Sprintf("zone has %v records, %v%% of the limit of %v records", size.Records,
+				size.Records*100/l.MaxRecords, l.MaxRecords),
+		})
+	}
+	if l.MaxFileSize > 0 && float64(size.FileSize) >= ZoneLimitWarnRatio*float64(l.MaxFileSize) {
+		warnings = append(warnings, &ValidationWarning{
+			Code: WarningZoneSize,
+			Message: fmt.Sprintf("zone file has %v bytes, %v%% of the limit of %v bytes", size.FileSize,
+				size.FileSize*100/l.MaxFileSize, l.MaxFileSize),
+		})
+	}
+	return warnings
+}
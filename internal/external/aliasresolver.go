@@ -0,0 +1,146 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const aliasResolveTimeout = 5 * time.Second
+
+type aliasResolver struct {
+	zoneRepo domain.ZoneRepository
+	interval time.Duration
+	onChange func(ctx context.Context) error
+	resolver *net.Resolver
+
+	addressLock sync.RWMutex
+	addresses   map[string][]string
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewAliasResolver creates a resolver keeping the addresses of the targets of the ALIAS records, resolved every
+// interval. onChange is called whenever the addresses of a target change, so the zone files can be regenerated.
+func NewAliasResolver(
+	zoneRepo domain.ZoneRepository, interval time.Duration, onChange func(ctx context.Context) error,
+) domain.AliasResolver {
+	return &aliasResolver{
+		zoneRepo:       zoneRepo,
+		interval:       interval,
+		onChange:       onChange,
+		resolver:       &net.Resolver{},
+		addresses:      map[string][]string{},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (a *aliasResolver) Start(ctx context.Context) {
+	a.stoppedWg.Add(1)
+	go func() {
+		defer a.stoppedWg.Done()
+
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.shutdownSignal:
+				return
+			case <-ticker.C:
+				a.resolveAll(ctx)
+			}
+		}
+	}()
+}
+
+func (a *aliasResolver) Shutdown(ctx context.Context) error {
+	a.shutdownSignal <- 1
+	a.stoppedWg.Wait()
+	return nil
+}
+
+func (a *aliasResolver) Addresses(ctx context.Context, target string) []string {
+	target = domain.NormalizeDomain(target)
+
+	a.addressLock.RLock()
+	addresses, ok := a.addresses[target]
+	a.addressLock.RUnlock()
+	if ok {
+		return addresses
+	}
+
+	addresses, err := a.resolve(ctx, target)
+	if err != nil {
+		log.Printf("ALIAS target %v could not be resolved: %v\n", target, err)
+		return nil
+	}
+	a.addressLock.Lock()
+	a.addresses[target] = addresses
+	a.addressLock.Unlock()
+	return addresses
+}
+
+func (a *aliasResolver) resolveAll(ctx context.Context) {
+	zones, err := a.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	changed := false
+	resolved := map[string][]string{}
+	for _, target := range domain.AliasTargets(zones) {
+		a.addressLock.RLock()
+		previous, ok := a.addresses[target]
+		a.addressLock.RUnlock()
+
+		addresses, err := a.resolve(ctx, target)
+		if err != nil {
+			log.Printf("ALIAS target %v could not be resolved, keeping its last addresses: %v\n", target, err)
+			addresses = previous
+		}
+		if ok && strings.Join(addresses, ",") != strings.Join(previous, ",") {
+			log.Printf("Addresses of ALIAS target %v changed to %v\n", target, addresses)
+			changed = true
+		}
+		if addresses != nil {
+			resolved[target] = addresses
+		}
+	}
+
+	a.addressLock.Lock()
+	a.addresses = resolved
+	a.addressLock.Unlock()
+
+	if changed && a.onChange != nil {
+		err = a.onChange(ctx)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func (a *aliasResolver) resolve(ctx context.Context, target string) ([]string, error) {
+	resolveCtx, cancel := context.WithTimeout(ctx, aliasResolveTimeout)
+	defer cancel()
+
+	ips, err := a.resolver.LookupIPAddr(resolveCtx, target)
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addresses = append(addresses, ip.IP.String())
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
@@ -0,0 +1,243 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	auditExportTimeout = 5 * time.Second
+	// auditExportQueueSize is how many events wait to be exported at most, the next ones being dropped while the
+	// collectors are slow.
+	auditExportQueueSize = 1000
+	// auditExportAttempts is how many times an event is sent to an exporter, auditExportRetryDelay apart doubling on
+	// every attempt.
+	auditExportAttempts   = 3
+	auditExportRetryDelay = time.Second
+
+	syslogAppName = "dns-server-manager"
+	// syslogPriority is the log audit facility (13) with the notice severity (5).
+	syslogPriority = 13*8 + 5
+	// syslogChangeSdId names the structured data element holding the change metadata, 32473 being the enterprise
+	// number reserved for documentation by RFC 5612.
+	syslogChangeSdId    = "change@32473"
+	syslogTimestampForm = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+type auditLogExporter struct {
+	auditRepo domain.AuditLogRepository
+	client    *http.Client
+	hostname  string
+
+	// syslogConns holds the connections to the syslog exporters by protocol and address, used by the worker alone.
+	syslogConns map[string]net.Conn
+
+	events         chan *domain.ChangeEvent
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewAuditLogExporter creates a listener forwarding every change event to the enabled audit exporters.
+func NewAuditLogExporter(auditRepo domain.AuditLogRepository) domain.AuditLogExporter {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &auditLogExporter{
+		auditRepo:      auditRepo,
+		client:         &http.Client{Timeout: auditExportTimeout},
+		hostname:       hostname,
+		syslogConns:    map[string]net.Conn{},
+		events:         make(chan *domain.ChangeEvent, auditExportQueueSize),
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+// Start exports the queued events one after the other, out of the outbox relay for it not to wait on the collectors.
+func (a *auditLogExporter) Start(ctx context.Context) {
+	a.stoppedWg.Add(1)
+	go func() {
+		defer a.stoppedWg.Done()
+		defer a.closeSyslogConns(nil)
+
+		for {
+			select {
+			case <-a.shutdownSignal:
+				return
+			case event := <-a.events:
+				if !a.export(ctx, event) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Shutdown stops exporting, the events still queued being left out. They stay in the audit log.
+func (a *auditLogExporter) Shutdown(ctx context.Context) error {
+	a.shutdownSignal <- 1
+	a.stoppedWg.Wait()
+	if dropped := len(a.events); dropped > 0 {
+		log.Printf("%v audit events are not exported\n", dropped)
+	}
+	return nil
+}
+
+func (a *auditLogExporter) OnChangeEvent(ctx context.Context, event *domain.ChangeEvent) {
+	select {
+	case a.events <- event:
+	default:
+		log.Printf("Unable to export %v, the audit export queue is full\n", event.Type)
+	}
+}
+
+// export sends the event to the enabled exporters, retrying the failed ones. It returns false once Shutdown is called
+// while waiting to retry.
+func (a *auditLogExporter) export(ctx context.Context, event *domain.ChangeEvent) bool {
+	exporters, err := a.auditRepo.GetAllExporters(ctx)
+	if err != nil {
+		log.Println(err)
+		return true
+	}
+	// The connections of the exporters removed or disabled since are closed.
+	a.closeSyslogConns(exporters)
+
+	for _, exporter := range exporters {
+		if !exporter.Enabled {
+			continue
+		}
+		delay := auditExportRetryDelay
+		for attempt := 1; ; attempt++ {
+			switch exporter.Type {
+			case domain.AuditExporterTypeSyslog:
+				err = a.exportSyslog(exporter, event)
+			case domain.AuditExporterTypeHttp:
+				err = a.exportHttp(ctx, exporter, event)
+			}
+			if err == nil {
+				break
+			}
+			if attempt == auditExportAttempts {
+				log.Printf("Unable to export %v to %v: %v\n", event.Type, exporter.Address, err)
+				break
+			}
+			select {
+			case <-a.shutdownSignal:
+				log.Printf("Unable to export %v to %v: %v\n", event.Type, exporter.Address, err)
+				return false
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+	return true
+}
+
+func (a *auditLogExporter) exportSyslog(exporter *domain.AuditExporter, event *domain.ChangeEvent) error {
+	message, err := a.formatSyslog(event)
+	if err != nil {
+		return err
+	}
+	// Messages sent over a stream are framed with octet counting (RFC 6587).
+	if exporter.Protocol == domain.AuditExporterProtocolTcp {
+		message = fmt.Sprintf("%d %v", len(message), message)
+	}
+
+	key := syslogConnKey(exporter)
+	conn, ok := a.syslogConns[key]
+	if !ok {
+		conn, err = net.DialTimeout(exporter.Protocol, exporter.Address, auditExportTimeout)
+		if err != nil {
+			return err
+		}
+		a.syslogConns[key] = conn
+	}
+
+	err = conn.SetWriteDeadline(time.Now().Add(auditExportTimeout))
+	if err == nil {
+		_, err = conn.Write([]byte(message))
+	}
+	if err != nil {
+		// The connection is dialed again on the next attempt.
+		conn.Close()
+		delete(a.syslogConns, key)
+	}
+	return err
+}
+
+// closeSyslogConns closes the connections to the syslog exporters which are not among the enabled exporters.
+func (a *auditLogExporter) closeSyslogConns(exporters []*domain.AuditExporter) {
+	enabled := map[string]bool{}
+	for _, exporter := range exporters {
+		if exporter.Enabled && exporter.Type == domain.AuditExporterTypeSyslog {
+			enabled[syslogConnKey(exporter)] = true
+		}
+	}
+	for key, conn := range a.syslogConns {
+		if !enabled[key] {
+			conn.Close()
+			delete(a.syslogConns, key)
+		}
+	}
+}
+
+func syslogConnKey(exporter *domain.AuditExporter) string {
+	return exporter.Protocol + " " + exporter.Address
+}
+
+// formatSyslog renders an RFC 5424 message, the change metadata goes to the structured data and the JSON payload of
+// the event to the message body.
+func (a *auditLogExporter) formatSyslog(event *domain.ChangeEvent) (string, error) {
+	payload, err := json.Marshal(event.Payload())
+	if err != nil {
+		return "", err
+	}
+
+	structuredData := "-"
+	if event.Change != nil {
+		structuredData = fmt.Sprintf(`[%v ticketId="%v" reason="%v" requestedBy="%v"]`, syslogChangeSdId,
+			syslogParamEscaper.Replace(event.Change.TicketId), syslogParamEscaper.Replace(event.Change.Reason),
+			syslogParamEscaper.Replace(event.Change.RequestedBy))
+	}
+
+	return fmt.Sprintf("<%d>1 %v %v %v %d %v %v %s", syslogPriority, event.Time.UTC().Format(syslogTimestampForm),
+		a.hostname, syslogAppName, os.Getpid(), event.Type, structuredData, payload), nil
+}
+
+func (a *auditLogExporter) exportHttp(
+	ctx context.Context, exporter *domain.AuditExporter, event *domain.ChangeEvent,
+) error {
+	payload, err := json.Marshal(event.Payload())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, exporter.Address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testAuditLogRepository struct {
+	domain.AuditLogRepository
+	exporters []*domain.AuditExporter
+}
+
+func (r *testAuditLogRepository) GetAllExporters(ctx context.Context) ([]*domain.AuditExporter, error) {
+	return r.exporters, nil
+}
+
+func TestAuditLogExporterKeepsTheSyslogConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	var accepted int32
+	messages := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					// The octet counts frame the messages, each ending with its JSON payload.
+					message, err := reader.ReadString('}')
+					if err != nil {
+						return
+					}
+					messages <- message
+				}
+			}()
+		}
+	}()
+
+	exporter := domain.NewAuditExporter(domain.AuditExporterTypeSyslog, listener.Addr().String())
+	exporter.Protocol = domain.AuditExporterProtocolTcp
+	a := NewAuditLogExporter(&testAuditLogRepository{exporters: []*domain.AuditExporter{exporter}})
+	a.Start(context.Background())
+	defer a.Shutdown(context.Background())
+
+	for i := 0; i < 2; i++ {
+		a.OnChangeEvent(context.Background(), &domain.ChangeEvent{Type: domain.EventZoneUpdated, Time: time.Now()})
+		select {
+		case <-messages:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the event to be exported")
+		}
+	}
+	if n := atomic.LoadInt32(&accepted); n != 1 {
+		t.Fatalf("expected the events to be sent over a single connection, got %v", n)
+	}
+}
+
+func TestAuditLogExporterRetriesTheFailedExports(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	exporter := domain.NewAuditExporter(domain.AuditExporterTypeHttp, server.URL)
+	a := NewAuditLogExporter(&testAuditLogRepository{exporters: []*domain.AuditExporter{exporter}})
+	a.Start(context.Background())
+	a.OnChangeEvent(context.Background(), &domain.ChangeEvent{Type: domain.EventZoneUpdated, Time: time.Now()})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	err := a.Shutdown(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Fatalf("expected the event to be sent again once failed, got %v requests", n)
+	}
+}
@@ -7,79 +7,554 @@ import (
 	"github.com/anantadwi13/dns-server-manager/internal/domain"
 	"github.com/pkg/errors"
 	"log"
+	"net"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
+// queryLogPattern matches the query lines named logs when querylog is enabled, e.g.
+// client @0x7f2c 192.0.2.1#53210 (www.example.com): query: www.example.com IN A +E(0)K (192.0.2.53) [ECS 198.51.100.0/24/0]
+var queryLogPattern = regexp.MustCompile(
+	`client (?:@0x[0-9a-f]+ )?([0-9a-fA-F:.]+)#\d+ \([^)]*\): (?:view \S+: )?query: (\S+) \S+ (\S+) \S+ \([^)]*\)(?: \[ECS ([^\]/]+/\d+)[^\]]*\])?`,
+)
+
+const (
+	bindWorkingDirectory = "/var/cache/bind"
+	bindDumpFile         = "named_dump.db"
+	bindLocalConf        = "named.conf.local"
+	bindOptionsConf      = "named.conf.options"
+	bindDefaultZonesConf = "named.conf.default-zones"
+	zoneFilePrefix       = "db-"
+	cacheDumpTimeout     = 10 * time.Second
+	namedCheckConfPath   = "/usr/sbin/named-checkconf"
+	namedConfCheckSuffix = ".check"
+	// optionsConfOrigSuffix names the named.conf.options kept as it was before being migrated.
+	optionsConfOrigSuffix = ".orig"
+	namedCheckZonePath    = "/usr/sbin/named-checkzone"
+	digPath               = "/usr/bin/dig"
+	bindUser              = "bind"
+	zoneTransferTimeout   = 2 * time.Minute
+)
+
+// The custom default zones and the managed root hints are written next to the files of the image, which are left as
+// they are.
+const (
+	managedDefaultZonesConf = "named.conf.default-zones.managed"
+	managedRootHints        = "db.root.managed"
+)
+
+// signedZoneSuffixes are the suffixes of the files named keeps next to a signed zone file: the signed zone and the
+// journals of the inline signing.
+var signedZoneSuffixes = []string{".signed", ".signed.jnl", ".jnl"}
+
+// named is restarted once it crashed, the delay doubling after each crash in a row up to namedRestartMaxDelay. A crash
+// after named ran for namedStableAfter starts over from namedRestartMinDelay.
+const (
+	namedRestartMinDelay = time.Second
+	namedRestartMaxDelay = time.Minute
+	namedStableAfter     = 5 * time.Minute
+)
+
+// namedHealthyAttempts bounds the seconds named is given to answer rndc once reloaded, loading its zones included.
+const namedHealthyAttempts = 30
+
 type bind9Server struct {
 	config         domain.Config
+	settings       domain.SettingsProvider
 	zoneRepo       domain.ZoneRepository
+	serverRepo     domain.ServerRepository
+	rpzRepo        domain.RpzRepository
+	fragmentRepo   domain.FragmentRepository
+	tsigKeyRepo    domain.TSIGKeyRepository
+	forwarders     domain.ForwarderMonitor
+	aliases        domain.AliasResolver
+	queryListeners []domain.QueryLogListener
+	// startLock keeps two reloads from starting named twice.
+	startLock sync.Mutex
+	// applyLock keeps a reload from writing its configs while another one restores those it replaced.
+	applyLock      sync.Mutex
 	numLock        sync.RWMutex
 	numCmds        int
 	runningCmdsWg  sync.WaitGroup
 	shutdownSignal chan int
-	reloadSignal   chan int
+	// stopped is closed by Shutdown, named is not started anymore once it is.
+	stopped chan struct{}
+
+	lastReloadLock sync.RWMutex
+	lastReload     *domain.ReloadResult
+	// leftOut lists the zones the last update of the configs left out, see domain.ReloadResult.LeftOut.
+	leftOut []string
+
+	crashLock sync.RWMutex
+	lastCrash *domain.NamedCrash
+	// crashesInRow counts the crashes of named since it last ran for namedStableAfter.
+	crashesInRow int
 }
 
-func NewBind9Server(config domain.Config, zoneRepo domain.ZoneRepository) domain.DNSServer {
+func NewBind9Server(
+	config domain.Config, settings domain.SettingsProvider, zoneRepo domain.ZoneRepository,
+	serverRepo domain.ServerRepository, rpzRepo domain.RpzRepository, fragmentRepo domain.FragmentRepository,
+	tsigKeyRepo domain.TSIGKeyRepository, forwarders domain.ForwarderMonitor, aliases domain.AliasResolver,
+) domain.DNSServer {
 	return &bind9Server{
 		config:         config,
+		settings:       settings,
 		zoneRepo:       zoneRepo,
+		serverRepo:     serverRepo,
+		rpzRepo:        rpzRepo,
+		fragmentRepo:   fragmentRepo,
+		tsigKeyRepo:    tsigKeyRepo,
+		forwarders:     forwarders,
+		aliases:        aliases,
 		shutdownSignal: make(chan int, 1),
-		reloadSignal:   make(chan int, 1),
+		stopped:        make(chan struct{}),
 	}
 }
 
 func (b *bind9Server) UpdateConfigs(ctx context.Context) error {
+	_, _, err := b.updateConfigs(ctx)
+	return err
+}
+
+// updateConfigs generates named.conf and the zone files, returning the zones named.conf refers to at their current
+// revision, the zones whose changes are deferred by their notify window being left out, and the path of every zone
+// file, the response policy zones included.
+func (b *bind9Server) updateConfigs(ctx context.Context) ([]*domain.Zone, []string, error) {
+	zones, err := b.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	options, err := b.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	rpzZones, err := b.generateRpzZones(ctx, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	tsigKeys, err := b.tsigKeyRepo.GetAllTSIGKeys(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	views := b.servedViews(options)
+	now := time.Now()
+	deferred, kept := map[string]bool{}, map[string]bool{}
+	for _, zone := range zones {
+		if !fileExists(zone.FilePath) {
+			continue
+		}
+		if zone.PublishDeferred(now) {
+			deferred[zone.Id] = true
+			kept[zone.Id] = true
+		}
+		// The records of the frozen zones do not change, their zone file is served as it is at the same serial.
+		if zone.IsFrozen() {
+			kept[zone.Id] = true
+		}
+	}
+	failures, err := b.generateDbRecords(ctx, zones, kept, views)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A zone whose zone file fails to load is left out rather than failing the reload of every zone, named serving the
+	// zone file it was last written to, if any, and the zone staying pending.
+	var leftOut []string
+	for _, zone := range zones {
+		if err, ok := failures[zone.Id]; ok {
+			log.Println("Zone", zone.Domain, "is left out of the reload:", err)
+			leftOut = append(leftOut, fmt.Sprintf("%v: %v", zone.Domain, err))
+		}
+	}
+	b.lastReloadLock.Lock()
+	b.leftOut = leftOut
+	b.lastReloadLock.Unlock()
+	err = b.generateKeyDirectories(zones, views)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = generateRndcKey(b.config)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The zone files are written first, the views refer to the zone files of their own records once they exist.
+	err = b.generateNamedConf(ctx, options, zones, rpzZones, tsigKeys, views)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var servedZones []*domain.Zone
+	var zoneFiles []string
+	for _, zone := range zones {
+		if !zone.IsValid() {
+			continue
+		}
+		if _, failed := failures[zone.Id]; !deferred[zone.Id] && !failed && fileExists(zone.FilePath) {
+			servedZones = append(servedZones, zone)
+		}
+		filePaths := []string{zone.FilePath}
+		for _, view := range views {
+			if path := viewZoneFilePath(zone, view.Name); zone.ServedInView(view.Name) && fileExists(path) {
+				filePaths = append(filePaths, path)
+			}
+		}
+		for _, filePath := range filePaths {
+			zoneFiles = append(zoneFiles, filePath)
+			// named keeps the signed zone and the journals of the inline signing next to the zone file.
+			if b.signsZone(zone) {
+				for _, suffix := range signedZoneSuffixes {
+					zoneFiles = append(zoneFiles, filePath+suffix)
+				}
+			}
+		}
+	}
+	for _, rpzZone := range rpzZones {
+		zoneFiles = append(zoneFiles, b.rpzZoneFilePath(rpzZone))
+	}
+	return servedZones, zoneFiles, nil
+}
+
+// checkNamedConf loads the named.conf at confPath and every zone file it refers to the way named would, without
+// serving them.
+func checkNamedConf(ctx context.Context, confPath string) error {
+	output, err := exec.CommandContext(ctx, namedCheckConfPath, "-z", confPath).CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%w: %v", domain.ErrorConfigCheckFailed, checkZoneFailure(string(output), confPath))
+	}
+	return err
+}
+
+func (b *bind9Server) RepairConsistency(
+	ctx context.Context, removeUnknownFiles bool,
+) (*domain.ConsistencyReport, error) {
+	report := domain.NewConsistencyReport()
+
 	zones, err := b.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = b.renameZoneFiles(ctx, zones, report)
+	if err != nil {
+		return nil, err
+	}
+	// The records stored before the owner names were validated are kept, but named would refuse them.
+	for _, zone := range zones {
+		for _, record := range zone.InvalidOwnerNameRecords() {
+			report.Add(domain.ConsistencyActionSkipped, zone.FilePath, zone.Domain, fmt.Sprintf(
+				"%q is not a valid owner name, its %v record is left out of the zone file", record.Name, record.Type))
+		}
+	}
+	var missingZones []*domain.Zone
+	for _, zone := range zones {
+		if zone.IsValid() && !fileExists(zone.FilePath) {
+			missingZones = append(missingZones, zone)
+		}
+	}
+
+	// named refuses to start when an included file is missing, the local config only holds the zones added by hand
+	// so an empty one is a safe replacement.
+	localConfPath := filepath.Join(b.config.BindFolderPath(), bindLocalConf)
+	if !fileExists(localConfPath) {
+		err := writeFile(localConfPath, "")
+		if err != nil {
+			return nil, err
+		}
+		report.Add(domain.ConsistencyActionRecreated, localConfPath, "", "")
+	}
+	err = b.migrateOptionsConf(report)
+	if err != nil {
+		return nil, err
+	}
+	options, err := b.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defaultZonesPath := filepath.Join(b.config.BindFolderPath(), bindDefaultZonesConf)
+	if options.DefaultZones == domain.DefaultZonesImage && !fileExists(defaultZonesPath) {
+		report.Add(domain.ConsistencyActionMissing, defaultZonesPath, "", "included by named.conf")
+	}
+
+	_, zoneFiles, err := b.updateConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, zone := range missingZones {
+		if fileExists(zone.FilePath) {
+			report.Add(domain.ConsistencyActionRecreated, zone.FilePath, zone.Domain, "")
+		} else {
+			report.Add(domain.ConsistencyActionMissing, zone.FilePath, zone.Domain, "the zone has no valid SOA record")
+		}
+	}
+
+	knownFiles := map[string]bool{}
+	for _, zoneFile := range zoneFiles {
+		knownFiles[filepath.Clean(zoneFile)] = true
+	}
+	folders, err := b.zoneFolders(zones)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		entries, err := os.ReadDir(folder)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(folder, entry.Name())
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), zoneFilePrefix) || knownFiles[path] {
+				continue
+			}
+			if !removeUnknownFiles {
+				report.Add(domain.ConsistencyActionUnknown, path, "", "")
+				continue
+			}
+			err := os.Remove(path)
+			if err != nil {
+				report.Add(domain.ConsistencyActionUnknown, path, "", err.Error())
+				continue
+			}
+			report.Add(domain.ConsistencyActionRemoved, path, "", "")
+		}
+	}
+
+	for _, action := range report.Actions {
+		log.Println("Consistency:", action.Action, action.Path, action.Zone, action.Message)
+	}
+	return report, nil
+}
+
+// migrateOptionsConf turns the named.conf.options holding an options block, as named.conf included it before rendering
+// the options, into the options it includes within its options block, the previous file being kept next to it. An empty
+// one replaces a missing one, named refusing to start when an included file is missing.
+func (b *bind9Server) migrateOptionsConf(report *domain.ConsistencyReport) error {
+	optionsConfPath := filepath.Join(b.config.BindFolderPath(), bindOptionsConf)
+	contents, err := os.ReadFile(optionsConfPath)
+	if os.IsNotExist(err) {
+		err := writeFile(optionsConfPath, "")
+		if err != nil {
+			return err
+		}
+		report.Add(domain.ConsistencyActionRecreated, optionsConfPath, "", "")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	err = b.generateNamedConf(zones)
+	migrated, commented, ok := migrateOptionsConf(string(contents))
+	if !ok {
+		return nil
+	}
+	err = writeFile(optionsConfPath+optionsConfOrigSuffix, string(contents))
 	if err != nil {
 		return err
 	}
-	err = b.generateDbRecords(ctx, zones)
+	err = writeFile(optionsConfPath, migrated)
 	if err != nil {
 		return err
 	}
+	report.Add(domain.ConsistencyActionMigrated, optionsConfPath, "", fmt.Sprintf(
+		"the options block is unwrapped, %v statements are commented out, the previous file is kept as %v",
+		commented, bindOptionsConf+optionsConfOrigSuffix))
+	return nil
+}
+
+// renameZoneFiles gives the zone files named after the domain alone, as they were before domain.ZoneFileName, their
+// current name. The view zone files, the files named keeps next to the signed ones and the key directories are renamed
+// along, named.conf referring to the new names once updated. A zone whose files fail to be renamed keeps its former
+// name.
+func (b *bind9Server) renameZoneFiles(
+	ctx context.Context, zones []*domain.Zone, report *domain.ConsistencyReport,
+) error {
+	for _, zone := range zones {
+		if zone.FilePath == "" {
+			continue
+		}
+		folder, name := filepath.Split(zone.FilePath)
+		newName := zoneFilePrefix + domain.ZoneFileName(zone.Domain)
+		if name == newName {
+			continue
+		}
+		entries, err := os.ReadDir(folder)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		var renames [][2]string
+		for _, entry := range entries {
+			suffix := strings.TrimPrefix(entry.Name(), name)
+			if !isZoneFileSuffix(suffix) || suffix == entry.Name() {
+				continue
+			}
+			renames = append(renames, [2]string{filepath.Join(folder, entry.Name()), filepath.Join(folder, newName+suffix)})
+			if keyDirectory := b.keyDirectory(entry.Name()); !isSignedZoneSuffix(suffix) && fileExists(keyDirectory) {
+				renames = append(renames, [2]string{keyDirectory, b.keyDirectory(newName + suffix)})
+			}
+		}
+
+		var renamed [][2]string
+		for _, rename := range renames {
+			err = os.Rename(rename[0], rename[1])
+			if err != nil {
+				break
+			}
+			renamed = append(renamed, rename)
+		}
+		if err != nil {
+			log.Println("Consistency: renaming the zone files of", zone.Domain, "failed:", err)
+			for _, rename := range renamed {
+				if err := os.Rename(rename[1], rename[0]); err != nil {
+					log.Println(err)
+				}
+			}
+			continue
+		}
+		previousPath := zone.FilePath
+		zone.FilePath = filepath.Join(folder, newName)
+		err = b.zoneRepo.PersistFilePath(ctx, zone)
+		if err != nil {
+			return err
+		}
+		report.Add(domain.ConsistencyActionRenamed, zone.FilePath, zone.Domain, "renamed from "+previousPath)
+	}
 	return nil
 }
 
+// isZoneFileSuffix tells whether a file named after a zone file followed by suffix belongs to the zone: the zone file
+// itself, a view zone file, '@' being part of no domain name, or a file named keeps next to a signed zone file.
+func isZoneFileSuffix(suffix string) bool {
+	return suffix == "" || strings.HasPrefix(suffix, "@") || isSignedZoneSuffix(suffix)
+}
+
+func isSignedZoneSuffix(suffix string) bool {
+	for _, signedSuffix := range signedZoneSuffixes {
+		if suffix == signedSuffix {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSignedZoneSuffix(fileName string) bool {
+	for _, signedSuffix := range signedZoneSuffixes {
+		if strings.HasSuffix(fileName, signedSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneFolders returns the folders holding zone files: the bind folder, the folders of the zones and the folders of the
+// zone groups, those the zones moved out of included.
+func (b *bind9Server) zoneFolders(zones []*domain.Zone) ([]string, error) {
+	seen := map[string]bool{b.config.BindFolderPath(): true}
+	folders := []string{b.config.BindFolderPath()}
+	add := func(folder string) {
+		folder = filepath.Clean(folder)
+		if !seen[folder] {
+			seen[folder] = true
+			folders = append(folders, folder)
+		}
+	}
+	for _, zone := range zones {
+		if zone.FilePath != "" {
+			add(filepath.Dir(zone.FilePath))
+		}
+	}
+	entries, err := os.ReadDir(b.config.ZoneGroupsFolderPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			add(filepath.Join(b.config.ZoneGroupsFolderPath(), entry.Name()))
+		}
+	}
+	return folders, nil
+}
+
 func (b *bind9Server) Reload(ctx context.Context) error {
-	cmd := exec.Command("/usr/sbin/named", "-g", "-c", b.config.NamedConfPath(), "-u", "bind")
+	err := b.reload(ctx)
+	b.setLastReload(err)
+	return err
+}
+
+// reload starts named when it is not running, e.g. on the first reload or once it exited, and has the running named
+// load named.conf and the changed zone files again otherwise, named keeping its cache and answering meanwhile.
+func (b *bind9Server) reload(ctx context.Context) error {
+	b.startLock.Lock()
+	defer b.startLock.Unlock()
+
+	b.numLock.RLock()
+	numCmds := b.numCmds
+	b.numLock.RUnlock()
+	if numCmds == 0 {
+		return b.start()
+	}
+
+	// named answers rndc once it loaded its zones, which takes a while after it started.
+	err := waitRndc(ctx, b.config, 30, time.Second)
+	if err != nil {
+		return err
+	}
+	_, err = runRndc(ctx, b.config, "reload")
+	if err != nil {
+		return err
+	}
+	log.Println("Reload Bind9")
+	return nil
+}
+
+// start runs named until Shutdown is called or until it exits, its logs being read for the query log. named is
+// restarted when it exits on its own, see restart.
+func (b *bind9Server) start() error {
+	select {
+	case <-b.stopped:
+		return errors.New("named is shut down")
+	default:
+	}
+	cmd := exec.Command("/usr/sbin/named", "-g", "-c", b.config.NamedConfPath(), "-u", bindUser)
 	logs, err := cmd.StderrPipe()
 	if err != nil {
 		return err
 	}
 
-	b.numLock.RLock()
-	numCmds := b.numCmds
-	b.numLock.RUnlock()
-	for i := 0; i < numCmds; i++ {
-		b.reloadSignal <- 1
+	err = cmd.Start()
+	log.Println("Start Bind9")
+	if err != nil {
+		return err
 	}
 
-	done := make(chan error, 1)
+	startedAt := time.Now()
+	b.numLock.Lock()
+	b.numCmds++
+	b.numLock.Unlock()
+	b.runningCmdsWg.Add(1)
 
-	go func() {
-		err = cmd.Start()
-		log.Println("Start Bind9")
-		if err != nil {
-			log.Fatalln(err)
-		}
+	go b.restoreNegativeTrustAnchors()
 
-		b.numLock.Lock()
-		b.numCmds++
-		b.numLock.Unlock()
-		b.runningCmdsWg.Add(1)
+	done := make(chan error, 1)
 
+	go func() {
 		scanner := bufio.NewScanner(logs)
 		for scanner.Scan() {
 			m := scanner.Text()
 			log.Println(m)
+			if entry := parseQueryLogLine(m); entry != nil {
+				for _, listener := range b.queryListeners {
+					listener.OnQuery(entry)
+				}
+			}
 		}
 
 		done <- cmd.Wait()
@@ -96,108 +571,1237 @@ func (b *bind9Server) Reload(ctx context.Context) error {
 		select {
 		case <-b.shutdownSignal:
 			if err := cmd.Process.Kill(); err != nil {
-				log.Fatalln(err)
+				log.Println(err)
 			}
 			log.Println("Shutdown Bind9")
-		case <-b.reloadSignal:
-			if err := cmd.Process.Kill(); err != nil {
-				log.Fatalln(err)
-			}
-			log.Println("Reload Bind9")
 		case err := <-done:
-			if err != nil {
-				log.Fatalln(err)
+			if err == nil {
+				err = errors.New("exit status 0")
 			}
-			log.Println("Exit Bind9")
+			log.Println("Exit Bind9:", err)
+			go b.restart(b.crashed(err, startedAt))
 		}
 	}()
-	return err
+	return nil
 }
 
-func (b *bind9Server) UpdateAndReload(ctx context.Context) error {
-	err := b.UpdateConfigs(ctx)
-	if err != nil {
-		return err
+// crashed records that named exited with err, returning the delay before it is restarted.
+func (b *bind9Server) crashed(err error, startedAt time.Time) time.Duration {
+	b.crashLock.Lock()
+	defer b.crashLock.Unlock()
+	crashes := 1
+	if b.lastCrash != nil {
+		crashes = b.lastCrash.Crashes + 1
 	}
-	err = b.Reload(ctx)
-	if err != nil {
-		return err
+	b.lastCrash = &domain.NamedCrash{Time: time.Now(), Error: err.Error(), Crashes: crashes}
+	if time.Since(startedAt) >= namedStableAfter {
+		b.crashesInRow = 0
 	}
-	return nil
+	b.crashesInRow++
+
+	delay := namedRestartMinDelay
+	for i := 1; i < b.crashesInRow && delay < namedRestartMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > namedRestartMaxDelay {
+		delay = namedRestartMaxDelay
+	}
+	return delay
 }
 
-func (b *bind9Server) Shutdown(ctx context.Context) error {
+// restart starts named again after delay, unless a reload started it meanwhile or the service is shutting down. A
+// failure to start it counts as another crash.
+func (b *bind9Server) restart(delay time.Duration) {
+	select {
+	case <-b.stopped:
+		return
+	case <-time.After(delay):
+	}
+
+	b.startLock.Lock()
+	defer b.startLock.Unlock()
 	b.numLock.RLock()
 	numCmds := b.numCmds
 	b.numLock.RUnlock()
-	for i := 0; i < numCmds; i++ {
-		b.shutdownSignal <- 1
+	if numCmds == 0 {
+		err := b.start()
+		if err != nil {
+			log.Println("Restart Bind9:", err)
+			go b.restart(b.crashed(err, time.Now()))
+			return
+		}
+		log.Println("Restart Bind9 after", delay)
 	}
-	b.runningCmdsWg.Wait()
-	return nil
+
+	b.crashLock.Lock()
+	if b.lastCrash != nil {
+		b.lastCrash.Running = true
+	}
+	b.crashLock.Unlock()
 }
 
-func (b *bind9Server) generateNamedConf(zones []*domain.Zone) error {
-	fileContents := fmt.Sprintf(`include "%v"; include "%v"; include "%v";`+"\n",
-		filepath.Join(b.config.BindFolderPath(), "named.conf.options"),
-		filepath.Join(b.config.BindFolderPath(), "named.conf.local"),
-		filepath.Join(b.config.BindFolderPath(), "named.conf.default-zones"))
-	zoneFormat := `zone "%v" {type primary; file "%v";};` + "\n"
+func (b *bind9Server) UpdateAndReload(ctx context.Context) error {
+	zones, err := b.updateAndReload(ctx)
+	b.setLastReload(err)
+	if err != nil {
+		return err
+	}
 	for _, zone := range zones {
-		if !zone.IsValid() {
-			continue
+		err = b.zoneRepo.MarkApplied(ctx, zone)
+		if err != nil {
+			return err
 		}
-		fileContents += fmt.Sprintf(zoneFormat, zone.Domain, zone.FilePath)
 	}
+	return nil
+}
+
+// updateAndReload returns the zones named serves once reloaded. The configs named served are restored when the new
+// ones fail to be written, and reloaded again when named fails to reload the new ones or does not come back healthy,
+// not when ctx is done before named answers.
+func (b *bind9Server) updateAndReload(ctx context.Context) ([]*domain.Zone, error) {
+	b.applyLock.Lock()
+	defer b.applyLock.Unlock()
+
+	paths, err := b.servedConfigPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := snapshotConfigs(paths)
+	if err != nil {
+		return nil, err
+	}
+	zones, _, err := b.updateConfigs(ctx)
+	if err != nil {
+		return nil, b.rollback(snapshot, err, false)
+	}
+	err = b.reloadHealthy(ctx)
+	if err != nil && ctx.Err() != nil {
+		// The request went away while named was reloading, the new configs are kept as named may be loading them.
+		return nil, err
+	}
+	if err != nil {
+		return nil, b.rollback(snapshot, err, true)
+	}
+	return zones, nil
+}
 
-	err := writeFile(b.config.NamedConfPath(), fileContents)
+// reloadHealthy reloads named and waits for it to answer rndc, named exiting or not answering after the reload, e.g.
+// while it fails to load the new configs, being an error.
+func (b *bind9Server) reloadHealthy(ctx context.Context) error {
+	err := b.reload(ctx)
 	if err != nil {
 		return err
 	}
+	err = waitRndc(ctx, b.config, namedHealthyAttempts, time.Second)
+	if err != nil {
+		return errors.Wrap(err, "named is not healthy after the reload")
+	}
+	b.numLock.RLock()
+	running := b.numCmds > 0
+	b.numLock.RUnlock()
+	if !running {
+		return errors.New("named exited after the reload")
+	}
 	return nil
 }
 
-func (b *bind9Server) generateDbRecords(ctx context.Context, zones []*domain.Zone) (err error) {
-	soaFormat := `%v	IN	SOA     %v %v (
-						%v				; Serial 2021082501
-						%v				; Refresh 7200
-						%v				; Retry 3600
-						%v				; Expire 1209600
-						%v )			; Negative Cache TTL 180` + "\n"
-	recordFormat := "%v	IN	%v	%v\n"
+// rollback restores the configs of the snapshot once the reload failed with err, named being reloaded with them again
+// when it was reloaded with the new ones. err is returned along with the outcome of the rollback.
+func (b *bind9Server) rollback(snapshot configSnapshot, err error, reloaded bool) error {
+	rollbackErr := snapshot.restore()
+	if rollbackErr == nil && reloaded {
+		// The rollback is not cancelled along with the request which failed to reload.
+		rollbackErr = b.reloadHealthy(context.Background())
+	}
+	if rollbackErr != nil {
+		log.Println("Restore the last known good configs:", rollbackErr)
+		return fmt.Errorf("%w, restoring the last known good configs failed: %v", err, rollbackErr)
+	}
+	if !reloaded {
+		return err
+	}
+	log.Println("Restore the last known good configs after the reload failed:", err)
+	return fmt.Errorf("%w, the last known good configs are restored", err)
+}
 
-	for _, zone := range zones {
-		fileContents := "$TTL    14400\n"
-		soa := zone.SOA
-		if soa == nil {
+// servedConfigPaths returns the files a reload writes for named to load: named.conf, the custom default zones and root
+// hints, and the zone files of the zones and of the response policy zones. The files named writes next to the signed
+// zones are left out, named keeping them in line with the zone files.
+func (b *bind9Server) servedConfigPaths(ctx context.Context) ([]string, error) {
+	zones, err := b.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := b.zoneFolders(zones)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{
+		b.config.NamedConfPath(),
+		filepath.Join(b.config.BindFolderPath(), managedDefaultZonesConf),
+		filepath.Join(b.config.BindFolderPath(), managedRootHints),
+	}
+	for _, folder := range folders {
+		entries, err := os.ReadDir(folder)
+		if os.IsNotExist(err) {
 			continue
 		}
-		soa.UpdateSerial()
-		if !soa.IsValid() {
-			continue // Skip current zone records because of invalid SOA
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasPrefix(entry.Name(), zoneFilePrefix) && !hasSignedZoneSuffix(entry.Name()) {
+				paths = append(paths, filepath.Join(folder, entry.Name()))
+			}
 		}
-		fileContents += fmt.Sprintf(soaFormat, soa.Name, soa.PrimaryNameServer, soa.MailAddress, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.CacheTTL)
+	}
+	return paths, nil
+}
+
+// UpdateAndReloadZone writes the zone files of the zone named domainName alone and has named reload that zone only.
+// Every zone is updated and reloaded like UpdateAndReload instead whenever named.conf changes as well, i.e. while named
+// is not running, when the zone was never applied or its view zone files come or go, and when named fails to reload
+// the zone.
+func (b *bind9Server) UpdateAndReloadZone(ctx context.Context, domainName string) error {
+	zone, full, err := b.updateAndReloadZone(ctx, domainName)
+	if err != nil {
+		log.Println("Reload zone", domainName, "failed, reloading every zone:", err)
+		return b.UpdateAndReload(ctx)
+	}
+	if full {
+		return b.UpdateAndReload(ctx)
+	}
+	// The changes deferred by the notify window of the zone are published by the reload following the window.
+	if zone.PublishDeferred(time.Now()) {
+		return nil
+	}
+	// The zone file of the zone loaded, the zone is not left out anymore.
+	b.lastReloadLock.Lock()
+	var leftOut []string
+	for _, entry := range b.leftOut {
+		if !strings.HasPrefix(entry, zone.Domain+": ") {
+			leftOut = append(leftOut, entry)
+		}
+	}
+	b.leftOut = leftOut
+	b.lastReloadLock.Unlock()
+	b.setLastReload(nil)
+	return b.zoneRepo.MarkApplied(ctx, zone)
+}
 
-		for _, record := range zone.Records {
-			if !record.IsValid() {
+// updateAndReloadZone returns the zone named serves once reloaded, full being set when named.conf has to change for
+// the zone to be served, nothing being written then.
+func (b *bind9Server) updateAndReloadZone(
+	ctx context.Context, domainName string,
+) (zone *domain.Zone, full bool, err error) {
+	b.applyLock.Lock()
+	defer b.applyLock.Unlock()
+
+	zone, err = b.zoneRepo.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return nil, false, err
+	}
+	// The zone was deleted in the meantime, named.conf has to stop declaring it.
+	if zone == nil {
+		return nil, true, nil
+	}
+	options, err := b.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	b.numLock.RLock()
+	running := b.numCmds > 0
+	b.numLock.RUnlock()
+	if !running || zone.AppliedRevision == 0 || !zone.IsValid() || zone.IsFrozen() || !fileExists(zone.FilePath) {
+		return zone, true, nil
+	}
+	views := b.servedViews(options)
+	var servedViews []string
+	for _, view := range views {
+		if !zone.ServedInView(view.Name) {
+			continue
+		}
+		// named.conf refers to the zone file of the records of the view once it exists.
+		if zone.HasViewRecords(view.Name) != fileExists(viewZoneFilePath(zone, view.Name)) {
+			return zone, true, nil
+		}
+		servedViews = append(servedViews, view.Name)
+	}
+	if zone.PublishDeferred(time.Now()) {
+		return zone, false, nil
+	}
+
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	// The zone files named served are restored when the zone fails to reload, before every zone is reloaded.
+	paths := []string{zone.FilePath}
+	for _, view := range servedViews {
+		paths = append(paths, viewZoneFilePath(zone, view))
+	}
+	snapshot, err := snapshotConfigs(paths)
+	if err != nil {
+		return nil, false, err
+	}
+	err = b.generateZoneFiles(ctx, zone, fragments, views)
+	if err != nil {
+		return nil, false, b.rollback(snapshot, err, false)
+	}
+
+	b.startLock.Lock()
+	defer b.startLock.Unlock()
+	if len(views) == 0 {
+		_, err = runRndc(ctx, b.config, "reload", zone.Domain)
+	}
+	for _, view := range servedViews {
+		if _, errTemp := runRndc(ctx, b.config, "reload", zone.Domain, "IN", view); errTemp != nil {
+			err = joinErrors(err, errTemp)
+		}
+	}
+	if err != nil && ctx.Err() != nil {
+		// The request went away while rndc was reloading the zone, which named may have loaded.
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, false, b.rollback(snapshot, err, false)
+	}
+	log.Println("Reload Bind9 zone", zone.Domain)
+	return zone, false, nil
+}
+
+func (b *bind9Server) setLastReload(err error) {
+	result := &domain.ReloadResult{Time: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	b.lastReloadLock.Lock()
+	result.LeftOut = b.leftOut
+	b.lastReload = result
+	b.lastReloadLock.Unlock()
+}
+
+func (b *bind9Server) LastReload() *domain.ReloadResult {
+	b.lastReloadLock.RLock()
+	defer b.lastReloadLock.RUnlock()
+	return b.lastReload
+}
+
+func (b *bind9Server) LastCrash() *domain.NamedCrash {
+	b.crashLock.RLock()
+	defer b.crashLock.RUnlock()
+	if b.lastCrash == nil {
+		return nil
+	}
+	crash := *b.lastCrash
+	return &crash
+}
+
+func (b *bind9Server) Ping(ctx context.Context) error {
+	_, err := runRndc(ctx, b.config, "status")
+	return err
+}
+
+func (b *bind9Server) Shutdown(ctx context.Context) error {
+	// No restart starts named once it is counted.
+	b.startLock.Lock()
+	close(b.stopped)
+	b.numLock.RLock()
+	numCmds := b.numCmds
+	b.numLock.RUnlock()
+	b.startLock.Unlock()
+	for i := 0; i < numCmds; i++ {
+		b.shutdownSignal <- 1
+	}
+	b.runningCmdsWg.Wait()
+	return nil
+}
+
+func (b *bind9Server) AddNegativeTrustAnchor(ctx context.Context, nta *domain.NegativeTrustAnchor) error {
+	lifetime := fmt.Sprintf("%vs", int(nta.Lifetime().Seconds()))
+	_, err := runRndc(ctx, b.config, "nta", "-force", "-lifetime", lifetime, nta.Domain)
+	return err
+}
+
+func (b *bind9Server) RemoveNegativeTrustAnchor(ctx context.Context, nta *domain.NegativeTrustAnchor) error {
+	_, err := runRndc(ctx, b.config, "nta", "-remove", nta.Domain)
+	return err
+}
+
+func (b *bind9Server) FlushCache(ctx context.Context, name string, tree bool) error {
+	args := []string{"flush"}
+	if name != "" {
+		args = []string{"flushname", name}
+		if tree {
+			args = []string{"flushtree", name}
+		}
+	}
+	_, err := runRndc(ctx, b.config, args...)
+	return err
+}
+
+func (b *bind9Server) DumpCache(ctx context.Context) ([]byte, error) {
+	dumpPath := filepath.Join(bindWorkingDirectory, bindDumpFile)
+	var lastModified time.Time
+	if info, err := os.Stat(dumpPath); err == nil {
+		lastModified = info.ModTime()
+	}
+
+	_, err := runRndc(ctx, b.config, "dumpdb", "-cache")
+	if err != nil {
+		return nil, err
+	}
+
+	// named writes the dump asynchronously, wait until a newer file shows up.
+	deadline := time.Now().Add(cacheDumpTimeout)
+	for time.Now().Before(deadline) {
+		info, err := os.Stat(dumpPath)
+		if err == nil && info.ModTime().After(lastModified) {
+			time.Sleep(100 * time.Millisecond)
+			return os.ReadFile(dumpPath)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil, errors.New("timeout waiting for the cache dump")
+}
+
+func (b *bind9Server) SubscribeQueryLog(listener domain.QueryLogListener) {
+	b.queryListeners = append(b.queryListeners, listener)
+}
+
+// restoreNegativeTrustAnchors re-applies the stored anchors once a freshly started named accepts rndc commands,
+// since anchors added at runtime do not survive named being restarted.
+func (b *bind9Server) restoreNegativeTrustAnchors() {
+	ctx := context.Background()
+	ntas, err := b.serverRepo.GetAllNegativeTrustAnchors(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if len(ntas) == 0 {
+		return
+	}
+	err = waitRndc(ctx, b.config, 30, time.Second)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, nta := range ntas {
+		if nta.IsExpired() {
+			continue
+		}
+		err := b.AddNegativeTrustAnchor(ctx, nta)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// generateNamedConf writes named.conf, the zones being declared in every view serving them when views are served. The
+// new named.conf only replaces the one named serves once named-checkconf accepts it, so that neither a reload nor a
+// restart of named picks up a broken config.
+func (b *bind9Server) generateNamedConf(
+	ctx context.Context, options *domain.ServerOptions, zones []*domain.Zone, rpzZones []string,
+	tsigKeys []*domain.TSIGKey, views []*domain.View,
+) error {
+	// The views apply the response policy zones of their profiles, the options applying every zone otherwise.
+	optionsRpzZones := rpzZones
+	var rpzProfiles []*domain.RpzProfile
+	if len(views) > 0 {
+		optionsRpzZones = nil
+		var err error
+		rpzProfiles, err = b.rpzRepo.GetProfiles(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	fileContents := b.renderOptions(options, optionsRpzZones)
+	// rndc controls named over the loopback only, with the key generated by the manager.
+	fileContents += fmt.Sprintf(`include "%v";`+"\n", b.config.RndcKeyPath())
+	fileContents += fmt.Sprintf(`controls {inet 127.0.0.1 port 953 allow {127.0.0.1;} keys {"%v";};};`+"\n",
+		rndcKeyName)
+	fileContents += fmt.Sprintf(`include "%v";`+"\n", filepath.Join(b.config.BindFolderPath(), bindLocalConf))
+	defaultZones, err := b.generateDefaultZones(options)
+	if err != nil {
+		return err
+	}
+	keyFormat := `key "%v" {algorithm %v; secret "%v";};` + "\n"
+	for _, key := range domain.UniqueTransferKeys(zones, tsigKeys) {
+		fileContents += fmt.Sprintf(keyFormat, key.Name, key.Algorithm, key.Secret)
+	}
+	if len(views) == 0 {
+		fileContents += defaultZones + b.renderZones(zones, rpzZones, nil, nil)
+	}
+	// Once a view is declared, named only takes the zones declared within views.
+	viewFormat := `view "%v" {match-clients { %v }; %v` + "\n" + `%v};` + "\n"
+	for _, view := range views {
+		fileContents += fmt.Sprintf(viewFormat, view.Name, addressMatchList(view.MatchClients),
+			renderResponsePolicy(domain.RpzZonesOfView(rpzProfiles, rpzZones, view.Name)),
+			defaultZones+b.renderZones(zones, rpzZones, view, views))
+	}
+
+	confPath := b.config.NamedConfPath()
+	checkPath := confPath + namedConfCheckSuffix
+	err = writeFile(checkPath, fileContents)
+	if err != nil {
+		return err
+	}
+	err = checkNamedConf(ctx, checkPath)
+	if err != nil {
+		_ = os.Remove(checkPath)
+		return err
+	}
+	return os.Rename(checkPath, confPath)
+}
+
+// renderZones returns the zone statements of the managed zones and of the response policy zones, those of the zones
+// the view serves when view is not nil, views being every view served.
+func (b *bind9Server) renderZones(
+	zones []*domain.Zone, rpzZones []string, view *domain.View, views []*domain.View,
+) string {
+	statements := ""
+	zoneFormat := `zone "%v" {type primary; file "%v";%v%v%v};` + "\n"
+	for _, zone := range zones {
+		// named.conf only declares the zones whose zone file was written, named refusing to load a missing one.
+		if !zone.IsValid() || !fileExists(zone.FilePath) {
+			continue
+		}
+		filePath := zone.FilePath
+		if view != nil {
+			if !zone.ServedInView(view.Name) {
+				continue
+			}
+			filePath = viewZoneFile(zone, view.Name)
+			// named signs a zone file in a single view, the other views serving the signed zone of that view.
+			if firstView := sharedZoneView(zone, views); b.signsZone(zone) && filePath == zone.FilePath &&
+				firstView != view.Name {
+				statements += fmt.Sprintf(`zone "%v" {in-view "%v";};`+"\n", zone.Domain, firstView)
 				continue
 			}
-			fileContents += fmt.Sprintf(recordFormat, record.Name, record.Type, record.Value)
 		}
+		statements += fmt.Sprintf(zoneFormat, zone.Domain, filePath, renderAllowTransfer(zone),
+			renderAlsoNotify(zone), b.renderDnssec(zone, filePath))
+	}
+	rpzZoneFormat := `zone "%v" {type primary; file "%v"; allow-query { none; };};` + "\n"
+	for _, rpzZone := range rpzZones {
+		statements += fmt.Sprintf(rpzZoneFormat, rpzZone, b.rpzZoneFilePath(rpzZone))
+	}
+	return statements
+}
 
-		errTemp := b.zoneRepo.Persist(ctx, zone)
-		if errTemp != nil {
-			err = errors.Wrap(errTemp, err.Error())
+// renderDnssec returns the options of the zone statement signing the zone served from filePath, none while the
+// dnssec feature is disabled.
+func (b *bind9Server) renderDnssec(zone *domain.Zone, filePath string) string {
+	if !b.signsZone(zone) {
+		return ""
+	}
+	return fmt.Sprintf(` dnssec-policy %v; inline-signing yes; key-directory "%v";`, zone.DnssecPolicy,
+		b.keyDirectory(filePath))
+}
+
+// signsZone reports whether named signs the zone, the zones being served unsigned while the dnssec feature is
+// disabled.
+func (b *bind9Server) signsZone(zone *domain.Zone) bool {
+	return zone.IsSigned() && b.settings.Settings().IsFeatureEnabled(domain.FeatureDnssec)
+}
+
+// keyDirectory returns the key directory of the zone served from the zone file filePath, the zone files of the views
+// getting their own keys.
+func (b *bind9Server) keyDirectory(filePath string) string {
+	return filepath.Join(b.config.KeysFolderPath(), filepath.Base(filePath))
+}
+
+// generateKeyDirectories creates the key directories of the signed zones, named generating their keys there.
+func (b *bind9Server) generateKeyDirectories(zones []*domain.Zone, views []*domain.View) error {
+	for _, zone := range zones {
+		if !zone.IsValid() || !b.signsZone(zone) {
 			continue
 		}
+		filePaths := []string{zone.FilePath}
+		for _, view := range ownZoneFileViews(zone, views) {
+			filePaths = append(filePaths, viewZoneFile(zone, view))
+		}
+		for _, filePath := range filePaths {
+			err := os.MkdirAll(b.keyDirectory(filePath), 0777)
+			if err != nil {
+				return err
+			}
+			err = chownBindUser(b.keyDirectory(filePath))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// servedViews returns the views named.conf declares, none while the views feature is disabled.
+func (b *bind9Server) servedViews(options *domain.ServerOptions) []*domain.View {
+	if !b.settings.Settings().IsFeatureEnabled(domain.FeatureViews) {
+		return nil
+	}
+	return options.Views
+}
+
+// generateDefaultZones writes the custom default zones and the managed root hints, returning the statements of
+// named.conf loading them.
+func (b *bind9Server) generateDefaultZones(options *domain.ServerOptions) (string, error) {
+	statements := ""
+	switch options.DefaultZones {
+	case domain.DefaultZonesImage:
+		statements += fmt.Sprintf(`include "%v";`+"\n", filepath.Join(b.config.BindFolderPath(), bindDefaultZonesConf))
+	case domain.DefaultZonesCustom:
+		path := filepath.Join(b.config.BindFolderPath(), managedDefaultZonesConf)
+		err := writeFile(path, options.DefaultZonesContent)
+		if err != nil {
+			return "", err
+		}
+		statements += fmt.Sprintf(`include "%v";`+"\n", path)
+	}
+	if rootHints := options.ManagedRootHints(); rootHints != "" {
+		path := filepath.Join(b.config.BindFolderPath(), managedRootHints)
+		err := writeFile(path, rootHints)
+		if err != nil {
+			return "", err
+		}
+		statements += fmt.Sprintf(`zone "." {type hint; file "%v";};`+"\n", path)
+	}
+	return statements, nil
+}
 
-		errTemp = writeFile(zone.FilePath, fileContents)
-		if errTemp != nil {
-			err = errors.Wrap(errTemp, err.Error())
+func (b *bind9Server) renderOptions(options *domain.ServerOptions, rpzZones []string) string {
+	statements := []string{
+		fmt.Sprintf(`directory "%v";`, bindWorkingDirectory),
+		"dnssec-validation auto;",
+		"listen-on-v6 { any; };",
+	}
+	if options.IsRecursive() {
+		statements = append(statements, "recursion yes;",
+			fmt.Sprintf("allow-recursion { %v };", addressMatchList(options.AllowRecursion)))
+		if len(options.Forwarders) > 0 {
+			statements = append(statements, "forward first;",
+				fmt.Sprintf("forwarders { %v };", addressMatchList(b.forwarders.HealthyForwarders(options.Forwarders))))
+		}
+	} else {
+		statements = append(statements, "recursion no;")
+	}
+	if blackhole := options.Blackhole(); len(blackhole) > 0 {
+		statements = append(statements, fmt.Sprintf("blackhole { %v };", addressMatchList(blackhole)))
+	}
+	if options.QueryLog {
+		statements = append(statements, "querylog yes;")
+	}
+	if responsePolicy := renderResponsePolicy(rpzZones); responsePolicy != "" {
+		statements = append(statements, responsePolicy)
+	}
+	if len(options.ValidateExcept) > 0 {
+		statements = append(statements, fmt.Sprintf("validate-except { %v };", quotedList(options.ValidateExcept)))
+	}
+	// The options set by hand, e.g. listen-on, are kept in named.conf.options.
+	statements = append(statements,
+		fmt.Sprintf(`include "%v";`, filepath.Join(b.config.BindFolderPath(), bindOptionsConf)))
+	return fmt.Sprintf("options { %v };\n", strings.Join(statements, " "))
+}
+
+// renderResponsePolicy returns the response-policy statement applying the response policy zones, none when there is
+// no zone to apply.
+func renderResponsePolicy(rpzZones []string) string {
+	if len(rpzZones) == 0 {
+		return ""
+	}
+	policies := ""
+	for _, rpzZone := range rpzZones {
+		policies += fmt.Sprintf(`zone "%v"; `, rpzZone)
+	}
+	return fmt.Sprintf("response-policy { %v};", policies)
+}
+
+// generateRpzZones writes one response policy zone per enabled profile out of its enabled feeds and returns the
+// names of the zones to load, none when no feed is enabled.
+func (b *bind9Server) generateRpzZones(ctx context.Context, options *domain.ServerOptions) ([]string, error) {
+	feeds, err := b.rpzRepo.GetAllFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storedProfiles, err := b.rpzRepo.GetProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profiles := domain.MergeRpzProfiles(storedProfiles, feeds)
+
+	feedsByProfile := map[string][]*domain.RpzFeed{}
+	for _, feed := range feeds {
+		if !feed.Enabled {
+			continue
+		}
+		if profile := domain.FindRpzProfile(profiles, feed.Profile); profile != nil && !profile.Enabled {
 			continue
 		}
+		feedsByProfile[feed.Profile] = append(feedsByProfile[feed.Profile], feed)
+	}
+
+	var rpzZones []string
+	for profile, profileFeeds := range feedsByProfile {
+		rpzZone := domain.RpzZoneNameOfProfile(profile)
+		err := b.generateRpzZone(rpzZone, profileFeeds, options.RpzAllowlist)
+		if err != nil {
+			return nil, err
+		}
+		rpzZones = append(rpzZones, rpzZone)
+	}
+	sort.Strings(rpzZones)
+	return rpzZones, nil
+}
+
+func (b *bind9Server) generateRpzZone(rpzZone string, feeds []*domain.RpzFeed, allowlist []string) error {
+	allowed := map[string]bool{}
+	for _, domainName := range allowlist {
+		allowed[domainName] = true
+	}
+
+	var contents strings.Builder
+	contents.WriteString("$TTL    60\n")
+	contents.WriteString(fmt.Sprintf("@\tIN\tSOA\tlocalhost. root.localhost. (%v 3600 600 86400 60)\n", time.Now().Unix()))
+	contents.WriteString("@\tIN\tNS\tlocalhost.\n")
+
+	blocked := map[string]bool{}
+	for _, feed := range feeds {
+		domains, err := readRpzFeedList(b.config, feed.Id)
+		if err != nil {
+			return err
+		}
+		for _, domainName := range domains {
+			if blocked[domainName] || allowed[domainName] {
+				continue
+			}
+			blocked[domainName] = true
+			contents.WriteString(fmt.Sprintf("%v\tCNAME\t.\n*.%v\tCNAME\t.\n", domainName, domainName))
+		}
+	}
+	// Allowlisted domains pass through explicitly, which also overrides a blocked parent domain.
+	for _, domainName := range allowlist {
+		contents.WriteString(fmt.Sprintf("%v\tCNAME\trpz-passthru.\n*.%v\tCNAME\trpz-passthru.\n", domainName, domainName))
+	}
+
+	return writeFile(b.rpzZoneFilePath(rpzZone), contents.String())
+}
+
+func (b *bind9Server) rpzZoneFilePath(rpzZone string) string {
+	return filepath.Join(b.config.BindFolderPath(), zoneFilePrefix+rpzZone)
+}
+
+// generateDbRecords writes the zone files, the kept zones, by id, keeping the one they were last published with. The
+// zones having records of one of the views get a zone file per such view as well. The zones whose zone files could
+// not be written are returned by id along with the reason.
+func (b *bind9Server) generateDbRecords(
+	ctx context.Context, zones []*domain.Zone, kept map[string]bool, views []*domain.View,
+) (map[string]error, error) {
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := map[string]error{}
+	for _, zone := range zones {
+		if zone.SOA == nil || kept[zone.Id] {
+			continue
+		}
+		err = b.generateZoneFiles(ctx, zone, fragments, views)
+		if err != nil {
+			failures[zone.Id] = err
+		}
+	}
+	return failures, nil
+}
+
+// generateZoneFiles writes the zone files of the zone at the next serial, none being written when one of them fails to
+// load.
+func (b *bind9Server) generateZoneFiles(
+	ctx context.Context, zone *domain.Zone, fragments []*domain.RecordFragment, views []*domain.View,
+) error {
+	soa := zone.SOA
+	soa.UpdateSerial()
+	soa.PublishedAt = time.Now()
+	if !soa.IsValid() {
+		return nil // Skip current zone records because of invalid SOA
+	}
+	fileContents := b.renderZoneFile(ctx, zone, fragments, "")
+
+	// A zone file named fails to load is not written, named keeping the one it serves.
+	err := b.checkZoneFile(ctx, zone, fileContents)
+	if err != nil {
+		return err
+	}
+	viewFiles := map[string]string{}
+	for _, view := range views {
+		if !zone.ServedInView(view.Name) || !zone.HasViewRecords(view.Name) {
+			continue
+		}
+		viewFiles[view.Name] = b.renderZoneFile(ctx, zone, fragments, view.Name)
+		if errTemp := b.checkZoneFile(ctx, zone, viewFiles[view.Name]); errTemp != nil {
+			err = joinErrors(err, errTemp)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Only the serial is stored back, the zone may have been changed since it was read
+	err = b.zoneRepo.PersistSerial(ctx, zone)
+	if err != nil {
+		return err
+	}
+
+	folder := filepath.Dir(zone.FilePath)
+	newFolder := !fileExists(folder)
+	err = writeFile(zone.FilePath, fileContents)
+	for view, viewContents := range viewFiles {
+		if errTemp := writeFile(viewZoneFilePath(zone, view), viewContents); errTemp != nil {
+			err = joinErrors(err, errTemp)
+		}
+	}
+	// named writes the journals of the zone next to its zone file, the folder of a zone group included.
+	if err == nil && newFolder {
+		err = chownBindUser(folder)
+	}
+	return err
+}
+
+// viewZoneFile returns the zone file the view named view serves the zone from, the zone file of the records of the
+// view once it exists, it is missing while the change adding them is deferred.
+func viewZoneFile(zone *domain.Zone, view string) string {
+	if path := viewZoneFilePath(zone, view); zone.HasViewRecords(view) && fileExists(path) {
+		return path
+	}
+	return zone.FilePath
+}
+
+// sharedZoneView returns the name of the first of views serving the zone from its own zone file, empty when none
+// does.
+func sharedZoneView(zone *domain.Zone, views []*domain.View) string {
+	for _, view := range views {
+		if zone.ServedInView(view.Name) && viewZoneFile(zone, view.Name) == zone.FilePath {
+			return view.Name
+		}
+	}
+	return ""
+}
+
+// ownZoneFileViews returns the names of the views serving the zone from a zone file of their own, named signing it
+// with the keys of its own key directory.
+func ownZoneFileViews(zone *domain.Zone, views []*domain.View) []string {
+	var names []string
+	for _, view := range views {
+		if zone.ServedInView(view.Name) && viewZoneFile(zone, view.Name) != zone.FilePath {
+			names = append(names, view.Name)
+		}
+	}
+	return names
+}
+
+// viewZoneFilePath returns the path of the zone file of the zone served to the view named view, '@' being part of no
+// domain name.
+func viewZoneFilePath(zone *domain.Zone, view string) string {
+	return zone.FilePath + "@" + view
+}
+
+func (b *bind9Server) CheckZone(ctx context.Context, zone *domain.Zone) error {
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return err
+	}
+	return b.checkZone(ctx, zone, fragments)
+}
+
+func (b *bind9Server) CheckZoneWithFragment(
+	ctx context.Context, zone *domain.Zone, fragment *domain.RecordFragment,
+) error {
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return err
+	}
+	checkedFragments := []*domain.RecordFragment{fragment}
+	for _, other := range fragments {
+		if other.Name != fragment.Name {
+			checkedFragments = append(checkedFragments, other)
+		}
+	}
+	return b.checkZone(ctx, zone, checkedFragments)
+}
+
+// checkZone checks the zone files of the zone rendered with the fragments, see CheckZone.
+func (b *bind9Server) checkZone(ctx context.Context, zone *domain.Zone, fragments []*domain.RecordFragment) error {
+	if zone.SOA == nil || !zone.SOA.IsValid() {
+		return nil
+	}
+	err := b.checkZoneFile(ctx, zone, b.renderZoneFile(ctx, zone, fragments, ""))
+	if err != nil {
+		return err
+	}
+	for _, view := range zone.RecordViews() {
+		err = b.checkZoneFile(ctx, zone, b.renderZoneFile(ctx, zone, fragments, view))
+		if err != nil {
+			return fmt.Errorf("%w (view %v)", err, view)
+		}
+	}
+	return nil
+}
+
+// checkZoneFile runs named-checkzone on the contents of a zone file of the zone through a temporary file.
+func (b *bind9Server) checkZoneFile(ctx context.Context, zone *domain.Zone, fileContents string) error {
+	file, err := os.CreateTemp("", "zone-check-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(fileContents)
+	errClose := file.Close()
+	if err != nil {
+		return err
+	}
+	if errClose != nil {
+		return errClose
+	}
+
+	output, err := exec.CommandContext(ctx, namedCheckZonePath, domain.NormalizeDomain(zone.Domain),
+		file.Name()).CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("%w: %v", domain.ErrorZoneCheckFailed, checkZoneFailure(string(output), file.Name()))
+	}
+	return err
+}
+
+// checkZoneFailure returns the errors named-checkzone or named-checkconf reported, the checked file being referred to
+// by line. The summary lines repeating them are left out.
+func checkZoneFailure(output string, filePath string) string {
+	var failures []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, filePath+":", "line "))
+		if line != "" && !strings.Contains(line, filePath) && !strings.HasSuffix(line, "not loaded due to errors.") {
+			failures = append(failures, line)
+		}
+	}
+	if len(failures) == 0 {
+		return strings.TrimSpace(output)
+	}
+	return strings.Join(failures, ", ")
+}
+
+func (b *bind9Server) ZoneFile(ctx context.Context, zone *domain.Zone) (string, error) {
+	if zone.SOA == nil || !zone.SOA.IsValid() {
+		return "", domain.ErrorZoneWithoutSOA
+	}
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return "", err
+	}
+	// The zone file named serves relies on the zone statement for its origin.
+	origin := fmt.Sprintf("$ORIGIN %v.\n", domain.NormalizeDomain(zone.Domain))
+	return origin + b.renderZoneFile(ctx, zone, fragments, ""), nil
+}
+
+func (b *bind9Server) DnssecKeys(ctx context.Context, zone *domain.Zone) ([]*domain.DnssecKey, error) {
+	options, err := b.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := readDnssecKeys(b.keyDirectory(zone.FilePath), "")
+	if err != nil {
+		return nil, err
+	}
+	// The views serving the zone from a zone file of their own sign it with keys of their own.
+	for _, view := range ownZoneFileViews(zone, b.servedViews(options)) {
+		viewKeys, err := readDnssecKeys(b.keyDirectory(viewZoneFile(zone, view)), view)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, viewKeys...)
+	}
+	return keys, nil
+}
+
+// readDnssecKeys returns the keys found in the key directory, those of the zone file of the view named view.
+func readDnssecKeys(keyDirectory string, view string) ([]*domain.DnssecKey, error) {
+	entries, err := os.ReadDir(keyDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var keys []*domain.DnssecKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), "K") || !strings.HasSuffix(entry.Name(), ".key") {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(keyDirectory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key, err := domain.ParseDnssecKey(string(contents))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", entry.Name(), err)
+		}
+		key.View = view
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (b *bind9Server) DnssecStatus(ctx context.Context, zone *domain.Zone) (*domain.DnssecStatus, error) {
+	keys, err := b.DnssecKeys(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	status := &domain.DnssecStatus{Policy: zone.DnssecPolicy, Signing: zone.IsValid() && b.signsZone(zone), Keys: keys}
+	if !status.Signing {
+		return status, nil
+	}
+
+	options, err := b.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	views := b.servedViews(options)
+	var reports []string
+	if view := sharedZoneView(zone, views); view != "" || len(views) == 0 {
+		reports = append(reports, b.dnssecStatusReport(ctx, zone, view))
+	}
+	for _, view := range ownZoneFileViews(zone, views) {
+		reports = append(reports, b.dnssecStatusReport(ctx, zone, view))
+	}
+	status.Status = strings.Join(reports, "\n\n")
+	return status, nil
+}
+
+// dnssecStatusReport returns the report of named on the keys of the zone served in the view named view, every view
+// sharing the zone file it is served from, or the failure named does not sign the zone with yet, e.g. while its change
+// is pending.
+func (b *bind9Server) dnssecStatusReport(ctx context.Context, zone *domain.Zone, view string) string {
+	args := []string{"dnssec", "-status", zone.Domain}
+	if view != "" {
+		args = append(args, "IN", view)
+	}
+	output, err := runRndc(ctx, b.config, args...)
+	report := strings.TrimSpace(output)
+	if err != nil {
+		report = err.Error()
+	}
+	if view != "" {
+		report = "view " + view + ":\n" + report
+	}
+	return report
+}
+
+func (b *bind9Server) TransferZone(
+	ctx context.Context, primary string, domainName string, key *domain.TSIGKey,
+) (string, error) {
+	fields := strings.Fields(primary)
+	if !domain.IsValidNotifyTarget(fields) {
+		return "", fmt.Errorf("%v is not a valid primary", primary)
+	}
+	// The name is given with -q and the address is the one parsed, neither being taken by dig as an option.
+	args := []string{"@" + net.ParseIP(fields[0]).String(), "-q", domain.NormalizeDomain(domainName) + ".",
+		"-t", "AXFR", "+onesoa", "+nocmd", "+nostats", "+tries=1"}
+	if len(fields) == 3 {
+		args = append(args, "-p", fields[2])
+	}
+	if key != nil {
+		// The key is given to dig through a file, the command lines being readable by every user.
+		keyFile, err := os.CreateTemp("", "transfer-key-")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(keyFile.Name())
+		_, err = fmt.Fprintf(keyFile, `key "%v" {algorithm %v; secret "%v";};`+"\n", key.Name, key.Algorithm,
+			key.Secret)
+		errClose := keyFile.Close()
+		if err != nil {
+			return "", err
+		}
+		if errClose != nil {
+			return "", errClose
+		}
+		args = append(args, "-k", keyFile.Name())
+	}
+
+	transferCtx, cancel := context.WithTimeout(ctx, zoneTransferTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(transferCtx, digPath, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrorZoneTransferFailed, digFailure(string(output), err))
+	}
+	// dig exits successfully when the primary refuses the transfer, only reporting it in a comment.
+	if strings.Contains(string(output), "; Transfer failed.") {
+		return "", fmt.Errorf("%w: %v", domain.ErrorZoneTransferFailed, digFailure(string(output), nil))
+	}
+	return string(output), nil
+}
+
+// digFailure returns the comments dig reported a failure with, or err when there are none.
+func digFailure(output string, err error) string {
+	var comments []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "; "))
+		if line != "" && line != "Transfer failed." {
+			comments = append(comments, line)
+		}
+	}
+	switch {
+	case len(comments) > 0:
+		return strings.Join(comments, ", ")
+	case err != nil:
+		return err.Error()
+	}
+	return "the primary refused the transfer"
+}
+
+// renderZoneFile returns the zone file of the zone at its current serial, holding the records named serves to the
+// view, see servedRecords.
+func (b *bind9Server) renderZoneFile(
+	ctx context.Context, zone *domain.Zone, fragments []*domain.RecordFragment, view string,
+) string {
+	soaFormat := `%v	IN	SOA     %v %v (
+						%v				; Serial 2021082501
+						%v				; Refresh 7200
+						%v				; Retry 3600
+						%v				; Expire 1209600
+						%v )			; Negative Cache TTL 180` + "\n"
+	recordFormat := "%v	IN	%v	%v\n"
+	ttlRecordFormat := "%v	%v	IN	%v	%v\n"
+
+	soa := zone.SOA
+	fileContents := fmt.Sprintf("$TTL    %v\n", domain.DefaultTTL)
+	fileContents += fmt.Sprintf(soaFormat, soa.Name, soa.RenderedPrimaryNameServer(), soa.RenderedMailAddress(),
+		soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.CacheTTL)
+
+	for _, record := range b.servedRecords(ctx, zone, fragments, view) {
+		fileContents += record.RenderedComment()
+		if record.TTL > 0 {
+			fileContents += fmt.Sprintf(ttlRecordFormat, record.Name, record.TTL, record.Type, record.RenderedValue())
+			continue
+		}
+		fileContents += fmt.Sprintf(recordFormat, record.Name, record.Type, record.RenderedValue())
+	}
+	return fileContents
+}
+
+func (b *bind9Server) ServedRecords(ctx context.Context, zone *domain.Zone) ([]*domain.Record, error) {
+	fragments, err := b.fragmentRepo.GetAllFragments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.servedRecords(ctx, zone, fragments, ""), nil
+}
+
+// servedRecords returns the records of the zone and of the fragments it includes served to the view, see
+// Zone.ViewRecords, their variables expanded and the ALIAS records materialized, the first A or AAAA record of an
+// ALIAS record keeping its comment. The disabled and the invalid records are left out.
+func (b *bind9Server) servedRecords(
+	ctx context.Context, zone *domain.Zone, fragments []*domain.RecordFragment, view string,
+) []*domain.Record {
+	var records []*domain.Record
+	for _, record := range zone.ViewRecords(zone.IncludedRecords(fragments), view) {
+		if record.Disabled {
+			continue
+		}
+		record, err := zone.ExpandedRecord(record)
+		if err != nil || !record.IsValid() {
+			continue
+		}
+		if domain.IsAliasRecord(record.Type) {
+			addresses := domain.MaterializeAlias(record, b.aliases.Addresses(ctx, domain.AliasTarget(record)))
+			if len(addresses) > 0 {
+				addresses[0].Comment = record.Comment
+			}
+			records = append(records, addresses...)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// renderAllowTransfer returns the allow-transfer statement of the zone, none when named's default applies.
+func renderAllowTransfer(zone *domain.Zone) string {
+	if len(zone.AllowTransfer) == 0 && len(zone.TransferKeys) == 0 {
+		return ""
+	}
+	elements := append([]string{}, zone.AllowTransfer...)
+	for _, key := range zone.TransferKeys {
+		elements = append(elements, fmt.Sprintf(`key "%v"`, key.Name))
+	}
+	return fmt.Sprintf(" allow-transfer { %v };", addressMatchList(elements))
+}
+
+// renderAlsoNotify returns the also-notify statement of the zone, none without targets.
+func renderAlsoNotify(zone *domain.Zone) string {
+	if len(zone.AlsoNotify) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" also-notify { %v };", addressMatchList(zone.AlsoNotify))
+}
+
+func parseQueryLogLine(line string) *domain.QueryLogEntry {
+	match := queryLogPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+	return &domain.QueryLogEntry{
+		Time:         time.Now(),
+		ClientIP:     match[1],
+		Name:         match[2],
+		Type:         match[3],
+		ClientSubnet: match[4],
+	}
+}
+
+func quotedList(items []string) string {
+	list := ""
+	for _, item := range items {
+		list += fmt.Sprintf(`"%v"; `, item)
+	}
+	return strings.TrimSpace(list)
+}
+
+func addressMatchList(elements []string) string {
+	list := ""
+	for _, element := range elements {
+		list += fmt.Sprintf("%v; ", element)
+	}
+	return strings.TrimSpace(list)
+}
+
+// joinErrors adds errTemp to the errors met so far, err being nil before the first one.
+func joinErrors(err error, errTemp error) error {
+	if err == nil {
+		return errTemp
+	}
+	return errors.Wrap(errTemp, err.Error())
+}
+
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// chownBindUser hands the file over to the user named runs as, the file being left as it is on hosts without it.
+func chownBindUser(filePath string) error {
+	bind, err := user.Lookup(bindUser)
+	if err != nil {
+		return nil
+	}
+	uid, err := strconv.Atoi(bind.Uid)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(bind.Gid)
+	if err != nil {
+		return err
 	}
-	return
+	return os.Chown(filePath, uid, gid)
 }
 
 func writeFile(filePath, fileContents string) error {
@@ -0,0 +1,158 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	canaryRefreshInterval = time.Minute
+	// canaryAlertInterval is how often a canary record raises an event at most, a scan querying it over and over
+	// raising a single one.
+	canaryAlertInterval = 10 * time.Minute
+	// canaryAlertQueueSize is how many alerts wait to be written at most, the next ones being dropped.
+	canaryAlertQueueSize = 100
+)
+
+type canaryMonitor struct {
+	zoneRepo   domain.ZoneRepository
+	outboxRepo domain.OutboxRepository
+
+	lock        sync.Mutex
+	zoneDomains []string
+	// zones holds the zones having canary records by domain.
+	zones map[string]*domain.Zone
+	// alertedAt holds when the canary records last raised an event by record id.
+	alertedAt map[string]time.Time
+
+	alerts         chan []*domain.ChangeEvent
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewCanaryMonitor creates a listener raising a record.canary_queried event for the queries of the canary records,
+// the zones being reloaded once per minute.
+func NewCanaryMonitor(zoneRepo domain.ZoneRepository, outboxRepo domain.OutboxRepository) domain.CanaryMonitor {
+	return &canaryMonitor{
+		zoneRepo:       zoneRepo,
+		outboxRepo:     outboxRepo,
+		zones:          map[string]*domain.Zone{},
+		alertedAt:      map[string]time.Time{},
+		alerts:         make(chan []*domain.ChangeEvent, canaryAlertQueueSize),
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+// Start reloads the zones every refresh interval and writes the alerts to the outbox, out of the query log reader for
+// it not to wait on the database.
+func (m *canaryMonitor) Start(ctx context.Context) {
+	m.stoppedWg.Add(1)
+	go func() {
+		defer m.stoppedWg.Done()
+
+		m.refresh(ctx)
+		ticker := time.NewTicker(canaryRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.shutdownSignal:
+				// The alerts queued already are written before stopping.
+				for {
+					select {
+					case events := <-m.alerts:
+						m.addEvents(ctx, events)
+					default:
+						return
+					}
+				}
+			case <-ticker.C:
+				m.refresh(ctx)
+			case events := <-m.alerts:
+				m.addEvents(ctx, events)
+			}
+		}
+	}()
+}
+
+func (m *canaryMonitor) Shutdown(ctx context.Context) error {
+	m.shutdownSignal <- 1
+	m.stoppedWg.Wait()
+	return nil
+}
+
+func (m *canaryMonitor) OnQuery(entry *domain.QueryLogEntry) {
+	events := m.queriedCanaries(entry)
+	if len(events) == 0 {
+		return
+	}
+	select {
+	case m.alerts <- events:
+	default:
+		log.Println("Canary alerts are dropped, too many wait to be written")
+	}
+}
+
+// queriedCanaries returns the events of the canary records the query is answered with, leaving out the records which
+// raised one within the alert interval.
+func (m *canaryMonitor) queriedCanaries(entry *domain.QueryLogEntry) []*domain.ChangeEvent {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	zone, ok := m.zones[domain.FindZoneOfName(entry.Name, m.zoneDomains)]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	var events []*domain.ChangeEvent
+	for _, record := range zone.QueriedCanaries(entry.Name) {
+		if now.Sub(m.alertedAt[record.Id]) < canaryAlertInterval {
+			continue
+		}
+		m.alertedAt[record.Id] = now
+		event := domain.NewCanaryEvent(zone, record, entry)
+		log.Println("Canary queried:", event.Zone, record.Name, record.Type, event.Detail)
+		events = append(events, event)
+	}
+	return events
+}
+
+func (m *canaryMonitor) addEvents(ctx context.Context, events []*domain.ChangeEvent) {
+	err := m.outboxRepo.AddEvents(ctx, events)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// refresh reloads the managed zones.
+func (m *canaryMonitor) refresh(ctx context.Context) {
+	zones, err := m.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// Every zone domain is kept so that the names of a subzone are not matched with the records of its parent.
+	var zoneDomains []string
+	canaryZones := map[string]*domain.Zone{}
+	for _, zone := range zones {
+		zoneDomains = append(zoneDomains, zone.Domain)
+		for _, record := range zone.Records {
+			if record.Canary {
+				canaryZones[domain.NormalizeDomain(zone.Domain)] = zone
+				break
+			}
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.zoneDomains = zoneDomains
+	m.zones = canaryZones
+	for id, alertedAt := range m.alertedAt {
+		if time.Since(alertedAt) >= canaryAlertInterval {
+			delete(m.alertedAt, id)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package external
+
+import (
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type catalogLocalizer struct {
+	catalogs map[string]map[string]string
+	// patterns holds the messages of the catalogs formatted with %v by language, matched against the messages
+	// formatted out of them.
+	patterns map[string][]*catalogPattern
+}
+
+// catalogPattern matches the messages formatted out of a catalog message with %v, the formatted values being put in
+// place of the %v of the translation, in the same order.
+type catalogPattern struct {
+	message     *regexp.Regexp
+	translation string
+}
+
+// NewCatalogLocalizer reads the catalogs out of the <language>.json files of dir, a catalog being a JSON object
+// mapping messages to their translation.
+func NewCatalogLocalizer(fsys fs.FS, dir string) (domain.Localizer, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := map[string]map[string]string{domain.DefaultLanguage: {}}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		catalog := map[string]string{}
+		err = json.Unmarshal(content, &catalog)
+		if err != nil {
+			return nil, err
+		}
+		catalogs[strings.ToLower(strings.TrimSuffix(entry.Name(), ".json"))] = catalog
+	}
+
+	patterns := map[string][]*catalogPattern{}
+	for language, catalog := range catalogs {
+		patterns[language] = catalogPatterns(catalog)
+	}
+	return &catalogLocalizer{catalogs: catalogs, patterns: patterns}, nil
+}
+
+// catalogPatterns returns the patterns of the messages of the catalog formatted with %v, the longest messages first
+// for the most specific one to match.
+func catalogPatterns(catalog map[string]string) []*catalogPattern {
+	var messages []string
+	for message := range catalog {
+		if strings.Contains(message, "%v") {
+			messages = append(messages, message)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if len(messages[i]) != len(messages[j]) {
+			return len(messages[i]) > len(messages[j])
+		}
+		return messages[i] < messages[j]
+	})
+
+	var patterns []*catalogPattern
+	for _, message := range messages {
+		parts := strings.Split(message, "%v")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		patterns = append(patterns, &catalogPattern{
+			message:     regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$"),
+			translation: catalog[message],
+		})
+	}
+	return patterns
+}
+
+func (l *catalogLocalizer) Languages() []string {
+	var languages []string
+	for language := range l.catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func (l *catalogLocalizer) Catalog(language string) map[string]string {
+	return l.catalogs[language]
+}
+
+// Translate translates the message, or its beginning up to ": " when it wraps an error, the messages formatted out of
+// a catalog message with %v being translated as well.
+func (l *catalogLocalizer) Translate(language, message string) string {
+	if translation, ok := l.translate(language, message); ok {
+		return translation
+	}
+	if idx := strings.Index(message, ": "); idx > 0 {
+		if translation, ok := l.translate(language, message[:idx]); ok {
+			return translation + message[idx:]
+		}
+	}
+	return message
+}
+
+func (l *catalogLocalizer) translate(language, message string) (string, bool) {
+	if translation, ok := l.catalogs[language][message]; ok {
+		return translation, true
+	}
+	for _, pattern := range l.patterns[language] {
+		values := pattern.message.FindStringSubmatch(message)
+		if values == nil {
+			continue
+		}
+		parts := strings.Split(pattern.translation, "%v")
+		translation := parts[0]
+		for i, part := range parts[1:] {
+			if i+1 < len(values) {
+				translation += values[i+1]
+			}
+			translation += part
+		}
+		return translation, true
+	}
+	return "", false
+}
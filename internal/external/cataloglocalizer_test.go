@@ -0,0 +1,32 @@
+package external
+
+import (
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"os"
+	"testing"
+)
+
+func TestCatalogLocalizerTranslatesTheFormattedMessages(t *testing.T) {
+	localizer, err := NewCatalogLocalizer(os.DirFS("../.."), "web/locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := domain.NewDefaultServerOptions()
+	view := &domain.View{Name: "internal", MatchClients: []string{"10.0.0.0/8"}}
+	err = options.SetViews([]*domain.View{view, view})
+	if err == nil {
+		t.Fatal("expected the views defined twice to be refused")
+	}
+	if got, want := localizer.Translate("id", err.Error()), "view internal didefinisikan dua kali"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// The message wrapping an error is translated up to the error.
+	if got, want := localizer.Translate("id", err.Error()+": internal"), "view internal didefinisikan dua kali: "+
+		"internal"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := localizer.Translate("id", "zone is not found"), "zona tidak ditemukan"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
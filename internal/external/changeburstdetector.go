@@ -0,0 +1,65 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+type changeBurstDetector struct {
+	settings   domain.SettingsProvider
+	auditRepo  domain.AuditLogRepository
+	outboxRepo domain.OutboxRepository
+
+	lock sync.Mutex
+	// flaggedAt holds when the bursts were last flagged, by zone, a burst being flagged once per window.
+	flaggedAt map[string]time.Time
+}
+
+// NewChangeBurstDetector creates a listener counting the changes of the zone of every change event, the zones
+// changed much more often than usual being flagged with a zone.change_burst event, see domain.ChangeBurstSettings.
+func NewChangeBurstDetector(
+	settings domain.SettingsProvider, auditRepo domain.AuditLogRepository, outboxRepo domain.OutboxRepository,
+) domain.ChangeEventListener {
+	return &changeBurstDetector{
+		settings:   settings,
+		auditRepo:  auditRepo,
+		outboxRepo: outboxRepo,
+		flaggedAt:  map[string]time.Time{},
+	}
+}
+
+func (d *changeBurstDetector) OnChangeEvent(ctx context.Context, event *domain.ChangeEvent) {
+	settings := d.settings.Settings().ChangeBurst
+	if settings == nil || event.IsAlert() {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	now := time.Now()
+	if now.Sub(d.flaggedAt[event.Zone]) < settings.Window() {
+		return
+	}
+
+	counts, err := d.auditRepo.CountChanges(ctx, event.Zone, now, settings.Window(), settings.Windows())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	burst := settings.DetectChangeBurst(event.Zone, counts)
+	if burst == nil {
+		return
+	}
+
+	burstEvent := burst.Event()
+	log.Println("Change burst:", burstEvent.Zone, burstEvent.Detail)
+	err = d.outboxRepo.AddEvents(ctx, []*domain.ChangeEvent{burstEvent})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	d.flaggedAt[event.Zone] = now
+}
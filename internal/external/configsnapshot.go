@@ -0,0 +1,34 @@
+package external
+
+import "os"
+
+// configSnapshot holds the contents of the configs of named, by path, as they were before a reload, for the reload to
+// restore them when named fails to load the new ones.
+type configSnapshot map[string][]byte
+
+// snapshotConfigs reads the files at paths, the missing ones being left out.
+func snapshotConfigs(paths []string) (configSnapshot, error) {
+	snapshot := configSnapshot{}
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshot[path] = contents
+	}
+	return snapshot, nil
+}
+
+// restore writes the files back as they were read, the files written since being left as they are.
+func (s configSnapshot) restore() error {
+	var err error
+	for path, contents := range s {
+		if errTemp := writeFile(path, string(contents)); errTemp != nil {
+			err = joinErrors(err, errTemp)
+		}
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package external
+
+import (
+	"errors"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestConfigs writes the configs named served, snapshots them, then writes the new ones over them, a new config
+// being added along.
+func writeTestConfigs(t *testing.T, dir string) (configSnapshot, []string) {
+	paths := []string{filepath.Join(dir, "named.conf"), filepath.Join(dir, "zone-example.com")}
+	for _, path := range paths {
+		err := writeFile(path, "served "+filepath.Base(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshot, err := snapshotConfigs(append(paths, filepath.Join(dir, "zone-example.org")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range append(paths, filepath.Join(dir, "zone-example.org")) {
+		err = writeFile(path, "new "+filepath.Base(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return snapshot, paths
+}
+
+func checkRestoredConfigs(t *testing.T, dir string, paths []string) {
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(contents) != "served "+filepath.Base(path) {
+			t.Errorf("expected %v to be restored, got %q", path, contents)
+		}
+	}
+	// The configs missing from the snapshot are left as they are.
+	if !fileExists(filepath.Join(dir, "zone-example.org")) {
+		t.Error("expected the config written since the snapshot to be kept")
+	}
+}
+
+func TestConfigSnapshotRestore(t *testing.T) {
+	dir := t.TempDir()
+	snapshot, paths := writeTestConfigs(t, dir)
+	if len(snapshot) != len(paths) {
+		t.Fatalf("expected the missing config left out of the snapshot, got %v configs", len(snapshot))
+	}
+
+	err := snapshot.restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRestoredConfigs(t, dir, paths)
+}
+
+func TestRollbackRestoresTheConfigsOnly(t *testing.T) {
+	dir := t.TempDir()
+	snapshot, paths := writeTestConfigs(t, dir)
+
+	b := &bind9Server{stopped: make(chan struct{})}
+	writeErr := errors.New("zone file can not be written")
+	err := b.rollback(snapshot, writeErr, false)
+	if err != writeErr {
+		t.Fatalf("expected the error of the reload alone, got %v", err)
+	}
+	checkRestoredConfigs(t, dir, paths)
+}
+
+func TestRollbackReloadsTheRestoredConfigs(t *testing.T) {
+	dir := t.TempDir()
+	snapshot, paths := writeTestConfigs(t, dir)
+
+	// named is shut down, the reload of the restored configs failing without running it.
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+	b := &bind9Server{config: config, stopped: make(chan struct{})}
+	close(b.stopped)
+	reloadErr := errors.New("named exited after the reload")
+	err := b.rollback(snapshot, reloadErr, true)
+	if !errors.Is(err, reloadErr) {
+		t.Fatalf("expected the error of the reload, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "restoring the last known good configs failed: named is shut down") {
+		t.Fatalf("expected the restored configs to be reloaded, got %v", err)
+	}
+	checkRestoredConfigs(t, dir, paths)
+}
@@ -0,0 +1,168 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	failoverProbeTimeout   = 3 * time.Second
+	failoverPromoteTimeout = time.Minute
+)
+
+type failoverWatchdog struct {
+	settings domain.SettingsProvider
+	interval time.Duration
+	client   *http.Client
+
+	statusLock  sync.RWMutex
+	status      *domain.FailoverStatus
+	promoteLock sync.Mutex
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewFailoverWatchdog creates a watchdog probing the API and the named of the primary pair every interval while the
+// failover settings make this manager a standby. The primary pair is considered down when either probe fails.
+func NewFailoverWatchdog(settings domain.SettingsProvider, interval time.Duration) domain.FailoverWatchdog {
+	return &failoverWatchdog{
+		settings:       settings,
+		interval:       interval,
+		client:         &http.Client{Timeout: failoverProbeTimeout},
+		status:         &domain.FailoverStatus{},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (f *failoverWatchdog) Start(ctx context.Context) {
+	f.stoppedWg.Add(1)
+	go func() {
+		defer f.stoppedWg.Done()
+
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.shutdownSignal:
+				return
+			case <-ticker.C:
+				f.watch(ctx)
+			}
+		}
+	}()
+}
+
+func (f *failoverWatchdog) Shutdown(ctx context.Context) error {
+	f.shutdownSignal <- 1
+	f.stoppedWg.Wait()
+	return nil
+}
+
+func (f *failoverWatchdog) Status() *domain.FailoverStatus {
+	f.statusLock.RLock()
+	defer f.statusLock.RUnlock()
+
+	status := *f.status
+	if failover := f.settings.Settings().Failover; failover != nil {
+		status.Role = failover.Role
+	}
+	return &status
+}
+
+func (f *failoverWatchdog) Promote(ctx context.Context) error {
+	failover := f.settings.Settings().Failover
+	if failover == nil || failover.Role != domain.FailoverRoleStandby {
+		return domain.ErrorFailoverNotStandby
+	}
+	return f.promote(ctx, failover)
+}
+
+func (f *failoverWatchdog) watch(ctx context.Context) {
+	failover := f.settings.Settings().Failover
+	if failover == nil || failover.Role != domain.FailoverRoleStandby || f.Status().Promoted {
+		return
+	}
+
+	err := f.probe(ctx, failover)
+
+	f.statusLock.Lock()
+	f.status.LastChecked = time.Now()
+	if err != nil {
+		f.status.LastError = err.Error()
+		f.status.ConsecutiveFailures++
+	} else {
+		f.status.LastError = ""
+		f.status.ConsecutiveFailures = 0
+	}
+	wasHealthy := f.status.PrimaryHealthy
+	f.status.PrimaryHealthy = f.status.ConsecutiveFailures < failover.Threshold()
+	f.statusLock.Unlock()
+
+	if f.Status().PrimaryHealthy {
+		if !wasHealthy {
+			log.Println("Primary pair is reachable")
+		}
+		return
+	}
+
+	log.Printf("Primary pair is down, taking over: %v\n", err)
+	err = f.promote(ctx, failover)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+func (f *failoverWatchdog) probe(ctx context.Context, failover *domain.FailoverSettings) error {
+	apiUrl := strings.TrimSuffix(failover.PrimaryApiUrl, "/") + "/features"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return err
+	}
+	res, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("primary API is unreachable: %w", err)
+	}
+	res.Body.Close()
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("primary API responded with status %v", res.Status)
+	}
+
+	_, err = probeDNS(ctx, failover.PrimaryDnsAddress, failover.ProbeName, failoverProbeTimeout)
+	if err != nil {
+		return fmt.Errorf("primary named is unreachable: %w", err)
+	}
+	return nil
+}
+
+// promote runs the promote command, the takeover is retried on the next probe when the command fails.
+func (f *failoverWatchdog) promote(ctx context.Context, failover *domain.FailoverSettings) error {
+	f.promoteLock.Lock()
+	defer f.promoteLock.Unlock()
+	if f.Status().Promoted {
+		return nil
+	}
+
+	promoteCtx, cancel := context.WithTimeout(ctx, failoverPromoteTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(promoteCtx, failover.PromoteCommand[0], failover.PromoteCommand[1:]...).
+		CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("promote command failed: %w: %v", err, strings.TrimSpace(string(output)))
+	}
+
+	f.statusLock.Lock()
+	f.status.Promoted = true
+	f.status.PromotedAt = time.Now()
+	f.statusLock.Unlock()
+	log.Println("This manager is promoted to primary")
+	return nil
+}
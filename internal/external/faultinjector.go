@@ -0,0 +1,223 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+type faultInjector struct {
+	config domain.Config
+	lock   sync.Mutex
+	faults []*domain.Fault
+}
+
+// NewFaultInjector holds the faults injected in chaos mode, see NewFaultyZoneRepository and NewFaultyDNSServer.
+func NewFaultInjector(config domain.Config) domain.FaultInjector {
+	return &faultInjector{config: config}
+}
+
+func (f *faultInjector) Inject(ctx context.Context, fault *domain.Fault) error {
+	if fault.Type == domain.FaultNamedCrash {
+		log.Println("Injected fault: halting Bind9")
+		_, err := runRndc(ctx, f.config, "halt")
+		return err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	fault.InjectedAt = time.Now()
+	f.remove(fault.Type)
+	f.faults = append(f.faults, fault)
+	log.Printf("Injected fault: %v\n", fault.Type)
+	return nil
+}
+
+func (f *faultInjector) Faults() []*domain.Fault {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var faults []*domain.Fault
+	for _, fault := range f.faults {
+		faultCopy := *fault
+		faults = append(faults, &faultCopy)
+	}
+	return faults
+}
+
+func (f *faultInjector) Clear() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.faults = nil
+	log.Println("Injected faults are cleared")
+}
+
+func (f *faultInjector) Take(faultType string) *domain.Fault {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, fault := range f.faults {
+		if fault.Type != faultType {
+			continue
+		}
+		taken := *fault
+		if fault.Remaining > 0 {
+			fault.Remaining--
+			if fault.Remaining == 0 {
+				f.remove(faultType)
+			}
+		}
+		return &taken
+	}
+	return nil
+}
+
+func (f *faultInjector) remove(faultType string) {
+	var faults []*domain.Fault
+	for _, fault := range f.faults {
+		if fault.Type != faultType {
+			faults = append(faults, fault)
+		}
+	}
+	f.faults = faults
+}
+
+type faultyZoneRepository struct {
+	repo     domain.ZoneRepository
+	injector domain.FaultInjector
+}
+
+// NewFaultyZoneRepository decorates repo, failing its calls with domain.ErrorInjectedFault while a repository_error
+// fault is injected.
+func NewFaultyZoneRepository(repo domain.ZoneRepository, injector domain.FaultInjector) domain.ZoneRepository {
+	return &faultyZoneRepository{repo: repo, injector: injector}
+}
+
+func (f *faultyZoneRepository) GetAllZones(ctx context.Context) ([]*domain.Zone, error) {
+	if err := f.fault(); err != nil {
+		return nil, err
+	}
+	return f.repo.GetAllZones(ctx)
+}
+
+func (f *faultyZoneRepository) FindZones(
+	ctx context.Context, filter domain.ZoneFilter, page domain.Page,
+) ([]*domain.Zone, int, error) {
+	if err := f.fault(); err != nil {
+		return nil, 0, err
+	}
+	return f.repo.FindZones(ctx, filter, page)
+}
+
+func (f *faultyZoneRepository) GetZoneById(ctx context.Context, zoneId string) (*domain.Zone, error) {
+	if err := f.fault(); err != nil {
+		return nil, err
+	}
+	return f.repo.GetZoneById(ctx, zoneId)
+}
+
+func (f *faultyZoneRepository) GetZoneByDomain(ctx context.Context, domainName string) (*domain.Zone, error) {
+	if err := f.fault(); err != nil {
+		return nil, err
+	}
+	return f.repo.GetZoneByDomain(ctx, domainName)
+}
+
+func (f *faultyZoneRepository) Persist(ctx context.Context, zone *domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.Persist(ctx, zone)
+}
+
+func (f *faultyZoneRepository) PersistAll(ctx context.Context, zones []*domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.PersistAll(ctx, zones)
+}
+
+func (f *faultyZoneRepository) PersistSerial(ctx context.Context, zone *domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.PersistSerial(ctx, zone)
+}
+
+func (f *faultyZoneRepository) PersistFilePath(ctx context.Context, zone *domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.PersistFilePath(ctx, zone)
+}
+
+func (f *faultyZoneRepository) MarkApplied(ctx context.Context, zone *domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.MarkApplied(ctx, zone)
+}
+
+func (f *faultyZoneRepository) Delete(ctx context.Context, zone *domain.Zone) error {
+	if err := f.fault(); err != nil {
+		return err
+	}
+	return f.repo.Delete(ctx, zone)
+}
+
+func (f *faultyZoneRepository) fault() error {
+	if f.injector.Take(domain.FaultRepositoryError) != nil {
+		return domain.ErrorInjectedFault
+	}
+	return nil
+}
+
+// faultyDNSServer only overrides the reloads, the other calls going straight to the decorated server.
+type faultyDNSServer struct {
+	domain.DNSServer
+	injector domain.FaultInjector
+}
+
+// NewFaultyDNSServer decorates server, delaying its reloads while a slow_reload fault is injected.
+func NewFaultyDNSServer(server domain.DNSServer, injector domain.FaultInjector) domain.DNSServer {
+	return &faultyDNSServer{DNSServer: server, injector: injector}
+}
+
+func (f *faultyDNSServer) Reload(ctx context.Context) error {
+	err := f.delay(ctx)
+	if err != nil {
+		return err
+	}
+	return f.DNSServer.Reload(ctx)
+}
+
+func (f *faultyDNSServer) UpdateAndReload(ctx context.Context) error {
+	err := f.delay(ctx)
+	if err != nil {
+		return err
+	}
+	return f.DNSServer.UpdateAndReload(ctx)
+}
+
+func (f *faultyDNSServer) UpdateAndReloadZone(ctx context.Context, domain string) error {
+	err := f.delay(ctx)
+	if err != nil {
+		return err
+	}
+	return f.DNSServer.UpdateAndReloadZone(ctx, domain)
+}
+
+func (f *faultyDNSServer) delay(ctx context.Context) error {
+	fault := f.injector.Take(domain.FaultSlowReload)
+	if fault == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(fault.Delay):
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+)
+
+var errorInvalidSettings = errors.New("settings file is not valid")
+
+type fileSettingsProvider struct {
+	config domain.Config
+
+	lock      sync.RWMutex
+	settings  *domain.Settings
+	listeners []domain.SettingsListener
+}
+
+// NewFileSettingsProvider reads the settings out of the JSON file at config.SettingsPath(), the default settings are
+// used while the file does not exist.
+func NewFileSettingsProvider(config domain.Config) domain.SettingsProvider {
+	return &fileSettingsProvider{config: config, settings: domain.DefaultSettings()}
+}
+
+func (f *fileSettingsProvider) Settings() *domain.Settings {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.settings
+}
+
+func (f *fileSettingsProvider) Reload(ctx context.Context) (*domain.Settings, error) {
+	settings, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	f.lock.Lock()
+	f.settings = settings
+	listeners := append([]domain.SettingsListener{}, f.listeners...)
+	f.lock.Unlock()
+
+	for _, listener := range listeners {
+		listener.OnSettingsChanged(settings)
+	}
+	return settings, nil
+}
+
+func (f *fileSettingsProvider) Subscribe(listener domain.SettingsListener) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.listeners = append(f.listeners, listener)
+}
+
+func (f *fileSettingsProvider) read() (*domain.Settings, error) {
+	settings := domain.DefaultSettings()
+	content, err := os.ReadFile(f.config.SettingsPath())
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(settings)
+	if err != nil {
+		return nil, errors.Wrap(err, f.config.SettingsPath())
+	}
+	if !settings.IsValid() {
+		return nil, errors.Wrap(errorInvalidSettings, f.config.SettingsPath())
+	}
+	return settings, nil
+}
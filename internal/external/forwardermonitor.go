@@ -0,0 +1,184 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	forwarderProbeName     = "a.root-servers.net"
+	forwarderProbeTimeout  = 2 * time.Second
+	forwarderFailThreshold = 3
+)
+
+type forwarderMonitor struct {
+	serverRepo     domain.ServerRepository
+	interval       time.Duration
+	onHealthChange func(ctx context.Context) error
+
+	statusLock sync.RWMutex
+	statuses   map[string]*domain.ForwarderStatus
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewForwarderMonitor creates a monitor probing the configured forwarders every interval. onHealthChange is called
+// whenever a forwarder becomes dead or alive again, so the DNS server configuration can be regenerated.
+func NewForwarderMonitor(
+	serverRepo domain.ServerRepository, interval time.Duration, onHealthChange func(ctx context.Context) error,
+) domain.ForwarderMonitor {
+	return &forwarderMonitor{
+		serverRepo:     serverRepo,
+		interval:       interval,
+		onHealthChange: onHealthChange,
+		statuses:       map[string]*domain.ForwarderStatus{},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (f *forwarderMonitor) Start(ctx context.Context) {
+	f.stoppedWg.Add(1)
+	go func() {
+		defer f.stoppedWg.Done()
+
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.shutdownSignal:
+				return
+			case <-ticker.C:
+				f.probeAll(ctx)
+			}
+		}
+	}()
+}
+
+func (f *forwarderMonitor) Shutdown(ctx context.Context) error {
+	f.shutdownSignal <- 1
+	f.stoppedWg.Wait()
+	return nil
+}
+
+func (f *forwarderMonitor) Statuses() []*domain.ForwarderStatus {
+	f.statusLock.RLock()
+	defer f.statusLock.RUnlock()
+
+	var statuses []*domain.ForwarderStatus
+	for _, status := range f.statuses {
+		statusCopy := *status
+		statuses = append(statuses, &statusCopy)
+	}
+	return statuses
+}
+
+func (f *forwarderMonitor) HealthyForwarders(forwarders []string) []string {
+	f.statusLock.RLock()
+	defer f.statusLock.RUnlock()
+
+	var healthy []string
+	for _, forwarder := range forwarders {
+		status, ok := f.statuses[forwarder]
+		if !ok || status.Healthy {
+			healthy = append(healthy, forwarder)
+		}
+	}
+	if len(healthy) == 0 {
+		return forwarders
+	}
+	return healthy
+}
+
+func (f *forwarderMonitor) probeAll(ctx context.Context) {
+	options, err := f.serverRepo.GetOptions(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	var forwarders []string
+	if options.IsRecursive() {
+		forwarders = options.Forwarders
+	}
+
+	results := make([]*domain.ForwarderStatus, len(forwarders))
+	var probeWg sync.WaitGroup
+	for i, forwarder := range forwarders {
+		probeWg.Add(1)
+		go func(i int, forwarder string) {
+			defer probeWg.Done()
+			results[i] = f.probe(ctx, forwarder)
+		}(i, forwarder)
+	}
+	probeWg.Wait()
+
+	changed := false
+	f.statusLock.Lock()
+	statuses := map[string]*domain.ForwarderStatus{}
+	for _, result := range results {
+		previous, ok := f.statuses[result.Address]
+		wasHealthy := !ok || previous.Healthy
+		if ok && result.LastError != "" {
+			result.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+		}
+		result.Healthy = result.ConsecutiveFailures < forwarderFailThreshold
+		if result.Healthy != wasHealthy {
+			changed = true
+			if result.Healthy {
+				log.Printf("Forwarder %v is alive again\n", result.Address)
+			} else {
+				log.Printf("Forwarder %v is dead, skipping it: %v\n", result.Address, result.LastError)
+			}
+		}
+		statuses[result.Address] = result
+	}
+	f.statuses = statuses
+	f.statusLock.Unlock()
+
+	if changed && f.onHealthChange != nil {
+		err = f.onHealthChange(ctx)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func (f *forwarderMonitor) probe(ctx context.Context, forwarder string) *domain.ForwarderStatus {
+	status := &domain.ForwarderStatus{Address: forwarder, LastChecked: time.Now()}
+	latency, err := probeDNS(ctx, forwarder, forwarderProbeName, forwarderProbeTimeout)
+	status.Latency = latency
+	if err != nil {
+		status.LastError = err.Error()
+		status.ConsecutiveFailures = 1
+	}
+	return status
+}
+
+// probeDNS resolves name through the DNS server listening on address, any answer, even a negative one, proves the
+// server is alive.
+func probeDNS(ctx context.Context, address string, name string, timeout time.Duration) (time.Duration, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: timeout}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(address, "53"))
+		},
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := resolver.LookupHost(probeCtx, name)
+	latency := time.Since(start)
+
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		err = nil
+	}
+	return latency, err
+}
@@ -0,0 +1,99 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const gandiEndpoint = "https://api.gandi.net/v5/domain"
+
+// gandiRegistrar uses the Gandi v5 domain API, authenticated with a personal access token.
+type gandiRegistrar struct {
+	account *domain.RegistrarAccount
+	client  *http.Client
+}
+
+func (g *gandiRegistrar) SetNameServers(ctx context.Context, domainName string, nameServers []string) error {
+	return g.do(ctx, http.MethodPut, "/domains/"+domainName+"/nameservers", map[string]interface{}{
+		"nameservers": nameServers,
+	})
+}
+
+// SetDelegationSigners replaces the keys published for the domain, Gandi computing the DS records out of the keys.
+func (g *gandiRegistrar) SetDelegationSigners(
+	ctx context.Context, domainName string, signers []*domain.DelegationSigner,
+) error {
+	keys := []struct {
+		Id string `json:"id"`
+	}{}
+	err := g.request(ctx, http.MethodGet, "/domains/"+domainName+"/dnskeys", nil, &keys)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		err = g.do(ctx, http.MethodDelete, "/domains/"+domainName+"/dnskeys/"+key.Id, nil)
+		if err != nil {
+			return err
+		}
+	}
+	for _, signer := range signers {
+		if signer.PublicKey == "" {
+			return fmt.Errorf("gandi requires the public key of the DS record with key tag %v", signer.KeyTag)
+		}
+		err = g.do(ctx, http.MethodPost, "/domains/"+domainName+"/dnskeys", map[string]interface{}{
+			"algorithm":  signer.Algorithm,
+			"flags":      signer.Flags,
+			"public_key": signer.PublicKey,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *gandiRegistrar) do(ctx context.Context, method, path string, body interface{}) error {
+	return g.request(ctx, method, path, body, nil)
+}
+
+func (g *gandiRegistrar) request(ctx context.Context, method, path string, body, res interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	url := strings.TrimSuffix(registrarEndpoint(g.account, gandiEndpoint), "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.account.ApiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(httpRes.Body, 1024))
+		return fmt.Errorf("gandi responded with status %v: %v", httpRes.Status, strings.TrimSpace(string(message)))
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(httpRes.Body).Decode(res)
+}
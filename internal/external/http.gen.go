@@ -6,17 +6,171 @@ package external
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/labstack/echo/v4"
 )
 
+// Defines values for AuditExporterReqProtocol.
+const (
+	AuditExporterReqProtocolTcp AuditExporterReqProtocol = "tcp"
+
+	AuditExporterReqProtocolUdp AuditExporterReqProtocol = "udp"
+)
+
+// Defines values for AuditExporterReqType.
+const (
+	AuditExporterReqTypeHttp AuditExporterReqType = "http"
+
+	AuditExporterReqTypeSyslog AuditExporterReqType = "syslog"
+)
+
+// Defines values for AuditExporterResProtocol.
+const (
+	AuditExporterResProtocolTcp AuditExporterResProtocol = "tcp"
+
+	AuditExporterResProtocolUdp AuditExporterResProtocol = "udp"
+)
+
+// Defines values for AuditExporterResType.
+const (
+	AuditExporterResTypeHttp AuditExporterResType = "http"
+
+	AuditExporterResTypeSyslog AuditExporterResType = "syslog"
+)
+
+// Defines values for ConsistencyActionAction.
+const (
+	ConsistencyActionActionMigrated ConsistencyActionAction = "migrated"
+
+	ConsistencyActionActionMissing ConsistencyActionAction = "missing"
+
+	ConsistencyActionActionRecreated ConsistencyActionAction = "recreated"
+
+	ConsistencyActionActionRemoved ConsistencyActionAction = "removed"
+
+	ConsistencyActionActionRenamed ConsistencyActionAction = "renamed"
+
+	ConsistencyActionActionSkipped ConsistencyActionAction = "skipped"
+
+	ConsistencyActionActionUnknown ConsistencyActionAction = "unknown"
+)
+
+// Defines values for CreateZoneJSONBodySerialStrategy.
+const (
+	CreateZoneJSONBodySerialStrategyDate CreateZoneJSONBodySerialStrategy = "date"
+
+	CreateZoneJSONBodySerialStrategyIncrement CreateZoneJSONBodySerialStrategy = "increment"
+
+	CreateZoneJSONBodySerialStrategyUnix CreateZoneJSONBodySerialStrategy = "unix"
+)
+
+// Defines values for DefaultZonesReqMode.
+const (
+	DefaultZonesReqModeCustom DefaultZonesReqMode = "custom"
+
+	DefaultZonesReqModeDisabled DefaultZonesReqMode = "disabled"
+
+	DefaultZonesReqModeImage DefaultZonesReqMode = "image"
+)
+
+// Defines values for DefaultZonesResMode.
+const (
+	DefaultZonesResModeCustom DefaultZonesResMode = "custom"
+
+	DefaultZonesResModeDisabled DefaultZonesResMode = "disabled"
+
+	DefaultZonesResModeImage DefaultZonesResMode = "image"
+)
+
+// Defines values for ExportZoneParamsFormat.
+const (
+	ExportZoneParamsFormatTinydns ExportZoneParamsFormat = "tinydns"
+
+	ExportZoneParamsFormatUnbound ExportZoneParamsFormat = "unbound"
+
+	ExportZoneParamsFormatZoneFile ExportZoneParamsFormat = "zone-file"
+)
+
+// Defines values for FaultReqType.
+const (
+	FaultReqTypeNamedCrash FaultReqType = "named_crash"
+
+	FaultReqTypeRepositoryError FaultReqType = "repository_error"
+
+	FaultReqTypeSlowReload FaultReqType = "slow_reload"
+)
+
+// Defines values for FaultResType.
+const (
+	FaultResTypeRepositoryError FaultResType = "repository_error"
+
+	FaultResTypeSlowReload FaultResType = "slow_reload"
+)
+
+// Defines values for GetZoneReportParamsFormat.
+const (
+	GetZoneReportParamsFormatHtml GetZoneReportParamsFormat = "html"
+
+	GetZoneReportParamsFormatMarkdown GetZoneReportParamsFormat = "markdown"
+)
+
+// Defines values for InstanceResRole.
+const (
+	InstanceResRoleFollower InstanceResRole = "follower"
+
+	InstanceResRoleLeader InstanceResRole = "leader"
+)
+
+// Defines values for InstanceResSyncStatus.
+const (
+	InstanceResSyncStatusInSync InstanceResSyncStatus = "in_sync"
+
+	InstanceResSyncStatusSchemaMismatch InstanceResSyncStatus = "schema_mismatch"
+
+	InstanceResSyncStatusStale InstanceResSyncStatus = "stale"
+)
+
+// Defines values for ManagedTsigKeyReqAlgorithm.
+const (
+	ManagedTsigKeyReqAlgorithmHmacMd5 ManagedTsigKeyReqAlgorithm = "hmac-md5"
+
+	ManagedTsigKeyReqAlgorithmHmacSha1 ManagedTsigKeyReqAlgorithm = "hmac-sha1"
+
+	ManagedTsigKeyReqAlgorithmHmacSha224 ManagedTsigKeyReqAlgorithm = "hmac-sha224"
+
+	ManagedTsigKeyReqAlgorithmHmacSha256 ManagedTsigKeyReqAlgorithm = "hmac-sha256"
+
+	ManagedTsigKeyReqAlgorithmHmacSha384 ManagedTsigKeyReqAlgorithm = "hmac-sha384"
+
+	ManagedTsigKeyReqAlgorithmHmacSha512 ManagedTsigKeyReqAlgorithm = "hmac-sha512"
+)
+
+// Defines values for NetworkStatsSource.
+const (
+	NetworkStatsSourceClient NetworkStatsSource = "client"
+
+	NetworkStatsSourceEcs NetworkStatsSource = "ecs"
+)
+
+// Defines values for RecordOperationAction.
+const (
+	RecordOperationActionCreate RecordOperationAction = "create"
+
+	RecordOperationActionDelete RecordOperationAction = "delete"
+
+	RecordOperationActionUpdate RecordOperationAction = "update"
+)
+
 // Defines values for RecordReqType.
 const (
 	RecordReqTypeA RecordReqType = "A"
 
 	RecordReqTypeAAAA RecordReqType = "AAAA"
 
+	RecordReqTypeALIAS RecordReqType = "ALIAS"
+
 	RecordReqTypeCAA RecordReqType = "CAA"
 
 	RecordReqTypeCNAME RecordReqType = "CNAME"
@@ -48,161 +202,3331 @@ const (
 
 	RecordResTypeAAAA RecordResType = "AAAA"
 
+	RecordResTypeALIAS RecordResType = "ALIAS"
+
 	RecordResTypeCAA RecordResType = "CAA"
 
 	RecordResTypeCNAME RecordResType = "CNAME"
 
-	RecordResTypeDNSKEY RecordResType = "DNSKEY"
+	RecordResTypeDNSKEY RecordResType = "DNSKEY"
+
+	RecordResTypeIPSECKEY RecordResType = "IPSECKEY"
+
+	RecordResTypeKEY RecordResType = "KEY"
+
+	RecordResTypeMX RecordResType = "MX"
+
+	RecordResTypeNS RecordResType = "NS"
+
+	RecordResTypePTR RecordResType = "PTR"
+
+	RecordResTypeSPF RecordResType = "SPF"
+
+	RecordResTypeSRV RecordResType = "SRV"
+
+	RecordResTypeTLSA RecordResType = "TLSA"
+
+	RecordResTypeTXT RecordResType = "TXT"
+)
+
+// Defines values for RecursionReqMode.
+const (
+	RecursionReqModeAuthoritative RecursionReqMode = "authoritative"
+
+	RecursionReqModeRecursive RecursionReqMode = "recursive"
+)
+
+// Defines values for RecursionResMode.
+const (
+	RecursionResModeAuthoritative RecursionResMode = "authoritative"
+
+	RecursionResModeRecursive RecursionResMode = "recursive"
+)
+
+// Defines values for RootHintsReqMode.
+const (
+	RootHintsReqModeCustom RootHintsReqMode = "custom"
+
+	RootHintsReqModeImage RootHintsReqMode = "image"
+
+	RootHintsReqModeRefresh RootHintsReqMode = "refresh"
+)
+
+// Defines values for RootHintsResMode.
+const (
+	RootHintsResModeCustom RootHintsResMode = "custom"
+
+	RootHintsResModeImage RootHintsResMode = "image"
+
+	RootHintsResModeRefresh RootHintsResMode = "refresh"
+)
+
+// Defines values for SettingsResLogLevel.
+const (
+	SettingsResLogLevelDebug SettingsResLogLevel = "debug"
+
+	SettingsResLogLevelError SettingsResLogLevel = "error"
+
+	SettingsResLogLevelInfo SettingsResLogLevel = "info"
+
+	SettingsResLogLevelWarn SettingsResLogLevel = "warn"
+)
+
+// Defines values for SoaResSerialStrategy.
+const (
+	SoaResSerialStrategyDate SoaResSerialStrategy = "date"
+
+	SoaResSerialStrategyIncrement SoaResSerialStrategy = "increment"
+
+	SoaResSerialStrategyUnix SoaResSerialStrategy = "unix"
+)
+
+// Defines values for TsigKeyReqAlgorithm.
+const (
+	TsigKeyReqAlgorithmHmacMd5 TsigKeyReqAlgorithm = "hmac-md5"
+
+	TsigKeyReqAlgorithmHmacSha1 TsigKeyReqAlgorithm = "hmac-sha1"
+
+	TsigKeyReqAlgorithmHmacSha224 TsigKeyReqAlgorithm = "hmac-sha224"
+
+	TsigKeyReqAlgorithmHmacSha256 TsigKeyReqAlgorithm = "hmac-sha256"
+
+	TsigKeyReqAlgorithmHmacSha384 TsigKeyReqAlgorithm = "hmac-sha384"
+
+	TsigKeyReqAlgorithmHmacSha512 TsigKeyReqAlgorithm = "hmac-sha512"
+)
+
+// Defines values for UpdateZoneJSONBodySerialStrategy.
+const (
+	UpdateZoneJSONBodySerialStrategyDate UpdateZoneJSONBodySerialStrategy = "date"
+
+	UpdateZoneJSONBodySerialStrategyIncrement UpdateZoneJSONBodySerialStrategy = "increment"
+
+	UpdateZoneJSONBodySerialStrategyUnix UpdateZoneJSONBodySerialStrategy = "unix"
+)
+
+// Defines values for ZoneResStatus.
+const (
+	ZoneResStatusApplied ZoneResStatus = "applied"
+
+	ZoneResStatusPending ZoneResStatus = "pending"
+)
+
+// AuditExporterReq defines model for audit-exporter-req.
+type AuditExporterReq struct {
+	// host:port of the syslog collector, or URL the events are POSTed to
+	Address string `json:"address"`
+	Enabled *bool  `json:"enabled,omitempty"`
+
+	// Syslog transport, udp by default
+	Protocol *AuditExporterReqProtocol `json:"protocol,omitempty"`
+	Type     AuditExporterReqType      `json:"type"`
+}
+
+// AuditExporterReqProtocol defines model for AuditExporterReq.Protocol.
+type AuditExporterReqProtocol string
+
+// AuditExporterReqType defines model for AuditExporterReq.Type.
+type AuditExporterReqType string
+
+// AuditExporterRes defines model for audit-exporter-res.
+type AuditExporterRes struct {
+	Address  string                    `json:"address"`
+	Enabled  bool                      `json:"enabled"`
+	Id       string                    `json:"id"`
+	Protocol *AuditExporterResProtocol `json:"protocol,omitempty"`
+	Type     AuditExporterResType      `json:"type"`
+}
+
+// AuditExporterResProtocol defines model for AuditExporterRes.Protocol.
+type AuditExporterResProtocol string
+
+// AuditExporterResType defines model for AuditExporterRes.Type.
+type AuditExporterResType string
+
+// AuditLogRes defines model for audit-log-res.
+type AuditLogRes struct {
+	Change *ChangeMetadata `json:"change,omitempty"`
+
+	// Describes the events flagging the zone, e.g. the changes of a zone.change_burst
+	Detail         *string    `json:"detail,omitempty"`
+	Id             string     `json:"id"`
+	PreviousRecord *RecordRes `json:"previous_record,omitempty"`
+	Record         *RecordRes `json:"record,omitempty"`
+	Time           time.Time  `json:"time"`
+	Type           string     `json:"type"`
+	Zone           string     `json:"zone"`
+}
+
+// BlackholePresetRes defines model for blackhole-preset-res.
+type BlackholePresetRes struct {
+	Description string   `json:"description"`
+	Name        string   `json:"name"`
+	Networks    []string `json:"networks"`
+}
+
+// BlackholeRes defines model for blackhole-res.
+type BlackholeRes struct {
+	Networks []string `json:"networks"`
+	Presets  []string `json:"presets"`
+}
+
+// ChangeMetadata defines model for change-metadata.
+type ChangeMetadata struct {
+	Reason      string `json:"reason"`
+	RequestedBy string `json:"requested_by"`
+	TicketId    string `json:"ticket_id"`
+}
+
+// CloneZoneReq defines model for clone-zone-req.
+type CloneZoneReq struct {
+	// Domain of the new zone
+	Domain string `json:"domain"`
+
+	// Create the zone even though its domain is a public suffix, e.g. co.uk
+	Force *bool `json:"force,omitempty"`
+}
+
+// ConsistencyAction defines model for consistency-action.
+type ConsistencyAction struct {
+	Action  ConsistencyActionAction `json:"action"`
+	Message *string                 `json:"message,omitempty"`
+	Path    string                  `json:"path"`
+	Zone    *string                 `json:"zone,omitempty"`
+}
+
+// ConsistencyActionAction defines model for ConsistencyAction.Action.
+type ConsistencyActionAction string
+
+// ConsistencyReportRes defines model for consistency-report-res.
+type ConsistencyReportRes struct {
+	Actions []ConsistencyAction `json:"actions"`
+	Time    time.Time           `json:"time"`
+}
+
+// DefaultZonesReq defines model for default-zones-req.
+type DefaultZonesReq struct {
+	// named.conf statements replacing named.conf.default-zones, required by the custom mode
+	Content *string             `json:"content,omitempty"`
+	Mode    DefaultZonesReqMode `json:"mode"`
+}
+
+// DefaultZonesReqMode defines model for DefaultZonesReq.Mode.
+type DefaultZonesReqMode string
+
+// DefaultZonesRes defines model for default-zones-res.
+type DefaultZonesRes struct {
+	Content *string             `json:"content,omitempty"`
+	Mode    DefaultZonesResMode `json:"mode"`
+}
+
+// DefaultZonesResMode defines model for DefaultZonesRes.Mode.
+type DefaultZonesResMode string
+
+// DelegatedNameServer defines model for delegated-name-server.
+type DelegatedNameServer struct {
+	// Glue addresses of the name server, only for the name servers in the zone
+	Addresses *[]string `json:"addresses,omitempty"`
+
+	// Host name of the name server, a name without any dot being relative to the zone
+	Host string `json:"host"`
+}
+
+// DelegationReq defines model for delegation-req.
+type DelegationReq struct {
+	NameServers []DelegatedNameServer `json:"name_servers"`
+}
+
+// DelegationRes defines model for delegation-res.
+type DelegationRes struct {
+	// Subdomain relative to the zone
+	Name        string                `json:"name"`
+	NameServers []DelegatedNameServer `json:"name_servers"`
+
+	// Advisories about the zone after the change, set in the responses of mutations only
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// DnssecKeyRes defines model for dnssec-key-res.
+type DnssecKeyRes struct {
+	Algorithm int    `json:"algorithm"`
+	Dnskey    string `json:"dnskey"`
+
+	// DS record to publish in the parent zone, with a SHA-256 digest, missing for the zone signing keys
+	Ds *string `json:"ds,omitempty"`
+
+	// 257 for the key signing keys, 256 for the zone signing keys
+	Flags int `json:"flags"`
+	Tag   int `json:"tag"`
+
+	// View serving the zone from a zone file of its own the key signs, missing for the zone file the other views share
+	View *string `json:"view,omitempty"`
+}
+
+// DnssecRes defines model for dnssec-res.
+type DnssecRes struct {
+	Keys   []DnssecKeyRes `json:"keys"`
+	Policy string         `json:"policy"`
+
+	// Whether named signs the zone, false while the dnssec feature is disabled or the zone has no policy
+	Signing bool `json:"signing"`
+
+	// Keys and next key events as reported by named, empty when the zone is not signed
+	Status string `json:"status"`
+}
+
+// DsRecordRes defines model for ds-record-res.
+type DsRecordRes struct {
+	Algorithm int    `json:"algorithm"`
+	Digest    string `json:"digest"`
+
+	// 2 for SHA-256, 4 for SHA-384
+	DigestType int    `json:"digest_type"`
+	KeyTag     int    `json:"key_tag"`
+	Record     string `json:"record"`
+}
+
+// DsRes defines model for ds-res.
+type DsRes struct {
+	Dnskeys []string `json:"dnskeys"`
+
+	// Empty until named generated the keys of the zone
+	Ds []DsRecordRes `json:"ds"`
+}
+
+// FailoverRes defines model for failover-res.
+type FailoverRes struct {
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastChecked         *time.Time `json:"last_checked,omitempty"`
+	LastError           *string    `json:"last_error,omitempty"`
+	PrimaryHealthy      bool       `json:"primary_healthy"`
+
+	// Set once this standby took over, until the manager restarts
+	Promoted   bool       `json:"promoted"`
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+
+	// Either primary or standby, missing when failover is not configured
+	Role *string `json:"role,omitempty"`
+}
+
+// FaultReq defines model for fault-req.
+type FaultReq struct {
+	// Number of calls the fault applies to, until the faults are cleared when missing or 0
+	Count *int `json:"count,omitempty"`
+
+	// Delay added to every reload, slow_reload only
+	DelayMs *int         `json:"delay_ms,omitempty"`
+	Type    FaultReqType `json:"type"`
+}
+
+// FaultReqType defines model for FaultReq.Type.
+type FaultReqType string
+
+// FaultRes defines model for fault-res.
+type FaultRes struct {
+	DelayMs    *int      `json:"delay_ms,omitempty"`
+	InjectedAt time.Time `json:"injected_at"`
+
+	// Number of calls the fault still applies to, missing when it applies until the faults are cleared
+	Remaining *int         `json:"remaining,omitempty"`
+	Type      FaultResType `json:"type"`
+}
+
+// FaultResType defines model for FaultRes.Type.
+type FaultResType string
+
+// FeatureRes defines model for feature-res.
+type FeatureRes struct {
+	// Whether the feature is part of this build, enabling an unavailable feature has no effect
+	Available   bool   `json:"available"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Name        string `json:"name"`
+}
+
+// ForwarderRes defines model for forwarder-res.
+type ForwarderRes struct {
+	Address             string     `json:"address"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Healthy             bool       `json:"healthy"`
+	LastChecked         *time.Time `json:"last_checked,omitempty"`
+	LastError           *string    `json:"last_error,omitempty"`
+	LatencyMs           *float64   `json:"latency_ms,omitempty"`
+}
+
+// FragmentRecord defines model for fragment-record.
+type FragmentRecord struct {
+	// Name relative to the zone including the fragment, @ for its apex
+	Name string `json:"name"`
+
+	// Preference of MX records or priority of SRV records
+	Priority *int `json:"priority,omitempty"`
+
+	// Type of the record, one of the types of the records but ALIAS
+	Type string `json:"type"`
+
+	// Checked against the type, the values referring to variables as ${NAME} being checked against the variables of the zones including the fragment
+	Value string `json:"value"`
+}
+
+// FragmentReq defines model for fragment-req.
+type FragmentReq struct {
+	Records []FragmentRecord `json:"records"`
+}
+
+// FragmentRes defines model for fragment-res.
+type FragmentRes struct {
+	Name    string           `json:"name"`
+	Records []FragmentRecord `json:"records"`
+
+	// Domains of the zones including the fragment
+	Zones []string `json:"zones"`
+}
+
+// FreezeZoneReq defines model for freeze-zone-req.
+type FreezeZoneReq struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+// GeneralRes defines model for general-res.
+type GeneralRes struct {
+	Code int `json:"code"`
+
+	// Rule refusing the change, e.g. last_ns_record, soa_record or cname_at_apex, left out for the other errors
+	ErrorCode *string `json:"error_code,omitempty"`
+	Message   string  `json:"message"`
+
+	// Advisories about the change, e.g. the PTR records which could not be synced, set by the deletions of records only
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// InsightsRes defines model for insights-res.
+type InsightsRes struct {
+	// The names queried the most first
+	MissingNames []MissingNameRes `json:"missing_names"`
+	Since        time.Time        `json:"since"`
+}
+
+// InstanceRes defines model for instance-res.
+type InstanceRes struct {
+	Hostname      string          `json:"hostname"`
+	Id            string          `json:"id"`
+	LastSeenAt    time.Time       `json:"last_seen_at"`
+	Role          InstanceResRole `json:"role"`
+	SchemaVersion int             `json:"schema_version"`
+
+	// Whether the instance is the one serving the request
+	Self       bool                  `json:"self"`
+	StartedAt  time.Time             `json:"started_at"`
+	SyncStatus InstanceResSyncStatus `json:"sync_status"`
+	Version    string                `json:"version"`
+}
+
+// InstanceResRole defines model for InstanceRes.Role.
+type InstanceResRole string
+
+// InstanceResSyncStatus defines model for InstanceRes.SyncStatus.
+type InstanceResSyncStatus string
+
+// ManagedTsigKeyReq defines model for managed-tsig-key-req.
+type ManagedTsigKeyReq struct {
+	Algorithm ManagedTsigKeyReqAlgorithm `json:"algorithm"`
+
+	// Base64 secret, generated when left out
+	Secret *string `json:"secret,omitempty"`
+}
+
+// ManagedTsigKeyReqAlgorithm defines model for ManagedTsigKeyReq.Algorithm.
+type ManagedTsigKeyReqAlgorithm string
+
+// ManagedTsigKeyRes defines model for managed-tsig-key-res.
+type ManagedTsigKeyRes struct {
+	Algorithm string `json:"algorithm"`
+	Name      string `json:"name"`
+
+	// Base64 secret, set in the response of the update generating it only
+	Secret *string `json:"secret,omitempty"`
+
+	// Domains of the zones referring to the key
+	Zones []string `json:"zones"`
+}
+
+// MissingNameRes defines model for missing-name-res.
+type MissingNameRes struct {
+	// Domain of the zone the name is missing from
+	Domain        string    `json:"domain"`
+	LastQueriedAt time.Time `json:"last_queried_at"`
+
+	// Name relative to the zone
+	Name    string `json:"name"`
+	Queries int    `json:"queries"`
+
+	// Records to create for the name, empty when it is not a well known one
+	Suggestions []RecordReq `json:"suggestions"`
+
+	// Number of queries by type
+	Types map[string]int `json:"types"`
+}
+
+// NegativeTrustAnchorRes defines model for negative-trust-anchor-res.
+type NegativeTrustAnchorRes struct {
+	Domain    string    `json:"domain"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Id        string    `json:"id"`
+}
+
+// NetworkStats defines model for network-stats.
+type NetworkStats struct {
+	Network string             `json:"network"`
+	Queries int                `json:"queries"`
+	Source  NetworkStatsSource `json:"source"`
+	Zones   []ZoneQueryCount   `json:"zones"`
+}
+
+// NetworkStatsSource defines model for NetworkStats.Source.
+type NetworkStatsSource string
+
+// NetworkStatsRes defines model for network-stats-res.
+type NetworkStatsRes struct {
+	Networks []NetworkStats `json:"networks"`
+	Since    time.Time      `json:"since"`
+}
+
+// NodeSerialRes defines model for node-serial-res.
+type NodeSerialRes struct {
+	Alerting bool `json:"alerting"`
+
+	// When the node stopped serving the newest serial, missing while it is in sync
+	DivergedSince *time.Time `json:"diverged_since,omitempty"`
+
+	// Why the node did not answer with the SOA record of the zone
+	Error  *string `json:"error,omitempty"`
+	InSync bool    `json:"in_sync"`
+	Node   string  `json:"node"`
+
+	// Missing when the node did not answer with the SOA record of the zone
+	Serial *string `json:"serial,omitempty"`
+}
+
+// QueryLogRes defines model for query-log-res.
+type QueryLogRes struct {
+	ClientSubnetStats bool `json:"client_subnet_stats"`
+	Enabled           bool `json:"enabled"`
+}
+
+// RateLimit defines model for rate-limit.
+type RateLimit struct {
+	Burst             int     `json:"burst"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+}
+
+// RecordBatchReq defines model for record-batch-req.
+type RecordBatchReq struct {
+	Operations []RecordOperation `json:"operations"`
+}
+
+// RecordBatchRes defines model for record-batch-res.
+type RecordBatchRes struct {
+	// Record of every operation in order, as created, updated or as it was before being deleted
+	Records []RecordRes `json:"records"`
+
+	// Advisories about the zone after the changes
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// RecordGeneratorReq defines model for record-generator-req.
+type RecordGeneratorReq struct {
+	// Owner name of the records, relative to the zone, $ being replaced by their index starting at 1
+	Name string `json:"name"`
+
+	// Addresses of the records, a CIDR, the network and broadcast addresses being left out for IPv4, or the first and the last address separated by -
+	Range string `json:"range"`
+}
+
+// RecordGeneratorRes defines model for record-generator-res.
+type RecordGeneratorRes struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Range string `json:"range"`
+
+	// Number of records kept by the generator
+	RecordCount int `json:"record_count"`
+
+	// Advisories about the zone after the change, set in the responses of mutations only
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// RecordGroupBatchReq defines model for record-group-batch-req.
+type RecordGroupBatchReq struct {
+	// Disables or enables the records
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// Zones the records are changed in, every zone when left out
+	Domains *[]string `json:"domains,omitempty"`
+
+	// Labels the records have every one of, given as key=value, or as key for any value
+	Label []string `json:"label"`
+
+	// TTL set on the records in seconds, 0 restoring the default TTL of the zone files
+	Ttl         *int               `json:"ttl,omitempty"`
+	ValueSuffix *ValueSuffixChange `json:"value_suffix,omitempty"`
+}
+
+// RecordGroupBatchRes defines model for record-group-batch-res.
+type RecordGroupBatchRes struct {
+	// Records changed, by zone, the selected records the change left as they were being left out
+	Groups []RecordGroupRes `json:"groups"`
+
+	// Advisories about the zones after the changes
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// RecordGroupRes defines model for record-group-res.
+type RecordGroupRes struct {
+	Domain  string      `json:"domain"`
+	Records []RecordRes `json:"records"`
+}
+
+// RecordImportRes defines model for record-import-res.
+type RecordImportRes struct {
+	// Records created, in the order of the file
+	Records []RecordRes `json:"records"`
+
+	// Entries left out, as address and host name
+	Skipped []string `json:"skipped"`
+
+	// Advisories about the zone after the changes
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// RecordOperation defines model for record-operation.
+type RecordOperation struct {
+	Action RecordOperationAction `json:"action"`
+	Record *RecordReq            `json:"record,omitempty"`
+
+	// Record to update or delete
+	RecordId *string `json:"record_id,omitempty"`
+}
+
+// RecordOperationAction defines model for RecordOperation.Action.
+type RecordOperationAction string
+
+// RecordPageRes defines model for record-page-res.
+type RecordPageRes struct {
+	Records []RecordRes `json:"records"`
+
+	// Number of records matching the filters, across every page
+	Total int `json:"total"`
+}
+
+// RecordReq defines model for record-req.
+type RecordReq struct {
+	// Canary records should never be queried, every query for their name raising a record.canary_queried event while the query log is enabled
+	Canary *bool `json:"canary,omitempty"`
+
+	// Why the record exists, written in the zone file above the record, empty to clear
+	Comment *string `json:"comment,omitempty"`
+
+	// Disabled records are kept but left out of the zone file
+	Disabled *bool `json:"disabled,omitempty"`
+
+	// Id of the record in the system of the client, unique among the records of the zone, empty to clear
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// Free-form key/value pairs organizing the records, replacing the labels of the record, empty to clear
+	Labels *map[string]string `json:"labels,omitempty"`
+
+	// Name relative to the zone, the apex can be given as @, as an empty name or as the zone domain with a trailing dot and is stored as @. Labels are made of letters, digits, hyphens and underscores, e.g. _dmarc, and the leftmost one may be a wildcard *
+	Name string `json:"name"`
+
+	// Preference of MX records or priority of SRV records, required for them and left out of the value, e.g. 10 with the value mail.example.com.
+	Priority *int `json:"priority,omitempty"`
+
+	// TTL of the record in seconds, 0 for the default TTL of the zone files
+	Ttl *int `json:"ttl,omitempty"`
+
+	// ALIAS points the name, e.g. the apex where a CNAME is not allowed, to the host name given as value, its addresses being published as A and AAAA records
+	Type RecordReqType `json:"type"`
+
+	// Checked against the type, e.g. an IPv4 address for A records, an IPv6 address for AAAA records and a host name for NS, CNAME, PTR and MX records
+	Value string `json:"value"`
+
+	// Name of the only view serving the record, overriding the records of the same name and type in that view, empty to serve it in every view
+	View *string `json:"view,omitempty"`
+}
+
+// RecordReqType defines model for RecordReq.Type.
+type RecordReqType string
+
+// RecordRes defines model for record-res.
+type RecordRes struct {
+	// Canary records should never be queried, every query for their name raising a record.canary_queried event while the query log is enabled
+	Canary bool `json:"canary"`
+
+	// Why the record exists, empty when not set
+	Comment string `json:"comment"`
+
+	// Disabled records are kept but left out of the zone file
+	Disabled bool `json:"disabled"`
+
+	// Id of the record in the system of the client, empty when not set
+	ExternalId string `json:"external_id"`
+
+	// Id of the generator keeping the record, set for the generated records only
+	GeneratorId *string `json:"generator_id,omitempty"`
+	Id          string  `json:"id"`
+
+	// Free-form key/value pairs organizing the records
+	Labels map[string]string `json:"labels"`
+	Name   string            `json:"name"`
+
+	// Preference of MX records or priority of SRV records
+	Priority *int `json:"priority,omitempty"`
+
+	// TTL of the record in seconds, 0 when the default TTL of the zone files applies
+	Ttl   int           `json:"ttl"`
+	Type  RecordResType `json:"type"`
+	Value string        `json:"value"`
+
+	// Name of the only view serving the record, empty when every view serves it
+	View string `json:"view"`
+
+	// Advisories about the zone after the change, set in the responses of mutations only
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// RecordResType defines model for RecordRes.Type.
+type RecordResType string
+
+// RecoveryBundleReq defines model for recovery-bundle-req.
+type RecoveryBundleReq struct {
+	// Passphrase of at least 12 characters encrypting the TSIG keys of the bundle
+	Passphrase string `json:"passphrase"`
+}
+
+// RecursionReq defines model for recursion-req.
+type RecursionReq struct {
+	AllowOpenResolver *bool            `json:"allow_open_resolver,omitempty"`
+	AllowRecursion    *[]string        `json:"allow_recursion,omitempty"`
+	Mode              RecursionReqMode `json:"mode"`
+}
+
+// RecursionReqMode defines model for RecursionReq.Mode.
+type RecursionReqMode string
+
+// RecursionRes defines model for recursion-res.
+type RecursionRes struct {
+	AllowOpenResolver bool             `json:"allow_open_resolver"`
+	AllowRecursion    []string         `json:"allow_recursion"`
+	Mode              RecursionResMode `json:"mode"`
+}
+
+// RecursionResMode defines model for RecursionRes.Mode.
+type RecursionResMode string
+
+// RegistrationRes defines model for registration-res.
+type RegistrationRes struct {
+	Domain    string     `json:"domain"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Name servers of the zone the domain is not delegated to
+	MissingAtRegistry []string `json:"missing_at_registry"`
+
+	// Name servers the domain is delegated to
+	NameServers      []string `json:"name_servers"`
+	NameServersMatch bool     `json:"name_servers_match"`
+	Registrar        string   `json:"registrar"`
+
+	// Delegated name servers the zone has no NS record for
+	UnknownToZone []string `json:"unknown_to_zone"`
+
+	// Targets of the NS records of the zone apex
+	ZoneNameServers []string `json:"zone_name_servers"`
+}
+
+// ReverseZoneReq defines model for reverse-zone-req.
+type ReverseZoneReq struct {
+	// IPv4 network of /8, /16, /24 or longer, or IPv6 network ending on a nibble, e.g. /48 or /64
+	Cidr string `json:"cidr"`
+
+	// Zone group, e.g. a tenant, whose folder holds the zone file, made of lowercase letters, digits and '-', empty to keep it in the bind folder
+	Group *string `json:"group,omitempty"`
+
+	// Either an email address, e.g. hostmaster@example.com, or a mail address in the SOA format, e.g. hostmaster.example.com.
+	MailAddr  string `json:"mail_addr"`
+	PrimaryNs string `json:"primary_ns"`
+}
+
+// RpzAllowlist defines model for rpz-allowlist.
+type RpzAllowlist struct {
+	Domains []string `json:"domains"`
+}
+
+// RootHintsReq defines model for root-hints-req.
+type RootHintsReq struct {
+	// Root hints zone file, required by the custom mode
+	Content *string          `json:"content,omitempty"`
+	Mode    RootHintsReqMode `json:"mode"`
+
+	// Seconds between the downloads of the refreshed root hints, at least a day
+	RefreshInterval *int `json:"refresh_interval,omitempty"`
+
+	// Url the refreshed root hints are downloaded from, defaults to the one of InterNIC
+	Url *string `json:"url,omitempty"`
+}
+
+// RootHintsReqMode defines model for RootHintsReq.Mode.
+type RootHintsReqMode string
+
+// RootHintsRes defines model for root-hints-res.
+type RootHintsRes struct {
+	// Custom root hints, or the last refreshed ones
+	Content         *string          `json:"content,omitempty"`
+	LastError       *string          `json:"last_error,omitempty"`
+	LastRefreshed   *time.Time       `json:"last_refreshed,omitempty"`
+	Mode            RootHintsResMode `json:"mode"`
+	RefreshInterval int              `json:"refresh_interval"`
+	Url             string           `json:"url"`
+}
+
+// RootHintsResMode defines model for RootHintsRes.Mode.
+type RootHintsResMode string
+
+// RpzFeedReq defines model for rpz-feed-req.
+type RpzFeedReq struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Name    string `json:"name"`
+
+	// Profile grouping the feed, feeds without profile are always applied
+	Profile *string `json:"profile,omitempty"`
+
+	// Refresh interval in seconds, at least 300
+	RefreshInterval *int   `json:"refresh_interval,omitempty"`
+	Url             string `json:"url"`
+}
+
+// RpzFeedRes defines model for rpz-feed-res.
+type RpzFeedRes struct {
+	DomainCount int    `json:"domain_count"`
+	Enabled     bool   `json:"enabled"`
+	Id          string `json:"id"`
+
+	// Why the last download failed, e.g. a feed larger than 64 MiB, empty once a download succeeds
+	LastError       *string    `json:"last_error,omitempty"`
+	LastRefreshed   *time.Time `json:"last_refreshed,omitempty"`
+	Name            string     `json:"name"`
+	Profile         string     `json:"profile"`
+	RefreshInterval int        `json:"refresh_interval"`
+	Url             string     `json:"url"`
+}
+
+// RpzProfileRes defines model for rpz-profile-res.
+type RpzProfileRes struct {
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	FeedCount   int    `json:"feed_count"`
+	Name        string `json:"name"`
+
+	// Names of the views applying the profile, every view when empty
+	Views []string `json:"views"`
+}
+
+// ScrapeTargetGroupRes defines model for scrape-target-group-res.
+type ScrapeTargetGroupRes struct {
+	Labels  map[string]string `json:"labels"`
+	Targets []string          `json:"targets"`
+}
+
+// SerialConsistencyRes defines model for serial-consistency-res.
+type SerialConsistencyRes struct {
+	// Number of seconds a node may diverge before it is alerting
+	AlertAfter int `json:"alert_after"`
+
+	// Missing until the first check
+	CheckedAt *time.Time       `json:"checked_at,omitempty"`
+	Nodes     []string         `json:"nodes"`
+	Zones     []ZoneSerialsRes `json:"zones"`
+}
+
+// SettingsRes defines model for settings-res.
+type SettingsRes struct {
+	LogLevel SettingsResLogLevel `json:"log_level"`
+
+	// Requests allowed per client address, a zero requests_per_second disables the limit
+	RateLimit RateLimit `json:"rate_limit"`
+
+	// Number of webhooks declared in the settings file
+	WebhookCount int `json:"webhook_count"`
+}
+
+// SettingsResLogLevel defines model for SettingsRes.LogLevel.
+type SettingsResLogLevel string
+
+// SoaPresetRes defines model for soa-preset-res.
+type SoaPresetRes struct {
+	// Negative caching TTL
+	CacheTtl int `json:"cache_ttl"`
+
+	// Whether the zones created without a preset get this one, see default_soa_preset in the settings
+	Default     bool   `json:"default"`
+	Description string `json:"description"`
+	Expire      int    `json:"expire"`
+	Name        string `json:"name"`
+	Refresh     int    `json:"refresh"`
+	Retry       int    `json:"retry"`
+}
+
+// SoaRes defines model for soa-res.
+type SoaRes struct {
+	CacheTtl          int                  `json:"cache_ttl"`
+	Expire            int                  `json:"expire"`
+	Id                string               `json:"id"`
+	MailAddress       string               `json:"mail_address"`
+	Name              string               `json:"name"`
+	PrimaryNameServer string               `json:"primary_name_server"`
+	Refresh           int                  `json:"refresh"`
+	Retry             int                  `json:"retry"`
+	Serial            string               `json:"serial"`
+	SerialStrategy    SoaResSerialStrategy `json:"serial_strategy"`
+}
+
+// SoaResSerialStrategy defines model for SoaRes.SerialStrategy.
+type SoaResSerialStrategy string
+
+// TransferZoneReq defines model for transfer-zone-req.
+type TransferZoneReq struct {
+	Domain string `json:"domain"`
+
+	// Create the zone even though its domain is a public suffix, e.g. co.uk
+	Force *bool `json:"force,omitempty"`
+
+	// IP address of the primary serving the zone, optionally followed by a port
+	Primary string      `json:"primary"`
+	TsigKey *TsigKeyReq `json:"tsig_key,omitempty"`
+}
+
+// TsigKeyReq defines model for tsig-key-req.
+type TsigKeyReq struct {
+	Algorithm *TsigKeyReqAlgorithm `json:"algorithm,omitempty"`
+	Name      string               `json:"name"`
+
+	// Base64 secret shared with the secondaries, e.g. generated by tsig-keygen
+	Secret *string `json:"secret,omitempty"`
+}
+
+// TsigKeyReqAlgorithm defines model for TsigKeyReq.Algorithm.
+type TsigKeyReqAlgorithm string
+
+// TsigKeyRes defines model for tsig-key-res.
+type TsigKeyRes struct {
+	Algorithm string `json:"algorithm"`
+	Name      string `json:"name"`
+}
+
+// UnusedRecordRes defines model for unused-record-res.
+type UnusedRecordRes struct {
+	// When the record last answered a query, unset when it did not since the usage is tracked
+	LastQueriedAt *time.Time `json:"last_queried_at,omitempty"`
+	Record        RecordRes  `json:"record"`
+}
+
+// UnusedRecordsRes defines model for unused-records-res.
+type UnusedRecordsRes struct {
+	// Whether the query log has been enabled for the whole period
+	Complete bool              `json:"complete"`
+	Days     int               `json:"days"`
+	Records  []UnusedRecordRes `json:"records"`
+
+	// When the query log was enabled, unset while it is disabled
+	TrackedSince *time.Time `json:"tracked_since,omitempty"`
+}
+
+// ValidationExceptionRes defines model for validation-exception-res.
+type ValidationExceptionRes struct {
+	Domain string `json:"domain"`
+}
+
+// ValidationWarning defines model for validation-warning.
+type ValidationWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValueSuffixChange defines model for value-suffix-change.
+type ValueSuffixChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ViewReq defines model for view-req.
+type ViewReq struct {
+	// Address match list of the clients of the view
+	MatchClients []string `json:"match_clients"`
+
+	// Made of lowercase letters, digits and '-'
+	Name string `json:"name"`
+}
+
+// ViewRes defines model for view-res.
+type ViewRes struct {
+	MatchClients []string `json:"match_clients"`
+	Name         string   `json:"name"`
+}
+
+// WebhookReq defines model for webhook-req.
+type WebhookReq struct {
+	// Content type of the rendered payload, application/json by default
+	ContentType *string `json:"content_type,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"`
+
+	// Events to deliver, every event when empty
+	Events *[]string `json:"events,omitempty"`
+
+	// Go template rendered with the event, the default JSON payload is sent when empty
+	PayloadTemplate *string `json:"payload_template,omitempty"`
+
+	// Only deliver record events of these types
+	RecordTypes *[]string `json:"record_types,omitempty"`
+	Url         string    `json:"url"`
+
+	// Only deliver events of these zones
+	Zones *[]string `json:"zones,omitempty"`
+}
+
+// WebhookRes defines model for webhook-res.
+type WebhookRes struct {
+	ContentType     string   `json:"content_type"`
+	Enabled         bool     `json:"enabled"`
+	Events          []string `json:"events"`
+	Id              string   `json:"id"`
+	PayloadTemplate string   `json:"payload_template"`
+	RecordTypes     []string `json:"record_types"`
+	Url             string   `json:"url"`
+	Zones           []string `json:"zones"`
+}
+
+// ZoneArchiveRes defines model for zone-archive-res.
+type ZoneArchiveRes struct {
+	ArchivedAt  time.Time `json:"archived_at"`
+	Domain      string    `json:"domain"`
+	Id          string    `json:"id"`
+	RecordCount int       `json:"record_count"`
+
+	// Size of the compressed bundle in bytes
+	Size int64 `json:"size"`
+}
+
+// ZonePageRes defines model for zone-page-res.
+type ZonePageRes struct {
+	// Number of zones matching the filters, across every page
+	Total int       `json:"total"`
+	Zones []ZoneRes `json:"zones"`
+}
+
+// ZoneQueryCount defines model for zone-query-count.
+type ZoneQueryCount struct {
+	Domain  string `json:"domain"`
+	Queries int    `json:"queries"`
+}
+
+// ZoneRes defines model for zone-res.
+type ZoneRes struct {
+	// Address match elements of the secondaries allowed to transfer the zone
+	AllowTransfer []string `json:"allow_transfer"`
+
+	// Secondaries notified of the changes of the zone on top of its name servers
+	AlsoNotify []string `json:"also_notify"`
+
+	// Last revision of the zone named serves
+	AppliedRevision int `json:"applied_revision"`
+
+	// dnssec-policy named signs the zone with, empty for unsigned zones
+	DnssecPolicy string `json:"dnssec_policy"`
+	Domain       string `json:"domain"`
+
+	// Date the registration of the domain expires or has to be renewed, YYYY-MM-DD
+	ExpiresAt *string `json:"expires_at,omitempty"`
+
+	// Id of the zone in the system of the client, empty when not set
+	ExternalId string `json:"external_id"`
+
+	// Names of the record fragments the zone serves along with its own records
+	Fragments []string `json:"fragments"`
+
+	// Why the zone was frozen
+	FreezeReason *string `json:"freeze_reason,omitempty"`
+
+	// When the zone was made read-only, missing while it can be changed
+	FrozenAt *time.Time `json:"frozen_at,omitempty"`
+
+	// Zone group whose folder holds the zone file, empty when it is kept in the bind folder
+	Group string `json:"group"`
+	Id    string `json:"id"`
+	Notes string `json:"notes"`
+
+	// Seconds the changes of the zone are batched for before being published, 0 when every change is published right away
+	NotifyInterval int         `json:"notify_interval"`
+	Records        []RecordRes `json:"records"`
+
+	// Registrar account the delegation is published through, NS changes being published automatically
+	Registrar string `json:"registrar"`
+
+	// Changes of regulated zones require change metadata
+	Regulated bool `json:"regulated"`
+
+	// Incremented by every change of the zone
+	Revision int    `json:"revision"`
+	Soa      SoaRes `json:"soa"`
+
+	// Pending while the latest changes of the zone are saved but not served by named yet, they are applied by the next successful reload
+	Status ZoneResStatus `json:"status"`
+
+	// Changes introducing validation warnings are rejected
+	StrictValidation bool `json:"strict_validation"`
+
+	// The primary name server of the SOA and the apex NS record pointing to it are kept in line
+	SyncPrimaryNs bool `json:"sync_primary_ns"`
+
+	// The PTR records of the A and AAAA records are kept in line in the reverse zones managed here
+	SyncPtr          bool   `json:"sync_ptr"`
+	TechnicalContact string `json:"technical_contact"`
+
+	// TSIG keys allowing the secondaries holding one of them to transfer the zone, their secret left out
+	TransferKeys []TsigKeyRes `json:"transfer_keys"`
+
+	// Values the record values refer to as ${NAME}
+	Variables map[string]string `json:"variables"`
+
+	// Names of the views serving the zone, every view when empty
+	Views []string `json:"views"`
+
+	// Advisories about the zone after the change, set in the responses of mutations only
+	Warnings *[]ValidationWarning `json:"warnings,omitempty"`
+}
+
+// ZoneResStatus defines model for ZoneRes.Status.
+type ZoneResStatus string
+
+// ZoneSerialsRes defines model for zone-serials-res.
+type ZoneSerialsRes struct {
+	Domain string          `json:"domain"`
+	Nodes  []NodeSerialRes `json:"nodes"`
+
+	// Newest serial served by a node, missing when none answered
+	Serial *string `json:"serial,omitempty"`
+}
+
+// ZoneStatsRes defines model for zone-stats-res.
+type ZoneStatsRes struct {
+	// Bytes of the zone file named serves, 0 while it is not written
+	FileSize int64 `json:"file_size"`
+
+	// Limit of the bytes of a zone file, 0 when unlimited
+	MaxFileSize int64 `json:"max_file_size"`
+
+	// Limit of the records of a zone, 0 when unlimited
+	MaxRecords int                 `json:"max_records"`
+	Records    int                 `json:"records"`
+	Warnings   []ValidationWarning `json:"warnings"`
+}
+
+// BadRequest defines model for bad-request.
+type BadRequest GeneralRes
+
+// DefaultError defines model for default-error.
+type DefaultError GeneralRes
+
+// NotFound defines model for not-found.
+type NotFound GeneralRes
+
+// GetAuditLogsParams defines parameters for GetAuditLogs.
+type GetAuditLogsParams struct {
+	// Only return the entries of this zone
+	Zone *string `json:"zone,omitempty"`
+
+	// Maximum number of entries, 100 by default
+	Limit *int `json:"limit,omitempty"`
+}
+
+// CreateAuditExporterJSONBody defines parameters for CreateAuditExporter.
+type CreateAuditExporterJSONBody AuditExporterReq
+
+// UpdateAuditExporterJSONBody defines parameters for UpdateAuditExporter.
+type UpdateAuditExporterJSONBody AuditExporterReq
+
+// UpdateFragmentJSONBody defines parameters for UpdateFragment.
+type UpdateFragmentJSONBody FragmentReq
+
+// GetInsightsParams defines parameters for GetInsights.
+type GetInsightsParams struct {
+	// Only return the names of this zone
+	Domain *string `json:"domain,omitempty"`
+
+	// Maximum number of names, 20 by default
+	Limit *int `json:"limit,omitempty"`
+}
+
+// GetInventoryParams defines parameters for GetInventory.
+type GetInventoryParams struct {
+	// Only return the hosts of this zone
+	Domain *string `json:"domain,omitempty"`
+
+	// Only return the address records having every label given as key=value, or as key for any value
+	Label *[]string `json:"label,omitempty"`
+}
+
+// GetScrapeTargetsParams defines parameters for GetScrapeTargets.
+type GetScrapeTargetsParams struct {
+	// Only return the targets of this zone
+	Domain *string `json:"domain,omitempty"`
+
+	// Only return the targets of the records having every label given as key=value, or as key for any value
+	Label *[]string `json:"label,omitempty"`
+}
+
+// GetRecordGroupsParams defines parameters for GetRecordGroups.
+type GetRecordGroupsParams struct {
+	// Only return the records of this zone
+	Domain *string `json:"domain,omitempty"`
+
+	// Only return the records having every label given as key=value, or as key for any value
+	Label []string `json:"label"`
+}
+
+// BatchRecordGroupsJSONBody defines parameters for BatchRecordGroups.
+type BatchRecordGroupsJSONBody RecordGroupBatchReq
+
+// GetRecordsParams defines parameters for GetRecords.
+type GetRecordsParams struct {
+	// Only return the record having this external id
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// Only return the records having every label given as key=value, or as key for any value
+	Label *[]string `json:"label,omitempty"`
+
+	// Maximum number of records, every record by default
+	Limit *int `json:"limit,omitempty"`
+
+	// Number of records to skip
+	Offset *int `json:"offset,omitempty"`
+}
+
+// CreateRecordJSONBody defines parameters for CreateRecord.
+type CreateRecordJSONBody RecordReq
+
+// BatchRecordsJSONBody defines parameters for BatchRecords.
+type BatchRecordsJSONBody RecordBatchReq
+
+// UpdateRecordJSONBody defines parameters for UpdateRecord.
+type UpdateRecordJSONBody RecordReq
+
+// GetRecordHistoryParams defines parameters for GetRecordHistory.
+type GetRecordHistoryParams struct {
+	// Maximum number of entries, 100 by default
+	Limit *int `json:"limit,omitempty"`
+}
+
+// CreateReverseZoneJSONBody defines parameters for CreateReverseZone.
+type CreateReverseZoneJSONBody ReverseZoneReq
+
+// UpdateRpzAllowlistJSONBody defines parameters for UpdateRpzAllowlist.
+type UpdateRpzAllowlistJSONBody RpzAllowlist
+
+// CreateRpzFeedJSONBody defines parameters for CreateRpzFeed.
+type CreateRpzFeedJSONBody RpzFeedReq
+
+// UpdateRpzFeedJSONBody defines parameters for UpdateRpzFeed.
+type UpdateRpzFeedJSONBody RpzFeedReq
+
+// UpdateRpzProfileJSONBody defines parameters for UpdateRpzProfile.
+type UpdateRpzProfileJSONBody struct {
+	Enabled bool `json:"enabled"`
+
+	// Names of the views applying the profile, every view when empty, replacing the current ones when set
+	Views *[]string `json:"views,omitempty"`
+}
+
+// UpdateBlackholeJSONBody defines parameters for UpdateBlackhole.
+type UpdateBlackholeJSONBody struct {
+	Networks *[]string `json:"networks,omitempty"`
+	Presets  *[]string `json:"presets,omitempty"`
+}
+
+// FlushCacheJSONBody defines parameters for FlushCache.
+type FlushCacheJSONBody struct {
+	Name *string `json:"name,omitempty"`
+	Tree *bool   `json:"tree,omitempty"`
+}
+
+// UpdateDefaultZonesJSONBody defines parameters for UpdateDefaultZones.
+type UpdateDefaultZonesJSONBody DefaultZonesReq
+
+// InjectFaultJSONBody defines parameters for InjectFault.
+type InjectFaultJSONBody FaultReq
+
+// UpdateForwardersJSONBody defines parameters for UpdateForwarders.
+type UpdateForwardersJSONBody struct {
+	Forwarders []string `json:"forwarders"`
+}
+
+// CreateNegativeTrustAnchorJSONBody defines parameters for CreateNegativeTrustAnchor.
+type CreateNegativeTrustAnchorJSONBody struct {
+	Domain string `json:"domain"`
+
+	// Lifetime in seconds, at most one week
+	Lifetime *int `json:"lifetime,omitempty"`
+}
+
+// UpdateQueryLogJSONBody defines parameters for UpdateQueryLog.
+type UpdateQueryLogJSONBody struct {
+	ClientSubnetStats *bool `json:"client_subnet_stats,omitempty"`
+	Enabled           bool  `json:"enabled"`
+}
+
+// ExportRecoveryBundleJSONBody defines parameters for ExportRecoveryBundle.
+type ExportRecoveryBundleJSONBody RecoveryBundleReq
+
+// UpdateRecursionJSONBody defines parameters for UpdateRecursion.
+type UpdateRecursionJSONBody RecursionReq
+
+// UpdateRootHintsJSONBody defines parameters for UpdateRootHints.
+type UpdateRootHintsJSONBody RootHintsReq
+
+// CreateValidationExceptionJSONBody defines parameters for CreateValidationException.
+type CreateValidationExceptionJSONBody struct {
+	Domain string `json:"domain"`
+}
+
+// UpdateViewsJSONBody defines parameters for UpdateViews.
+type UpdateViewsJSONBody struct {
+	Views []ViewReq `json:"views"`
+}
+
+// UpdateTsigKeyJSONBody defines parameters for UpdateTsigKey.
+type UpdateTsigKeyJSONBody ManagedTsigKeyReq
+
+// CreateWebhookJSONBody defines parameters for CreateWebhook.
+type CreateWebhookJSONBody WebhookReq
+
+// UpdateWebhookJSONBody defines parameters for UpdateWebhook.
+type UpdateWebhookJSONBody WebhookReq
+
+// GetZonesParams defines parameters for GetZones.
+type GetZonesParams struct {
+	// Only return the zones expiring within this number of days, including the expired ones
+	ExpiringWithin *int `json:"expiring_within,omitempty"`
+
+	// Only return the zone having this external id
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// Only return the zones of this zone group
+	Group *string `json:"group,omitempty"`
+
+	// Maximum number of zones, every zone by default
+	Limit *int `json:"limit,omitempty"`
+
+	// Number of zones to skip, the zones being ordered by domain
+	Offset *int `json:"offset,omitempty"`
+
+	// Only return the zones whose technical contact contains this value
+	TechnicalContact *string `json:"technical_contact,omitempty"`
+}
+
+// CreateZoneJSONBody defines parameters for CreateZone.
+type CreateZoneJSONBody struct {
+	// Address match elements of the secondaries allowed to transfer the zone, named's default applying when empty along with transfer_keys
+	AllowTransfer *[]string `json:"allow_transfer,omitempty"`
+
+	// Secondaries notified of the changes of the zone on top of its name servers, IP addresses optionally followed by a port, replacing the current targets when set
+	AlsoNotify *[]string `json:"also_notify,omitempty"`
+
+	// dnssec-policy named signs the zone with once the dnssec feature is enabled, either default or insecure to unsign a signed zone gradually, empty for unsigned zones
+	DnssecPolicy *string `json:"dnssec_policy,omitempty"`
+	Domain       string  `json:"domain"`
+
+	// Date the registration of the domain expires or has to be renewed, YYYY-MM-DD, empty to clear
+	ExpiresAt *string `json:"expires_at,omitempty"`
+
+	// Id of the zone in the system of the client, e.g. a CRM, unique among the zones, empty to clear
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// Create the zone even though its domain is a public suffix, e.g. co.uk
+	Force *bool `json:"force,omitempty"`
+
+	// Names of the record fragments the zone serves along with its own records, replacing the current ones when set
+	Fragments *[]string `json:"fragments,omitempty"`
+
+	// Zone group, e.g. a tenant, whose folder holds the zone file, made of lowercase letters, digits and '-', empty to keep it in the bind folder
+	Group *string `json:"group,omitempty"`
+
+	// Either an email address, e.g. hostmaster@example.com, or a mail address in the SOA format, e.g. hostmaster.example.com.
+	MailAddr string  `json:"mail_addr"`
+	Notes    *string `json:"notes,omitempty"`
+
+	// Publish the changes of the zone, and notify its secondaries, at most once per this number of seconds, 0 to publish every change right away
+	NotifyInterval *int   `json:"notify_interval,omitempty"`
+	PrimaryNs      string `json:"primary_ns"`
+
+	// Name of the registrar account of the settings file the delegation is published through, empty to clear
+	Registrar *string `json:"registrar,omitempty"`
+	Regulated *bool   `json:"regulated,omitempty"`
+
+	// Serial of the next versions of the zone, moving to a strategy producing lower serials takes a few refresh intervals
+	SerialStrategy *CreateZoneJSONBodySerialStrategy `json:"serial_strategy,omitempty"`
+
+	// Sets the refresh, retry, expire and negative caching timers of the SOA record together, see /zones/soa-presets
+	SoaPreset *string `json:"soa_preset,omitempty"`
+
+	// Reject the changes introducing validation warnings instead of returning them
+	StrictValidation *bool `json:"strict_validation,omitempty"`
+
+	// Keep the primary name server of the SOA and the apex NS record pointing to it in line, changing either one changes the other
+	SyncPrimaryNs *bool `json:"sync_primary_ns,omitempty"`
+
+	// Create, update and delete the PTR records of the A and AAAA records in the reverse zones managed here
+	SyncPtr          *bool   `json:"sync_ptr,omitempty"`
+	TechnicalContact *string `json:"technical_contact,omitempty"`
+
+	// TSIG keys allowing the secondaries holding one of them to transfer the zone
+	TransferKeys *[]TsigKeyReq `json:"transfer_keys,omitempty"`
+
+	// Values the record values refer to as ${NAME}, expanded when the zone file is written, replacing the current variables when set
+	Variables *map[string]string `json:"variables,omitempty"`
+
+	// Names of the views serving the zone, every view when empty, replacing the current ones when set
+	Views *[]string `json:"views,omitempty"`
+}
+
+// CreateZoneJSONBodySerialStrategy defines parameters for CreateZone.
+type CreateZoneJSONBodySerialStrategy string
+
+// ImportZoneParams defines parameters for ImportZone.
+type ImportZoneParams struct {
+	// Domain of the zone, the owner of the SOA record by default
+	Domain *string `json:"domain,omitempty"`
+
+	// Create the zone even though its domain is a public suffix, e.g. co.uk
+	Force *bool `json:"force,omitempty"`
+}
+
+// TransferZoneJSONBody defines parameters for TransferZone.
+type TransferZoneJSONBody TransferZoneReq
+
+// UpdateZoneJSONBody defines parameters for UpdateZone.
+type UpdateZoneJSONBody struct {
+	// Address match elements of the secondaries allowed to transfer the zone, named's default applying when empty along with transfer_keys
+	AllowTransfer *[]string `json:"allow_transfer,omitempty"`
+
+	// Secondaries notified of the changes of the zone on top of its name servers, IP addresses optionally followed by a port, replacing the current targets when set
+	AlsoNotify *[]string `json:"also_notify,omitempty"`
+
+	// dnssec-policy named signs the zone with once the dnssec feature is enabled, either default or insecure to unsign a signed zone gradually, empty for unsigned zones
+	DnssecPolicy *string `json:"dnssec_policy,omitempty"`
+	Domain       *string `json:"domain,omitempty"`
+
+	// Date the registration of the domain expires or has to be renewed, YYYY-MM-DD, empty to clear
+	ExpiresAt *string `json:"expires_at,omitempty"`
+
+	// Id of the zone in the system of the client, e.g. a CRM, unique among the zones, empty to clear
+	ExternalId *string `json:"external_id,omitempty"`
+
+	// Names of the record fragments the zone serves along with its own records, replacing the current ones when set
+	Fragments *[]string `json:"fragments,omitempty"`
+
+	// Zone group, e.g. a tenant, whose folder holds the zone file, made of lowercase letters, digits and '-', empty to keep it in the bind folder
+	Group *string `json:"group,omitempty"`
+
+	// Either an email address, e.g. hostmaster@example.com, or a mail address in the SOA format, e.g. hostmaster.example.com.
+	MailAddr *string `json:"mail_addr,omitempty"`
+	Notes    *string `json:"notes,omitempty"`
+
+	// Publish the changes of the zone, and notify its secondaries, at most once per this number of seconds, 0 to publish every change right away
+	NotifyInterval *int    `json:"notify_interval,omitempty"`
+	PrimaryNs      *string `json:"primary_ns,omitempty"`
+
+	// Name of the registrar account of the settings file the delegation is published through, empty to clear
+	Registrar *string `json:"registrar,omitempty"`
+	Regulated *bool   `json:"regulated,omitempty"`
+
+	// Serial of the next versions of the zone, moving to a strategy producing lower serials takes a few refresh intervals
+	SerialStrategy *UpdateZoneJSONBodySerialStrategy `json:"serial_strategy,omitempty"`
+
+	// Sets the refresh, retry, expire and negative caching timers of the SOA record together, see /zones/soa-presets
+	SoaPreset *string `json:"soa_preset,omitempty"`
+
+	// Reject the changes introducing validation warnings instead of returning them
+	StrictValidation *bool `json:"strict_validation,omitempty"`
+
+	// Keep the primary name server of the SOA and the apex NS record pointing to it in line, changing either one changes the other
+	SyncPrimaryNs *bool `json:"sync_primary_ns,omitempty"`
+
+	// Create, update and delete the PTR records of the A and AAAA records in the reverse zones managed here
+	SyncPtr          *bool   `json:"sync_ptr,omitempty"`
+	TechnicalContact *string `json:"technical_contact,omitempty"`
+
+	// TSIG keys allowing the secondaries holding one of them to transfer the zone
+	TransferKeys *[]TsigKeyReq `json:"transfer_keys,omitempty"`
+
+	// Values the record values refer to as ${NAME}, expanded when the zone file is written, replacing the current variables when set
+	Variables *map[string]string `json:"variables,omitempty"`
+
+	// Names of the views serving the zone, every view when empty, replacing the current ones when set
+	Views *[]string `json:"views,omitempty"`
+}
+
+// UpdateZoneJSONBodySerialStrategy defines parameters for UpdateZone.
+type UpdateZoneJSONBodySerialStrategy string
+
+// CloneZoneJSONBody defines parameters for CloneZone.
+type CloneZoneJSONBody CloneZoneReq
+
+// SetDelegationJSONBody defines parameters for SetDelegation.
+type SetDelegationJSONBody DelegationReq
+
+// ExportZoneParams defines parameters for ExportZone.
+type ExportZoneParams struct {
+	// Format of the export, zone-file by default
+	Format *ExportZoneParamsFormat `json:"format,omitempty"`
+}
+
+// ExportZoneParamsFormat defines parameters for ExportZone.
+type ExportZoneParamsFormat string
+
+// FreezeZoneJSONBody defines parameters for FreezeZone.
+type FreezeZoneJSONBody FreezeZoneReq
+
+// CreateRecordGeneratorJSONBody defines parameters for CreateRecordGenerator.
+type CreateRecordGeneratorJSONBody RecordGeneratorReq
+
+// UpdateRecordGeneratorJSONBody defines parameters for UpdateRecordGenerator.
+type UpdateRecordGeneratorJSONBody RecordGeneratorReq
+
+// GetZoneReportParams defines parameters for GetZoneReport.
+type GetZoneReportParams struct {
+	// Start of the period, 30 days before to by default
+	From *time.Time `json:"from,omitempty"`
+
+	// End of the period, now by default
+	To *time.Time `json:"to,omitempty"`
+
+	// Format of the report, markdown by default
+	Format *GetZoneReportParamsFormat `json:"format,omitempty"`
+}
+
+// GetZoneReportParamsFormat defines parameters for GetZoneReport.
+type GetZoneReportParamsFormat string
+
+// GetUnusedRecordsParams defines parameters for GetUnusedRecords.
+type GetUnusedRecordsParams struct {
+	// Number of days without any query, 30 by default
+	Days *int `json:"days,omitempty"`
+}
+
+// CreateAuditExporterJSONRequestBody defines body for CreateAuditExporter for application/json ContentType.
+type CreateAuditExporterJSONRequestBody CreateAuditExporterJSONBody
+
+// UpdateAuditExporterJSONRequestBody defines body for UpdateAuditExporter for application/json ContentType.
+type UpdateAuditExporterJSONRequestBody UpdateAuditExporterJSONBody
+
+// UpdateFragmentJSONRequestBody defines body for UpdateFragment for application/json ContentType.
+type UpdateFragmentJSONRequestBody UpdateFragmentJSONBody
+
+// BatchRecordGroupsJSONRequestBody defines body for BatchRecordGroups for application/json ContentType.
+type BatchRecordGroupsJSONRequestBody BatchRecordGroupsJSONBody
+
+// CreateRecordJSONRequestBody defines body for CreateRecord for application/json ContentType.
+type CreateRecordJSONRequestBody CreateRecordJSONBody
+
+// BatchRecordsJSONRequestBody defines body for BatchRecords for application/json ContentType.
+type BatchRecordsJSONRequestBody BatchRecordsJSONBody
+
+// UpdateRecordJSONRequestBody defines body for UpdateRecord for application/json ContentType.
+type UpdateRecordJSONRequestBody UpdateRecordJSONBody
+
+// CreateReverseZoneJSONRequestBody defines body for CreateReverseZone for application/json ContentType.
+type CreateReverseZoneJSONRequestBody CreateReverseZoneJSONBody
+
+// UpdateRpzAllowlistJSONRequestBody defines body for UpdateRpzAllowlist for application/json ContentType.
+type UpdateRpzAllowlistJSONRequestBody UpdateRpzAllowlistJSONBody
+
+// CreateRpzFeedJSONRequestBody defines body for CreateRpzFeed for application/json ContentType.
+type CreateRpzFeedJSONRequestBody CreateRpzFeedJSONBody
+
+// UpdateRpzFeedJSONRequestBody defines body for UpdateRpzFeed for application/json ContentType.
+type UpdateRpzFeedJSONRequestBody UpdateRpzFeedJSONBody
+
+// UpdateRpzProfileJSONRequestBody defines body for UpdateRpzProfile for application/json ContentType.
+type UpdateRpzProfileJSONRequestBody UpdateRpzProfileJSONBody
+
+// UpdateBlackholeJSONRequestBody defines body for UpdateBlackhole for application/json ContentType.
+type UpdateBlackholeJSONRequestBody UpdateBlackholeJSONBody
+
+// FlushCacheJSONRequestBody defines body for FlushCache for application/json ContentType.
+type FlushCacheJSONRequestBody FlushCacheJSONBody
+
+// UpdateDefaultZonesJSONRequestBody defines body for UpdateDefaultZones for application/json ContentType.
+type UpdateDefaultZonesJSONRequestBody UpdateDefaultZonesJSONBody
+
+// InjectFaultJSONRequestBody defines body for InjectFault for application/json ContentType.
+type InjectFaultJSONRequestBody InjectFaultJSONBody
+
+// UpdateForwardersJSONRequestBody defines body for UpdateForwarders for application/json ContentType.
+type UpdateForwardersJSONRequestBody UpdateForwardersJSONBody
+
+// CreateNegativeTrustAnchorJSONRequestBody defines body for CreateNegativeTrustAnchor for application/json ContentType.
+type CreateNegativeTrustAnchorJSONRequestBody CreateNegativeTrustAnchorJSONBody
+
+// UpdateQueryLogJSONRequestBody defines body for UpdateQueryLog for application/json ContentType.
+type UpdateQueryLogJSONRequestBody UpdateQueryLogJSONBody
+
+// ExportRecoveryBundleJSONRequestBody defines body for ExportRecoveryBundle for application/json ContentType.
+type ExportRecoveryBundleJSONRequestBody ExportRecoveryBundleJSONBody
+
+// UpdateRecursionJSONRequestBody defines body for UpdateRecursion for application/json ContentType.
+type UpdateRecursionJSONRequestBody UpdateRecursionJSONBody
+
+// UpdateRootHintsJSONRequestBody defines body for UpdateRootHints for application/json ContentType.
+type UpdateRootHintsJSONRequestBody UpdateRootHintsJSONBody
+
+// CreateValidationExceptionJSONRequestBody defines body for CreateValidationException for application/json ContentType.
+type CreateValidationExceptionJSONRequestBody CreateValidationExceptionJSONBody
+
+// UpdateViewsJSONRequestBody defines body for UpdateViews for application/json ContentType.
+type UpdateViewsJSONRequestBody UpdateViewsJSONBody
+
+// UpdateTsigKeyJSONRequestBody defines body for UpdateTsigKey for application/json ContentType.
+type UpdateTsigKeyJSONRequestBody UpdateTsigKeyJSONBody
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody CreateWebhookJSONBody
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody UpdateWebhookJSONBody
+
+// CreateZoneJSONRequestBody defines body for CreateZone for application/json ContentType.
+type CreateZoneJSONRequestBody CreateZoneJSONBody
+
+// TransferZoneJSONRequestBody defines body for TransferZone for application/json ContentType.
+type TransferZoneJSONRequestBody TransferZoneJSONBody
+
+// UpdateZoneJSONRequestBody defines body for UpdateZone for application/json ContentType.
+type UpdateZoneJSONRequestBody UpdateZoneJSONBody
+
+// CloneZoneJSONRequestBody defines body for CloneZone for application/json ContentType.
+type CloneZoneJSONRequestBody CloneZoneJSONBody
+
+// SetDelegationJSONRequestBody defines body for SetDelegation for application/json ContentType.
+type SetDelegationJSONRequestBody SetDelegationJSONBody
+
+// FreezeZoneJSONRequestBody defines body for FreezeZone for application/json ContentType.
+type FreezeZoneJSONRequestBody FreezeZoneJSONBody
+
+// CreateRecordGeneratorJSONRequestBody defines body for CreateRecordGenerator for application/json ContentType.
+type CreateRecordGeneratorJSONRequestBody CreateRecordGeneratorJSONBody
+
+// UpdateRecordGeneratorJSONRequestBody defines body for UpdateRecordGenerator for application/json ContentType.
+type UpdateRecordGeneratorJSONRequestBody UpdateRecordGeneratorJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get the archived zones, newest first
+	// (GET /archives)
+	GetZoneArchives(ctx echo.Context) error
+	// Delete an archived zone for good
+	// (DELETE /archives/{archive_id})
+	DeleteZoneArchive(ctx echo.Context, archiveId string) error
+	// Download the bundle of an archived zone
+	// (GET /archives/{archive_id})
+	GetZoneArchiveBundle(ctx echo.Context, archiveId string) error
+	// Serve an archived zone again
+	// (POST /archives/{archive_id}/restore)
+	RestoreZoneArchive(ctx echo.Context, archiveId string) error
+	// Get the audit log of zone and record changes, newest first
+	// (GET /audit-logs)
+	GetAuditLogs(ctx echo.Context, params GetAuditLogsParams) error
+	// Get the audit log exporters
+	// (GET /audit-logs/exporters)
+	GetAuditExporters(ctx echo.Context) error
+	// Stream the audit log to a syslog collector or an HTTP endpoint
+	// (POST /audit-logs/exporters)
+	CreateAuditExporter(ctx echo.Context) error
+	// Delete an audit log exporter
+	// (DELETE /audit-logs/exporters/{exporter_id})
+	DeleteAuditExporter(ctx echo.Context, exporterId string) error
+	// Update an audit log exporter
+	// (PUT /audit-logs/exporters/{exporter_id})
+	UpdateAuditExporter(ctx echo.Context, exporterId string) error
+	// Get the manager instances sharing the database
+	// (GET /cluster)
+	GetClusterInstances(ctx echo.Context) error
+	// Reload the settings file
+	// (POST /config/reload)
+	ReloadConfig(ctx echo.Context) error
+	// Get the experimental features and whether they are enabled
+	// (GET /features)
+	GetFeatures(ctx echo.Context) error
+	// Get the record fragments shared by the zones
+	// (GET /fragments)
+	GetFragments(ctx echo.Context) error
+	// Delete a record fragment no zone includes
+	// (DELETE /fragments/{name})
+	DeleteFragment(ctx echo.Context, name string) error
+	// Create or replace a record fragment
+	// (PUT /fragments/{name})
+	UpdateFragment(ctx echo.Context, name string) error
+	// Get the missing names queried the most
+	// (GET /insights)
+	GetInsights(ctx echo.Context, params GetInsightsParams) error
+	// Get the hosts of the managed zones as an Ansible dynamic inventory
+	// (GET /inventory)
+	GetInventory(ctx echo.Context, params GetInventoryParams) error
+	// Get the languages the API and the UI are translated to
+	// (GET /locales)
+	GetLocales(ctx echo.Context) error
+	// Get the translation catalog of a language
+	// (GET /locales/{language})
+	GetLocaleCatalog(ctx echo.Context, language string) error
+	// Get the scrape targets of the labelled records for the Prometheus HTTP service discovery
+	// (GET /prometheus/sd)
+	GetScrapeTargets(ctx echo.Context, params GetScrapeTargetsParams) error
+	// Get the records selected by their labels across the zones
+	// (GET /record-groups)
+	GetRecordGroups(ctx echo.Context, params GetRecordGroupsParams) error
+	// Change the records selected by their labels across the zones at once
+	// (POST /record-groups/batch)
+	BatchRecordGroups(ctx echo.Context) error
+	// Get all records on the selected zone
+	// (GET /records/{domain})
+	GetRecords(ctx echo.Context, domain string, params GetRecordsParams) error
+	// Create a new record on the selected zone
+	// (POST /records/{domain})
+	CreateRecord(ctx echo.Context, domain string) error
+	// Create, update and delete records of the selected zone at once
+	// (POST /records/{domain}/batch)
+	BatchRecords(ctx echo.Context, domain string) error
+	// Import the records of a hosts file into the selected zone
+	// (POST /records/{domain}/import)
+	ImportRecords(ctx echo.Context, domain string) error
+	// Delete a record by id on the selected zone
+	// (DELETE /records/{domain}/{record_id})
+	DeleteRecord(ctx echo.Context, domain string, recordId string) error
+	// Get a record by id on the selected zone
+	// (GET /records/{domain}/{record_id})
+	GetRecordById(ctx echo.Context, domain string, recordId string) error
+	// Update a record by id on the selected zone
+	// (PUT /records/{domain}/{record_id})
+	UpdateRecord(ctx echo.Context, domain string, recordId string) error
+	// Get the changes of a record, newest first
+	// (GET /records/{domain}/{record_id}/history)
+	GetRecordHistory(ctx echo.Context, domain string, recordId string, params GetRecordHistoryParams) error
+	// Create the reverse zone of a network
+	// (POST /reverse-zones)
+	CreateReverseZone(ctx echo.Context) error
+	// Get the domains exempted from every feed
+	// (GET /rpz/allowlist)
+	GetRpzAllowlist(ctx echo.Context) error
+	// Replace the domains exempted from every feed
+	// (PUT /rpz/allowlist)
+	UpdateRpzAllowlist(ctx echo.Context) error
+	// Get all response policy feeds
+	// (GET /rpz/feeds)
+	GetRpzFeeds(ctx echo.Context) error
+	// Subscribe to a blocklist feed
+	// (POST /rpz/feeds)
+	CreateRpzFeed(ctx echo.Context) error
+	// Unsubscribe from a blocklist feed
+	// (DELETE /rpz/feeds/{feed_id})
+	DeleteRpzFeed(ctx echo.Context, feedId string) error
+	// Update a blocklist feed
+	// (PUT /rpz/feeds/{feed_id})
+	UpdateRpzFeed(ctx echo.Context, feedId string) error
+	// Download a blocklist feed right away
+	// (POST /rpz/feeds/{feed_id}/refresh)
+	RefreshRpzFeed(ctx echo.Context, feedId string) error
+	// Get the filtering profiles
+	// (GET /rpz/profiles)
+	GetRpzProfiles(ctx echo.Context) error
+	// Enable or disable a filtering profile
+	// (PUT /rpz/profiles/{name})
+	UpdateRpzProfile(ctx echo.Context, name string) error
+	// Get the networks whose queries are ignored
+	// (GET /server/blackhole)
+	GetBlackhole(ctx echo.Context) error
+	// Replace the blackhole presets and custom networks
+	// (PUT /server/blackhole)
+	UpdateBlackhole(ctx echo.Context) error
+	// Get the curated blackhole presets
+	// (GET /server/blackhole/presets)
+	GetBlackholePresets(ctx echo.Context) error
+	// Retrieve a dump of the cache
+	// (GET /server/cache/dump)
+	DumpCache(ctx echo.Context) error
+	// Purge cached answers
+	// (POST /server/cache/flush)
+	FlushCache(ctx echo.Context) error
+	// Get the consistency repair report of the last start
+	// (GET /server/consistency)
+	GetConsistencyReport(ctx echo.Context) error
+	// Get how the default zones are included
+	// (GET /server/default-zones)
+	GetDefaultZones(ctx echo.Context) error
+	// Include the default zones of the image, leave them out or replace them by custom content
+	// (PUT /server/default-zones)
+	UpdateDefaultZones(ctx echo.Context) error
+	// Get the failover role of this manager and the health of the primary pair
+	// (GET /server/failover)
+	GetFailoverStatus(ctx echo.Context) error
+	// Promote this standby to primary right away
+	// (POST /server/failover/promote)
+	PromoteFailover(ctx echo.Context) error
+	// Clear the injected faults
+	// (DELETE /server/faults)
+	ClearFaults(ctx echo.Context) error
+	// Get the faults injected in chaos mode
+	// (GET /server/faults)
+	GetFaults(ctx echo.Context) error
+	// Inject a fault to rehearse the monitoring and the runbooks
+	// (POST /server/faults)
+	InjectFault(ctx echo.Context) error
+	// Get the configured forwarders and their health
+	// (GET /server/forwarders)
+	GetForwarders(ctx echo.Context) error
+	// Replace the forwarders used in recursive mode
+	// (PUT /server/forwarders)
+	UpdateForwarders(ctx echo.Context) error
+	// Get all active negative trust anchors
+	// (GET /server/negative-trust-anchors)
+	GetNegativeTrustAnchors(ctx echo.Context) error
+	// Temporarily disable DNSSEC validation for a domain
+	// (POST /server/negative-trust-anchors)
+	CreateNegativeTrustAnchor(ctx echo.Context) error
+	// Remove a negative trust anchor
+	// (DELETE /server/negative-trust-anchors/{domain})
+	DeleteNegativeTrustAnchor(ctx echo.Context, domain string) error
+	// Get the query logging settings
+	// (GET /server/query-log)
+	GetQueryLog(ctx echo.Context) error
+	// Update the query logging settings
+	// (PUT /server/query-log)
+	UpdateQueryLog(ctx echo.Context) error
+	// Export everything needed to rebuild the manager after a disaster
+	// (POST /server/recovery-bundle)
+	ExportRecoveryBundle(ctx echo.Context) error
+	// Get the recursion profile
+	// (GET /server/recursion)
+	GetRecursion(ctx echo.Context) error
+	// Switch between authoritative-only and recursive+authoritative mode
+	// (PUT /server/recursion)
+	UpdateRecursion(ctx echo.Context) error
+	// Get the root hints settings
+	// (GET /server/root-hints)
+	GetRootHints(ctx echo.Context) error
+	// Leave the root hints to the image, or give custom ones or a url to refresh them from
+	// (PUT /server/root-hints)
+	UpdateRootHints(ctx echo.Context) error
+	// Download the refreshed root hints right away
+	// (POST /server/root-hints/refresh)
+	RefreshRootHints(ctx echo.Context) error
+	// Get the SOA serials the nodes serve for every zone
+	// (GET /server/serial-consistency)
+	GetSerialConsistency(ctx echo.Context) error
+	// Get all domains excluded from DNSSEC validation
+	// (GET /server/validation-exceptions)
+	GetValidationExceptions(ctx echo.Context) error
+	// Exclude a domain from DNSSEC validation (validate-except)
+	// (POST /server/validation-exceptions)
+	CreateValidationException(ctx echo.Context) error
+	// Remove a domain from the DNSSEC validation exceptions
+	// (DELETE /server/validation-exceptions/{domain})
+	DeleteValidationException(ctx echo.Context, domain string) error
+	// Get the views serving different answers per client network
+	// (GET /server/views)
+	GetViews(ctx echo.Context) error
+	// Replace the views serving different answers per client network
+	// (PUT /server/views)
+	UpdateViews(ctx echo.Context) error
+	// Get query statistics broken down by client network
+	// (GET /stats/networks)
+	GetNetworkStats(ctx echo.Context) error
+	// Get the managed TSIG keys, without their secret
+	// (GET /tsig-keys)
+	GetTsigKeys(ctx echo.Context) error
+	// Delete a managed TSIG key no zone refers to
+	// (DELETE /tsig-keys/{name})
+	DeleteTsigKey(ctx echo.Context, name string) error
+	// Create or replace a managed TSIG key
+	// (PUT /tsig-keys/{name})
+	UpdateTsigKey(ctx echo.Context, name string) error
+	// Get all webhooks
+	// (GET /webhooks)
+	GetWebhooks(ctx echo.Context) error
+	// Subscribe a webhook to change events
+	// (POST /webhooks)
+	CreateWebhook(ctx echo.Context) error
+	// Delete a webhook
+	// (DELETE /webhooks/{webhook_id})
+	DeleteWebhook(ctx echo.Context, webhookId string) error
+	// Update a webhook
+	// (PUT /webhooks/{webhook_id})
+	UpdateWebhook(ctx echo.Context, webhookId string) error
+	// Get all zones
+	// (GET /zones)
+	GetZones(ctx echo.Context, params GetZonesParams) error
+	// Create a new zone
+	// (POST /zones)
+	CreateZone(ctx echo.Context) error
+	// Import a zone from a zone file
+	// (POST /zones/import)
+	ImportZone(ctx echo.Context, params ImportZoneParams) error
+	// Get the SOA timer presets
+	// (GET /zones/soa-presets)
+	GetSoaPresets(ctx echo.Context) error
+	// Import a zone transferred from another DNS server
+	// (POST /zones/transfer)
+	TransferZone(ctx echo.Context) error
+	// Delete the selected zone
+	// (DELETE /zones/{domain})
+	DeleteZone(ctx echo.Context, domain string) error
+	// Get a zone by domain name
+	// (GET /zones/{domain})
+	GetZoneByDomain(ctx echo.Context, domain string) error
+	// Update the selected zone
+	// (PUT /zones/{domain})
+	UpdateZone(ctx echo.Context, domain string) error
+	// Archive the selected zone
+	// (POST /zones/{domain}/archive)
+	ArchiveZone(ctx echo.Context, domain string) error
+	// Clone the selected zone under a new domain
+	// (POST /zones/{domain}/clone)
+	CloneZone(ctx echo.Context, domain string) error
+	// Get the subdomains delegated by the selected zone
+	// (GET /zones/{domain}/delegations)
+	GetDelegations(ctx echo.Context, domain string) error
+	// Delete the delegation of a subdomain of the selected zone along with the glue under it
+	// (DELETE /zones/{domain}/delegations/{name})
+	DeleteDelegation(ctx echo.Context, domain string, name string) error
+	// Delegate a subdomain of the selected zone
+	// (PUT /zones/{domain}/delegations/{name})
+	SetDelegation(ctx echo.Context, domain string, name string) error
+	// Get the signing state of the selected zone
+	// (GET /zones/{domain}/dnssec)
+	GetZoneDnssec(ctx echo.Context, domain string) error
+	// Get the DS records of the selected zone
+	// (GET /zones/{domain}/ds)
+	GetZoneDs(ctx echo.Context, domain string) error
+	// Export the selected zone as a zone file
+	// (GET /zones/{domain}/export)
+	ExportZone(ctx echo.Context, domain string, params ExportZoneParams) error
+	// Make the selected zone read-only
+	// (POST /zones/{domain}/freeze)
+	FreezeZone(ctx echo.Context, domain string) error
+	// Get the record generators of the selected zone
+	// (GET /zones/{domain}/generators)
+	GetRecordGenerators(ctx echo.Context, domain string) error
+	// Create a record generator on the selected zone
+	// (POST /zones/{domain}/generators)
+	CreateRecordGenerator(ctx echo.Context, domain string) error
+	// Delete a record generator of the selected zone along with its records
+	// (DELETE /zones/{domain}/generators/{generator_id})
+	DeleteRecordGenerator(ctx echo.Context, domain string, generatorId string) error
+	// Update a record generator of the selected zone
+	// (PUT /zones/{domain}/generators/{generator_id})
+	UpdateRecordGenerator(ctx echo.Context, domain string, generatorId string) error
+	// Publish the delegation of the selected zone at its registrar
+	// (POST /zones/{domain}/registrar/publish)
+	PublishZoneDelegation(ctx echo.Context, domain string) error
+	// Look the registration of the selected zone up
+	// (GET /zones/{domain}/registration)
+	GetZoneRegistration(ctx echo.Context, domain string) error
+	// Generate a human readable report of the selected zone
+	// (GET /zones/{domain}/report)
+	GetZoneReport(ctx echo.Context, domain string, params GetZoneReportParams) error
+	// Get the size of the selected zone
+	// (GET /zones/{domain}/stats)
+	GetZoneStats(ctx echo.Context, domain string) error
+	// Let the selected frozen zone be changed again
+	// (POST /zones/{domain}/thaw)
+	ThawZone(ctx echo.Context, domain string) error
+	// Get the records of the selected zone which answered no query lately
+	// (GET /zones/{domain}/unused)
+	GetUnusedRecords(ctx echo.Context, domain string, params GetUnusedRecordsParams) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetZoneArchives converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneArchives(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZoneArchives(ctx)
+	return err
+}
+
+// DeleteZoneArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteZoneArchive(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "archive_id" -------------
+	var archiveId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "archive_id", runtime.ParamLocationPath, ctx.Param("archive_id"), &archiveId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter archive_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteZoneArchive(ctx, archiveId)
+	return err
+}
+
+// GetZoneArchiveBundle converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneArchiveBundle(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "archive_id" -------------
+	var archiveId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "archive_id", runtime.ParamLocationPath, ctx.Param("archive_id"), &archiveId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter archive_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZoneArchiveBundle(ctx, archiveId)
+	return err
+}
+
+// RestoreZoneArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) RestoreZoneArchive(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "archive_id" -------------
+	var archiveId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "archive_id", runtime.ParamLocationPath, ctx.Param("archive_id"), &archiveId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter archive_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RestoreZoneArchive(ctx, archiveId)
+	return err
+}
+
+// GetAuditLogs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAuditLogs(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuditLogsParams
+	// ------------- Optional query parameter "zone" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "zone", ctx.QueryParams(), &params.Zone)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter zone: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAuditLogs(ctx, params)
+	return err
+}
+
+// GetAuditExporters converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAuditExporters(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAuditExporters(ctx)
+	return err
+}
+
+// CreateAuditExporter converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAuditExporter(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateAuditExporter(ctx)
+	return err
+}
+
+// DeleteAuditExporter converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteAuditExporter(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "exporter_id" -------------
+	var exporterId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "exporter_id", runtime.ParamLocationPath, ctx.Param("exporter_id"), &exporterId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter exporter_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteAuditExporter(ctx, exporterId)
+	return err
+}
+
+// UpdateAuditExporter converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateAuditExporter(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "exporter_id" -------------
+	var exporterId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "exporter_id", runtime.ParamLocationPath, ctx.Param("exporter_id"), &exporterId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter exporter_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateAuditExporter(ctx, exporterId)
+	return err
+}
+
+// GetClusterInstances converts echo context to params.
+func (w *ServerInterfaceWrapper) GetClusterInstances(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetClusterInstances(ctx)
+	return err
+}
+
+// ReloadConfig converts echo context to params.
+func (w *ServerInterfaceWrapper) ReloadConfig(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ReloadConfig(ctx)
+	return err
+}
+
+// GetFeatures converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFeatures(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFeatures(ctx)
+	return err
+}
+
+// GetFragments converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFragments(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFragments(ctx)
+	return err
+}
+
+// DeleteFragment converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteFragment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteFragment(ctx, name)
+	return err
+}
+
+// UpdateFragment converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateFragment(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateFragment(ctx, name)
+	return err
+}
+
+// GetInsights converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInsights(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetInsightsParams
+	// ------------- Optional query parameter "domain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", ctx.QueryParams(), &params.Domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetInsights(ctx, params)
+	return err
+}
+
+// GetInventory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInventory(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetInventoryParams
+	// ------------- Optional query parameter "domain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", ctx.QueryParams(), &params.Domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetInventory(ctx, params)
+	return err
+}
+
+// GetLocales converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLocales(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetLocales(ctx)
+	return err
+}
+
+// GetLocaleCatalog converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLocaleCatalog(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "language" -------------
+	var language string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "language", runtime.ParamLocationPath, ctx.Param("language"), &language)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter language: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetLocaleCatalog(ctx, language)
+	return err
+}
+
+// GetScrapeTargets converts echo context to params.
+func (w *ServerInterfaceWrapper) GetScrapeTargets(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetScrapeTargetsParams
+	// ------------- Optional query parameter "domain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", ctx.QueryParams(), &params.Domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetScrapeTargets(ctx, params)
+	return err
+}
+
+// GetRecordGroups converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordGroups(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRecordGroupsParams
+	// ------------- Required query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "domain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", ctx.QueryParams(), &params.Domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRecordGroups(ctx, params)
+	return err
+}
+
+// BatchRecordGroups converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchRecordGroups(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.BatchRecordGroups(ctx)
+	return err
+}
+
+// GetRecords converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecords(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRecordsParams
+	// ------------- Optional query parameter "external_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "external_id", ctx.QueryParams(), &params.ExternalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter external_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRecords(ctx, domain, params)
+	return err
+}
+
+// CreateRecord converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRecord(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateRecord(ctx, domain)
+	return err
+}
+
+// BatchRecords converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchRecords(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.BatchRecords(ctx, domain)
+	return err
+}
+
+// ImportRecords converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportRecords(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ImportRecords(ctx, domain)
+	return err
+}
+
+// DeleteRecord converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteRecord(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Path parameter "record_id" -------------
+	var recordId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteRecord(ctx, domain, recordId)
+	return err
+}
+
+// GetRecordById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Path parameter "record_id" -------------
+	var recordId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRecordById(ctx, domain, recordId)
+	return err
+}
+
+// UpdateRecord converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRecord(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Path parameter "record_id" -------------
+	var recordId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRecord(ctx, domain, recordId)
+	return err
+}
+
+// GetRecordHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Path parameter "record_id" -------------
+	var recordId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRecordHistoryParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRecordHistory(ctx, domain, recordId, params)
+	return err
+}
+
+// CreateReverseZone converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateReverseZone(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateReverseZone(ctx)
+	return err
+}
+
+// GetRpzAllowlist converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRpzAllowlist(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRpzAllowlist(ctx)
+	return err
+}
+
+// UpdateRpzAllowlist converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRpzAllowlist(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRpzAllowlist(ctx)
+	return err
+}
+
+// GetRpzFeeds converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRpzFeeds(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRpzFeeds(ctx)
+	return err
+}
+
+// CreateRpzFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRpzFeed(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateRpzFeed(ctx)
+	return err
+}
+
+// DeleteRpzFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteRpzFeed(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "feed_id" -------------
+	var feedId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "feed_id", runtime.ParamLocationPath, ctx.Param("feed_id"), &feedId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feed_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteRpzFeed(ctx, feedId)
+	return err
+}
+
+// UpdateRpzFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRpzFeed(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "feed_id" -------------
+	var feedId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "feed_id", runtime.ParamLocationPath, ctx.Param("feed_id"), &feedId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feed_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRpzFeed(ctx, feedId)
+	return err
+}
+
+// RefreshRpzFeed converts echo context to params.
+func (w *ServerInterfaceWrapper) RefreshRpzFeed(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "feed_id" -------------
+	var feedId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "feed_id", runtime.ParamLocationPath, ctx.Param("feed_id"), &feedId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter feed_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RefreshRpzFeed(ctx, feedId)
+	return err
+}
+
+// GetRpzProfiles converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRpzProfiles(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRpzProfiles(ctx)
+	return err
+}
+
+// UpdateRpzProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRpzProfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRpzProfile(ctx, name)
+	return err
+}
+
+// GetBlackhole converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBlackhole(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBlackhole(ctx)
+	return err
+}
+
+// UpdateBlackhole converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateBlackhole(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateBlackhole(ctx)
+	return err
+}
+
+// GetBlackholePresets converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBlackholePresets(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBlackholePresets(ctx)
+	return err
+}
+
+// DumpCache converts echo context to params.
+func (w *ServerInterfaceWrapper) DumpCache(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DumpCache(ctx)
+	return err
+}
+
+// FlushCache converts echo context to params.
+func (w *ServerInterfaceWrapper) FlushCache(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FlushCache(ctx)
+	return err
+}
+
+// GetConsistencyReport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetConsistencyReport(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetConsistencyReport(ctx)
+	return err
+}
+
+// GetDefaultZones converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDefaultZones(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetDefaultZones(ctx)
+	return err
+}
+
+// UpdateDefaultZones converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateDefaultZones(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateDefaultZones(ctx)
+	return err
+}
+
+// GetFailoverStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFailoverStatus(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFailoverStatus(ctx)
+	return err
+}
+
+// PromoteFailover converts echo context to params.
+func (w *ServerInterfaceWrapper) PromoteFailover(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PromoteFailover(ctx)
+	return err
+}
+
+// ClearFaults converts echo context to params.
+func (w *ServerInterfaceWrapper) ClearFaults(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ClearFaults(ctx)
+	return err
+}
+
+// GetFaults converts echo context to params.
+func (w *ServerInterfaceWrapper) GetFaults(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetFaults(ctx)
+	return err
+}
+
+// InjectFault converts echo context to params.
+func (w *ServerInterfaceWrapper) InjectFault(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.InjectFault(ctx)
+	return err
+}
+
+// GetForwarders converts echo context to params.
+func (w *ServerInterfaceWrapper) GetForwarders(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetForwarders(ctx)
+	return err
+}
+
+// UpdateForwarders converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateForwarders(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateForwarders(ctx)
+	return err
+}
+
+// GetNegativeTrustAnchors converts echo context to params.
+func (w *ServerInterfaceWrapper) GetNegativeTrustAnchors(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetNegativeTrustAnchors(ctx)
+	return err
+}
+
+// CreateNegativeTrustAnchor converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateNegativeTrustAnchor(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateNegativeTrustAnchor(ctx)
+	return err
+}
+
+// DeleteNegativeTrustAnchor converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteNegativeTrustAnchor(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteNegativeTrustAnchor(ctx, domain)
+	return err
+}
+
+// GetQueryLog converts echo context to params.
+func (w *ServerInterfaceWrapper) GetQueryLog(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetQueryLog(ctx)
+	return err
+}
+
+// UpdateQueryLog converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateQueryLog(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateQueryLog(ctx)
+	return err
+}
+
+// ExportRecoveryBundle converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportRecoveryBundle(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ExportRecoveryBundle(ctx)
+	return err
+}
+
+// GetRecursion converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecursion(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRecursion(ctx)
+	return err
+}
+
+// UpdateRecursion converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRecursion(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRecursion(ctx)
+	return err
+}
+
+// GetRootHints converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRootHints(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRootHints(ctx)
+	return err
+}
+
+// UpdateRootHints converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRootHints(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateRootHints(ctx)
+	return err
+}
+
+// RefreshRootHints converts echo context to params.
+func (w *ServerInterfaceWrapper) RefreshRootHints(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RefreshRootHints(ctx)
+	return err
+}
+
+// GetSerialConsistency converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSerialConsistency(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetSerialConsistency(ctx)
+	return err
+}
+
+// GetValidationExceptions converts echo context to params.
+func (w *ServerInterfaceWrapper) GetValidationExceptions(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetValidationExceptions(ctx)
+	return err
+}
+
+// CreateValidationException converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateValidationException(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateValidationException(ctx)
+	return err
+}
+
+// DeleteValidationException converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteValidationException(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteValidationException(ctx, domain)
+	return err
+}
+
+// GetViews converts echo context to params.
+func (w *ServerInterfaceWrapper) GetViews(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetViews(ctx)
+	return err
+}
+
+// UpdateViews converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateViews(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateViews(ctx)
+	return err
+}
+
+// GetNetworkStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetNetworkStats(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetNetworkStats(ctx)
+	return err
+}
+
+// GetTsigKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTsigKeys(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTsigKeys(ctx)
+	return err
+}
+
+// DeleteTsigKey converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteTsigKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteTsigKey(ctx, name)
+	return err
+}
+
+// UpdateTsigKey converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateTsigKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateTsigKey(ctx, name)
+	return err
+}
+
+// GetWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWebhooks(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetWebhooks(ctx)
+	return err
+}
+
+// CreateWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateWebhook(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateWebhook(ctx)
+	return err
+}
+
+// DeleteWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWebhook(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteWebhook(ctx, webhookId)
+	return err
+}
+
+// UpdateWebhook converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateWebhook(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateWebhook(ctx, webhookId)
+	return err
+}
+
+// GetZones converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZones(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetZonesParams
+	// ------------- Optional query parameter "expiring_within" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expiring_within", ctx.QueryParams(), &params.ExpiringWithin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter expiring_within: %s", err))
+	}
+
+	// ------------- Optional query parameter "technical_contact" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "technical_contact", ctx.QueryParams(), &params.TechnicalContact)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter technical_contact: %s", err))
+	}
+
+	// ------------- Optional query parameter "external_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "external_id", ctx.QueryParams(), &params.ExternalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter external_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "group" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "group", ctx.QueryParams(), &params.Group)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter group: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", ctx.QueryParams(), &params.Offset)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZones(ctx, params)
+	return err
+}
+
+// CreateZone converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateZone(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateZone(ctx)
+	return err
+}
+
+// ImportZone converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportZone(ctx echo.Context) error {
+	var err error
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportZoneParams
+	// ------------- Optional query parameter "domain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "domain", ctx.QueryParams(), &params.Domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// ------------- Optional query parameter "force" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "force", ctx.QueryParams(), &params.Force)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter force: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ImportZone(ctx, params)
+	return err
+}
+
+// GetSoaPresets converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoaPresets(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetSoaPresets(ctx)
+	return err
+}
+
+// TransferZone converts echo context to params.
+func (w *ServerInterfaceWrapper) TransferZone(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.TransferZone(ctx)
+	return err
+}
+
+// DeleteZone converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteZone(ctx, domain)
+	return err
+}
+
+// GetZoneByDomain converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneByDomain(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZoneByDomain(ctx, domain)
+	return err
+}
+
+// UpdateZone converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateZone(ctx, domain)
+	return err
+}
+
+// ArchiveZone converts echo context to params.
+func (w *ServerInterfaceWrapper) ArchiveZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ArchiveZone(ctx, domain)
+	return err
+}
 
-	RecordResTypeIPSECKEY RecordResType = "IPSECKEY"
+// CloneZone converts echo context to params.
+func (w *ServerInterfaceWrapper) CloneZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-	RecordResTypeKEY RecordResType = "KEY"
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-	RecordResTypeMX RecordResType = "MX"
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CloneZone(ctx, domain)
+	return err
+}
 
-	RecordResTypeNS RecordResType = "NS"
+// GetDelegations converts echo context to params.
+func (w *ServerInterfaceWrapper) GetDelegations(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-	RecordResTypePTR RecordResType = "PTR"
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-	RecordResTypeSPF RecordResType = "SPF"
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetDelegations(ctx, domain)
+	return err
+}
 
-	RecordResTypeSRV RecordResType = "SRV"
+// DeleteDelegation converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteDelegation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-	RecordResTypeTLSA RecordResType = "TLSA"
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-	RecordResTypeTXT RecordResType = "TXT"
-)
+	// ------------- Path parameter "name" -------------
+	var name string
 
-// GeneralRes defines model for general-res.
-type GeneralRes struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
 
-// RecordReq defines model for record-req.
-type RecordReq struct {
-	Name  string        `json:"name"`
-	Type  RecordReqType `json:"type"`
-	Value string        `json:"value"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteDelegation(ctx, domain, name)
+	return err
 }
 
-// RecordReqType defines model for RecordReq.Type.
-type RecordReqType string
+// SetDelegation converts echo context to params.
+func (w *ServerInterfaceWrapper) SetDelegation(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-// RecordRes defines model for record-res.
-type RecordRes struct {
-	Id    string        `json:"id"`
-	Name  string        `json:"name"`
-	Type  RecordResType `json:"type"`
-	Value string        `json:"value"`
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-// RecordResType defines model for RecordRes.Type.
-type RecordResType string
+	// ------------- Path parameter "name" -------------
+	var name string
 
-// SoaRes defines model for soa-res.
-type SoaRes struct {
-	CacheTtl          int    `json:"cache_ttl"`
-	Expire            int    `json:"expire"`
-	Id                string `json:"id"`
-	MailAddress       string `json:"mail_address"`
-	Name              string `json:"name"`
-	PrimaryNameServer string `json:"primary_name_server"`
-	Refresh           int    `json:"refresh"`
-	Retry             int    `json:"retry"`
-	Serial            string `json:"serial"`
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
 
-// ZoneRes defines model for zone-res.
-type ZoneRes struct {
-	Domain  string      `json:"domain"`
-	Id      string      `json:"id"`
-	Records []RecordRes `json:"records"`
-	Soa     SoaRes      `json:"soa"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SetDelegation(ctx, domain, name)
+	return err
 }
 
-// BadRequest defines model for bad-request.
-type BadRequest GeneralRes
-
-// DefaultError defines model for default-error.
-type DefaultError GeneralRes
+// GetZoneDnssec converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneDnssec(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-// NotFound defines model for not-found.
-type NotFound GeneralRes
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-// CreateRecordJSONBody defines parameters for CreateRecord.
-type CreateRecordJSONBody RecordReq
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZoneDnssec(ctx, domain)
+	return err
+}
 
-// UpdateRecordJSONBody defines parameters for UpdateRecord.
-type UpdateRecordJSONBody RecordReq
+// GetZoneDs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneDs(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-// CreateZoneJSONBody defines parameters for CreateZone.
-type CreateZoneJSONBody struct {
-	Domain    string `json:"domain"`
-	MailAddr  string `json:"mail_addr"`
-	PrimaryNs string `json:"primary_ns"`
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-// UpdateZoneJSONBody defines parameters for UpdateZone.
-type UpdateZoneJSONBody struct {
-	Domain    *string `json:"domain,omitempty"`
-	MailAddr  *string `json:"mail_addr,omitempty"`
-	PrimaryNs *string `json:"primary_ns,omitempty"`
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetZoneDs(ctx, domain)
+	return err
 }
 
-// CreateRecordJSONRequestBody defines body for CreateRecord for application/json ContentType.
-type CreateRecordJSONRequestBody CreateRecordJSONBody
+// ExportZone converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
 
-// UpdateRecordJSONRequestBody defines body for UpdateRecord for application/json ContentType.
-type UpdateRecordJSONRequestBody UpdateRecordJSONBody
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
-// CreateZoneJSONRequestBody defines body for CreateZone for application/json ContentType.
-type CreateZoneJSONRequestBody CreateZoneJSONBody
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportZoneParams
+	// ------------- Optional query parameter "format" -------------
 
-// UpdateZoneJSONRequestBody defines body for UpdateZone for application/json ContentType.
-type UpdateZoneJSONRequestBody UpdateZoneJSONBody
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Get all records on the selected zone
-	// (GET /records/{domain})
-	GetRecords(ctx echo.Context, domain string) error
-	// Create a new record on the selected zone
-	// (POST /records/{domain})
-	CreateRecord(ctx echo.Context, domain string) error
-	// Delete a record by id on the selected zone
-	// (DELETE /records/{domain}/{record_id})
-	DeleteRecord(ctx echo.Context, domain string, recordId string) error
-	// Get a record by id on the selected zone
-	// (GET /records/{domain}/{record_id})
-	GetRecordById(ctx echo.Context, domain string, recordId string) error
-	// Update a record by id on the selected zone
-	// (PUT /records/{domain}/{record_id})
-	UpdateRecord(ctx echo.Context, domain string, recordId string) error
-	// Get all zones
-	// (GET /zones)
-	GetZones(ctx echo.Context) error
-	// Create a new zone
-	// (POST /zones)
-	CreateZone(ctx echo.Context) error
-	// Delete the selected zone
-	// (DELETE /zones/{domain})
-	DeleteZone(ctx echo.Context, domain string) error
-	// Get a zone by domain name
-	// (GET /zones/{domain})
-	GetZoneByDomain(ctx echo.Context, domain string) error
-	// Update the selected zone
-	// (PUT /zones/{domain})
-	UpdateZone(ctx echo.Context, domain string) error
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ExportZone(ctx, domain, params)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// FreezeZone converts echo context to params.
+func (w *ServerInterfaceWrapper) FreezeZone(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.FreezeZone(ctx, domain)
+	return err
 }
 
-// GetRecords converts echo context to params.
-func (w *ServerInterfaceWrapper) GetRecords(ctx echo.Context) error {
+// GetRecordGenerators converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRecordGenerators(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -213,12 +3537,12 @@ func (w *ServerInterfaceWrapper) GetRecords(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetRecords(ctx, domain)
+	err = w.Handler.GetRecordGenerators(ctx, domain)
 	return err
 }
 
-// CreateRecord converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateRecord(ctx echo.Context) error {
+// CreateRecordGenerator converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRecordGenerator(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -229,12 +3553,12 @@ func (w *ServerInterfaceWrapper) CreateRecord(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.CreateRecord(ctx, domain)
+	err = w.Handler.CreateRecordGenerator(ctx, domain)
 	return err
 }
 
-// DeleteRecord converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteRecord(ctx echo.Context) error {
+// DeleteRecordGenerator converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteRecordGenerator(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -244,21 +3568,21 @@ func (w *ServerInterfaceWrapper) DeleteRecord(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
 	}
 
-	// ------------- Path parameter "record_id" -------------
-	var recordId string
+	// ------------- Path parameter "generator_id" -------------
+	var generatorId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "generator_id", runtime.ParamLocationPath, ctx.Param("generator_id"), &generatorId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter generator_id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteRecord(ctx, domain, recordId)
+	err = w.Handler.DeleteRecordGenerator(ctx, domain, generatorId)
 	return err
 }
 
-// GetRecordById converts echo context to params.
-func (w *ServerInterfaceWrapper) GetRecordById(ctx echo.Context) error {
+// UpdateRecordGenerator converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateRecordGenerator(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -268,21 +3592,21 @@ func (w *ServerInterfaceWrapper) GetRecordById(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
 	}
 
-	// ------------- Path parameter "record_id" -------------
-	var recordId string
+	// ------------- Path parameter "generator_id" -------------
+	var generatorId string
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
+	err = runtime.BindStyledParameterWithLocation("simple", false, "generator_id", runtime.ParamLocationPath, ctx.Param("generator_id"), &generatorId)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter generator_id: %s", err))
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetRecordById(ctx, domain, recordId)
+	err = w.Handler.UpdateRecordGenerator(ctx, domain, generatorId)
 	return err
 }
 
-// UpdateRecord converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateRecord(ctx echo.Context) error {
+// PublishZoneDelegation converts echo context to params.
+func (w *ServerInterfaceWrapper) PublishZoneDelegation(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -292,39 +3616,68 @@ func (w *ServerInterfaceWrapper) UpdateRecord(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
 	}
 
-	// ------------- Path parameter "record_id" -------------
-	var recordId string
-
-	err = runtime.BindStyledParameterWithLocation("simple", false, "record_id", runtime.ParamLocationPath, ctx.Param("record_id"), &recordId)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter record_id: %s", err))
-	}
-
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateRecord(ctx, domain, recordId)
+	err = w.Handler.PublishZoneDelegation(ctx, domain)
 	return err
 }
 
-// GetZones converts echo context to params.
-func (w *ServerInterfaceWrapper) GetZones(ctx echo.Context) error {
+// GetZoneRegistration converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneRegistration(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetZones(ctx)
+	err = w.Handler.GetZoneRegistration(ctx, domain)
 	return err
 }
 
-// CreateZone converts echo context to params.
-func (w *ServerInterfaceWrapper) CreateZone(ctx echo.Context) error {
+// GetZoneReport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneReport(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetZoneReportParams
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.CreateZone(ctx)
+	err = w.Handler.GetZoneReport(ctx, domain, params)
 	return err
 }
 
-// DeleteZone converts echo context to params.
-func (w *ServerInterfaceWrapper) DeleteZone(ctx echo.Context) error {
+// GetZoneStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetZoneStats(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -335,12 +3688,12 @@ func (w *ServerInterfaceWrapper) DeleteZone(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteZone(ctx, domain)
+	err = w.Handler.GetZoneStats(ctx, domain)
 	return err
 }
 
-// GetZoneByDomain converts echo context to params.
-func (w *ServerInterfaceWrapper) GetZoneByDomain(ctx echo.Context) error {
+// ThawZone converts echo context to params.
+func (w *ServerInterfaceWrapper) ThawZone(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -351,12 +3704,12 @@ func (w *ServerInterfaceWrapper) GetZoneByDomain(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetZoneByDomain(ctx, domain)
+	err = w.Handler.ThawZone(ctx, domain)
 	return err
 }
 
-// UpdateZone converts echo context to params.
-func (w *ServerInterfaceWrapper) UpdateZone(ctx echo.Context) error {
+// GetUnusedRecords converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUnusedRecords(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "domain" -------------
 	var domain string
@@ -366,8 +3719,17 @@ func (w *ServerInterfaceWrapper) UpdateZone(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUnusedRecordsParams
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", ctx.QueryParams(), &params.Days)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter days: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateZone(ctx, domain)
+	err = w.Handler.GetUnusedRecords(ctx, domain, params)
 	return err
 }
 
@@ -399,15 +3761,112 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/archives", wrapper.GetZoneArchives)
+	router.DELETE(baseURL+"/archives/:archive_id", wrapper.DeleteZoneArchive)
+	router.GET(baseURL+"/archives/:archive_id", wrapper.GetZoneArchiveBundle)
+	router.POST(baseURL+"/archives/:archive_id/restore", wrapper.RestoreZoneArchive)
+	router.GET(baseURL+"/audit-logs", wrapper.GetAuditLogs)
+	router.GET(baseURL+"/audit-logs/exporters", wrapper.GetAuditExporters)
+	router.POST(baseURL+"/audit-logs/exporters", wrapper.CreateAuditExporter)
+	router.DELETE(baseURL+"/audit-logs/exporters/:exporter_id", wrapper.DeleteAuditExporter)
+	router.PUT(baseURL+"/audit-logs/exporters/:exporter_id", wrapper.UpdateAuditExporter)
+	router.GET(baseURL+"/cluster", wrapper.GetClusterInstances)
+	router.POST(baseURL+"/config/reload", wrapper.ReloadConfig)
+	router.GET(baseURL+"/features", wrapper.GetFeatures)
+	router.GET(baseURL+"/fragments", wrapper.GetFragments)
+	router.DELETE(baseURL+"/fragments/:name", wrapper.DeleteFragment)
+	router.PUT(baseURL+"/fragments/:name", wrapper.UpdateFragment)
+	router.GET(baseURL+"/insights", wrapper.GetInsights)
+	router.GET(baseURL+"/inventory", wrapper.GetInventory)
+	router.GET(baseURL+"/locales", wrapper.GetLocales)
+	router.GET(baseURL+"/locales/:language", wrapper.GetLocaleCatalog)
+	router.GET(baseURL+"/prometheus/sd", wrapper.GetScrapeTargets)
+	router.GET(baseURL+"/record-groups", wrapper.GetRecordGroups)
+	router.POST(baseURL+"/record-groups/batch", wrapper.BatchRecordGroups)
 	router.GET(baseURL+"/records/:domain", wrapper.GetRecords)
 	router.POST(baseURL+"/records/:domain", wrapper.CreateRecord)
+	router.POST(baseURL+"/records/:domain/batch", wrapper.BatchRecords)
+	router.POST(baseURL+"/records/:domain/import", wrapper.ImportRecords)
 	router.DELETE(baseURL+"/records/:domain/:record_id", wrapper.DeleteRecord)
 	router.GET(baseURL+"/records/:domain/:record_id", wrapper.GetRecordById)
 	router.PUT(baseURL+"/records/:domain/:record_id", wrapper.UpdateRecord)
+	router.GET(baseURL+"/records/:domain/:record_id/history", wrapper.GetRecordHistory)
+	router.POST(baseURL+"/reverse-zones", wrapper.CreateReverseZone)
+	router.GET(baseURL+"/rpz/allowlist", wrapper.GetRpzAllowlist)
+	router.PUT(baseURL+"/rpz/allowlist", wrapper.UpdateRpzAllowlist)
+	router.GET(baseURL+"/rpz/feeds", wrapper.GetRpzFeeds)
+	router.POST(baseURL+"/rpz/feeds", wrapper.CreateRpzFeed)
+	router.DELETE(baseURL+"/rpz/feeds/:feed_id", wrapper.DeleteRpzFeed)
+	router.PUT(baseURL+"/rpz/feeds/:feed_id", wrapper.UpdateRpzFeed)
+	router.POST(baseURL+"/rpz/feeds/:feed_id/refresh", wrapper.RefreshRpzFeed)
+	router.GET(baseURL+"/rpz/profiles", wrapper.GetRpzProfiles)
+	router.PUT(baseURL+"/rpz/profiles/:name", wrapper.UpdateRpzProfile)
+	router.GET(baseURL+"/server/blackhole", wrapper.GetBlackhole)
+	router.PUT(baseURL+"/server/blackhole", wrapper.UpdateBlackhole)
+	router.GET(baseURL+"/server/blackhole/presets", wrapper.GetBlackholePresets)
+	router.GET(baseURL+"/server/cache/dump", wrapper.DumpCache)
+	router.POST(baseURL+"/server/cache/flush", wrapper.FlushCache)
+	router.GET(baseURL+"/server/consistency", wrapper.GetConsistencyReport)
+	router.GET(baseURL+"/server/default-zones", wrapper.GetDefaultZones)
+	router.PUT(baseURL+"/server/default-zones", wrapper.UpdateDefaultZones)
+	router.GET(baseURL+"/server/failover", wrapper.GetFailoverStatus)
+	router.POST(baseURL+"/server/failover/promote", wrapper.PromoteFailover)
+	router.DELETE(baseURL+"/server/faults", wrapper.ClearFaults)
+	router.GET(baseURL+"/server/faults", wrapper.GetFaults)
+	router.POST(baseURL+"/server/faults", wrapper.InjectFault)
+	router.GET(baseURL+"/server/forwarders", wrapper.GetForwarders)
+	router.PUT(baseURL+"/server/forwarders", wrapper.UpdateForwarders)
+	router.GET(baseURL+"/server/negative-trust-anchors", wrapper.GetNegativeTrustAnchors)
+	router.POST(baseURL+"/server/negative-trust-anchors", wrapper.CreateNegativeTrustAnchor)
+	router.DELETE(baseURL+"/server/negative-trust-anchors/:domain", wrapper.DeleteNegativeTrustAnchor)
+	router.GET(baseURL+"/server/query-log", wrapper.GetQueryLog)
+	router.PUT(baseURL+"/server/query-log", wrapper.UpdateQueryLog)
+	router.POST(baseURL+"/server/recovery-bundle", wrapper.ExportRecoveryBundle)
+	router.GET(baseURL+"/server/recursion", wrapper.GetRecursion)
+	router.PUT(baseURL+"/server/recursion", wrapper.UpdateRecursion)
+	router.GET(baseURL+"/server/root-hints", wrapper.GetRootHints)
+	router.PUT(baseURL+"/server/root-hints", wrapper.UpdateRootHints)
+	router.POST(baseURL+"/server/root-hints/refresh", wrapper.RefreshRootHints)
+	router.GET(baseURL+"/server/serial-consistency", wrapper.GetSerialConsistency)
+	router.GET(baseURL+"/server/validation-exceptions", wrapper.GetValidationExceptions)
+	router.POST(baseURL+"/server/validation-exceptions", wrapper.CreateValidationException)
+	router.DELETE(baseURL+"/server/validation-exceptions/:domain", wrapper.DeleteValidationException)
+	router.GET(baseURL+"/server/views", wrapper.GetViews)
+	router.PUT(baseURL+"/server/views", wrapper.UpdateViews)
+	router.GET(baseURL+"/stats/networks", wrapper.GetNetworkStats)
+	router.GET(baseURL+"/tsig-keys", wrapper.GetTsigKeys)
+	router.DELETE(baseURL+"/tsig-keys/:name", wrapper.DeleteTsigKey)
+	router.PUT(baseURL+"/tsig-keys/:name", wrapper.UpdateTsigKey)
+	router.GET(baseURL+"/webhooks", wrapper.GetWebhooks)
+	router.POST(baseURL+"/webhooks", wrapper.CreateWebhook)
+	router.DELETE(baseURL+"/webhooks/:webhook_id", wrapper.DeleteWebhook)
+	router.PUT(baseURL+"/webhooks/:webhook_id", wrapper.UpdateWebhook)
 	router.GET(baseURL+"/zones", wrapper.GetZones)
 	router.POST(baseURL+"/zones", wrapper.CreateZone)
+	router.POST(baseURL+"/zones/import", wrapper.ImportZone)
+	router.GET(baseURL+"/zones/soa-presets", wrapper.GetSoaPresets)
+	router.POST(baseURL+"/zones/transfer", wrapper.TransferZone)
 	router.DELETE(baseURL+"/zones/:domain", wrapper.DeleteZone)
 	router.GET(baseURL+"/zones/:domain", wrapper.GetZoneByDomain)
 	router.PUT(baseURL+"/zones/:domain", wrapper.UpdateZone)
+	router.POST(baseURL+"/zones/:domain/archive", wrapper.ArchiveZone)
+	router.POST(baseURL+"/zones/:domain/clone", wrapper.CloneZone)
+	router.GET(baseURL+"/zones/:domain/delegations", wrapper.GetDelegations)
+	router.DELETE(baseURL+"/zones/:domain/delegations/:name", wrapper.DeleteDelegation)
+	router.PUT(baseURL+"/zones/:domain/delegations/:name", wrapper.SetDelegation)
+	router.GET(baseURL+"/zones/:domain/dnssec", wrapper.GetZoneDnssec)
+	router.GET(baseURL+"/zones/:domain/ds", wrapper.GetZoneDs)
+	router.GET(baseURL+"/zones/:domain/export", wrapper.ExportZone)
+	router.POST(baseURL+"/zones/:domain/freeze", wrapper.FreezeZone)
+	router.GET(baseURL+"/zones/:domain/generators", wrapper.GetRecordGenerators)
+	router.POST(baseURL+"/zones/:domain/generators", wrapper.CreateRecordGenerator)
+	router.DELETE(baseURL+"/zones/:domain/generators/:generator_id", wrapper.DeleteRecordGenerator)
+	router.PUT(baseURL+"/zones/:domain/generators/:generator_id", wrapper.UpdateRecordGenerator)
+	router.POST(baseURL+"/zones/:domain/registrar/publish", wrapper.PublishZoneDelegation)
+	router.GET(baseURL+"/zones/:domain/registration", wrapper.GetZoneRegistration)
+	router.GET(baseURL+"/zones/:domain/report", wrapper.GetZoneReport)
+	router.GET(baseURL+"/zones/:domain/stats", wrapper.GetZoneStats)
+	router.POST(baseURL+"/zones/:domain/thaw", wrapper.ThawZone)
+	router.GET(baseURL+"/zones/:domain/unused", wrapper.GetUnusedRecords)
 
 }
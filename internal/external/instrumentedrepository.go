@@ -0,0 +1,75 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"time"
+)
+
+type instrumentedZoneRepository struct {
+	repo    domain.ZoneRepository
+	metrics domain.Metrics
+}
+
+// NewInstrumentedZoneRepository decorates repo, recording the latency and outcome of every call into metrics.
+func NewInstrumentedZoneRepository(repo domain.ZoneRepository, metrics domain.Metrics) domain.ZoneRepository {
+	return &instrumentedZoneRepository{repo: repo, metrics: metrics}
+}
+
+func (i *instrumentedZoneRepository) GetAllZones(ctx context.Context) (zones []*domain.Zone, err error) {
+	defer i.observe(domain.OperationGet, time.Now(), &err)
+	return i.repo.GetAllZones(ctx)
+}
+
+func (i *instrumentedZoneRepository) FindZones(
+	ctx context.Context, filter domain.ZoneFilter, page domain.Page,
+) (zones []*domain.Zone, total int, err error) {
+	defer i.observe(domain.OperationGet, time.Now(), &err)
+	return i.repo.FindZones(ctx, filter, page)
+}
+
+func (i *instrumentedZoneRepository) GetZoneById(ctx context.Context, zoneId string) (zone *domain.Zone, err error) {
+	defer i.observe(domain.OperationGet, time.Now(), &err)
+	return i.repo.GetZoneById(ctx, zoneId)
+}
+
+func (i *instrumentedZoneRepository) GetZoneByDomain(
+	ctx context.Context, domainName string,
+) (zone *domain.Zone, err error) {
+	defer i.observe(domain.OperationGet, time.Now(), &err)
+	return i.repo.GetZoneByDomain(ctx, domainName)
+}
+
+func (i *instrumentedZoneRepository) Persist(ctx context.Context, zone *domain.Zone) (err error) {
+	defer i.observe(domain.OperationPersist, time.Now(), &err)
+	return i.repo.Persist(ctx, zone)
+}
+
+func (i *instrumentedZoneRepository) PersistAll(ctx context.Context, zones []*domain.Zone) (err error) {
+	defer i.observe(domain.OperationPersist, time.Now(), &err)
+	return i.repo.PersistAll(ctx, zones)
+}
+
+func (i *instrumentedZoneRepository) PersistSerial(ctx context.Context, zone *domain.Zone) (err error) {
+	defer i.observe(domain.OperationPersist, time.Now(), &err)
+	return i.repo.PersistSerial(ctx, zone)
+}
+
+func (i *instrumentedZoneRepository) PersistFilePath(ctx context.Context, zone *domain.Zone) (err error) {
+	defer i.observe(domain.OperationPersist, time.Now(), &err)
+	return i.repo.PersistFilePath(ctx, zone)
+}
+
+func (i *instrumentedZoneRepository) MarkApplied(ctx context.Context, zone *domain.Zone) (err error) {
+	defer i.observe(domain.OperationPersist, time.Now(), &err)
+	return i.repo.MarkApplied(ctx, zone)
+}
+
+func (i *instrumentedZoneRepository) Delete(ctx context.Context, zone *domain.Zone) (err error) {
+	defer i.observe(domain.OperationDelete, time.Now(), &err)
+	return i.repo.Delete(ctx, zone)
+}
+
+func (i *instrumentedZoneRepository) observe(operation string, start time.Time, err *error) {
+	i.metrics.ObserveRepositoryOperation("zone", operation, time.Since(start), *err)
+}
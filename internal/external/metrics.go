@@ -0,0 +1,262 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const metricsNamespace = "dns_server_manager"
+
+var repositoryLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+type operationKey struct {
+	repository string
+	operation  string
+}
+
+type operationStats struct {
+	successes int64
+	errors    int64
+	// buckets holds the cumulative count of observations per latency bucket.
+	buckets []int64
+	sum     float64
+}
+
+type prometheusMetrics struct {
+	config domain.Config
+	db     *sql.DB
+	// forwarderStatuses returns the latest probe result of every forwarder, see domain.ForwarderMonitor.
+	forwarderStatuses func() []*domain.ForwarderStatus
+
+	lock       sync.Mutex
+	operations map[operationKey]*operationStats
+}
+
+// NewPrometheusMetrics creates the metrics registry, sqlite statistics are collected from db and the forwarder
+// statuses from forwarderStatuses when rendered.
+func NewPrometheusMetrics(
+	config domain.Config, db *sql.DB, forwarderStatuses func() []*domain.ForwarderStatus,
+) domain.Metrics {
+	return &prometheusMetrics{
+		config:            config,
+		db:                db,
+		forwarderStatuses: forwarderStatuses,
+		operations:        map[operationKey]*operationStats{},
+	}
+}
+
+func (m *prometheusMetrics) ObserveRepositoryOperation(
+	repository, operation string, duration time.Duration, err error,
+) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := operationKey{repository: repository, operation: operation}
+	stats, ok := m.operations[key]
+	if !ok {
+		stats = &operationStats{buckets: make([]int64, len(repositoryLatencyBuckets))}
+		m.operations[key] = stats
+	}
+
+	if err != nil {
+		stats.errors++
+	} else {
+		stats.successes++
+	}
+	seconds := duration.Seconds()
+	stats.sum += seconds
+	for i, bucket := range repositoryLatencyBuckets {
+		if seconds <= bucket {
+			stats.buckets[i]++
+		}
+	}
+}
+
+func (m *prometheusMetrics) Write(ctx context.Context, w io.Writer) error {
+	var out strings.Builder
+	m.writeRepositoryMetrics(&out)
+	err := m.writeSqliteMetrics(ctx, &out)
+	if err != nil {
+		return err
+	}
+	err = m.writeZoneMetrics(ctx, &out)
+	if err != nil {
+		return err
+	}
+	m.writeForwarderMetrics(&out)
+	_, err = io.WriteString(w, out.String())
+	return err
+}
+
+func (m *prometheusMetrics) writeRepositoryMetrics(out *strings.Builder) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var keys []operationKey
+	for key := range m.operations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].repository != keys[j].repository {
+			return keys[i].repository < keys[j].repository
+		}
+		return keys[i].operation < keys[j].operation
+	})
+
+	name := metricsNamespace + "_repository_operations_total"
+	writeMetricHeader(out, name, "counter", "Repository operations by result.")
+	for _, key := range keys {
+		stats := m.operations[key]
+		labels := fmt.Sprintf(`repository="%v",operation="%v"`, key.repository, key.operation)
+		fmt.Fprintf(out, "%v{%v,result=\"success\"} %d\n", name, labels, stats.successes)
+		fmt.Fprintf(out, "%v{%v,result=\"error\"} %d\n", name, labels, stats.errors)
+	}
+
+	name = metricsNamespace + "_repository_operation_duration_seconds"
+	writeMetricHeader(out, name, "histogram", "Latency of repository operations.")
+	for _, key := range keys {
+		stats := m.operations[key]
+		labels := fmt.Sprintf(`repository="%v",operation="%v"`, key.repository, key.operation)
+		for i, bucket := range repositoryLatencyBuckets {
+			fmt.Fprintf(out, "%v_bucket{%v,le=\"%v\"} %d\n", name, labels, bucket, stats.buckets[i])
+		}
+		count := stats.successes + stats.errors
+		fmt.Fprintf(out, "%v_bucket{%v,le=\"+Inf\"} %d\n", name, labels, count)
+		fmt.Fprintf(out, "%v_sum{%v} %v\n", name, labels, stats.sum)
+		fmt.Fprintf(out, "%v_count{%v} %d\n", name, labels, count)
+	}
+}
+
+func (m *prometheusMetrics) writeSqliteMetrics(ctx context.Context, out *strings.Builder) error {
+	var fileSize int64
+	// The write-ahead log and the rollback journal hold data not yet checkpointed into the database file.
+	for _, suffix := range []string{"", "-wal", "-journal"} {
+		info, err := os.Stat(m.config.DBPath() + suffix)
+		if err == nil {
+			fileSize += info.Size()
+		}
+	}
+	writeGauge(out, metricsNamespace+"_sqlite_file_size_bytes", "Size of the sqlite database files.", fileSize)
+
+	pragmas := []struct {
+		pragma string
+		name   string
+		help   string
+	}{
+		{"page_count", "_sqlite_pages", "Number of pages in the database."},
+		{"page_size", "_sqlite_page_size_bytes", "Size of a database page."},
+		{"freelist_count", "_sqlite_freelist_pages", "Number of unused pages in the database."},
+		{"cache_size", "_sqlite_cache_size", "Suggested page cache size, in pages or in KiB when negative."},
+	}
+	for _, pragma := range pragmas {
+		var value int64
+		err := m.db.QueryRowContext(ctx, fmt.Sprintf("PRAGMA %v;", pragma.pragma)).Scan(&value)
+		if err != nil {
+			return err
+		}
+		writeGauge(out, metricsNamespace+pragma.name, pragma.help, value)
+	}
+
+	stats := m.db.Stats()
+	writeGauge(out, metricsNamespace+"_sqlite_open_connections", "Open database connections.",
+		int64(stats.OpenConnections))
+	writeGauge(out, metricsNamespace+"_sqlite_in_use_connections", "Database connections in use.",
+		int64(stats.InUse))
+	writeGauge(out, metricsNamespace+"_sqlite_idle_connections", "Idle database connections.", int64(stats.Idle))
+	name := metricsNamespace + "_sqlite_wait_total"
+	writeMetricHeader(out, name, "counter", "Database connections waited for.")
+	fmt.Fprintf(out, "%v %d\n", name, stats.WaitCount)
+	return nil
+}
+
+// writeZoneMetrics writes the size of every zone, see domain.ZoneSize, to plan splitting the largest ones.
+func (m *prometheusMetrics) writeZoneMetrics(ctx context.Context, out *strings.Builder) error {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT zones.domain, zones.zone_group, zones.file_path, COUNT(records.id) FROM zones
+		LEFT JOIN records ON records.zone_id = zones.id
+		GROUP BY zones.id ORDER BY zones.domain;
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var domains []string
+	var sizes []*domain.ZoneSize
+	for rows.Next() {
+		var domainName, group, storedPath string
+		size := &domain.ZoneSize{}
+		err = rows.Scan(&domainName, &group, &storedPath, &size.Records)
+		if err != nil {
+			return err
+		}
+		// The zone file is where the repository places it, the stored path possibly being out of date.
+		if info, err := os.Stat(zoneFilePath(m.config, domainName, group, storedPath)); err == nil {
+			size.FileSize = info.Size()
+		}
+		domains = append(domains, domainName)
+		sizes = append(sizes, size)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	name := metricsNamespace + "_zone_records"
+	writeMetricHeader(out, name, "gauge", "Records of the zone, those of its fragments left out.")
+	for i, domainName := range domains {
+		fmt.Fprintf(out, "%v{zone=\"%v\"} %d\n", name, domainName, sizes[i].Records)
+	}
+	name = metricsNamespace + "_zone_file_size_bytes"
+	writeMetricHeader(out, name, "gauge", "Size of the zone file named serves, 0 while it is not written.")
+	for i, domainName := range domains {
+		fmt.Fprintf(out, "%v{zone=\"%v\"} %d\n", name, domainName, sizes[i].FileSize)
+	}
+	return nil
+}
+
+// writeForwarderMetrics writes the outcome of the last probe of every forwarder, see domain.ForwarderMonitor.
+func (m *prometheusMetrics) writeForwarderMetrics(out *strings.Builder) {
+	statuses := m.forwarderStatuses()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Address < statuses[j].Address
+	})
+
+	name := metricsNamespace + "_forwarder_up"
+	writeMetricHeader(out, name, "gauge", "Whether the forwarder is healthy, named only forwarding to the healthy ones.")
+	for _, status := range statuses {
+		up := 0
+		if status.Healthy {
+			up = 1
+		}
+		fmt.Fprintf(out, "%v{forwarder=\"%v\"} %d\n", name, status.Address, up)
+	}
+	name = metricsNamespace + "_forwarder_rtt_seconds"
+	writeMetricHeader(out, name, "gauge", "Round trip time of the last probe, left out when it failed.")
+	for _, status := range statuses {
+		if status.LastError == "" {
+			fmt.Fprintf(out, "%v{forwarder=\"%v\"} %v\n", name, status.Address, status.Latency.Seconds())
+		}
+	}
+	name = metricsNamespace + "_forwarder_consecutive_failures"
+	writeMetricHeader(out, name, "gauge", "Probes the forwarder failed in a row.")
+	for _, status := range statuses {
+		fmt.Fprintf(out, "%v{forwarder=\"%v\"} %d\n", name, status.Address, status.ConsecutiveFailures)
+	}
+}
+
+func writeMetricHeader(out *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(out, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, metricType)
+}
+
+func writeGauge(out *strings.Builder, name, help string, value int64) {
+	writeMetricHeader(out, name, "gauge", help)
+	fmt.Fprintf(out, "%v %d\n", name, value)
+}
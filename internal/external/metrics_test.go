@@ -0,0 +1,72 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteForwarderMetrics(t *testing.T) {
+	metrics := NewPrometheusMetrics(nil, nil, func() []*domain.ForwarderStatus {
+		return []*domain.ForwarderStatus{
+			{Address: "9.9.9.9", Healthy: true, LastError: "i/o timeout", ConsecutiveFailures: 1},
+			{Address: "1.1.1.1", Healthy: true, Latency: 12 * time.Millisecond},
+			{Address: "8.8.8.8", LastError: "i/o timeout", ConsecutiveFailures: 3},
+		}
+	}).(*prometheusMetrics)
+
+	var out strings.Builder
+	metrics.writeForwarderMetrics(&out)
+
+	for _, want := range []string{
+		"dns_server_manager_forwarder_up{forwarder=\"1.1.1.1\"} 1\n" +
+			"dns_server_manager_forwarder_up{forwarder=\"8.8.8.8\"} 0\n" +
+			"dns_server_manager_forwarder_up{forwarder=\"9.9.9.9\"} 1\n",
+		"# TYPE dns_server_manager_forwarder_rtt_seconds gauge\n" +
+			"dns_server_manager_forwarder_rtt_seconds{forwarder=\"1.1.1.1\"} 0.012\n# HELP",
+		"dns_server_manager_forwarder_consecutive_failures{forwarder=\"8.8.8.8\"} 3\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("got\n%v\nwant it to hold\n%v", out.String(), want)
+		}
+	}
+}
+
+func TestWriteZoneMetricsSizesTheServedZoneFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+	db := newTestAuditDb(t)
+	zoneRepo := NewSqliteZoneRepository(config, db, db)
+	zone := domain.NewZone("example.com")
+	err := zone.RegisterSOA(domain.NewDefaultSOARecord("ns1.example.com.", "admin.example.com."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = zoneRepo.Persist(ctx, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The stored path is the one of the bind folder the zone was created in, which moved since.
+	zone.FilePath = filepath.Join(dir, "old-bind", filepath.Base(zone.FilePath))
+	err = zoneRepo.PersistFilePath(ctx, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writeFile(filepath.Join(config.BindFolderPath(), filepath.Base(zone.FilePath)), "12345")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	err = NewPrometheusMetrics(config, db, nil).(*prometheusMetrics).writeZoneMetrics(ctx, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "dns_server_manager_zone_file_size_bytes{zone=\"example.com\"} 5\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("got\n%v\nwant it to hold\n%v", out.String(), want)
+	}
+}
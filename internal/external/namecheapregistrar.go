@@ -0,0 +1,82 @@
+package external
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const namecheapEndpoint = "https://api.namecheap.com/xml.response"
+
+// namecheapRegistrar uses the Namecheap XML API, which requires the address of the service to be whitelisted.
+type namecheapRegistrar struct {
+	account *domain.RegistrarAccount
+	client  *http.Client
+}
+
+type namecheapResponse struct {
+	Status string `xml:"Status,attr"`
+	Errors []struct {
+		Number  string `xml:"Number,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"Errors>Error"`
+}
+
+func (n *namecheapRegistrar) SetNameServers(ctx context.Context, domainName string, nameServers []string) error {
+	labels := strings.SplitN(domain.NormalizeDomain(domainName), ".", 2)
+	if len(labels) != 2 {
+		return fmt.Errorf("%v is not a registrable domain", domainName)
+	}
+
+	query := url.Values{}
+	query.Set("ApiUser", n.account.Username)
+	query.Set("ApiKey", n.account.ApiKey)
+	query.Set("UserName", n.account.Username)
+	query.Set("ClientIp", n.account.ClientIP)
+	query.Set("Command", "namecheap.domains.dns.setCustom")
+	query.Set("SLD", labels[0])
+	query.Set("TLD", labels[1])
+	query.Set("Nameservers", strings.Join(nameServers, ","))
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, registrarEndpoint(n.account, namecheapEndpoint), strings.NewReader(query.Encode()),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpRes, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode >= 300 {
+		return fmt.Errorf("namecheap responded with status %v", httpRes.Status)
+	}
+	res := &namecheapResponse{}
+	err = xml.NewDecoder(httpRes.Body).Decode(res)
+	if err != nil {
+		return err
+	}
+	if res.Status != "OK" {
+		var messages []string
+		for _, resErr := range res.Errors {
+			messages = append(messages, fmt.Sprintf("%v (%v)", strings.TrimSpace(resErr.Message), resErr.Number))
+		}
+		return fmt.Errorf("namecheap refused the name servers: %v", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// SetDelegationSigners is not offered by the Namecheap API, DS records are managed from its dashboard.
+func (n *namecheapRegistrar) SetDelegationSigners(
+	ctx context.Context, domainName string, signers []*domain.DelegationSigner,
+) error {
+	return domain.ErrorRegistrarUnsupported
+}
@@ -0,0 +1,72 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+type notifyScheduler struct {
+	zoneRepo domain.ZoneRepository
+	interval time.Duration
+	onDue    func(ctx context.Context) error
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewNotifyScheduler creates a scheduler looking every interval for the zones whose notify window is over while
+// their changes are still pending. onDue is called to publish them.
+func NewNotifyScheduler(
+	zoneRepo domain.ZoneRepository, interval time.Duration, onDue func(ctx context.Context) error,
+) domain.NotifyScheduler {
+	return &notifyScheduler{
+		zoneRepo:       zoneRepo,
+		interval:       interval,
+		onDue:          onDue,
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (n *notifyScheduler) Start(ctx context.Context) {
+	n.stoppedWg.Add(1)
+	go func() {
+		defer n.stoppedWg.Done()
+
+		ticker := time.NewTicker(n.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-n.shutdownSignal:
+				return
+			case <-ticker.C:
+				err := n.publishDue(ctx)
+				if err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+}
+
+func (n *notifyScheduler) Shutdown(ctx context.Context) error {
+	n.shutdownSignal <- 1
+	n.stoppedWg.Wait()
+	return nil
+}
+
+func (n *notifyScheduler) publishDue(ctx context.Context) error {
+	zones, err := n.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, zone := range zones {
+		if zone.PublishDue(now) {
+			return n.onDue(ctx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const openSRSEndpoint = "https://rr-n1-tor.opensrs.net:55443"
+
+var (
+	openSRSSuccessPattern  = regexp.MustCompile(`<item key="is_success">\s*1\s*</item>`)
+	openSRSResponsePattern = regexp.MustCompile(`<item key="response_text">([^<]*)</item>`)
+)
+
+// openSRSRegistrar uses the OpenSRS XCP API with reseller credentials, requests being signed with the private key.
+type openSRSRegistrar struct {
+	account *domain.RegistrarAccount
+	client  *http.Client
+}
+
+func (o *openSRSRegistrar) SetNameServers(ctx context.Context, domainName string, nameServers []string) error {
+	var assigned strings.Builder
+	for i, nameServer := range nameServers {
+		assigned.WriteString(fmt.Sprintf(`<item key="%v">%v</item>`, i, xmlEscape(nameServer)))
+	}
+	return o.request(ctx, "ADVANCED_UPDATE_NAMESERVERS", fmt.Sprintf(
+		`<item key="domain">%v</item><item key="op_type">assign</item>`+
+			`<item key="assign_ns"><dt_array>%v</dt_array></item>`,
+		xmlEscape(domain.NormalizeDomain(domainName)), assigned.String(),
+	))
+}
+
+// SetDelegationSigners is not supported through the XCP API, DS records are managed from the OpenSRS control panel.
+func (o *openSRSRegistrar) SetDelegationSigners(
+	ctx context.Context, domainName string, signers []*domain.DelegationSigner,
+) error {
+	return domain.ErrorRegistrarUnsupported
+}
+
+func (o *openSRSRegistrar) request(ctx context.Context, action, attributes string) error {
+	body := `<?xml version='1.0' encoding='UTF-8' standalone='no' ?>` +
+		`<!DOCTYPE OPS_envelope SYSTEM 'ops.dtd'><OPS_envelope><header><version>0.9</version></header>` +
+		`<body><data_block><dt_assoc><item key="protocol">XCP</item><item key="object">DOMAIN</item>` +
+		`<item key="action">` + action + `</item><item key="attributes"><dt_assoc>` + attributes +
+		`</dt_assoc></item></dt_assoc></data_block></body></OPS_envelope>`
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, registrarEndpoint(o.account, openSRSEndpoint), strings.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	req.Header.Set("X-Username", o.account.Username)
+	req.Header.Set("X-Signature", openSRSSignature(body, o.account.ApiKey))
+
+	httpRes, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	res, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return err
+	}
+	if httpRes.StatusCode >= 300 || !openSRSSuccessPattern.Match(res) {
+		message := httpRes.Status
+		if match := openSRSResponsePattern.FindSubmatch(res); match != nil {
+			message = string(match[1])
+		}
+		return fmt.Errorf("opensrs refused %v: %v", strings.ToLower(action), message)
+	}
+	return nil
+}
+
+// openSRSSignature is md5(md5(body + key) + key) in hexadecimal.
+func openSRSSignature(body, key string) string {
+	inner := md5.Sum([]byte(body + key))
+	outer := md5.Sum([]byte(hex.EncodeToString(inner[:]) + key))
+	return hex.EncodeToString(outer[:])
+}
+
+func xmlEscape(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
@@ -0,0 +1,115 @@
+package external
+
+import "strings"
+
+// renderedOptions are the options named.conf renders in its options block, named refusing an option set twice.
+var renderedOptions = map[string]bool{
+	"directory":         true,
+	"dnssec-validation": true,
+	"listen-on-v6":      true,
+	"recursion":         true,
+	"allow-recursion":   true,
+	"forward":           true,
+	"forwarders":        true,
+	"blackhole":         true,
+	"querylog":          true,
+	"response-policy":   true,
+	"validate-except":   true,
+}
+
+// migrateOptionsConf returns named.conf.options as it is included within the options block of named.conf: the
+// statements of its options block, those named.conf renders and those out of the options block being commented out,
+// along with the number of commented out statements. It returns false when the file holds no options block, e.g. when
+// it is migrated already.
+func migrateOptionsConf(contents string) (string, int, bool) {
+	statements := splitConfStatements(contents)
+	var options string
+	for _, statement := range statements {
+		if confStatementName(statement) == "options" {
+			options = statement
+		}
+	}
+	start, end := strings.Index(options, "{"), strings.LastIndex(options, "}")
+	if start < 0 || end < start {
+		return "", 0, false
+	}
+
+	migrated := "// Included within the options block of named.conf, the commented out statements are rendered by named.conf\n" +
+		"// or are not options.\n"
+	commented := 0
+	comment := func(statement string) {
+		migrated += "// " + strings.Join(strings.Fields(statement), " ") + ";\n"
+		commented++
+	}
+	for _, statement := range statements {
+		if statement != options {
+			comment(statement)
+		}
+	}
+	for _, option := range splitConfStatements(options[start+1 : end]) {
+		if renderedOptions[confStatementName(option)] {
+			comment(option)
+			continue
+		}
+		migrated += option + ";\n"
+	}
+	return migrated, commented, true
+}
+
+// splitConfStatements returns the statements of a named config at its top level, without their trailing semicolon
+// and without comments.
+func splitConfStatements(conf string) []string {
+	var statements []string
+	var statement strings.Builder
+	depth := 0
+	for i := 0; i < len(conf); i++ {
+		switch {
+		case conf[i] == '"':
+			end := strings.IndexByte(conf[i+1:], '"')
+			if end < 0 {
+				end = len(conf) - i - 2
+			}
+			statement.WriteString(conf[i : i+end+2])
+			i += end + 1
+			continue
+		case conf[i] == '#' || strings.HasPrefix(conf[i:], "//"):
+			end := strings.IndexByte(conf[i:], '\n')
+			if end < 0 {
+				end = len(conf) - i
+			}
+			i += end - 1
+			continue
+		case strings.HasPrefix(conf[i:], "/*"):
+			end := strings.Index(conf[i:], "*/")
+			if end < 0 {
+				end = len(conf) - i - 2
+			}
+			i += end + 1
+			continue
+		case conf[i] == '{':
+			depth++
+		case conf[i] == '}':
+			depth--
+		case conf[i] == ';' && depth == 0:
+			if trimmed := strings.TrimSpace(statement.String()); trimmed != "" {
+				statements = append(statements, trimmed)
+			}
+			statement.Reset()
+			continue
+		}
+		statement.WriteByte(conf[i])
+	}
+	if trimmed := strings.TrimSpace(statement.String()); trimmed != "" {
+		statements = append(statements, trimmed)
+	}
+	return statements
+}
+
+// confStatementName returns the keyword a statement starts with, e.g. options for options { ... }.
+func confStatementName(statement string) string {
+	fields := strings.Fields(strings.Replace(statement, "{", " {", 1))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,56 @@
+package external
+
+import "testing"
+
+func TestMigrateOptionsConf(t *testing.T) {
+	// The named.conf.options of the image, along with options set by hand.
+	contents := `options {
+	directory "/var/cache/bind";
+
+	// If there is a firewall between you and nameservers you want
+	// to talk to, you may need to fix the firewall to allow multiple
+	// ports to talk.  See http://www.kb.cert.org/vuls/id/800113; {
+
+	forwarders {
+		192.0.2.1; # the resolver of the office
+	};
+
+	/* listen-on { none; }; */
+	listen-on { 127.0.0.1; 192.0.2.53; };
+	version "not disclosed";
+	dnssec-validation auto;
+
+	listen-on-v6 { any; };
+};
+logging { category lame-servers { null; }; };
+`
+	want := `// Included within the options block of named.conf, the commented out statements are rendered by named.conf
+// or are not options.
+// logging { category lame-servers { null; }; };
+// directory "/var/cache/bind";
+// forwarders { 192.0.2.1; };
+listen-on { 127.0.0.1; 192.0.2.53; };
+version "not disclosed";
+// dnssec-validation auto;
+// listen-on-v6 { any; };
+`
+	migrated, commented, ok := migrateOptionsConf(contents)
+	if !ok {
+		t.Fatal("got no options block, want one")
+	}
+	if migrated != want {
+		t.Errorf("got\n%v\nwant\n%v", migrated, want)
+	}
+	if commented != 5 {
+		t.Errorf("got %v commented out statements, want 5", commented)
+	}
+
+	_, _, ok = migrateOptionsConf(migrated)
+	if ok {
+		t.Error("got an options block in the migrated file, want none")
+	}
+	_, _, ok = migrateOptionsConf("")
+	if ok {
+		t.Error("got an options block in an empty file, want none")
+	}
+}
@@ -0,0 +1,265 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	queryStatsRefreshInterval = time.Minute
+	// missingNameEvictionRatio is the share of the missing names forgotten at once once they reached
+	// domain.MaxMissingNames, one tenth of them.
+	missingNameEvictionRatio = 10
+)
+
+type queryStatistics struct {
+	zoneRepo   domain.ZoneRepository
+	serverRepo domain.ServerRepository
+	usageRepo  domain.RecordUsageRepository
+
+	lock     sync.RWMutex
+	since    time.Time
+	networks map[string]*domain.NetworkQueryStats
+	// zones holds the managed zones by normalized domain.
+	zones             map[string]*domain.Zone
+	clientSubnetStats bool
+	// lastQueries holds when the records last answered a query by record id, until stored on the next refresh.
+	lastQueries map[string]time.Time
+	// missingNames holds the names answered NXDOMAIN by zone domain and name.
+	missingNames map[string]*domain.MissingName
+
+	refreshSignal  chan int
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+func NewQueryStatistics(
+	zoneRepo domain.ZoneRepository, serverRepo domain.ServerRepository, usageRepo domain.RecordUsageRepository,
+) domain.QueryStatistics {
+	return &queryStatistics{
+		zoneRepo:       zoneRepo,
+		serverRepo:     serverRepo,
+		usageRepo:      usageRepo,
+		since:          time.Now(),
+		networks:       map[string]*domain.NetworkQueryStats{},
+		zones:          map[string]*domain.Zone{},
+		lastQueries:    map[string]time.Time{},
+		missingNames:   map[string]*domain.MissingName{},
+		refreshSignal:  make(chan int, 1),
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+// Start refreshes the statistics every refresh interval and whenever a change event is relayed, out of the query log
+// reader for it not to wait on the database.
+func (q *queryStatistics) Start(ctx context.Context) {
+	q.stoppedWg.Add(1)
+	go func() {
+		defer q.stoppedWg.Done()
+
+		q.refresh(ctx)
+		ticker := time.NewTicker(queryStatsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-q.shutdownSignal:
+				q.refresh(ctx)
+				return
+			case <-ticker.C:
+				q.refresh(ctx)
+			case <-q.refreshSignal:
+				q.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (q *queryStatistics) Shutdown(ctx context.Context) error {
+	q.shutdownSignal <- 1
+	q.stoppedWg.Wait()
+	return nil
+}
+
+// OnChangeEvent schedules a refresh, the changed zone answering the queries differently.
+func (q *queryStatistics) OnChangeEvent(ctx context.Context, event *domain.ChangeEvent) {
+	if event.IsAlert() {
+		return
+	}
+	select {
+	case q.refreshSignal <- 1:
+	default:
+	}
+}
+
+func (q *queryStatistics) OnQuery(entry *domain.QueryLogEntry) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	network := domain.ClientNetwork(entry.ClientIP)
+	fromClientSubnet := false
+	if q.clientSubnetStats && entry.ClientSubnet != "" {
+		network = entry.ClientSubnet
+		fromClientSubnet = true
+	}
+
+	stats, ok := q.networks[network]
+	if !ok && len(q.networks) < domain.MaxQueryStatsNetworks {
+		stats = &domain.NetworkQueryStats{
+			Network:          network,
+			FromClientSubnet: fromClientSubnet,
+			Zones:            map[string]int{},
+		}
+		q.networks[network] = stats
+	}
+	zone := q.zoneOfName(entry.Name)
+	if stats != nil {
+		stats.Queries++
+		if zone != nil {
+			stats.Zones[domain.NormalizeDomain(zone.Domain)]++
+		}
+	}
+	if zone == nil {
+		return
+	}
+	for _, record := range zone.RecordsAnswering(entry.Name, entry.Type) {
+		q.lastQueries[record.Id] = entry.Time
+	}
+	if zone.IsMissingName(entry.Name) {
+		q.addMissingName(zone, entry)
+	}
+}
+
+// zoneOfName returns the managed zone name belongs to, the closest one when zones are nested, nil when none does.
+func (q *queryStatistics) zoneOfName(name string) *domain.Zone {
+	name = domain.NormalizeDomain(name)
+	for {
+		if zone, ok := q.zones[name]; ok {
+			return zone
+		}
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			return nil
+		}
+		name = name[dot+1:]
+	}
+}
+
+func (q *queryStatistics) addMissingName(zone *domain.Zone, entry *domain.QueryLogEntry) {
+	zoneDomain, name := domain.NormalizeDomain(zone.Domain), domain.NormalizeDomain(entry.Name)
+	name = strings.TrimSuffix(name, "."+zoneDomain)
+	key := zoneDomain + " " + name
+	missing, ok := q.missingNames[key]
+	if !ok {
+		if len(q.missingNames) >= domain.MaxMissingNames {
+			q.evictMissingNames()
+		}
+		missing = &domain.MissingName{Zone: zoneDomain, Name: name, Types: map[string]int{}}
+		q.missingNames[key] = missing
+	}
+	missing.Queries++
+	missing.Types[strings.ToUpper(entry.Type)]++
+	missing.LastQueriedAt = entry.Time
+}
+
+// evictMissingNames forgets the missing names queried the least, the least recently queried first, making room for
+// the new ones by batches for a flood of queries not to sort them on every query.
+func (q *queryStatistics) evictMissingNames() {
+	missingNames := make([]*domain.MissingName, 0, len(q.missingNames))
+	for _, missing := range q.missingNames {
+		missingNames = append(missingNames, missing)
+	}
+	sort.Slice(missingNames, func(i, j int) bool {
+		if missingNames[i].Queries != missingNames[j].Queries {
+			return missingNames[i].Queries < missingNames[j].Queries
+		}
+		return missingNames[i].LastQueriedAt.Before(missingNames[j].LastQueriedAt)
+	})
+	for _, missing := range missingNames[:len(missingNames)/missingNameEvictionRatio+1] {
+		delete(q.missingNames, missing.Zone+" "+missing.Name)
+	}
+}
+
+func (q *queryStatistics) Since() time.Time {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+	return q.since
+}
+
+func (q *queryStatistics) Networks() []*domain.NetworkQueryStats {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	var networks []*domain.NetworkQueryStats
+	for _, stats := range q.networks {
+		zones := map[string]int{}
+		for zoneDomain, count := range stats.Zones {
+			zones[zoneDomain] = count
+		}
+		statsCopy := *stats
+		statsCopy.Zones = zones
+		networks = append(networks, &statsCopy)
+	}
+	return networks
+}
+
+func (q *queryStatistics) MissingNames() []*domain.MissingName {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	var missingNames []*domain.MissingName
+	for _, missing := range q.missingNames {
+		types := map[string]int{}
+		for queryType, count := range missing.Types {
+			types[queryType] = count
+		}
+		missingCopy := *missing
+		missingCopy.Types = types
+		missingNames = append(missingNames, &missingCopy)
+	}
+	return missingNames
+}
+
+// refresh reloads the managed zones and the statistics options, stores the last queries of the records and forgets
+// the names which are no longer missing.
+func (q *queryStatistics) refresh(ctx context.Context) {
+	zones, err := q.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	options, err := q.serverRepo.GetOptions(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	zonesByDomain := map[string]*domain.Zone{}
+	for _, zone := range zones {
+		zonesByDomain[domain.NormalizeDomain(zone.Domain)] = zone
+	}
+
+	q.lock.Lock()
+	q.zones = zonesByDomain
+	q.clientSubnetStats = options.ClientSubnetStats
+	// The names created since they were queried, or whose zone is gone, make room for others.
+	for key, missing := range q.missingNames {
+		if zone, ok := zonesByDomain[missing.Zone]; !ok || !zone.IsMissingName(missing.Name+"."+missing.Zone) {
+			delete(q.missingNames, key)
+		}
+	}
+	lastQueries := q.lastQueries
+	q.lastQueries = map[string]time.Time{}
+	q.lock.Unlock()
+
+	if len(lastQueries) > 0 {
+		err = q.usageRepo.PersistLastQueries(ctx, lastQueries)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package external
+
+import (
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestQueryStatisticsOnQuery(t *testing.T) {
+	stats := NewQueryStatistics(nil, nil, nil).(*queryStatistics)
+	stats.zones = map[string]*domain.Zone{
+		"example.com":     {Domain: "example.com"},
+		"dev.example.com": {Domain: "dev.example.com"},
+	}
+
+	for _, name := range []string{"www.example.com.", "api.dev.example.com", "DEV.example.com", "example.org"} {
+		stats.OnQuery(&domain.QueryLogEntry{Time: time.Now(), ClientIP: "192.0.2.10", Name: name, Type: "A"})
+	}
+
+	networks := stats.Networks()
+	if len(networks) != 1 {
+		t.Fatalf("got %v networks, want 1", len(networks))
+	}
+	if networks[0].Network != "192.0.2.0/24" || networks[0].Queries != 4 {
+		t.Errorf("got %v queries of %v, want 4 queries of 192.0.2.0/24", networks[0].Queries, networks[0].Network)
+	}
+	want := map[string]int{"example.com": 1, "dev.example.com": 2}
+	if fmt.Sprint(networks[0].Zones) != fmt.Sprint(want) {
+		t.Errorf("got the queries by zone %v, want %v", networks[0].Zones, want)
+	}
+}
+
+func TestQueryStatisticsBoundsTheNetworks(t *testing.T) {
+	stats := NewQueryStatistics(nil, nil, nil).(*queryStatistics)
+	for i := 0; i < domain.MaxQueryStatsNetworks+10; i++ {
+		stats.OnQuery(&domain.QueryLogEntry{
+			Time: time.Now(), ClientIP: fmt.Sprintf("10.%v.%v.1", i/256, i%256), Name: "example.com", Type: "A",
+		})
+	}
+	if networks := len(stats.Networks()); networks != domain.MaxQueryStatsNetworks {
+		t.Errorf("got %v networks, want %v", networks, domain.MaxQueryStatsNetworks)
+	}
+}
+
+func TestQueryStatisticsEvictsTheMissingNamesQueriedTheLeast(t *testing.T) {
+	stats := NewQueryStatistics(nil, nil, nil).(*queryStatistics)
+	stats.zones = map[string]*domain.Zone{"example.com": {Domain: "example.com"}}
+	for i := 0; i < 3; i++ {
+		stats.OnQuery(&domain.QueryLogEntry{Time: time.Now(), ClientIP: "192.0.2.10", Name: "www.example.com", Type: "A"})
+	}
+	for i := 0; i < domain.MaxMissingNames; i++ {
+		stats.OnQuery(&domain.QueryLogEntry{
+			Time: time.Now(), ClientIP: "192.0.2.10", Name: fmt.Sprintf("random-%v.example.com", i), Type: "A",
+		})
+	}
+
+	missingNames := map[string]int{}
+	for _, missing := range stats.MissingNames() {
+		missingNames[missing.Name] = missing.Queries
+	}
+	if len(missingNames) > domain.MaxMissingNames {
+		t.Fatalf("got %v missing names, want at most %v", len(missingNames), domain.MaxMissingNames)
+	}
+	if missingNames["www"] != 3 {
+		t.Errorf("expected the name queried the most to be kept, got %v queries", missingNames["www"])
+	}
+	last := fmt.Sprintf("random-%v", domain.MaxMissingNames-1)
+	if missingNames[last] != 1 {
+		t.Errorf("expected the new name %v to be tracked", last)
+	}
+}
@@ -0,0 +1,87 @@
+package external
+
+import (
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/labstack/echo/v4"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiterIdleTimeout is how long the bucket of a client without any request is kept.
+const rateLimiterIdleTimeout = 10 * time.Minute
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	settings domain.SettingsProvider
+
+	lock      sync.Mutex
+	limit     domain.RateLimitSettings
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimitMiddleware limits the requests of every client address with a token bucket, following the rate limit
+// of the current settings. The address is the one of the connection, the X-Forwarded-For and X-Real-IP headers being
+// set by the clients as they like.
+func NewRateLimitMiddleware(settings domain.SettingsProvider) echo.MiddlewareFunc {
+	limiter := &rateLimiter{settings: settings, buckets: map[string]*tokenBucket{}}
+	extractIP := echo.ExtractIPDirect()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !limiter.allow(extractIP(c.Request()), time.Now()) {
+				return c.JSON(http.StatusTooManyRequests, GeneralRes{
+					Code:    http.StatusTooManyRequests,
+					Message: http.StatusText(http.StatusTooManyRequests),
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+func (r *rateLimiter) allow(client string, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if limit := r.settings.Settings().RateLimit; limit != r.limit {
+		r.limit = limit
+		r.buckets = map[string]*tokenBucket{}
+	}
+	if r.limit.RequestsPerSecond <= 0 {
+		return true
+	}
+	r.sweep(now)
+
+	bucket, ok := r.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(r.limit.Burst), lastSeen: now}
+		r.buckets[client] = bucket
+	}
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(float64(r.limit.Burst), bucket.tokens+elapsed*r.limit.RequestsPerSecond)
+	bucket.lastSeen = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// sweep drops the buckets of idle clients, they would be full again anyway.
+func (r *rateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < rateLimiterIdleTimeout {
+		return
+	}
+	r.lastSweep = now
+	for client, bucket := range r.buckets {
+		if now.Sub(bucket.lastSeen) >= rateLimiterIdleTimeout {
+			delete(r.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package external
+
+import (
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testSettingsProvider struct {
+	domain.SettingsProvider
+	settings *domain.Settings
+}
+
+func (p *testSettingsProvider) Settings() *domain.Settings {
+	return p.settings
+}
+
+func TestRateLimitMiddlewareIgnoresTheForwardedAddress(t *testing.T) {
+	settings := &testSettingsProvider{settings: &domain.Settings{
+		RateLimit: domain.RateLimitSettings{RequestsPerSecond: 0.001, Burst: 1},
+	}}
+	e := echo.New()
+	handler := NewRateLimitMiddleware(settings)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for i, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		rec := httptest.NewRecorder()
+		err := handler(e.NewContext(req, rec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := http.StatusOK
+		if i > 0 {
+			// The bucket of the connection address is empty whatever the forwarded address.
+			expected = http.StatusTooManyRequests
+		}
+		if rec.Code != expected {
+			t.Fatalf("request %v: expected %v, got %v", i, expected, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rdapBootstrapUrl = "https://data.iana.org/rdap/dns.json"
+	// rdapBootstrapTTL is how long the RDAP servers of the top level domains are cached.
+	rdapBootstrapTTL  = 24 * time.Hour
+	rdapLookupTimeout = 10 * time.Second
+)
+
+type rdapLookup struct {
+	client *http.Client
+
+	lock      sync.Mutex
+	servers   map[string]string
+	fetchedAt time.Time
+}
+
+// NewRdapLookup creates a lookup querying the RDAP server of the registry, as listed in the IANA bootstrap file.
+func NewRdapLookup() domain.RegistrationLookup {
+	return &rdapLookup{client: &http.Client{Timeout: rdapLookupTimeout}}
+}
+
+type rdapDomain struct {
+	Events []struct {
+		EventAction string    `json:"eventAction"`
+		EventDate   time.Time `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string      `json:"roles"`
+		VcardArray []interface{} `json:"vcardArray"`
+	} `json:"entities"`
+	NameServers []struct {
+		LdhName string `json:"ldhName"`
+	} `json:"nameservers"`
+}
+
+func (r *rdapLookup) Lookup(ctx context.Context, domainName string) (*domain.Registration, error) {
+	domainName = domain.NormalizeDomain(domainName)
+	server, err := r.server(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rdapDomain{}
+	err = r.get(ctx, server+"domain/"+domainName, res)
+	if err != nil {
+		return nil, err
+	}
+
+	registration := &domain.Registration{Domain: domainName}
+	for _, event := range res.Events {
+		if event.EventAction == "expiration" {
+			registration.ExpiresAt = event.EventDate
+		}
+	}
+	for _, entity := range res.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				registration.Registrar = vcardFullName(entity.VcardArray)
+			}
+		}
+	}
+	for _, nameServer := range res.NameServers {
+		registration.NameServers = append(registration.NameServers, domain.NormalizeDomain(nameServer.LdhName))
+	}
+	return registration, nil
+}
+
+// server returns the base URL of the RDAP server responsible for the longest matching suffix of the domain.
+func (r *rdapLookup) server(ctx context.Context, domainName string) (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.servers == nil || time.Since(r.fetchedAt) >= rdapBootstrapTTL {
+		bootstrap := &struct {
+			Services [][][]string `json:"services"`
+		}{}
+		err := r.get(ctx, rdapBootstrapUrl, bootstrap)
+		if err != nil {
+			return "", err
+		}
+		servers := map[string]string{}
+		for _, service := range bootstrap.Services {
+			if len(service) < 2 || len(service[1]) == 0 {
+				continue
+			}
+			for _, suffix := range service[0] {
+				servers[strings.ToLower(suffix)] = service[1][0]
+			}
+		}
+		r.servers = servers
+		r.fetchedAt = time.Now()
+	}
+
+	labels := strings.Split(domainName, ".")
+	for i := range labels {
+		if server, ok := r.servers[strings.Join(labels[i:], ".")]; ok {
+			if !strings.HasSuffix(server, "/") {
+				server += "/"
+			}
+			return server, nil
+		}
+	}
+	return "", fmt.Errorf("no RDAP server is known for %v", domainName)
+}
+
+func (r *rdapLookup) get(ctx context.Context, url string, res interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	httpRes, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode == http.StatusNotFound {
+		return domain.ErrorRegistrationNotFound
+	}
+	if httpRes.StatusCode >= 300 {
+		return fmt.Errorf("%v responded with status %v", url, httpRes.Status)
+	}
+	return json.NewDecoder(httpRes.Body).Decode(res)
+}
+
+// vcardFullName returns the fn property of a jCard, e.g. ["vcard", [["fn", {}, "text", "Example Registrar"]]].
+func vcardFullName(vcard []interface{}) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+	properties, _ := vcard[1].([]interface{})
+	for _, property := range properties {
+		values, _ := property.([]interface{})
+		if len(values) < 4 || values[0] != "fn" {
+			continue
+		}
+		name, _ := values[3].(string)
+		return name
+	}
+	return ""
+}
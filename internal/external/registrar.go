@@ -0,0 +1,31 @@
+package external
+
+import (
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"net/http"
+	"time"
+)
+
+const registrarRequestTimeout = 30 * time.Second
+
+// NewRegistrarConnector creates the connector of the registrar the account belongs to.
+func NewRegistrarConnector(account *domain.RegistrarAccount) (domain.RegistrarConnector, error) {
+	client := &http.Client{Timeout: registrarRequestTimeout}
+	switch account.Type {
+	case domain.RegistrarTypeGandi:
+		return &gandiRegistrar{account: account, client: client}, nil
+	case domain.RegistrarTypeNamecheap:
+		return &namecheapRegistrar{account: account, client: client}, nil
+	case domain.RegistrarTypeOpenSRS:
+		return &openSRSRegistrar{account: account, client: client}, nil
+	}
+	return nil, fmt.Errorf("registrar type %v is not supported", account.Type)
+}
+
+func registrarEndpoint(account *domain.RegistrarAccount, defaultEndpoint string) string {
+	if account.Endpoint != "" {
+		return account.Endpoint
+	}
+	return defaultEndpoint
+}
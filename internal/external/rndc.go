@@ -0,0 +1,64 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/pkg/errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	rndcPath = "/usr/sbin/rndc"
+	// rndcKeyName is the name of the key rndc authenticates to named with, see domain.Config.RndcKeyPath.
+	rndcKeyName      = "dns-server-manager"
+	rndcKeyAlgorithm = "hmac-sha256"
+)
+
+func runRndc(ctx context.Context, config domain.Config, args ...string) (string, error) {
+	args = append([]string{"-k", config.RndcKeyPath(), "-s", "127.0.0.1"}, args...)
+	output, err := exec.CommandContext(ctx, rndcPath, args...).CombinedOutput()
+	if err != nil {
+		return "", errors.Wrap(err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// waitRndc polls named through rndc until it answers or the attempts run out.
+func waitRndc(ctx context.Context, config domain.Config, attempts int, interval time.Duration) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		_, err = runRndc(ctx, config, "status")
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	return err
+}
+
+// generateRndcKey writes the key rndc authenticates to named with, unless it exists already. named is only allowed to
+// read it, the key being read again on every reload.
+func generateRndcKey(config domain.Config) error {
+	keyPath := config.RndcKeyPath()
+	if fileExists(keyPath) {
+		return nil
+	}
+	key, err := domain.NewTSIGKey(rndcKeyName, rndcKeyAlgorithm, "")
+	if err != nil {
+		return err
+	}
+	fileContents := fmt.Sprintf(`key "%v" {algorithm %v; secret "%v";};`+"\n", key.Name, key.Algorithm, key.Secret)
+	err = os.WriteFile(keyPath, []byte(fileContents), 0640)
+	if err != nil {
+		return err
+	}
+	return chownBindUser(keyPath)
+}
@@ -0,0 +1,140 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	rootHintsCheckInterval = time.Hour
+	rootHintsFetchTimeout  = time.Minute
+	maxRootHintsSize       = 1 << 20
+)
+
+type rootHintsUpdater struct {
+	serverRepo domain.ServerRepository
+	onChange   func(ctx context.Context) error
+	client     *http.Client
+
+	refreshLock    sync.Mutex
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewRootHintsUpdater creates an updater checking once per hour whether the root hints are due. onChange is called
+// after they have been downloaded so named.conf can be regenerated.
+func NewRootHintsUpdater(
+	serverRepo domain.ServerRepository, onChange func(ctx context.Context) error,
+) domain.RootHintsUpdater {
+	return &rootHintsUpdater{
+		serverRepo:     serverRepo,
+		onChange:       onChange,
+		client:         &http.Client{Timeout: rootHintsFetchTimeout},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (r *rootHintsUpdater) Start(ctx context.Context) {
+	r.stoppedWg.Add(1)
+	go func() {
+		defer r.stoppedWg.Done()
+
+		ticker := time.NewTicker(rootHintsCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.shutdownSignal:
+				return
+			case <-ticker.C:
+				r.refreshDue(ctx)
+			}
+		}
+	}()
+}
+
+func (r *rootHintsUpdater) Shutdown(ctx context.Context) error {
+	r.shutdownSignal <- 1
+	r.stoppedWg.Wait()
+	return nil
+}
+
+func (r *rootHintsUpdater) Refresh(ctx context.Context) error {
+	err := r.download(ctx)
+	if err != nil {
+		return err
+	}
+	return r.onChange(ctx)
+}
+
+func (r *rootHintsUpdater) refreshDue(ctx context.Context) {
+	options, err := r.serverRepo.GetOptions(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if !options.IsRootHintsRefreshDue(time.Now()) {
+		return
+	}
+	err = r.Refresh(ctx)
+	if err != nil {
+		log.Printf("Root hints failed to refresh: %v\n", err)
+	}
+}
+
+// download fetches the root hints, keeping the previously downloaded ones when anything goes wrong. A failed download
+// is recorded and retried on the next check, the refresh time only moving on success. The outcome is persisted alone,
+// the options being changed through the API while downloading.
+func (r *rootHintsUpdater) download(ctx context.Context) (err error) {
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+
+	options, err := r.serverRepo.GetOptions(ctx)
+	if err != nil {
+		return err
+	}
+	if options.RootHints != domain.RootHintsRefresh {
+		return domain.ErrorInvalidRootHints
+	}
+
+	defer func() {
+		options.RootHintsLastError = ""
+		if err != nil {
+			options.RootHintsLastError = err.Error()
+		}
+		if persistErr := r.serverRepo.PersistRootHintsRefresh(ctx, options); persistErr != nil && err == nil {
+			err = persistErr
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, options.RootHintsUrl, nil)
+	if err != nil {
+		return err
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(res.Body, maxRootHintsSize))
+	if err != nil {
+		return err
+	}
+	err = domain.CheckRootHints(string(content))
+	if err != nil {
+		return err
+	}
+	options.RootHintsContent = string(content)
+	options.RootHintsRefreshedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,195 @@
+package external
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rpzFeedCheckInterval = time.Minute
+	rpzFeedFetchTimeout  = 5 * time.Minute
+	// maxRpzFeedSize bounds the size of a feed, the largest public blocklists weighing a few dozen megabytes. A feed
+	// going over it fails to refresh, keeping its previous domains.
+	maxRpzFeedSize = 64 << 20
+)
+
+type rpzFeedUpdater struct {
+	config   domain.Config
+	rpzRepo  domain.RpzRepository
+	onChange func(ctx context.Context) error
+	client   *http.Client
+
+	refreshLock    sync.Mutex
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewRpzFeedUpdater creates an updater downloading every due feed once per minute. onChange is called after feeds
+// have been downloaded so the response policy zone can be regenerated.
+func NewRpzFeedUpdater(
+	config domain.Config, rpzRepo domain.RpzRepository, onChange func(ctx context.Context) error,
+) domain.RpzFeedUpdater {
+	return &rpzFeedUpdater{
+		config:         config,
+		rpzRepo:        rpzRepo,
+		onChange:       onChange,
+		client:         &http.Client{Timeout: rpzFeedFetchTimeout},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (r *rpzFeedUpdater) Start(ctx context.Context) {
+	r.stoppedWg.Add(1)
+	go func() {
+		defer r.stoppedWg.Done()
+
+		ticker := time.NewTicker(rpzFeedCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.shutdownSignal:
+				return
+			case <-ticker.C:
+				r.refreshDue(ctx)
+			}
+		}
+	}()
+}
+
+func (r *rpzFeedUpdater) Shutdown(ctx context.Context) error {
+	r.shutdownSignal <- 1
+	r.stoppedWg.Wait()
+	return nil
+}
+
+func (r *rpzFeedUpdater) Refresh(ctx context.Context, feed *domain.RpzFeed) error {
+	err := r.download(ctx, feed)
+	if err != nil {
+		return err
+	}
+	return r.onChange(ctx)
+}
+
+func (r *rpzFeedUpdater) Remove(ctx context.Context, feed *domain.RpzFeed) error {
+	err := os.Remove(rpzFeedListPath(r.config, feed.Id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *rpzFeedUpdater) refreshDue(ctx context.Context) {
+	feeds, err := r.rpzRepo.GetAllFeeds(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	refreshed := false
+	now := time.Now()
+	for _, feed := range feeds {
+		if !feed.IsDue(now) {
+			continue
+		}
+		err := r.download(ctx, feed)
+		if err != nil {
+			log.Printf("RPZ feed %v failed to refresh: %v\n", feed.Name, err)
+			continue
+		}
+		refreshed = true
+	}
+
+	if refreshed {
+		err = r.onChange(ctx)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// download fetches and parses the feed, keeping the previously downloaded domains when anything goes wrong. The
+// outcome is recorded on the feed either way, LastRefreshed being only set once downloaded.
+func (r *rpzFeedUpdater) download(ctx context.Context, feed *domain.RpzFeed) (err error) {
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+
+	defer func() {
+		feed.LastAttempted = time.Now()
+		feed.LastError = ""
+		if err != nil {
+			feed.LastError = err.Error()
+		} else {
+			feed.LastRefreshed = feed.LastAttempted
+		}
+		if persistErr := r.rpzRepo.PersistFeed(ctx, feed); persistErr != nil && err == nil {
+			err = persistErr
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.Url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	if res.ContentLength > maxRpzFeedSize {
+		return fmt.Errorf("feed is larger than %v MiB, its previous domains are kept", maxRpzFeedSize>>20)
+	}
+
+	body := &io.LimitedReader{R: res.Body, N: maxRpzFeedSize + 1}
+	domains, err := domain.ParseBlocklist(body)
+	if err != nil {
+		return err
+	}
+	if body.N == 0 {
+		return fmt.Errorf("feed is larger than %v MiB, its previous domains are kept", maxRpzFeedSize>>20)
+	}
+
+	err = writeFile(rpzFeedListPath(r.config, feed.Id), strings.Join(domains, "\n"))
+	if err != nil {
+		return err
+	}
+	feed.DomainCount = len(domains)
+	return nil
+}
+
+func rpzFeedListPath(config domain.Config, feedId string) string {
+	return filepath.Join(config.RpzFolderPath(), feedId+".list")
+}
+
+func readRpzFeedList(config domain.Config, feedId string) ([]string, error) {
+	file, err := os.Open(rpzFeedListPath(config, feedId))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			domains = append(domains, line)
+		}
+	}
+	return domains, scanner.Err()
+}
@@ -0,0 +1,260 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"golang.org/x/net/dns/dnsmessage"
+	"log"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	serialQueryTimeout = 3 * time.Second
+	// serialQueryConcurrency bounds the queries in flight while checking, the zones of every node being queried at
+	// once otherwise.
+	serialQueryConcurrency = 16
+)
+
+type serialChecker struct {
+	settings domain.SettingsProvider
+	zoneRepo domain.ZoneRepository
+	interval time.Duration
+
+	lock        sync.RWMutex
+	consistency *domain.SerialConsistency
+	// divergedSince holds when the nodes stopped serving the newest serial, and alerting whether they diverged for
+	// longer than the alert delay, by zone domain and node name.
+	divergedSince map[string]time.Time
+	alerting      map[string]bool
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewSerialChecker creates a checker querying the nodes of the serial check settings for the SOA serial of every
+// zone every interval, a few queries at once, each check ending by the next one. A node serving another serial than the
+// newest one for longer than the alert delay is logged.
+func NewSerialChecker(
+	settings domain.SettingsProvider, zoneRepo domain.ZoneRepository, interval time.Duration,
+) domain.SerialChecker {
+	return &serialChecker{
+		settings:       settings,
+		zoneRepo:       zoneRepo,
+		interval:       interval,
+		divergedSince:  map[string]time.Time{},
+		alerting:       map[string]bool{},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (c *serialChecker) Start(ctx context.Context) {
+	c.stoppedWg.Add(1)
+	go func() {
+		defer c.stoppedWg.Done()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.shutdownSignal:
+				return
+			case <-ticker.C:
+				err := c.check(ctx)
+				if err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+}
+
+func (c *serialChecker) Shutdown(ctx context.Context) error {
+	c.shutdownSignal <- 1
+	c.stoppedWg.Wait()
+	return nil
+}
+
+func (c *serialChecker) Consistency() *domain.SerialConsistency {
+	settings := c.settings.Settings().SerialCheck
+	if settings == nil {
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.consistency == nil {
+		consistency := &domain.SerialConsistency{AlertAfter: settings.AlertAfter()}
+		for _, node := range settings.Nodes {
+			consistency.Nodes = append(consistency.Nodes, node.Name)
+		}
+		return consistency
+	}
+	return c.consistency
+}
+
+func (c *serialChecker) check(ctx context.Context) error {
+	settings := c.settings.Settings().SerialCheck
+	if settings == nil {
+		c.lock.Lock()
+		c.consistency = nil
+		c.divergedSince, c.alerting = map[string]time.Time{}, map[string]bool{}
+		c.lock.Unlock()
+		return nil
+	}
+
+	zones, err := c.zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Domain < zones[j].Domain
+	})
+
+	consistency := &domain.SerialConsistency{AlertAfter: settings.AlertAfter()}
+	for _, node := range settings.Nodes {
+		consistency.Nodes = append(consistency.Nodes, node.Name)
+	}
+	// The check ends by the next one, the serials not answered by then being reported as errors.
+	queryCtx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, serialQueryConcurrency)
+	for _, zone := range zones {
+		if !zone.IsValid() {
+			continue
+		}
+		zoneSerials := &domain.ZoneSerials{Zone: zone.Domain}
+		for _, node := range settings.Nodes {
+			nodeSerial := &domain.NodeSerial{Node: node.Name}
+			zoneSerials.Nodes = append(zoneSerials.Nodes, nodeSerial)
+			wg.Add(1)
+			go func(address, zoneDomain string) {
+				defer wg.Done()
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-queryCtx.Done():
+					nodeSerial.Error = queryCtx.Err().Error()
+					return
+				}
+				serial, err := querySerial(queryCtx, address, zoneDomain)
+				if err != nil {
+					nodeSerial.Error = err.Error()
+					return
+				}
+				nodeSerial.Serial = serial
+			}(node.Address, zone.Domain)
+		}
+		consistency.Zones = append(consistency.Zones, zoneSerials)
+	}
+	wg.Wait()
+	for _, zoneSerials := range consistency.Zones {
+		zoneSerials.ResolveSerials()
+	}
+	consistency.CheckedAt = time.Now()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	divergedSince, alerting := map[string]time.Time{}, map[string]bool{}
+	for _, zoneSerials := range consistency.Zones {
+		for _, node := range zoneSerials.Nodes {
+			key := zoneSerials.Zone + " " + node.Node
+			if node.InSync {
+				if c.alerting[key] {
+					log.Printf("Node %v serves the serial %v of %v again\n", node.Node, zoneSerials.Serial,
+						zoneSerials.Zone)
+				}
+				continue
+			}
+			since, ok := c.divergedSince[key]
+			if !ok {
+				since = consistency.CheckedAt
+			}
+			divergedSince[key] = since
+			node.DivergedSince = since
+			node.Alerting = consistency.CheckedAt.Sub(since) >= consistency.AlertAfter
+			alerting[key] = node.Alerting
+			if node.Alerting && !c.alerting[key] {
+				log.Printf("Node %v diverged from the serial %v of %v since %v\n", node.Node, zoneSerials.Serial,
+					zoneSerials.Zone, since.Format(time.RFC3339))
+			}
+		}
+	}
+	c.divergedSince, c.alerting = divergedSince, alerting
+	c.consistency = consistency
+	return nil
+}
+
+// querySerial asks the DNS server listening on address, port 53 unless it is given, for the SOA serial of the zone.
+func querySerial(ctx context.Context, address string, zone string) (string, error) {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+	name, err := dnsmessage.NewName(domain.NormalizeDomain(zone) + ".")
+	if err != nil {
+		return "", err
+	}
+	id := uint16(rand.Intn(1 << 16))
+	query, err := (&dnsmessage.Message{
+		Header: dnsmessage.Header{ID: id},
+		Questions: []dnsmessage.Question{{
+			Name:  name,
+			Type:  dnsmessage.TypeSOA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}).Pack()
+	if err != nil {
+		return "", err
+	}
+
+	dialer := net.Dialer{Timeout: serialQueryTimeout}
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(serialQueryTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return "", err
+	}
+	_, err = conn.Write(query)
+	if err != nil {
+		return "", err
+	}
+
+	response := make([]byte, 4096)
+	for {
+		n, err := conn.Read(response)
+		if err != nil {
+			return "", err
+		}
+		var message dnsmessage.Message
+		err = message.Unpack(response[:n])
+		if err != nil || message.ID != id || !message.Response {
+			// A stray or malformed datagram, the answer may still come.
+			continue
+		}
+		if message.RCode != dnsmessage.RCodeSuccess {
+			return "", fmt.Errorf("query answered with %v", message.RCode)
+		}
+		if !message.Authoritative {
+			return "", fmt.Errorf("zone is not served authoritatively")
+		}
+		for _, answer := range message.Answers {
+			if soa, ok := answer.Body.(*dnsmessage.SOAResource); ok {
+				return strconv.FormatUint(uint64(soa.Serial), 10), nil
+			}
+		}
+		return "", fmt.Errorf("zone is not served")
+	}
+}
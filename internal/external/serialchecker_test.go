@@ -0,0 +1,59 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSerialCheckEndsByTheNextCheck(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+	db := newTestAuditDb(t)
+	zoneRepo := NewSqliteZoneRepository(config, db, db)
+	for i := 0; i < 40; i++ {
+		zone := domain.NewZone(fmt.Sprintf("example-%v.com", i))
+		err := zone.RegisterSOA(domain.NewDefaultSOARecord("ns1.example.com.", "admin.example.com."))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = zoneRepo.Persist(ctx, zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The node never answers, each query waiting for its whole timeout.
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	settings := &domain.Settings{SerialCheck: &domain.SerialCheckSettings{
+		Nodes: []*domain.SerialCheckNode{{Name: "secondary", Address: conn.LocalAddr().String()}},
+	}}
+	checker := NewSerialChecker(&testSettingsProvider{settings: settings}, zoneRepo, 500*time.Millisecond)
+
+	start := time.Now()
+	err = checker.(*serialChecker).check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > serialQueryTimeout {
+		t.Fatalf("expected the check to end by the next one, took %v", elapsed)
+	}
+	consistency := checker.Consistency()
+	if len(consistency.Zones) != 40 {
+		t.Fatalf("expected the 40 zones to be checked, got %v", len(consistency.Zones))
+	}
+	for _, zoneSerials := range consistency.Zones {
+		if zoneSerials.Nodes[0].Error == "" {
+			t.Fatalf("expected the serial of %v not to be answered", zoneSerials.Zone)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+)
+
+// settingsWebhookNamespace derives the ids of the webhooks declared in the settings from their url, so the ids
+// survive reloads.
+var settingsWebhookNamespace = uuid.MustParse("3c2d4a4e-5d0c-4b53-9d1e-8f3f8a3f8a21")
+
+type settingsWebhookRepository struct {
+	domain.WebhookRepository
+	settings domain.SettingsProvider
+}
+
+// NewSettingsWebhookRepository adds the webhooks declared in the settings to the ones stored in repo. They are read
+// only, persisting or deleting them only affects repo.
+func NewSettingsWebhookRepository(
+	repo domain.WebhookRepository, settings domain.SettingsProvider,
+) domain.WebhookRepository {
+	return &settingsWebhookRepository{WebhookRepository: repo, settings: settings}
+}
+
+func (s *settingsWebhookRepository) GetAllWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	webhooks, err := s.WebhookRepository.GetAllWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(webhooks, s.settingsWebhooks()...), nil
+}
+
+func (s *settingsWebhookRepository) GetWebhookById(ctx context.Context, webhookId string) (*domain.Webhook, error) {
+	for _, webhook := range s.settingsWebhooks() {
+		if webhook.Id == webhookId {
+			return webhook, nil
+		}
+	}
+	return s.WebhookRepository.GetWebhookById(ctx, webhookId)
+}
+
+func (s *settingsWebhookRepository) settingsWebhooks() []*domain.Webhook {
+	var webhooks []*domain.Webhook
+	seen := map[string]bool{}
+	for _, target := range s.settings.Settings().Webhooks {
+		if seen[target.Url] {
+			continue
+		}
+		seen[target.Url] = true
+		webhookId := uuid.NewSHA1(settingsWebhookNamespace, []byte(target.Url)).String()
+		webhooks = append(webhooks, target.Webhook(webhookId))
+	}
+	return webhooks
+}
@@ -0,0 +1,119 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type sqliteZoneArchiveRepository struct {
+	config domain.Config
+	db     *sql.DB
+}
+
+// NewSqliteZoneArchiveRepository stores the archives in sqlite and their bundles as files in the archive folder.
+func NewSqliteZoneArchiveRepository(config domain.Config, db *sql.DB) domain.ZoneArchiveRepository {
+	return &sqliteZoneArchiveRepository{config: config, db: db}
+}
+
+func (r *sqliteZoneArchiveRepository) GetAllArchives(ctx context.Context) ([]*domain.ZoneArchive, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, domain, archived_at, record_count, size FROM zone_archives ORDER BY archived_at DESC;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var archives []*domain.ZoneArchive
+	for rows.Next() {
+		archive, err := r.archiveMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, archive)
+	}
+	return archives, nil
+}
+
+func (r *sqliteZoneArchiveRepository) GetArchiveById(
+	ctx context.Context, archiveId string,
+) (*domain.ZoneArchive, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, domain, archived_at, record_count, size FROM zone_archives WHERE id = ?;
+	`, archiveId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.archiveMapper(rows)
+}
+
+func (r *sqliteZoneArchiveRepository) GetBundle(ctx context.Context, archive *domain.ZoneArchive) ([]byte, error) {
+	return os.ReadFile(r.bundlePath(archive))
+}
+
+func (r *sqliteZoneArchiveRepository) PersistArchive(
+	ctx context.Context, archive *domain.ZoneArchive, bundle []byte,
+) error {
+	if archive.Id == "" {
+		archive.Id = uuid.NewString()
+	}
+	archive.Size = int64(len(bundle))
+
+	err := os.MkdirAll(r.config.ArchiveFolderPath(), 0777)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(r.bundlePath(archive), bundle, 0666)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		REPLACE INTO zone_archives(id, domain, archived_at, record_count, size) VALUES(?, ?, ?, ?, ?);
+	`, archive.Id, archive.Domain, archive.ArchivedAt.Unix(), archive.RecordCount, archive.Size)
+	if err != nil {
+		os.Remove(r.bundlePath(archive))
+		return err
+	}
+	return nil
+}
+
+func (r *sqliteZoneArchiveRepository) DeleteArchive(ctx context.Context, archive *domain.ZoneArchive) error {
+	if archive == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM zone_archives WHERE id = ?;", archive.Id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(r.bundlePath(archive))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *sqliteZoneArchiveRepository) bundlePath(archive *domain.ZoneArchive) string {
+	return filepath.Join(r.config.ArchiveFolderPath(), archive.Id+".json.gz")
+}
+
+func (r *sqliteZoneArchiveRepository) archiveMapper(rows *sql.Rows) (*domain.ZoneArchive, error) {
+	archive := &domain.ZoneArchive{}
+	var archivedAt int64
+	err := rows.Scan(&archive.Id, &archive.Domain, &archivedAt, &archive.RecordCount, &archive.Size)
+	if err != nil {
+		return nil, err
+	}
+	archive.ArchivedAt = time.Unix(archivedAt, 0)
+	return archive, nil
+}
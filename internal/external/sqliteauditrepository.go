@@ -0,0 +1,172 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const defaultAuditLogLimit = 100
+
+type sqliteAuditLogRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteAuditLogRepository(db *sql.DB) domain.AuditLogRepository {
+	return &sqliteAuditLogRepository{db: db}
+}
+
+func (r *sqliteAuditLogRepository) GetAuditLogs(
+	ctx context.Context, filter domain.AuditLogFilter,
+) ([]*domain.ChangeEvent, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	if filter.All {
+		// sqlite does not limit the result for a negative limit.
+		limit = -1
+	}
+
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Zone != "" {
+		conditions = append(conditions, "zone = ?")
+		args = append(args, filter.Zone)
+	}
+	if filter.RecordId != "" {
+		conditions = append(conditions, "record_id = ?")
+		args = append(args, filter.RecordId)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "time >= ?")
+		args = append(args, filter.From.UnixNano())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "time < ?")
+		args = append(args, filter.To.UnixNano())
+	}
+	query := fmt.Sprintf("SELECT event FROM audit_logs WHERE %v ORDER BY time DESC LIMIT ?;",
+		strings.Join(conditions, " AND "))
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.ChangeEvent
+	for rows.Next() {
+		var encoded string
+		err := rows.Scan(&encoded)
+		if err != nil {
+			return nil, err
+		}
+		event := &domain.ChangeEvent{}
+		err = json.Unmarshal([]byte(encoded), event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *sqliteAuditLogRepository) CountChanges(
+	ctx context.Context, zone string, to time.Time, window time.Duration, windows int,
+) ([]int, error) {
+	from := to.Add(-time.Duration(windows) * window)
+	// The events are stored as JSON, the alerts being told apart by their type.
+	burstType := fmt.Sprintf(`"Type":%q`, domain.EventChangeBurst)
+	canaryType := fmt.Sprintf(`"Type":%q`, domain.EventCanaryQueried)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT (? - 1 - time) / ? AS bucket, COUNT(*) FROM audit_logs
+		WHERE zone = ? AND time >= ? AND time < ? AND instr(event, ?) = 0 AND instr(event, ?) = 0
+		GROUP BY bucket;
+	`, to.UnixNano(), window.Nanoseconds(), zone, from.UnixNano(), to.UnixNano(), burstType, canaryType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int, windows)
+	for rows.Next() {
+		var index, count int
+		err := rows.Scan(&index, &count)
+		if err != nil {
+			return nil, err
+		}
+		if index >= 0 && index < windows {
+			counts[index] = count
+		}
+	}
+	return counts, rows.Err()
+}
+
+func (r *sqliteAuditLogRepository) GetAllExporters(ctx context.Context) ([]*domain.AuditExporter, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, type, address, protocol, enabled FROM audit_exporters;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exporters []*domain.AuditExporter
+	for rows.Next() {
+		exporter, err := r.exporterMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
+
+func (r *sqliteAuditLogRepository) GetExporterById(
+	ctx context.Context, exporterId string,
+) (*domain.AuditExporter, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, type, address, protocol, enabled FROM audit_exporters WHERE id = ?;
+	`, exporterId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.exporterMapper(rows)
+}
+
+func (r *sqliteAuditLogRepository) PersistExporter(ctx context.Context, exporter *domain.AuditExporter) error {
+	if exporter.Id == "" {
+		exporter.Id = uuid.NewString()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		REPLACE INTO audit_exporters(id, type, address, protocol, enabled) VALUES(?, ?, ?, ?, ?);
+	`, exporter.Id, exporter.Type, exporter.Address, exporter.Protocol, exporter.Enabled)
+	return err
+}
+
+func (r *sqliteAuditLogRepository) DeleteExporter(ctx context.Context, exporter *domain.AuditExporter) error {
+	if exporter == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM audit_exporters WHERE id = ?;", exporter.Id)
+	return err
+}
+
+func (r *sqliteAuditLogRepository) exporterMapper(rows *sql.Rows) (*domain.AuditExporter, error) {
+	exporter := &domain.AuditExporter{}
+	err := rows.Scan(&exporter.Id, &exporter.Type, &exporter.Address, &exporter.Protocol, &exporter.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	return exporter, nil
+}
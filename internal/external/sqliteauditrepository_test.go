@@ -0,0 +1,128 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	_ "github.com/mattn/go-sqlite3"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestAuditDb(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = NewSqliteMigration(db).Migrate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func insertTestEvents(t *testing.T, db *sql.DB, events []*domain.ChangeEvent) {
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = insertChangeEvents(context.Background(), tx, events)
+	if err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAuditLogsLimitsTheHistoryOfARecord(t *testing.T) {
+	db := newTestAuditDb(t)
+	start := time.Now().Add(-time.Hour)
+	var events []*domain.ChangeEvent
+	// The record changes first, then another record changes more often than the limit.
+	for i := 0; i < 3; i++ {
+		events = append(events, &domain.ChangeEvent{
+			Type: domain.EventRecordUpdated, Time: start.Add(time.Duration(i) * time.Second), Zone: "example.com",
+			Record: &domain.Record{Id: "record-1", Name: "www", Type: "A", Value: "192.0.2.1"},
+		})
+	}
+	for i := 0; i < 10; i++ {
+		events = append(events, &domain.ChangeEvent{
+			Type: domain.EventRecordUpdated, Time: start.Add(time.Minute + time.Duration(i)*time.Second),
+			Zone:   "example.com",
+			Record: &domain.Record{Id: "record-2", Name: "mail", Type: "A", Value: "192.0.2.2"},
+		})
+	}
+	insertTestEvents(t, db, events)
+
+	repository := NewSqliteAuditLogRepository(db)
+	history, err := repository.GetAuditLogs(context.Background(), domain.AuditLogFilter{
+		Zone: "example.com", RecordId: "record-1", Limit: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %v events, want 2", len(history))
+	}
+	for _, event := range history {
+		if event.Record.Id != "record-1" {
+			t.Errorf("got an event of %v, want record-1", event.Record.Id)
+		}
+	}
+	if !history[0].Time.After(history[1].Time) {
+		t.Errorf("got the events in ascending order, want the latest first")
+	}
+}
+
+func TestMigrateBackfillsTheRecordOfTheAuditLogs(t *testing.T) {
+	db := newTestAuditDb(t)
+	// An event logged before the record was stored in its own column, mentioning another record in its value.
+	event := &domain.ChangeEvent{
+		Id: "event-1", Type: domain.EventRecordUpdated, Time: time.Now(), Zone: "example.com",
+		Record:         &domain.Record{Id: "record-1", Name: "alias", Type: "CNAME", Value: "record-2"},
+		PreviousRecord: &domain.Record{Id: "record-1", Name: "alias", Type: "CNAME", Value: "www"},
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zoneEvent := &domain.ChangeEvent{Id: "event-2", Type: domain.EventZoneCreated, Time: time.Now(), Zone: "example.com"}
+	encodedZoneEvent, err := json.Marshal(zoneEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		INSERT INTO audit_logs(id, time, zone, event) VALUES(?, ?, ?, ?), (?, ?, ?, ?);
+	`, event.Id, event.Time.UnixNano(), event.Zone, string(encoded),
+		zoneEvent.Id, zoneEvent.Time.UnixNano(), zoneEvent.Zone, string(encodedZoneEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, migration := range schemaMigrations {
+		if strings.Contains(migration, "SET record_id") {
+			_, err = db.Exec(migration)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for id, want := range map[string]string{"event-1": "record-1", "event-2": ""} {
+		var recordId string
+		err = db.QueryRow("SELECT record_id FROM audit_logs WHERE id = ?;", id).Scan(&recordId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recordId != want {
+			t.Errorf("got record %q for %v, want %q", recordId, id, want)
+		}
+	}
+}
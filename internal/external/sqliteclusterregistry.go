@@ -0,0 +1,134 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// instanceStaleHeartbeats is the number of heartbeats an instance may miss before it is reported stale.
+	instanceStaleHeartbeats = 3
+	// instancePruneAfter is how long stale instances are still listed before they are forgotten.
+	instancePruneAfter = time.Hour
+)
+
+type sqliteClusterRegistry struct {
+	db       *sql.DB
+	interval time.Duration
+	instance *domain.Instance
+
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewSqliteClusterRegistry registers the instance in the shared database, sending its heartbeat every interval. The
+// schema version an instance reports is the one its migrations bring the database to.
+func NewSqliteClusterRegistry(db *sql.DB, interval time.Duration) domain.ClusterRegistry {
+	hostname, _ := os.Hostname()
+	return &sqliteClusterRegistry{
+		db:       db,
+		interval: interval,
+		instance: &domain.Instance{
+			Id:            uuid.NewString(),
+			Hostname:      hostname,
+			Version:       domain.Version,
+			SchemaVersion: len(schemaMigrations),
+			StartedAt:     time.Now(),
+		},
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (r *sqliteClusterRegistry) Start(ctx context.Context) error {
+	err := r.heartbeat(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.stoppedWg.Add(1)
+	go func() {
+		defer r.stoppedWg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.shutdownSignal:
+				return
+			case <-ticker.C:
+				err := r.heartbeat(ctx)
+				if err != nil {
+					log.Println(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *sqliteClusterRegistry) Shutdown(ctx context.Context) error {
+	r.shutdownSignal <- 1
+	r.stoppedWg.Wait()
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM instances WHERE id = ?;`, r.instance.Id)
+	return err
+}
+
+func (r *sqliteClusterRegistry) InstanceId() string {
+	return r.instance.Id
+}
+
+func (r *sqliteClusterRegistry) Instances(ctx context.Context) ([]*domain.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, hostname, version, schema_version, started_at, last_seen_at FROM instances;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []*domain.Instance
+	for rows.Next() {
+		var (
+			instance   = &domain.Instance{}
+			startedAt  int64
+			lastSeenAt int64
+		)
+		err = rows.Scan(
+			&instance.Id, &instance.Hostname, &instance.Version, &instance.SchemaVersion, &startedAt, &lastSeenAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		instance.StartedAt = fromUnixTime(startedAt)
+		instance.LastSeenAt = fromUnixTime(lastSeenAt)
+		instances = append(instances, instance)
+	}
+	domain.ResolveInstances(instances, instanceStaleHeartbeats*r.interval, time.Now())
+	return instances, nil
+}
+
+// heartbeat stores the instance and forgets the instances gone for long.
+func (r *sqliteClusterRegistry) heartbeat(ctx context.Context) error {
+	r.instance.LastSeenAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO instances (id, hostname, version, schema_version, started_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET last_seen_at = excluded.last_seen_at;
+	`, r.instance.Id, r.instance.Hostname, r.instance.Version, r.instance.SchemaVersion,
+		toUnixTime(r.instance.StartedAt), toUnixTime(r.instance.LastSeenAt))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM instances WHERE last_seen_at < ?;`,
+		toUnixTime(time.Now().Add(-instancePruneAfter)))
+	return err
+}
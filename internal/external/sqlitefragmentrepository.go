@@ -0,0 +1,82 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+)
+
+type sqliteFragmentRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteFragmentRepository(db *sql.DB) domain.FragmentRepository {
+	return &sqliteFragmentRepository{db: db}
+}
+
+func (r *sqliteFragmentRepository) GetAllFragments(ctx context.Context) ([]*domain.RecordFragment, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, records FROM record_fragments ORDER BY name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fragments []*domain.RecordFragment
+	for rows.Next() {
+		fragment, err := r.fragmentMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, fragment)
+	}
+	return fragments, nil
+}
+
+func (r *sqliteFragmentRepository) GetFragmentByName(
+	ctx context.Context, name string,
+) (*domain.RecordFragment, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, records FROM record_fragments WHERE name = ?;", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.fragmentMapper(rows)
+}
+
+func (r *sqliteFragmentRepository) PersistFragment(ctx context.Context, fragment *domain.RecordFragment) error {
+	records, err := json.Marshal(fragment.Records)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		REPLACE INTO record_fragments(name, records) VALUES(?, ?);
+	`, fragment.Name, string(records))
+	return err
+}
+
+func (r *sqliteFragmentRepository) DeleteFragment(ctx context.Context, fragment *domain.RecordFragment) error {
+	if fragment == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM record_fragments WHERE name = ?;", fragment.Name)
+	return err
+}
+
+func (r *sqliteFragmentRepository) fragmentMapper(rows *sql.Rows) (*domain.RecordFragment, error) {
+	fragment := &domain.RecordFragment{}
+	var records string
+	err := rows.Scan(&fragment.Name, &records)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(records), &fragment.Records)
+	if err != nil {
+		return nil, err
+	}
+	return fragment, nil
+}
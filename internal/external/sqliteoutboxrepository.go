@@ -0,0 +1,180 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+type sqliteOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteOutboxRepository(db *sql.DB) domain.OutboxRepository {
+	return &sqliteOutboxRepository{db: db}
+}
+
+func (r *sqliteOutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]*domain.ChangeEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT event FROM outbox_events WHERE dispatched = 0 ORDER BY rowid LIMIT ?;
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.ChangeEvent
+	for rows.Next() {
+		var encoded string
+		err := rows.Scan(&encoded)
+		if err != nil {
+			return nil, err
+		}
+		event := &domain.ChangeEvent{}
+		err = json.Unmarshal([]byte(encoded), event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *sqliteOutboxRepository) DispatchEvent(
+	ctx context.Context, event *domain.ChangeEvent, deliveries []*domain.WebhookDelivery,
+) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	for _, delivery := range deliveries {
+		err = persistDelivery(ctx, tx, delivery)
+		if err != nil {
+			return
+		}
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE outbox_events SET dispatched = 1 WHERE id = ?;", event.Id)
+	return
+}
+
+func (r *sqliteOutboxRepository) GetDueDeliveries(
+	ctx context.Context, now time.Time, limit int,
+) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.id, d.webhook_id, e.event, d.status, d.attempts, d.next_attempt_at, d.last_error
+		FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id
+		WHERE d.status = ? AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at LIMIT ?;
+	`, domain.DeliveryStatusPending, now.Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		delivery := &domain.WebhookDelivery{Event: &domain.ChangeEvent{}}
+		var encodedEvent string
+		var nextAttemptAt int64
+		err := rows.Scan(&delivery.Id, &delivery.WebhookId, &encodedEvent, &delivery.Status, &delivery.Attempts,
+			&nextAttemptAt, &delivery.LastError)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(encodedEvent), delivery.Event)
+		if err != nil {
+			return nil, err
+		}
+		delivery.NextAttemptAt = fromUnixTime(nextAttemptAt)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *sqliteOutboxRepository) PersistDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return persistDelivery(ctx, r.db, delivery)
+}
+
+func (r *sqliteOutboxRepository) Prune(ctx context.Context, before time.Time) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries WHERE status != ? AND event_id IN (
+		    SELECT id FROM outbox_events WHERE created_at < ?
+		);
+		DELETE FROM outbox_events WHERE dispatched = 1 AND created_at < ? AND id NOT IN (
+		    SELECT event_id FROM webhook_deliveries
+		);
+	`, domain.DeliveryStatusPending, before.Unix(), before.Unix())
+	return
+}
+
+func (r *sqliteOutboxRepository) AddEvents(ctx context.Context, events []*domain.ChangeEvent) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	err = insertChangeEvents(ctx, tx, events)
+	return
+}
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func persistDelivery(ctx context.Context, db sqlExecutor, delivery *domain.WebhookDelivery) error {
+	if delivery.Id == "" {
+		delivery.Id = uuid.NewString()
+	}
+	_, err := db.ExecContext(ctx, `
+		REPLACE INTO webhook_deliveries(id, webhook_id, event_id, status, attempts, next_attempt_at, last_error)
+		VALUES(?, ?, ?, ?, ?, ?, ?);
+	`, delivery.Id, delivery.WebhookId, delivery.Event.Id, delivery.Status, delivery.Attempts,
+		toUnixTime(delivery.NextAttemptAt), delivery.LastError)
+	return err
+}
+
+// insertChangeEvents writes the events to the outbox and the audit log within the transaction of the mutation
+// producing them, so an event is recorded if and only if the change is committed.
+func insertChangeEvents(ctx context.Context, tx *sql.Tx, events []*domain.ChangeEvent) error {
+	for _, event := range events {
+		if event.Id == "" {
+			event.Id = uuid.NewString()
+		}
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		// The record is stored along with the event for the history of a record to be filtered and limited in SQL.
+		recordId := ""
+		if event.Record != nil {
+			recordId = event.Record.Id
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO outbox_events(id, event, created_at, dispatched) VALUES(?, ?, ?, 0);
+			INSERT INTO audit_logs(id, time, zone, record_id, event) VALUES(?, ?, ?, ?, ?);
+		`, event.Id, string(encoded), event.Time.Unix(), event.Id, event.Time.UnixNano(), event.Zone, recordId,
+			string(encoded))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
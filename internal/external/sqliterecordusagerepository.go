@@ -0,0 +1,64 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"time"
+)
+
+type sqliteRecordUsageRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteRecordUsageRepository(db *sql.DB) domain.RecordUsageRepository {
+	return &sqliteRecordUsageRepository{db: db}
+}
+
+func (r *sqliteRecordUsageRepository) GetLastQueries(
+	ctx context.Context, zone *domain.Zone,
+) (map[string]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT record_id, last_queried_at FROM record_queries WHERE zone_id = ?;",
+		zone.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastQueries := map[string]time.Time{}
+	for rows.Next() {
+		var recordId string
+		var lastQueriedAt int64
+		err := rows.Scan(&recordId, &lastQueriedAt)
+		if err != nil {
+			return nil, err
+		}
+		lastQueries[recordId] = fromUnixTime(lastQueriedAt)
+	}
+	return lastQueries, rows.Err()
+}
+
+func (r *sqliteRecordUsageRepository) PersistLastQueries(
+	ctx context.Context, lastQueries map[string]time.Time,
+) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	for recordId, lastQueriedAt := range lastQueries {
+		// The records deleted since the query are skipped.
+		_, err = tx.ExecContext(ctx, `
+			REPLACE INTO record_queries(record_id, zone_id, last_queried_at)
+			SELECT id, zone_id, ? FROM records WHERE id = ?;
+		`, toUnixTime(lastQueriedAt), recordId)
+		if err != nil {
+			return
+		}
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM record_queries WHERE record_id NOT IN (SELECT id FROM records);")
+	return
+}
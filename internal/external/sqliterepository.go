@@ -3,73 +3,142 @@ package external
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/anantadwi13/dns-server-manager/internal/domain"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"log"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type sqliteZoneRepository struct {
 	config domain.Config
 	db     *sql.DB
+	readDb *sql.DB
 }
 
-func NewSqliteZoneRepository(config domain.Config, db *sql.DB) domain.ZoneRepository {
-	return &sqliteZoneRepository{config: config, db: db}
+// NewSqliteZoneRepository creates the zone repository, queries made with a domain.WithReadOnly context are served
+// by readDb which may be the same database as db.
+func NewSqliteZoneRepository(config domain.Config, db *sql.DB, readDb *sql.DB) domain.ZoneRepository {
+	return &sqliteZoneRepository{config: config, db: db, readDb: readDb}
 }
 
 func (z *sqliteZoneRepository) GetAllZones(ctx context.Context) ([]*domain.Zone, error) {
-	zoneRows, err := z.db.QueryContext(ctx, "SELECT * FROM zones;")
+	zones, _, err := z.FindZones(ctx, domain.ZoneFilter{}, domain.Page{})
+	return zones, err
+}
+
+func (z *sqliteZoneRepository) FindZones(
+	ctx context.Context, filter domain.ZoneFilter, page domain.Page,
+) ([]*domain.Zone, int, error) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.ExpiringWithin > 0 {
+		conditions = append(conditions, "expires_at != 0 AND expires_at < ?")
+		args = append(args, toUnixTime(time.Now().Add(filter.ExpiringWithin)))
+	}
+	if filter.TechnicalContact != "" {
+		conditions = append(conditions, "instr(lower(technical_contact), lower(?)) > 0")
+		args = append(args, filter.TechnicalContact)
+	}
+	if filter.ExternalId != "" {
+		conditions = append(conditions, "external_id = ?")
+		args = append(args, filter.ExternalId)
+	}
+	if filter.Group != "" {
+		conditions = append(conditions, "zone_group = ?")
+		args = append(args, filter.Group)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	err := z.reader(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM zones WHERE "+where+";", args...).Scan(&total)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	limit := page.Limit
+	if limit == 0 {
+		// sqlite does not limit the result for a negative limit.
+		limit = -1
+	}
+	pageQuery := "SELECT id FROM zones WHERE " + where + " ORDER BY domain LIMIT ? OFFSET ?"
+	pageArgs := append(append([]interface{}{}, args...), limit, page.Offset)
+
+	zoneRows, err := z.reader(ctx).QueryContext(ctx,
+		"SELECT "+zoneColumns+" FROM zones WHERE id IN ("+pageQuery+") ORDER BY domain;", pageArgs...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer zoneRows.Close()
 
-	recordRows, err := z.db.QueryContext(ctx, "SELECT * FROM records;")
+	recordRows, err := z.reader(ctx).QueryContext(ctx,
+		"SELECT * FROM records WHERE zone_id IN ("+pageQuery+");", pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer recordRows.Close()
 
-	soaRows, err := z.db.QueryContext(ctx, "SELECT * FROM soas;")
+	soaRows, err := z.reader(ctx).QueryContext(ctx, "SELECT * FROM soas WHERE zone_id IN ("+pageQuery+");", pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer soaRows.Close()
 
+	labelRows, err := z.reader(ctx).QueryContext(ctx,
+		"SELECT record_id, key, value FROM record_labels WHERE zone_id IN ("+pageQuery+");", pageArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer labelRows.Close()
+
+	var zones []*domain.Zone
 	var mapZones = map[string]*domain.Zone{}
+	var mapRecords = map[string]*domain.Record{}
 	for zoneRows.Next() {
-		zone := &domain.Zone{}
-		err := zoneRows.Scan(&zone.Id, &zone.Domain, &zone.FilePath)
+		zone, err := z.zoneMapper(zoneRows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		z.filePathAssigner(zone)
+		zones = append(zones, zone)
 		mapZones[zone.Id] = zone
 	}
 
 	for recordRows.Next() {
 		record := &domain.Record{}
-		var zoneId string
-		err := recordRows.Scan(&record.Id, &zoneId, &record.Name, &record.Type, &record.Value)
+		var zoneId, generatorId string
+		err := recordRows.Scan(
+			&record.Id, &zoneId, &record.Name, &record.Type, &record.Value, &record.Priority, &record.ExternalId,
+			&record.Comment, &record.Disabled, &generatorId, &record.TTL, &record.View, &record.Canary,
+		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		zone, ok := mapZones[zoneId]
 		if !ok {
 			continue
 		}
+		record.Generator = zone.FindRecordGenerator(generatorId)
 		zone.Records = append(zone.Records, record)
+		mapRecords[record.Id] = record
 	}
 
 	for soaRows.Next() {
 		soa := &domain.SOARecord{}
 		var zoneId string
+		var serialStepAt, publishedAt int64
 		err := soaRows.Scan(&soa.Id, &zoneId, &soa.Name, &soa.PrimaryNameServer, &soa.MailAddress, &soa.Serial,
-			&soa.SerialCounter, &soa.Refresh, &soa.Retry, &soa.Expire, &soa.CacheTTL)
+			&soa.SerialCounter, &soa.Refresh, &soa.Retry, &soa.Expire, &soa.CacheTTL, &soa.SerialStrategy,
+			&serialStepAt, &publishedAt)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		soa.SerialStepAt = fromUnixTime(serialStepAt)
+		soa.PublishedAt = fromUnixTime(publishedAt)
 		zone, ok := mapZones[zoneId]
 		if !ok {
 			continue
@@ -77,15 +146,15 @@ func (z *sqliteZoneRepository) GetAllZones(ctx context.Context) ([]*domain.Zone,
 		zone.SOA = soa
 	}
 
-	var zones []*domain.Zone
-	for _, zone := range mapZones {
-		zones = append(zones, zone)
+	err = labelsMapper(mapRecords, labelRows)
+	if err != nil {
+		return nil, 0, err
 	}
-	return zones, nil
+	return zones, total, nil
 }
 
 func (z *sqliteZoneRepository) GetZoneById(ctx context.Context, zoneId string) (*domain.Zone, error) {
-	zoneRows, err := z.db.QueryContext(ctx, "SELECT * FROM zones WHERE id = ?;", zoneId)
+	zoneRows, err := z.reader(ctx).QueryContext(ctx, "SELECT "+zoneColumns+" FROM zones WHERE id = ?;", zoneId)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +162,7 @@ func (z *sqliteZoneRepository) GetZoneById(ctx context.Context, zoneId string) (
 
 	var zone *domain.Zone
 	for zoneRows.Next() {
-		zone = &domain.Zone{}
-		err := zoneRows.Scan(&zone.Id, &zone.Domain, &zone.FilePath)
+		zone, err = z.zoneMapper(zoneRows)
 		if err != nil {
 			return nil, err
 		}
@@ -106,19 +174,26 @@ func (z *sqliteZoneRepository) GetZoneById(ctx context.Context, zoneId string) (
 	}
 	z.filePathAssigner(zone)
 
-	recordRows, err := z.db.QueryContext(ctx, "SELECT * FROM records WHERE zone_id = ?;", zone.Id)
+	recordRows, err := z.reader(ctx).QueryContext(ctx, "SELECT * FROM records WHERE zone_id = ?;", zone.Id)
 	if err != nil {
 		return nil, err
 	}
 	defer recordRows.Close()
 
-	soaRows, err := z.db.QueryContext(ctx, "SELECT * FROM soas WHERE zone_id = ?;", zone.Id)
+	soaRows, err := z.reader(ctx).QueryContext(ctx, "SELECT * FROM soas WHERE zone_id = ?;", zone.Id)
 	if err != nil {
 		return nil, err
 	}
 	defer soaRows.Close()
 
-	err = z.zonesMapper(zone, recordRows, soaRows)
+	labelRows, err := z.reader(ctx).QueryContext(ctx,
+		"SELECT record_id, key, value FROM record_labels WHERE zone_id = ?;", zone.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer labelRows.Close()
+
+	err = z.zonesMapper(zone, recordRows, soaRows, labelRows)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +202,7 @@ func (z *sqliteZoneRepository) GetZoneById(ctx context.Context, zoneId string) (
 }
 
 func (z *sqliteZoneRepository) GetZoneByDomain(ctx context.Context, domainName string) (*domain.Zone, error) {
-	zoneRows, err := z.db.QueryContext(ctx, "SELECT * FROM zones WHERE domain = ?;", domainName)
+	zoneRows, err := z.reader(ctx).QueryContext(ctx, "SELECT "+zoneColumns+" FROM zones WHERE domain = ?;", domainName)
 	if err != nil {
 		return nil, err
 	}
@@ -135,8 +210,7 @@ func (z *sqliteZoneRepository) GetZoneByDomain(ctx context.Context, domainName s
 
 	var zone *domain.Zone
 	for zoneRows.Next() {
-		zone = &domain.Zone{}
-		err := zoneRows.Scan(&zone.Id, &zone.Domain, &zone.FilePath)
+		zone, err = z.zoneMapper(zoneRows)
 		if err != nil {
 			return nil, err
 		}
@@ -148,19 +222,26 @@ func (z *sqliteZoneRepository) GetZoneByDomain(ctx context.Context, domainName s
 	}
 	z.filePathAssigner(zone)
 
-	recordRows, err := z.db.QueryContext(ctx, "SELECT * FROM records WHERE zone_id = ?;", zone.Id)
+	recordRows, err := z.reader(ctx).QueryContext(ctx, "SELECT * FROM records WHERE zone_id = ?;", zone.Id)
 	if err != nil {
 		return nil, err
 	}
 	defer recordRows.Close()
 
-	soaRows, err := z.db.QueryContext(ctx, "SELECT * FROM soas WHERE zone_id = ?;", zone.Id)
+	soaRows, err := z.reader(ctx).QueryContext(ctx, "SELECT * FROM soas WHERE zone_id = ?;", zone.Id)
 	if err != nil {
 		return nil, err
 	}
 	defer soaRows.Close()
 
-	err = z.zonesMapper(zone, recordRows, soaRows)
+	labelRows, err := z.reader(ctx).QueryContext(ctx,
+		"SELECT record_id, key, value FROM record_labels WHERE zone_id = ?;", zone.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer labelRows.Close()
+
+	err = z.zonesMapper(zone, recordRows, soaRows, labelRows)
 	if err != nil {
 		return nil, err
 	}
@@ -168,16 +249,37 @@ func (z *sqliteZoneRepository) GetZoneByDomain(ctx context.Context, domainName s
 	return zone, nil
 }
 
+func (z *sqliteZoneRepository) reader(ctx context.Context) *sql.DB {
+	if domain.IsReadOnly(ctx) {
+		return z.readDb
+	}
+	return z.db
+}
+
 func (z *sqliteZoneRepository) Persist(ctx context.Context, zone *domain.Zone) (err error) {
+	return z.PersistAll(ctx, []*domain.Zone{zone})
+}
+
+func (z *sqliteZoneRepository) PersistAll(ctx context.Context, zones []*domain.Zone) (err error) {
 	tx, err := z.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
 
 	defer func() {
-		err = z.finishTransaction(err, tx)
+		err = finishTransaction(err, tx)
 	}()
 
+	for _, zone := range zones {
+		err = z.persist(ctx, tx, zone)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (z *sqliteZoneRepository) persist(ctx context.Context, tx *sql.Tx, zone *domain.Zone) (err error) {
 	if zone.Id == "" {
 		zone.Id = uuid.NewString()
 	}
@@ -190,7 +292,11 @@ func (z *sqliteZoneRepository) Persist(ctx context.Context, zone *domain.Zone) (
 		return
 	}
 
+	zone.Revision, zone.AppliedRevision = 1, 0
 	if oldZone != nil {
+		zone.Revision = oldZone.Revision + 1
+		zone.AppliedRevision = oldZone.AppliedRevision
+
 		deletedRecords := make(map[string]*domain.Record)
 		for _, record := range oldZone.Records {
 			deletedRecords[record.Id] = record
@@ -210,9 +316,51 @@ func (z *sqliteZoneRepository) Persist(ctx context.Context, zone *domain.Zone) (
 		}
 	}
 
+	allowTransfer, err := json.Marshal(zone.AllowTransfer)
+	if err != nil {
+		return
+	}
+	transferKeys, err := json.Marshal(zone.TransferKeys)
+	if err != nil {
+		return
+	}
+	variables, err := json.Marshal(zone.Variables)
+	if err != nil {
+		return
+	}
+	for _, generator := range zone.Generators {
+		if generator.Id == "" {
+			generator.Id = uuid.NewString()
+		}
+	}
+	generators, err := json.Marshal(zone.Generators)
+	if err != nil {
+		return
+	}
+	alsoNotify, err := json.Marshal(zone.AlsoNotify)
+	if err != nil {
+		return
+	}
+	fragments, err := json.Marshal(zone.Fragments)
+	if err != nil {
+		return
+	}
+	views, err := json.Marshal(zone.Views)
+	if err != nil {
+		return
+	}
+
 	_, err = tx.ExecContext(ctx, `
-		REPLACE INTO zones(id, domain, file_path) VALUES(?, ?, ?);
-	`, zone.Id, zone.Domain, zone.FilePath)
+		REPLACE INTO zones(id, domain, file_path, regulated, strict_validation, notes, technical_contact, expires_at,
+		                   registrar, sync_ptr, external_id, revision, applied_revision, sync_primary_ns, notify_interval,
+		                   allow_transfer, transfer_keys, variables, generators, also_notify, fragments, frozen_at,
+		                   freeze_reason, views, dnssec_policy, zone_group)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`, zone.Id, zone.Domain, zone.FilePath, zone.Regulated, zone.StrictValidation, zone.Notes, zone.TechnicalContact,
+		toUnixTime(zone.ExpiresAt), zone.Registrar, zone.SyncPTR, zone.ExternalId, zone.Revision, zone.AppliedRevision,
+		zone.SyncPrimaryNS, int64(zone.NotifyInterval/time.Second), string(allowTransfer), string(transferKeys),
+		string(variables), string(generators), string(alsoNotify), string(fragments), toUnixTime(zone.FrozenAt),
+		zone.FreezeReason, string(views), zone.DnssecPolicy, zone.Group)
 	if err != nil {
 		return
 	}
@@ -224,29 +372,86 @@ func (z *sqliteZoneRepository) Persist(ctx context.Context, zone *domain.Zone) (
 		}
 
 		_, err = tx.ExecContext(ctx, `
-			REPLACE INTO soas(id, zone_id, name, primary_ns, mail_addr, serial, serial_counter, refresh, retry, expire, cache_ttl) 
-			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
-		`, soa.Id, zone.Id, soa.Name, soa.PrimaryNameServer, soa.MailAddress, soa.Serial, soa.SerialCounter, soa.Refresh, soa.Retry, soa.Expire, soa.CacheTTL)
+			REPLACE INTO soas(id, zone_id, name, primary_ns, mail_addr, serial, serial_counter, refresh, retry, expire, cache_ttl, serial_strategy, serial_step_at, published_at) 
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+		`, soa.Id, zone.Id, soa.Name, soa.PrimaryNameServer, soa.MailAddress, soa.Serial, soa.SerialCounter, soa.Refresh, soa.Retry, soa.Expire, soa.CacheTTL, soa.SerialStrategy, toUnixTime(soa.SerialStepAt), toUnixTime(soa.PublishedAt))
 		if err != nil {
 			return
 		}
 	}
 
+	_, err = tx.ExecContext(ctx, `DELETE FROM record_labels WHERE zone_id = ?;`, zone.Id)
+	if err != nil {
+		return
+	}
 	for _, record := range zone.Records {
 		if record.Id == "" {
 			record.Id = uuid.NewString()
 		}
 
+		generatorId := ""
+		if record.Generator != nil {
+			generatorId = record.Generator.Id
+		}
 		_, err = tx.ExecContext(ctx, `
-			REPLACE INTO records(id, zone_id, name, type, value) VALUES(?, ?, ?, ?, ?);
-		`, record.Id, zone.Id, record.Name, record.Type, record.Value)
+			REPLACE INTO records(id, zone_id, name, type, value, priority, external_id, comment, disabled, generator_id,
+			                     ttl, view, canary)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+		`, record.Id, zone.Id, record.Name, record.Type, record.Value, record.Priority, record.ExternalId,
+			record.Comment, record.Disabled, generatorId, record.TTL, record.View, record.Canary)
 		if err != nil {
 			return
 		}
+		for key, value := range record.Labels {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO record_labels(record_id, zone_id, key, value) VALUES(?, ?, ?, ?);
+			`, record.Id, zone.Id, key, value)
+			if err != nil {
+				return
+			}
+		}
 	}
+
+	err = insertChangeEvents(ctx, tx, zone.PendingEvents())
+	if err != nil {
+		return
+	}
+	zone.ClearEvents()
 	return
 }
 
+func (z *sqliteZoneRepository) PersistSerial(ctx context.Context, zone *domain.Zone) error {
+	if zone == nil || zone.SOA == nil {
+		return nil
+	}
+
+	_, err := z.db.ExecContext(ctx, `
+		UPDATE soas SET serial = ?, serial_counter = ?, serial_step_at = ?, published_at = ? WHERE id = ?;
+	`, zone.SOA.Serial, zone.SOA.SerialCounter, toUnixTime(zone.SOA.SerialStepAt), toUnixTime(zone.SOA.PublishedAt),
+		zone.SOA.Id)
+	return err
+}
+
+func (z *sqliteZoneRepository) PersistFilePath(ctx context.Context, zone *domain.Zone) error {
+	_, err := z.db.ExecContext(ctx, `
+		UPDATE zones SET file_path = ? WHERE id = ?;
+	`, zone.FilePath, zone.Id)
+	return err
+}
+
+func (z *sqliteZoneRepository) MarkApplied(ctx context.Context, zone *domain.Zone) error {
+	_, err := z.db.ExecContext(ctx, `
+		UPDATE zones SET applied_revision = ? WHERE id = ? AND applied_revision < ?;
+	`, zone.Revision, zone.Id, zone.Revision)
+	if err != nil {
+		return err
+	}
+	if zone.AppliedRevision < zone.Revision {
+		zone.AppliedRevision = zone.Revision
+	}
+	return nil
+}
+
 func (z *sqliteZoneRepository) Delete(ctx context.Context, zone *domain.Zone) (err error) {
 	if zone == nil {
 		return domain.ErrorZoneNotFound
@@ -257,19 +462,28 @@ func (z *sqliteZoneRepository) Delete(ctx context.Context, zone *domain.Zone) (e
 		return err
 	}
 	defer func() {
-		err = z.finishTransaction(err, tx)
+		err = finishTransaction(err, tx)
 	}()
 
 	_, err = tx.ExecContext(ctx, `
 		DELETE FROM zones WHERE id = ?;
 		DELETE FROM soas WHERE zone_id = ?;
 		DELETE FROM records WHERE zone_id = ?;
-	`, zone.Id, zone.Id, zone.Id)
+		DELETE FROM record_labels WHERE zone_id = ?;
+	`, zone.Id, zone.Id, zone.Id, zone.Id)
+	if err != nil {
+		return
+	}
 
+	err = insertChangeEvents(ctx, tx, zone.PendingEvents())
+	if err != nil {
+		return
+	}
+	zone.ClearEvents()
 	return
 }
 
-func (z *sqliteZoneRepository) finishTransaction(err error, tx *sql.Tx) error {
+func finishTransaction(err error, tx *sql.Tx) error {
 	if err != nil {
 		if rollbackError := tx.Rollback(); rollbackError != nil {
 			return errors.Wrap(err, rollbackError.Error())
@@ -285,34 +499,196 @@ func (z *sqliteZoneRepository) finishTransaction(err error, tx *sql.Tx) error {
 	}
 }
 
-func (z *sqliteZoneRepository) zonesMapper(zone *domain.Zone, recordRows, soaRows *sql.Rows) error {
+// zoneColumns are the columns of the zones table read by zoneMapper, in order.
+const zoneColumns = "id, domain, file_path, regulated, strict_validation, notes, technical_contact, expires_at, " +
+	"registrar, sync_ptr, external_id, revision, applied_revision, sync_primary_ns, notify_interval, allow_transfer, " +
+	"transfer_keys, variables, generators, also_notify, fragments, frozen_at, freeze_reason, views, dnssec_policy, " +
+	"zone_group"
+
+func (z *sqliteZoneRepository) zoneMapper(rows *sql.Rows) (*domain.Zone, error) {
+	zone := &domain.Zone{}
+	var expiresAt, notifyInterval, frozenAt int64
+	var allowTransfer, transferKeys, variables, generators, alsoNotify, fragments, views string
+	err := rows.Scan(&zone.Id, &zone.Domain, &zone.FilePath, &zone.Regulated, &zone.StrictValidation, &zone.Notes,
+		&zone.TechnicalContact, &expiresAt, &zone.Registrar, &zone.SyncPTR, &zone.ExternalId, &zone.Revision,
+		&zone.AppliedRevision, &zone.SyncPrimaryNS, &notifyInterval, &allowTransfer, &transferKeys,
+		&variables, &generators, &alsoNotify, &fragments, &frozenAt, &zone.FreezeReason, &views,
+		&zone.DnssecPolicy, &zone.Group)
+	if err != nil {
+		return nil, err
+	}
+	zone.ExpiresAt = fromUnixTime(expiresAt)
+	zone.FrozenAt = fromUnixTime(frozenAt)
+	zone.NotifyInterval = time.Duration(notifyInterval) * time.Second
+	err = json.Unmarshal([]byte(allowTransfer), &zone.AllowTransfer)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(transferKeys), &zone.TransferKeys)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(variables), &zone.Variables)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(generators), &zone.Generators)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(alsoNotify), &zone.AlsoNotify)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(fragments), &zone.Fragments)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(views), &zone.Views)
+	if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (z *sqliteZoneRepository) zonesMapper(zone *domain.Zone, recordRows, soaRows, labelRows *sql.Rows) error {
 	for soaRows.Next() {
 		soa := &domain.SOARecord{}
 		var zoneId string
+		var serialStepAt, publishedAt int64
 		err := soaRows.Scan(&soa.Id, &zoneId, &soa.Name, &soa.PrimaryNameServer, &soa.MailAddress, &soa.Serial,
-			&soa.SerialCounter, &soa.Refresh, &soa.Retry, &soa.Expire, &soa.CacheTTL)
+			&soa.SerialCounter, &soa.Refresh, &soa.Retry, &soa.Expire, &soa.CacheTTL, &soa.SerialStrategy,
+			&serialStepAt, &publishedAt)
 		if err != nil {
 			return err
 		}
+		soa.SerialStepAt = fromUnixTime(serialStepAt)
+		soa.PublishedAt = fromUnixTime(publishedAt)
 		zone.SOA = soa
 	}
 
 	for recordRows.Next() {
 		record := &domain.Record{}
-		var zoneId string
-		err := recordRows.Scan(&record.Id, &zoneId, &record.Name, &record.Type, &record.Value)
+		var zoneId, generatorId string
+		err := recordRows.Scan(
+			&record.Id, &zoneId, &record.Name, &record.Type, &record.Value, &record.Priority, &record.ExternalId,
+			&record.Comment, &record.Disabled, &generatorId, &record.TTL, &record.View, &record.Canary,
+		)
 		if err != nil {
 			return err
 		}
+		record.Generator = zone.FindRecordGenerator(generatorId)
 		zone.Records = append(zone.Records, record)
 	}
+
+	mapRecords := map[string]*domain.Record{}
+	for _, record := range zone.Records {
+		mapRecords[record.Id] = record
+	}
+	return labelsMapper(mapRecords, labelRows)
+}
+
+// labelsMapper sets the labels of the records, by id, the labels of the other records being skipped.
+func labelsMapper(records map[string]*domain.Record, labelRows *sql.Rows) error {
+	for labelRows.Next() {
+		var recordId, key, value string
+		err := labelRows.Scan(&recordId, &key, &value)
+		if err != nil {
+			return err
+		}
+		record, ok := records[recordId]
+		if !ok {
+			continue
+		}
+		if record.Labels == nil {
+			record.Labels = map[string]string{}
+		}
+		record.Labels[key] = value
+	}
 	return nil
 }
 
+// filePathAssigner places the zone file in the folder of the zone group, the bind folder possibly having moved. The
+// file keeps the name it was stored with, the consistency check renaming the files named before domain.ZoneFileName,
+// the new zones being given the name of domain.ZoneFileName.
 func (z *sqliteZoneRepository) filePathAssigner(zone *domain.Zone) {
-	zone.FilePath = filepath.Join(z.config.BindFolderPath(), "db-"+zone.Domain)
+	zone.FilePath = zoneFilePath(z.config, zone.Domain, zone.Group, zone.FilePath)
 }
 
+// zoneFilePath returns the path of the zone file of a zone out of the path stored, see filePathAssigner.
+func zoneFilePath(config domain.Config, domainName, group, storedPath string) string {
+	name := zoneFilePrefix + domain.ZoneFileName(domainName)
+	if storedPath != "" {
+		name = filepath.Base(storedPath)
+	}
+	return filepath.Join(config.ZoneFolderPath(group), name)
+}
+
+// schemaMigrations alter the base schema created by Migrate. They are applied in order and exactly once, the
+// schema version stored in the database being the number of migrations already applied. Append only.
+var schemaMigrations = []string{
+	`ALTER TABLE rpz_feeds ADD COLUMN profile TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN regulated INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN strict_validation INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE soas ADD COLUMN serial_strategy TEXT NOT NULL DEFAULT 'date';`,
+	`ALTER TABLE soas ADD COLUMN serial_step_at INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN notes TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN technical_contact TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN registrar TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE records ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;`,
+	// The priority of MX and SRV records used to head their value.
+	`UPDATE records SET priority = CAST(substr(value, 1, instr(value, ' ') - 1) AS INTEGER),
+	                    value = trim(substr(value, instr(value, ' ') + 1))
+	 WHERE upper(type) IN ('MX', 'SRV') AND value GLOB '[0-9]* *';`,
+	`ALTER TABLE zones ADD COLUMN sync_ptr INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN external_id TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE records ADD COLUMN external_id TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN applied_revision INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE records ADD COLUMN comment TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE records ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN sync_primary_ns INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN notify_interval INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE soas ADD COLUMN published_at INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN allow_transfer TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE zones ADD COLUMN transfer_keys TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE zones ADD COLUMN variables TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE zones ADD COLUMN generators TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE records ADD COLUMN generator_id TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN also_notify TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE zones ADD COLUMN fragments TEXT NOT NULL DEFAULT 'null';`,
+	// The usage of the records is tracked from now on when the query log is enabled already.
+	`INSERT OR IGNORE INTO server_options(name, value)
+	 SELECT 'query_log_since', '"' || strftime('%Y-%m-%dT%H:%M:%SZ', 'now') || '"'
+	 FROM server_options WHERE name = 'query_log' AND value = 'true';`,
+	`ALTER TABLE zones ADD COLUMN frozen_at INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN freeze_reason TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE records ADD COLUMN ttl INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN views TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE records ADD COLUMN view TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE zones ADD COLUMN dnssec_policy TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE records ADD COLUMN canary INTEGER NOT NULL DEFAULT 0;`,
+	`ALTER TABLE zones ADD COLUMN zone_group TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE audit_logs ADD COLUMN record_id TEXT NOT NULL DEFAULT '';`,
+	// The record of the events logged so far is read from the encoded event, where the Id of the record comes first.
+	`UPDATE audit_logs
+	 SET record_id = substr(event, instr(event, '"Record":{"Id":"') + 16,
+	                        instr(substr(event, instr(event, '"Record":{"Id":"') + 16), '"') - 1)
+	 WHERE instr(event, '"Record":{"Id":"') > 0;`,
+	`CREATE INDEX IF NOT EXISTS audit_logs_zone_record_time ON audit_logs(zone, record_id, time);`,
+	`ALTER TABLE rpz_profiles ADD COLUMN views TEXT NOT NULL DEFAULT 'null';`,
+	`ALTER TABLE rpz_feeds ADD COLUMN last_attempted INTEGER NOT NULL DEFAULT 0;`,
+	`UPDATE rpz_feeds SET last_attempted = last_refreshed;`,
+}
+
+const (
+	migrationLockTimeout = time.Minute
+	migrationLockRetry   = 500 * time.Millisecond
+	// migrationLockExpiry lets the other instances take the lock over from an instance which died while migrating.
+	migrationLockExpiry = 5 * time.Minute
+)
+
 type sqliteMigration struct {
 	db *sql.DB
 }
@@ -322,6 +698,13 @@ func NewSqliteMigration(db *sql.DB) domain.Migration {
 }
 
 func (m *sqliteMigration) Migrate(ctx context.Context) error {
+	owner := uuid.NewString()
+	err := m.lock(ctx, owner)
+	if err != nil {
+		return err
+	}
+	defer m.unlock(ctx, owner)
+
 	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -352,14 +735,141 @@ func (m *sqliteMigration) Migrate(ctx context.Context) error {
 		    expire INTEGER NOT NULL,
 		    cache_ttl INTEGER NOT NULL
 		);
+		CREATE TABLE IF NOT EXISTS server_options (
+		    name TEXT PRIMARY KEY,
+		    value TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS negative_trust_anchors (
+		    id TEXT PRIMARY KEY,
+		    domain TEXT NOT NULL UNIQUE,
+		    expires_at INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS rpz_feeds (
+		    id TEXT PRIMARY KEY,
+		    name TEXT NOT NULL,
+		    url TEXT NOT NULL,
+		    refresh_interval INTEGER NOT NULL,
+		    enabled INTEGER NOT NULL,
+		    last_refreshed INTEGER NOT NULL,
+		    last_error TEXT NOT NULL,
+		    domain_count INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS rpz_profiles (
+		    name TEXT PRIMARY KEY,
+		    enabled INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS webhooks (
+		    id TEXT PRIMARY KEY,
+		    url TEXT NOT NULL,
+		    events TEXT NOT NULL,
+		    zones TEXT NOT NULL,
+		    record_types TEXT NOT NULL,
+		    payload_template TEXT NOT NULL,
+		    content_type TEXT NOT NULL,
+		    enabled INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS outbox_events (
+		    id TEXT PRIMARY KEY,
+		    event TEXT NOT NULL,
+		    created_at INTEGER NOT NULL,
+		    dispatched INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		    id TEXT PRIMARY KEY,
+		    webhook_id TEXT NOT NULL,
+		    event_id TEXT NOT NULL,
+		    status TEXT NOT NULL,
+		    attempts INTEGER NOT NULL,
+		    next_attempt_at INTEGER NOT NULL,
+		    last_error TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS audit_logs (
+		    id TEXT PRIMARY KEY,
+		    time INTEGER NOT NULL,
+		    zone TEXT NOT NULL,
+		    event TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS audit_exporters (
+		    id TEXT PRIMARY KEY,
+		    type TEXT NOT NULL,
+		    address TEXT NOT NULL,
+		    protocol TEXT NOT NULL,
+		    enabled INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS zone_archives (
+		    id TEXT PRIMARY KEY,
+		    domain TEXT NOT NULL,
+		    archived_at INTEGER NOT NULL,
+		    record_count INTEGER NOT NULL,
+		    size INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS record_labels (
+		    record_id TEXT NOT NULL,
+		    zone_id TEXT NOT NULL,
+		    key TEXT NOT NULL,
+		    value TEXT NOT NULL,
+		    PRIMARY KEY (record_id, key)
+		);
+		CREATE TABLE IF NOT EXISTS record_fragments (
+		    name TEXT PRIMARY KEY,
+		    records TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS tsig_keys (
+		    name TEXT PRIMARY KEY,
+		    algorithm TEXT NOT NULL,
+		    secret TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS record_queries (
+		    record_id TEXT PRIMARY KEY,
+		    zone_id TEXT NOT NULL,
+		    last_queried_at INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS instances (
+		    id TEXT PRIMARY KEY,
+		    hostname TEXT NOT NULL,
+		    version TEXT NOT NULL,
+		    schema_version INTEGER NOT NULL,
+		    started_at INTEGER NOT NULL,
+		    last_seen_at INTEGER NOT NULL
+		);
 		CREATE INDEX IF NOT EXISTS zones_domain ON zones(domain);
 		CREATE INDEX IF NOT EXISTS records_zone_id ON records(zone_id);
 		CREATE INDEX IF NOT EXISTS soas_zone_id ON soas(zone_id);
+		CREATE INDEX IF NOT EXISTS record_labels_zone_id ON record_labels(zone_id);
+		CREATE INDEX IF NOT EXISTS record_queries_zone_id ON record_queries(zone_id);
+		CREATE INDEX IF NOT EXISTS audit_logs_zone_time ON audit_logs(zone, time);
+		CREATE INDEX IF NOT EXISTS outbox_events_dispatched ON outbox_events(dispatched);
+		CREATE INDEX IF NOT EXISTS webhook_deliveries_status ON webhook_deliveries(status, next_attempt_at);
 	`)
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if version > len(schemaMigrations) {
+		tx.Rollback()
+		log.Printf("Database schema version %v is newer than %v, changes are refused\n", version, len(schemaMigrations))
+		return nil
+	}
+	for ; version < len(schemaMigrations); version++ {
+		_, err = tx.ExecContext(ctx, schemaMigrations[version])
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrapf(err, "schema migration %v", version+1)
+		}
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d;", version))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
@@ -367,3 +877,64 @@ func (m *sqliteMigration) Migrate(ctx context.Context) error {
 	}
 	return nil
 }
+
+func (m *sqliteMigration) CheckSchema(ctx context.Context) error {
+	var version int
+	err := m.db.QueryRowContext(ctx, "PRAGMA user_version;").Scan(&version)
+	if err != nil {
+		return err
+	}
+	if version > len(schemaMigrations) {
+		return domain.ErrorSchemaOutdated
+	}
+	return nil
+}
+
+func (m *sqliteMigration) SchemaVersion() int {
+	return len(schemaMigrations)
+}
+
+// lock takes the advisory lock making the instances sharing the database migrate one after the other.
+func (m *sqliteMigration) lock(ctx context.Context, owner string) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_lock (
+		    id INTEGER PRIMARY KEY CHECK (id = 1),
+		    owner TEXT NOT NULL,
+		    expires_at INTEGER NOT NULL
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(migrationLockTimeout)
+	for {
+		now := time.Now()
+		res, err := m.db.ExecContext(ctx, `
+			INSERT INTO schema_lock (id, owner, expires_at) VALUES (1, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+			WHERE schema_lock.expires_at < ?;
+		`, owner, toUnixTime(now.Add(migrationLockExpiry)), toUnixTime(now))
+		if err != nil {
+			return err
+		}
+		acquired, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if acquired > 0 {
+			return nil
+		}
+		if now.After(deadline) {
+			return errors.New("timeout waiting for another instance to migrate the database")
+		}
+		time.Sleep(migrationLockRetry)
+	}
+}
+
+func (m *sqliteMigration) unlock(ctx context.Context, owner string) {
+	_, err := m.db.ExecContext(ctx, `DELETE FROM schema_lock WHERE owner = ?;`, owner)
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,141 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+type sqliteRpzRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteRpzRepository(db *sql.DB) domain.RpzRepository {
+	return &sqliteRpzRepository{db: db}
+}
+
+func (r *sqliteRpzRepository) GetAllFeeds(ctx context.Context) ([]*domain.RpzFeed, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, profile, url, refresh_interval, enabled, last_refreshed, last_attempted, last_error,
+		       domain_count FROM rpz_feeds;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []*domain.RpzFeed
+	for rows.Next() {
+		feed, err := r.feedMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds, nil
+}
+
+func (r *sqliteRpzRepository) GetFeedById(ctx context.Context, feedId string) (*domain.RpzFeed, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, profile, url, refresh_interval, enabled, last_refreshed, last_attempted, last_error,
+		       domain_count FROM rpz_feeds
+		WHERE id = ?;
+	`, feedId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.feedMapper(rows)
+}
+
+func (r *sqliteRpzRepository) PersistFeed(ctx context.Context, feed *domain.RpzFeed) error {
+	if feed.Id == "" {
+		feed.Id = uuid.NewString()
+	}
+	_, err := r.db.ExecContext(ctx, `
+		REPLACE INTO rpz_feeds(id, name, profile, url, refresh_interval, enabled, last_refreshed, last_attempted,
+		                       last_error, domain_count)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`, feed.Id, feed.Name, feed.Profile, feed.Url, int64(feed.RefreshInterval.Seconds()), feed.Enabled,
+		toUnixTime(feed.LastRefreshed), toUnixTime(feed.LastAttempted), feed.LastError, feed.DomainCount)
+	return err
+}
+
+func (r *sqliteRpzRepository) DeleteFeed(ctx context.Context, feed *domain.RpzFeed) error {
+	if feed == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM rpz_feeds WHERE id = ?;", feed.Id)
+	return err
+}
+
+func (r *sqliteRpzRepository) GetProfiles(ctx context.Context) ([]*domain.RpzProfile, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, enabled, views FROM rpz_profiles;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*domain.RpzProfile
+	for rows.Next() {
+		profile := &domain.RpzProfile{}
+		var views string
+		err := rows.Scan(&profile.Name, &profile.Enabled, &views)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(views), &profile.Views)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (r *sqliteRpzRepository) PersistProfile(ctx context.Context, profile *domain.RpzProfile) error {
+	views, err := json.Marshal(profile.Views)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		REPLACE INTO rpz_profiles(name, enabled, views) VALUES(?, ?, ?);
+	`, profile.Name, profile.Enabled, string(views))
+	return err
+}
+
+func (r *sqliteRpzRepository) feedMapper(rows *sql.Rows) (*domain.RpzFeed, error) {
+	feed := &domain.RpzFeed{}
+	var refreshInterval, lastRefreshed, lastAttempted int64
+	err := rows.Scan(&feed.Id, &feed.Name, &feed.Profile, &feed.Url, &refreshInterval, &feed.Enabled, &lastRefreshed,
+		&lastAttempted, &feed.LastError, &feed.DomainCount)
+	if err != nil {
+		return nil, err
+	}
+	feed.RefreshInterval = time.Duration(refreshInterval) * time.Second
+	feed.LastRefreshed = fromUnixTime(lastRefreshed)
+	feed.LastAttempted = fromUnixTime(lastAttempted)
+	return feed, nil
+}
+
+// toUnixTime stores the zero time as 0 rather than as a large negative number.
+func toUnixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromUnixTime(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
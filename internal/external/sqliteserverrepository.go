@@ -0,0 +1,272 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	serverOptionValidateExcept    = "validate_except"
+	serverOptionRecursionMode     = "recursion_mode"
+	serverOptionAllowRecursion    = "allow_recursion"
+	serverOptionAllowOpenResolver = "allow_open_resolver"
+	serverOptionForwarders        = "forwarders"
+	serverOptionQueryLog          = "query_log"
+	serverOptionQueryLogSince     = "query_log_since"
+	serverOptionClientSubnetStats = "client_subnet_stats"
+	serverOptionBlackholePresets  = "blackhole_presets"
+	serverOptionBlackholeNetworks = "blackhole_networks"
+	serverOptionRpzAllowlist      = "rpz_allowlist"
+	serverOptionViews             = "views"
+
+	serverOptionDefaultZones             = "default_zones"
+	serverOptionDefaultZonesContent      = "default_zones_content"
+	serverOptionRootHints                = "root_hints"
+	serverOptionRootHintsContent         = "root_hints_content"
+	serverOptionRootHintsUrl             = "root_hints_url"
+	serverOptionRootHintsRefreshInterval = "root_hints_refresh_interval"
+	serverOptionRootHintsRefreshedAt     = "root_hints_refreshed_at"
+	serverOptionRootHintsLastError       = "root_hints_last_error"
+)
+
+type sqliteServerRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteServerRepository(db *sql.DB) domain.ServerRepository {
+	return &sqliteServerRepository{db: db}
+}
+
+func (s *sqliteServerRepository) GetOptions(ctx context.Context) (*domain.ServerOptions, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT name, value FROM server_options;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := domain.NewDefaultServerOptions()
+	for rows.Next() {
+		var name, value string
+		err := rows.Scan(&name, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		var dest interface{}
+		switch name {
+		case serverOptionValidateExcept:
+			dest = &options.ValidateExcept
+		case serverOptionRecursionMode:
+			dest = &options.RecursionMode
+		case serverOptionAllowRecursion:
+			dest = &options.AllowRecursion
+		case serverOptionAllowOpenResolver:
+			dest = &options.AllowOpenResolver
+		case serverOptionForwarders:
+			dest = &options.Forwarders
+		case serverOptionQueryLog:
+			dest = &options.QueryLog
+		case serverOptionQueryLogSince:
+			dest = &options.QueryLogSince
+		case serverOptionClientSubnetStats:
+			dest = &options.ClientSubnetStats
+		case serverOptionBlackholePresets:
+			dest = &options.BlackholePresets
+		case serverOptionBlackholeNetworks:
+			dest = &options.BlackholeNetworks
+		case serverOptionRpzAllowlist:
+			dest = &options.RpzAllowlist
+		case serverOptionViews:
+			dest = &options.Views
+		case serverOptionDefaultZones:
+			dest = &options.DefaultZones
+		case serverOptionDefaultZonesContent:
+			dest = &options.DefaultZonesContent
+		case serverOptionRootHints:
+			dest = &options.RootHints
+		case serverOptionRootHintsContent:
+			dest = &options.RootHintsContent
+		case serverOptionRootHintsUrl:
+			dest = &options.RootHintsUrl
+		case serverOptionRootHintsRefreshInterval:
+			dest = &options.RootHintsRefreshInterval
+		case serverOptionRootHintsRefreshedAt:
+			dest = &options.RootHintsRefreshedAt
+		case serverOptionRootHintsLastError:
+			dest = &options.RootHintsLastError
+		default:
+			continue
+		}
+		err = json.Unmarshal([]byte(value), dest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+func (s *sqliteServerRepository) PersistOptions(ctx context.Context, options *domain.ServerOptions) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	values := map[string]interface{}{
+		serverOptionValidateExcept:    options.ValidateExcept,
+		serverOptionRecursionMode:     options.RecursionMode,
+		serverOptionAllowRecursion:    options.AllowRecursion,
+		serverOptionAllowOpenResolver: options.AllowOpenResolver,
+		serverOptionForwarders:        options.Forwarders,
+		serverOptionQueryLog:          options.QueryLog,
+		serverOptionQueryLogSince:     options.QueryLogSince,
+		serverOptionClientSubnetStats: options.ClientSubnetStats,
+		serverOptionBlackholePresets:  options.BlackholePresets,
+		serverOptionBlackholeNetworks: options.BlackholeNetworks,
+		serverOptionRpzAllowlist:      options.RpzAllowlist,
+		serverOptionViews:             options.Views,
+
+		serverOptionDefaultZones:             options.DefaultZones,
+		serverOptionDefaultZonesContent:      options.DefaultZonesContent,
+		serverOptionRootHints:                options.RootHints,
+		serverOptionRootHintsContent:         options.RootHintsContent,
+		serverOptionRootHintsUrl:             options.RootHintsUrl,
+		serverOptionRootHintsRefreshInterval: options.RootHintsRefreshInterval,
+		serverOptionRootHintsRefreshedAt:     options.RootHintsRefreshedAt,
+		serverOptionRootHintsLastError:       options.RootHintsLastError,
+	}
+	for name, value := range values {
+		var encoded []byte
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return
+		}
+		_, err = tx.ExecContext(ctx, `
+			REPLACE INTO server_options(name, value) VALUES(?, ?);
+		`, name, string(encoded))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// PersistRootHintsRefresh persists the content, the refresh time and the error of the root hints alone, the other
+// options changing meanwhile being kept. Nothing is persisted once the root hints are not refreshed from the same url
+// anymore.
+func (s *sqliteServerRepository) PersistRootHintsRefresh(
+	ctx context.Context, options *domain.ServerOptions,
+) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = finishTransaction(err, tx)
+	}()
+
+	for name, value := range map[string]interface{}{
+		serverOptionRootHints:    options.RootHints,
+		serverOptionRootHintsUrl: options.RootHintsUrl,
+	} {
+		var encoded []byte
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return
+		}
+		var stored string
+		err = tx.QueryRowContext(ctx, "SELECT value FROM server_options WHERE name = ?;", name).Scan(&stored)
+		if err == sql.ErrNoRows || err == nil && stored != string(encoded) {
+			err = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	for name, value := range map[string]interface{}{
+		serverOptionRootHintsContent:     options.RootHintsContent,
+		serverOptionRootHintsRefreshedAt: options.RootHintsRefreshedAt,
+		serverOptionRootHintsLastError:   options.RootHintsLastError,
+	} {
+		var encoded []byte
+		encoded, err = json.Marshal(value)
+		if err != nil {
+			return
+		}
+		_, err = tx.ExecContext(ctx, `
+			REPLACE INTO server_options(name, value) VALUES(?, ?);
+		`, name, string(encoded))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (s *sqliteServerRepository) GetAllNegativeTrustAnchors(ctx context.Context) ([]*domain.NegativeTrustAnchor, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, domain, expires_at FROM negative_trust_anchors;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ntas []*domain.NegativeTrustAnchor
+	for rows.Next() {
+		nta, err := s.ntaMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		ntas = append(ntas, nta)
+	}
+	return ntas, nil
+}
+
+func (s *sqliteServerRepository) GetNegativeTrustAnchorByDomain(ctx context.Context, domainName string) (*domain.NegativeTrustAnchor, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, domain, expires_at FROM negative_trust_anchors WHERE domain = ?;", domainName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return s.ntaMapper(rows)
+}
+
+func (s *sqliteServerRepository) PersistNegativeTrustAnchor(ctx context.Context, nta *domain.NegativeTrustAnchor) error {
+	if nta.Id == "" {
+		nta.Id = uuid.NewString()
+	}
+	_, err := s.db.ExecContext(ctx, `
+		REPLACE INTO negative_trust_anchors(id, domain, expires_at) VALUES(?, ?, ?);
+	`, nta.Id, nta.Domain, nta.ExpiresAt.Unix())
+	return err
+}
+
+func (s *sqliteServerRepository) DeleteNegativeTrustAnchor(ctx context.Context, nta *domain.NegativeTrustAnchor) error {
+	if nta == nil {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, "DELETE FROM negative_trust_anchors WHERE id = ?;", nta.Id)
+	return err
+}
+
+func (s *sqliteServerRepository) ntaMapper(rows *sql.Rows) (*domain.NegativeTrustAnchor, error) {
+	nta := &domain.NegativeTrustAnchor{}
+	var expiresAt int64
+	err := rows.Scan(&nta.Id, &nta.Domain, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	nta.ExpiresAt = time.Unix(expiresAt, 0)
+	return nta, nil
+}
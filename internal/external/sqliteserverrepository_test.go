@@ -0,0 +1,65 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"testing"
+)
+
+func TestPersistRootHintsRefreshKeepsTheOtherOptions(t *testing.T) {
+	ctx := context.Background()
+	serverRepo := NewSqliteServerRepository(newTestAuditDb(t))
+	options := domain.NewDefaultServerOptions()
+	options.RootHints = domain.RootHintsRefresh
+	options.RootHintsUrl = domain.DefaultRootHintsUrl
+	err := serverRepo.PersistOptions(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The forwarders are changed through the API while the root hints are downloaded.
+	downloaded, err := serverRepo.GetOptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.Forwarders = []string{"192.0.2.53"}
+	err = serverRepo.PersistOptions(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded.RootHintsContent = ". 3600000 NS a.root-servers.net."
+	err = serverRepo.PersistRootHintsRefresh(ctx, downloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := serverRepo.GetOptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Forwarders) != 1 || stored.Forwarders[0] != "192.0.2.53" {
+		t.Fatalf("expected the forwarders to be kept, got %v", stored.Forwarders)
+	}
+	if stored.RootHintsContent != downloaded.RootHintsContent {
+		t.Fatalf("expected the downloaded root hints, got %q", stored.RootHintsContent)
+	}
+
+	// The root hints are set by hand while downloaded, the download being dropped.
+	options.RootHints = domain.RootHintsCustom
+	options.RootHintsContent = ". 3600000 NS b.root-servers.net."
+	err = serverRepo.PersistOptions(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = serverRepo.PersistRootHintsRefresh(ctx, downloaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err = serverRepo.GetOptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.RootHintsContent != options.RootHintsContent {
+		t.Fatalf("expected the custom root hints to be kept, got %q", stored.RootHintsContent)
+	}
+}
@@ -0,0 +1,67 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+)
+
+type sqliteTSIGKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteTSIGKeyRepository(db *sql.DB) domain.TSIGKeyRepository {
+	return &sqliteTSIGKeyRepository{db: db}
+}
+
+func (r *sqliteTSIGKeyRepository) GetAllTSIGKeys(ctx context.Context) ([]*domain.TSIGKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, algorithm, secret FROM tsig_keys ORDER BY name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.TSIGKey
+	for rows.Next() {
+		key := &domain.TSIGKey{}
+		err := rows.Scan(&key.Name, &key.Algorithm, &key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *sqliteTSIGKeyRepository) GetTSIGKeyByName(ctx context.Context, name string) (*domain.TSIGKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name, algorithm, secret FROM tsig_keys WHERE name = ?;", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	key := &domain.TSIGKey{}
+	err = rows.Scan(&key.Name, &key.Algorithm, &key.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *sqliteTSIGKeyRepository) PersistTSIGKey(ctx context.Context, key *domain.TSIGKey) error {
+	_, err := r.db.ExecContext(ctx, `
+		REPLACE INTO tsig_keys(name, algorithm, secret) VALUES(?, ?, ?);
+	`, key.Name, key.Algorithm, key.Secret)
+	return err
+}
+
+func (r *sqliteTSIGKeyRepository) DeleteTSIGKey(ctx context.Context, key *domain.TSIGKey) error {
+	if key == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM tsig_keys WHERE name = ?;", key.Name)
+	return err
+}
@@ -0,0 +1,108 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/google/uuid"
+)
+
+type sqliteWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewSqliteWebhookRepository(db *sql.DB) domain.WebhookRepository {
+	return &sqliteWebhookRepository{db: db}
+}
+
+func (r *sqliteWebhookRepository) GetAllWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, url, events, zones, record_types, payload_template, content_type, enabled FROM webhooks;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*domain.Webhook
+	for rows.Next() {
+		webhook, err := r.webhookMapper(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *sqliteWebhookRepository) GetWebhookById(ctx context.Context, webhookId string) (*domain.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, url, events, zones, record_types, payload_template, content_type, enabled FROM webhooks
+		WHERE id = ?;
+	`, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, nil
+	}
+	return r.webhookMapper(rows)
+}
+
+func (r *sqliteWebhookRepository) PersistWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	if webhook.Id == "" {
+		webhook.Id = uuid.NewString()
+	}
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+	zones, err := json.Marshal(webhook.Zones)
+	if err != nil {
+		return err
+	}
+	recordTypes, err := json.Marshal(webhook.RecordTypes)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		REPLACE INTO webhooks(id, url, events, zones, record_types, payload_template, content_type, enabled)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?);
+	`, webhook.Id, webhook.Url, string(events), string(zones), string(recordTypes), webhook.PayloadTemplate,
+		webhook.ContentType, webhook.Enabled)
+	return err
+}
+
+func (r *sqliteWebhookRepository) DeleteWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	if webhook == nil {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ?;", webhook.Id)
+	return err
+}
+
+func (r *sqliteWebhookRepository) webhookMapper(rows *sql.Rows) (*domain.Webhook, error) {
+	webhook := &domain.Webhook{}
+	var events, zones, recordTypes string
+	err := rows.Scan(&webhook.Id, &webhook.Url, &events, &zones, &recordTypes, &webhook.PayloadTemplate,
+		&webhook.ContentType, &webhook.Enabled)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(events), &webhook.Events)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(zones), &webhook.Zones)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(recordTypes), &webhook.RecordTypes)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
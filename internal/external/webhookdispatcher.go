@@ -0,0 +1,209 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/pkg/errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	webhookDeliveryTimeout = 10 * time.Second
+	outboxPollInterval     = 5 * time.Second
+	outboxBatchSize        = 100
+	outboxRetention        = 7 * 24 * time.Hour
+	outboxPruneInterval    = time.Hour
+)
+
+var errorWebhookDeleted = errors.New("webhook has been deleted")
+
+type webhookDispatcher struct {
+	webhookRepo domain.WebhookRepository
+	outboxRepo  domain.OutboxRepository
+	client      *http.Client
+
+	listenerLock sync.RWMutex
+	listeners    []domain.ChangeEventListener
+
+	lastPrune      time.Time
+	wakeUp         chan struct{}
+	shutdownSignal chan int
+	stoppedWg      sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a publisher relaying the outbox to the webhooks. Pending events are fanned out to the
+// matching webhooks and every delivery is retried until it succeeds or runs out of attempts. Deliveries are at least
+// once, receivers can deduplicate them with the X-Event-Id header.
+func NewWebhookDispatcher(
+	webhookRepo domain.WebhookRepository, outboxRepo domain.OutboxRepository,
+) domain.EventPublisher {
+	return &webhookDispatcher{
+		webhookRepo:    webhookRepo,
+		outboxRepo:     outboxRepo,
+		client:         &http.Client{Timeout: webhookDeliveryTimeout},
+		wakeUp:         make(chan struct{}, 1),
+		shutdownSignal: make(chan int, 1),
+	}
+}
+
+func (d *webhookDispatcher) Start(ctx context.Context) {
+	d.stoppedWg.Add(1)
+	go func() {
+		defer d.stoppedWg.Done()
+
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			d.relay(ctx)
+			select {
+			case <-d.shutdownSignal:
+				return
+			case <-d.wakeUp:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (d *webhookDispatcher) Notify() {
+	select {
+	case d.wakeUp <- struct{}{}:
+	default:
+	}
+}
+
+func (d *webhookDispatcher) Shutdown(ctx context.Context) error {
+	d.shutdownSignal <- 1
+	d.stoppedWg.Wait()
+	return nil
+}
+
+func (d *webhookDispatcher) Subscribe(listener domain.ChangeEventListener) {
+	d.listenerLock.Lock()
+	defer d.listenerLock.Unlock()
+	d.listeners = append(d.listeners, listener)
+}
+
+func (d *webhookDispatcher) relay(ctx context.Context) {
+	webhooks, err := d.webhookRepo.GetAllWebhooks(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	err = d.dispatchPending(ctx, webhooks)
+	if err != nil {
+		log.Println(err)
+	}
+	err = d.deliverDue(ctx, webhooks)
+	if err != nil {
+		log.Println(err)
+	}
+
+	if time.Since(d.lastPrune) >= outboxPruneInterval {
+		err = d.outboxRepo.Prune(ctx, time.Now().Add(-outboxRetention))
+		if err != nil {
+			log.Println(err)
+		}
+		d.lastPrune = time.Now()
+	}
+}
+
+// dispatchPending hands every pending event to the listeners and queues a delivery for every matching webhook.
+func (d *webhookDispatcher) dispatchPending(ctx context.Context, webhooks []*domain.Webhook) error {
+	events, err := d.outboxRepo.GetPendingEvents(ctx, outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	d.listenerLock.RLock()
+	listeners := d.listeners
+	d.listenerLock.RUnlock()
+
+	for _, event := range events {
+		for _, listener := range listeners {
+			listener.OnChangeEvent(ctx, event)
+		}
+
+		var deliveries []*domain.WebhookDelivery
+		for _, webhook := range webhooks {
+			if webhook.Matches(event) {
+				deliveries = append(deliveries, domain.NewWebhookDelivery(webhook, event))
+			}
+		}
+		err = d.outboxRepo.DispatchEvent(ctx, event, deliveries)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *webhookDispatcher) deliverDue(ctx context.Context, webhooks []*domain.Webhook) error {
+	deliveries, err := d.outboxRepo.GetDueDeliveries(ctx, time.Now(), outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	webhooksById := map[string]*domain.Webhook{}
+	for _, webhook := range webhooks {
+		webhooksById[webhook.Id] = webhook
+	}
+
+	var deliveryWg sync.WaitGroup
+	for _, delivery := range deliveries {
+		deliveryWg.Add(1)
+		go func(delivery *domain.WebhookDelivery) {
+			defer deliveryWg.Done()
+
+			webhook, ok := webhooksById[delivery.WebhookId]
+			if !ok {
+				delivery.Abandon(errorWebhookDeleted)
+			} else if err := d.deliver(webhook, delivery.Event); err != nil {
+				delivery.MarkFailed(err, time.Now())
+				log.Printf("Unable to deliver %v to webhook %v (attempt %v): %v\n", delivery.Event.Type, webhook.Url,
+					delivery.Attempts, err)
+			} else {
+				delivery.MarkDelivered()
+			}
+
+			err := d.outboxRepo.PersistDelivery(ctx, delivery)
+			if err != nil {
+				log.Println(err)
+			}
+		}(delivery)
+	}
+	deliveryWg.Wait()
+	return nil
+}
+
+func (d *webhookDispatcher) deliver(webhook *domain.Webhook, event *domain.ChangeEvent) error {
+	payload, err := webhook.RenderPayload(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", webhook.ContentType)
+	req.Header.Set("X-Event-Id", event.Id)
+	req.Header.Set("X-Event-Type", event.Type)
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	return nil
+}
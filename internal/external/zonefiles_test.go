@@ -0,0 +1,80 @@
+package external
+
+import (
+	"context"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameZoneFilesRenamesTheFilesNamedAfterTheDomain(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+	db := newTestAuditDb(t)
+	zoneRepo := NewSqliteZoneRepository(config, db, db)
+
+	zone := domain.NewZone("example.com")
+	err := zone.RegisterSOA(domain.NewDefaultSOARecord("ns1.example.com.", "admin.example.com."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = zoneRepo.Persist(ctx, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The zone was created before domain.ZoneFileName, its files and its key directory being named after the domain.
+	oldPath := filepath.Join(config.BindFolderPath(), zoneFilePrefix+"example.com")
+	zone.FilePath = oldPath
+	err = zoneRepo.PersistFilePath(ctx, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKeyDirectory := filepath.Join(config.KeysFolderPath(), zoneFilePrefix+"example.com")
+	for _, path := range []string{oldPath, oldPath + "@internal", filepath.Join(oldKeyDirectory, "Kexample.com.key")} {
+		err = writeFile(path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	zones, err := zoneRepo.GetAllZones(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0].FilePath != oldPath {
+		t.Fatalf("expected the zone file stored, %v", oldPath)
+	}
+
+	b := &bind9Server{config: config, zoneRepo: zoneRepo}
+	report := domain.NewConsistencyReport()
+	err = b.renameZoneFiles(ctx, zones, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newName := zoneFilePrefix + domain.ZoneFileName("example.com")
+	newPath := filepath.Join(config.BindFolderPath(), newName)
+	for _, path := range []string{newPath, newPath + "@internal", filepath.Join(config.KeysFolderPath(), newName)} {
+		if !fileExists(path) {
+			t.Errorf("expected %v to exist", path)
+		}
+	}
+	for _, path := range []string{oldPath, oldPath + "@internal", oldKeyDirectory} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %v to be renamed", path)
+		}
+	}
+	if len(report.Actions) != 1 || report.Actions[0].Action != domain.ConsistencyActionRenamed {
+		t.Fatalf("expected the zone to be reported renamed, got %v actions", len(report.Actions))
+	}
+
+	renamed, err := zoneRepo.GetZoneByDomain(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.FilePath != newPath {
+		t.Fatalf("expected the zone file %v, got %v", newPath, renamed.FilePath)
+	}
+}
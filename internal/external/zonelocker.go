@@ -0,0 +1,51 @@
+package external
+
+import (
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"sync"
+)
+
+type zoneLock struct {
+	sync.Mutex
+	waiters int
+}
+
+type zoneLocker struct {
+	lock  sync.Mutex
+	zones map[string]*zoneLock
+}
+
+// NewZoneLocker creates a locker serializing the changes of a zone within this process, the lock of a zone being
+// dropped once no change of it is in progress.
+func NewZoneLocker() domain.ZoneLocker {
+	return &zoneLocker{zones: map[string]*zoneLock{}}
+}
+
+func (l *zoneLocker) Lock(domainName string) func() {
+	key := domain.NormalizeDomain(domainName)
+
+	l.lock.Lock()
+	zone, ok := l.zones[key]
+	if !ok {
+		zone = &zoneLock{}
+		l.zones[key] = zone
+	}
+	zone.waiters++
+	l.lock.Unlock()
+
+	zone.Lock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			zone.Unlock()
+
+			l.lock.Lock()
+			zone.waiters--
+			if zone.waiters == 0 {
+				delete(l.zones, key)
+			}
+			l.lock.Unlock()
+		})
+	}
+}
@@ -1,52 +1,330 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
+	dnsservermanager "github.com/anantadwi13/dns-server-manager"
 	"github.com/anantadwi13/dns-server-manager/internal/domain"
 	"github.com/anantadwi13/dns-server-manager/internal/external"
 	"github.com/labstack/echo/v4"
+	echolog "github.com/labstack/gommon/log"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 type service struct {
-	config         domain.Config
-	apiServer      *echo.Echo
-	db             *sql.DB
-	migration      domain.Migration
-	zoneRepository domain.ZoneRepository
-	bindHelper     domain.DNSServer
-	shutdownWg     sync.WaitGroup
+	config             domain.Config
+	apiServer          *echo.Echo
+	db                 *sql.DB
+	readDb             *sql.DB
+	migration          domain.Migration
+	cluster            domain.ClusterRegistry
+	zoneRepository     domain.ZoneRepository
+	zoneLocks          domain.ZoneLocker
+	serverRepository   domain.ServerRepository
+	rpzRepository      domain.RpzRepository
+	fragmentRepository domain.FragmentRepository
+	tsigKeyRepository  domain.TSIGKeyRepository
+	usageRepository    domain.RecordUsageRepository
+	webhookRepository  domain.WebhookRepository
+	outboxRepository   domain.OutboxRepository
+	auditLogRepository domain.AuditLogRepository
+	archiveRepository  domain.ZoneArchiveRepository
+	registrations      domain.RegistrationLookup
+	bindHelper         domain.DNSServer
+	forwarders         domain.ForwarderMonitor
+	aliases            domain.AliasResolver
+	notifies           domain.NotifyScheduler
+	failover           domain.FailoverWatchdog
+	serials            domain.SerialChecker
+	faults             domain.FaultInjector
+	queryStats         domain.QueryStatistics
+	canaries           domain.CanaryMonitor
+	auditExporter      domain.AuditLogExporter
+	rpzFeedUpdater     domain.RpzFeedUpdater
+	rootHintsUpdater   domain.RootHintsUpdater
+	events             domain.EventPublisher
+	metrics            domain.Metrics
+	settings           domain.SettingsProvider
+	localizer          domain.Localizer
+	consistencyReport  *domain.ConsistencyReport
+	shutdownWg         sync.WaitGroup
 }
 
+const (
+	forwarderProbeInterval   = 30 * time.Second
+	failoverProbeInterval    = 5 * time.Second
+	clusterHeartbeatInterval = 10 * time.Second
+	aliasRefreshInterval     = 5 * time.Minute
+	notifyCheckInterval      = 30 * time.Second
+	serialCheckInterval      = time.Minute
+)
+
 func NewService(config domain.Config) *service {
 	return &service{config: config}
 }
 
+// RestoreRecoveryBundle imports a bundle exported by ExportRecoveryBundle into the database of a new manager, which
+// must not hold any zone yet, failing with domain.ErrorRecoveryTargetNotEmpty otherwise, e.g. once restored already.
+// The bundle is restored into a database of its own moved in place once complete, a failed restore leaving the
+// database as it was to be run again. The manager writes the configs of named out of it on its next start.
+func RestoreRecoveryBundle(ctx context.Context, config domain.Config, reader io.Reader, passphrase string) error {
+	bundle, err := domain.DecodeRecoveryBundle(reader, passphrase)
+	if err != nil {
+		return err
+	}
+	if bundle.SchemaVersion > external.NewSqliteMigration(nil).SchemaVersion() {
+		return domain.ErrorRecoveryBundleSchema
+	}
+
+	err = os.MkdirAll(config.DataFolderPath(), 0777)
+	if err != nil {
+		return err
+	}
+	holdsZones, err := databaseHoldsZones(ctx, config)
+	if err != nil {
+		return err
+	}
+	if holdsZones {
+		return domain.ErrorRecoveryTargetNotEmpty
+	}
+
+	restorePath := config.DBPath() + ".restoring"
+	err = os.Remove(restorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = restoreRecoveryBundle(ctx, config, restorePath, bundle)
+	if err != nil {
+		if errRemove := os.Remove(restorePath); errRemove != nil && !os.IsNotExist(errRemove) {
+			log.Println(errRemove)
+		}
+		return err
+	}
+	return os.Rename(restorePath, config.DBPath())
+}
+
+// databaseHoldsZones reports whether the database of the manager holds zones, false when it does not exist yet.
+func databaseHoldsZones(ctx context.Context, config domain.Config) (bool, error) {
+	if _, err := os.Stat(config.DBPath()); os.IsNotExist(err) {
+		return false, nil
+	}
+	db, err := sql.Open("sqlite3", config.DBPath())
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+	err = external.NewSqliteMigration(db).Migrate(ctx)
+	if err != nil {
+		return false, err
+	}
+	zones, err := external.NewSqliteZoneRepository(config, db, db).GetAllZones(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(zones) > 0, nil
+}
+
+// restoreRecoveryBundle writes the bundle into a new database at dbPath.
+func restoreRecoveryBundle(
+	ctx context.Context, config domain.Config, dbPath string, bundle *domain.RecoveryBundle,
+) error {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	err = external.NewSqliteMigration(db).Migrate(ctx)
+	if err != nil {
+		return err
+	}
+	zoneRepository := external.NewSqliteZoneRepository(config, db, db)
+
+	// The usage of the records is not part of the bundle, it is tracked again from now on.
+	if bundle.Options.QueryLog {
+		bundle.Options.QueryLogSince = time.Now()
+	}
+	serverRepository := external.NewSqliteServerRepository(db)
+	err = serverRepository.PersistOptions(ctx, bundle.Options)
+	if err != nil {
+		return err
+	}
+	tsigKeyRepository := external.NewSqliteTSIGKeyRepository(db)
+	for _, key := range bundle.TSIGKeys {
+		err = tsigKeyRepository.PersistTSIGKey(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+	fragmentRepository := external.NewSqliteFragmentRepository(db)
+	for _, fragment := range bundle.Fragments {
+		err = fragmentRepository.PersistFragment(ctx, fragment)
+		if err != nil {
+			return err
+		}
+	}
+	rpzRepository := external.NewSqliteRpzRepository(db)
+	for _, profile := range bundle.RpzProfiles {
+		err = rpzRepository.PersistProfile(ctx, profile)
+		if err != nil {
+			return err
+		}
+	}
+	for _, feed := range bundle.RpzFeeds {
+		// The domains of the feeds are not part of the bundle, the feeds are downloaded again once started.
+		feed.LastRefreshed, feed.LastAttempted, feed.LastError, feed.DomainCount = time.Time{}, time.Time{}, "", 0
+		err = rpzRepository.PersistFeed(ctx, feed)
+		if err != nil {
+			return err
+		}
+	}
+	// The zones are checked like the changes made through the API, the fragments they include being restored already.
+	checker := external.NewBind9Server(
+		config, external.NewFileSettingsProvider(config), zoneRepository, serverRepository, rpzRepository,
+		fragmentRepository, tsigKeyRepository, nil, external.NewAliasResolver(zoneRepository, aliasRefreshInterval, nil),
+	)
+	for _, zone := range bundle.Zones {
+		err = checker.CheckZone(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("%v: %w", zone.Domain, err)
+		}
+	}
+	for _, zone := range bundle.Zones {
+		// The bind folder of the new host may differ from the one of the exported manager.
+		zone.FilePath = ""
+		zone.AddEvent(domain.NewZoneEvent(domain.EventZoneRestored, zone))
+		err = zoneRepository.Persist(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("%v: %w", zone.Domain, err)
+		}
+	}
+	return nil
+}
+
 func (s *service) Start() {
 	ctx := context.Background()
 	signalOS := make(chan os.Signal, 1)
-	signal.Notify(signalOS, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(signalOS, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	s.registerDependencies(ctx)
 
 	s.loadBindService(ctx)
 
+	s.forwarders.Start(ctx)
+	s.aliases.Start(ctx)
+	s.notifies.Start(ctx)
+	s.failover.Start(ctx)
+	s.serials.Start(ctx)
+	s.queryStats.Start(ctx)
+	s.canaries.Start(ctx)
+	s.auditExporter.Start(ctx)
+	s.rpzFeedUpdater.Start(ctx)
+	s.rootHintsUpdater.Start(ctx)
+	s.events.Start(ctx)
+
 	s.loadAPIServer(ctx)
 
-	select {
-	case <-signalOS:
+	for sig := range signalOS {
+		if sig == syscall.SIGHUP {
+			s.reloadSettings(ctx)
+			continue
+		}
 		log.Println("Service is stopping")
 		s.gracefulShutdown(ctx)
-		s.shutdownWg.Wait()
 		log.Println("Service is stopped")
+		return
+	}
+}
+
+func (s *service) reloadSettings(ctx context.Context) {
+	_, err := s.settings.Reload(ctx)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println("Settings are reloaded")
+}
+
+// readOnlyRequests lets the repositories serve GET requests from the read replica, they never write.
+func readOnlyRequests(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Method == http.MethodGet || c.Request().Method == http.MethodHead {
+			c.SetRequest(c.Request().WithContext(domain.WithReadOnly(c.Request().Context())))
+		}
+		return next(c)
+	}
+}
+
+// refuseOutdatedChanges rejects the changes once a newer instance migrated the shared database, this instance could
+// write data the newer schema does not expect. Reads keep being served.
+func (s *service) refuseOutdatedChanges(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Method == http.MethodGet || c.Request().Method == http.MethodHead {
+			return next(c)
+		}
+		err := s.migration.CheckSchema(c.Request().Context())
+		if errors.Is(err, domain.ErrorSchemaOutdated) {
+			return responseUnavailable(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		return next(c)
+	}
+}
+
+const translateContextKey = "translate"
+
+// negotiateLanguage lets the responses translate their message to the language preferred by the client.
+func (s *service) negotiateLanguage(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		language := domain.NegotiateLanguage(c.Request().Header.Get("Accept-Language"), s.localizer.Languages())
+		c.Set(translateContextKey, func(message string) string {
+			return s.localizer.Translate(language, message)
+		})
+		c.Response().Header().Add("Vary", "Accept-Language")
+		c.Response().Header().Set("Content-Language", language)
+		return next(c)
+	}
+}
+
+// OnSettingsChanged applies the log level of the settings to the API server.
+func (s *service) OnSettingsChanged(settings *domain.Settings) {
+	levels := map[string]echolog.Lvl{
+		domain.LogLevelDebug: echolog.DEBUG,
+		domain.LogLevelInfo:  echolog.INFO,
+		domain.LogLevelWarn:  echolog.WARN,
+		domain.LogLevelError: echolog.ERROR,
+	}
+	s.apiServer.Logger.SetLevel(levels[settings.LogLevel])
+}
+
+// logRequests logs every API request when the log level is debug.
+func (s *service) logRequests(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		if s.settings.Settings().LogLevel == domain.LogLevelDebug {
+			if err != nil {
+				c.Error(err)
+			}
+			s.apiServer.Logger.Debugf("%v %v %v %v %v", c.RealIP(), c.Request().Method, c.Request().URL.Path,
+				c.Response().Status, time.Since(start))
+			return nil
+		}
+		return err
 	}
 }
 
@@ -54,7 +332,20 @@ func (s *service) registerDependencies(ctx context.Context) {
 	s.apiServer = echo.New()
 	s.apiServer.HideBanner = true
 
-	err := os.MkdirAll(s.config.DataFolderPath(), 0777)
+	s.settings = external.NewFileSettingsProvider(s.config)
+	s.settings.Subscribe(s)
+	_, err := s.settings.Reload(ctx)
+	if err != nil {
+		log.Panicln(err)
+	}
+	s.localizer, err = external.NewCatalogLocalizer(dnsservermanager.Assets, "web/locales")
+	if err != nil {
+		log.Panicln(err)
+	}
+	s.apiServer.Use(s.logRequests, external.NewRateLimitMiddleware(s.settings), readOnlyRequests, s.negotiateLanguage,
+		s.refuseOutdatedChanges)
+
+	err = os.MkdirAll(s.config.DataFolderPath(), 0777)
 	if err != nil {
 		log.Panicln(err)
 	}
@@ -63,19 +354,106 @@ func (s *service) registerDependencies(ctx context.Context) {
 		log.Panicln(err)
 	}
 
+	s.readDb = s.db
+	if s.config.DBReadDSN() != "" {
+		s.readDb, err = sql.Open("sqlite3", s.config.DBReadDSN())
+		if err != nil {
+			log.Panicln(err)
+		}
+	}
+
 	s.migration = external.NewSqliteMigration(s.db)
 	err = s.migration.Migrate(ctx)
 	if err != nil {
 		log.Panicln(err)
 	}
 
-	s.zoneRepository = external.NewSqliteZoneRepository(s.config, s.db)
+	s.cluster = external.NewSqliteClusterRegistry(s.db, clusterHeartbeatInterval)
+	err = s.cluster.Start(ctx)
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	// The forwarder monitor is created later on, its statuses are read once the metrics are rendered.
+	s.metrics = external.NewPrometheusMetrics(s.config, s.db, func() []*domain.ForwarderStatus {
+		return s.forwarders.Statuses()
+	})
+
+	// Injected repository errors go through the instrumentation, showing up in the metrics like real ones.
+	zoneRepository := external.NewSqliteZoneRepository(s.config, s.db, s.readDb)
+	if s.config.ChaosMode() {
+		log.Println("Chaos mode is enabled, faults can be injected through the API")
+		s.faults = external.NewFaultInjector(s.config)
+		zoneRepository = external.NewFaultyZoneRepository(zoneRepository, s.faults)
+	}
+	s.zoneRepository = external.NewInstrumentedZoneRepository(zoneRepository, s.metrics)
+	s.zoneLocks = external.NewZoneLocker()
+	s.serverRepository = external.NewSqliteServerRepository(s.db)
+	s.rpzRepository = external.NewSqliteRpzRepository(s.db)
+	s.fragmentRepository = external.NewSqliteFragmentRepository(s.db)
+	s.tsigKeyRepository = external.NewSqliteTSIGKeyRepository(s.db)
+	s.usageRepository = external.NewSqliteRecordUsageRepository(s.db)
+	s.webhookRepository = external.NewSqliteWebhookRepository(s.db)
+	s.outboxRepository = external.NewSqliteOutboxRepository(s.db)
+	s.auditLogRepository = external.NewSqliteAuditLogRepository(s.db)
+	s.archiveRepository = external.NewSqliteZoneArchiveRepository(s.config, s.db)
+
+	s.forwarders = external.NewForwarderMonitor(s.serverRepository, forwarderProbeInterval,
+		func(ctx context.Context) error {
+			return s.bindHelper.UpdateAndReload(ctx)
+		})
+
+	s.aliases = external.NewAliasResolver(s.zoneRepository, aliasRefreshInterval, func(ctx context.Context) error {
+		return s.bindHelper.UpdateAndReload(ctx)
+	})
+
+	s.notifies = external.NewNotifyScheduler(s.zoneRepository, notifyCheckInterval, func(ctx context.Context) error {
+		return s.bindHelper.UpdateAndReload(ctx)
+	})
+
+	s.bindHelper = external.NewBind9Server(
+		s.config, s.settings, s.zoneRepository, s.serverRepository, s.rpzRepository, s.fragmentRepository,
+		s.tsigKeyRepository, s.forwarders, s.aliases,
+	)
+	if s.faults != nil {
+		s.bindHelper = external.NewFaultyDNSServer(s.bindHelper, s.faults)
+	}
+
+	s.rpzFeedUpdater = external.NewRpzFeedUpdater(s.config, s.rpzRepository, func(ctx context.Context) error {
+		return s.bindHelper.UpdateAndReload(ctx)
+	})
+
+	s.rootHintsUpdater = external.NewRootHintsUpdater(s.serverRepository, func(ctx context.Context) error {
+		return s.bindHelper.UpdateAndReload(ctx)
+	})
+
+	s.events = external.NewWebhookDispatcher(
+		external.NewSettingsWebhookRepository(s.webhookRepository, s.settings), s.outboxRepository,
+	)
+	s.auditExporter = external.NewAuditLogExporter(s.auditLogRepository)
+	s.events.Subscribe(s.auditExporter)
+	s.events.Subscribe(external.NewChangeBurstDetector(s.settings, s.auditLogRepository, s.outboxRepository))
 
-	s.bindHelper = external.NewBind9Server(s.config, s.zoneRepository)
+	s.queryStats = external.NewQueryStatistics(s.zoneRepository, s.serverRepository, s.usageRepository)
+	s.bindHelper.SubscribeQueryLog(s.queryStats)
+	s.events.Subscribe(s.queryStats)
+	s.canaries = external.NewCanaryMonitor(s.zoneRepository, s.outboxRepository)
+	s.bindHelper.SubscribeQueryLog(s.canaries)
+
+	s.registrations = external.NewRdapLookup()
+
+	s.failover = external.NewFailoverWatchdog(s.settings, failoverProbeInterval)
+
+	s.serials = external.NewSerialChecker(s.settings, s.zoneRepository, serialCheckInterval)
 }
 
 func (s *service) loadBindService(ctx context.Context) {
-	err := s.bindHelper.UpdateAndReload(ctx)
+	report, err := s.bindHelper.RepairConsistency(ctx, s.settings.Settings().RemoveUnknownZoneFiles)
+	if err != nil {
+		log.Panicln(err)
+	}
+	s.consistencyReport = report
+	err = s.bindHelper.Reload(ctx)
 	if err != nil {
 		log.Panicln(err)
 	}
@@ -85,36 +463,20 @@ func (s *service) loadAPIServer(ctx context.Context) {
 	go func() {
 		external.RegisterHandlers(s.apiServer, s)
 		s.apiServer.GET("/specs", func(c echo.Context) error {
-			return c.File("./specification.yaml")
+			return serveAsset(c, "specification.yaml", "application/yaml")
+		})
+		s.apiServer.GET("/metrics", func(c echo.Context) error {
+			var buf bytes.Buffer
+			err := s.metrics.Write(c.Request().Context(), &buf)
+			if err != nil {
+				return err
+			}
+			return c.Blob(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
 		})
 		s.apiServer.GET("/docs", func(c echo.Context) error {
-			return c.HTML(http.StatusOK, `
-			<!DOCTYPE html>
-			<html>
-			  <head>
-				<title>DNS Server Manager</title>
-				<!-- needed for adaptive design -->
-				<meta charset="utf-8"/>
-				<meta name="viewport" content="width=device-width, initial-scale=1">
-				<link href="https://fonts.googleapis.com/css?family=Montserrat:300,400,700|Roboto:300,400,700" rel="stylesheet">
-			
-				<!--
-				ReDoc doesn't change outer page styles
-				-->
-				<style>
-				  body {
-					margin: 0;
-					padding: 0;
-				  }
-				</style>
-			  </head>
-			  <body>
-				<redoc spec-url='/specs'></redoc>
-				<script src="https://cdn.jsdelivr.net/npm/redoc@next/bundles/redoc.standalone.js"> </script>
-			  </body>
-			</html>
-		`)
+			return serveAsset(c, "web/docs.html", echo.MIMETextHTMLCharsetUTF8)
 		})
+		s.apiServer.GET("/status", s.getStatusPage)
 		err := s.apiServer.Start(":5555")
 		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("shutting down the server %v\n", err)
@@ -123,33 +485,130 @@ func (s *service) loadAPIServer(ctx context.Context) {
 }
 
 func (s *service) gracefulShutdown(ctx context.Context) {
+	// The instance is unregistered before the database is closed.
+	err := s.cluster.Shutdown(ctx)
+	if err != nil {
+		log.Println(err)
+	}
+
+	s.shutdownWg.Add(13)
 	go func() {
-		s.shutdownWg.Add(1)
 		defer s.shutdownWg.Done()
-		err := s.bindHelper.Shutdown(ctx)
+		err := s.forwarders.Shutdown(ctx)
 		if err != nil {
 			log.Fatalln(err)
 		}
 	}()
 	go func() {
-		s.shutdownWg.Add(1)
 		defer s.shutdownWg.Done()
-		err := s.apiServer.Shutdown(ctx)
+		err := s.aliases.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.notifies.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.failover.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.serials.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.queryStats.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.canaries.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.auditExporter.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.rpzFeedUpdater.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.rootHintsUpdater.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.events.Shutdown(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
+	go func() {
+		defer s.shutdownWg.Done()
+		err := s.bindHelper.Shutdown(ctx)
 		if err != nil {
 			log.Fatalln(err)
 		}
 	}()
 	go func() {
-		s.shutdownWg.Add(1)
 		defer s.shutdownWg.Done()
-		err := s.db.Close()
+		err := s.apiServer.Shutdown(ctx)
 		if err != nil {
 			log.Fatalln(err)
 		}
 	}()
+
+	// The database is closed once every component stopped, a refresh or a delivery in progress using it until then.
+	s.shutdownWg.Wait()
+	err = s.db.Close()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if s.readDb != s.db {
+		err = s.readDb.Close()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
 }
 
-func (s *service) GetRecords(c echo.Context, domainName string) error {
+func (s *service) GetRecords(c echo.Context, domainName string, params external.GetRecordsParams) error {
+	page, err := pageOf(params.Limit, params.Offset)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	selectors, err := labelSelectors(params.Label)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
 	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
 	if err != nil {
 		return responseServerErr(c, err)
@@ -158,12 +617,23 @@ func (s *service) GetRecords(c echo.Context, domainName string) error {
 		return responseNotFound(c, "zone is not found")
 	}
 
-	var recordsRes = make([]*external.RecordRes, 0)
+	var records []*domain.Record
 	for _, record := range zone.Records {
-		recordsRes = append(recordsRes, recordMapper(record))
+		if params.ExternalId != nil && record.ExternalId != *params.ExternalId {
+			continue
+		}
+		if !record.MatchesLabels(selectors) {
+			continue
+		}
+		records = append(records, record)
 	}
 
-	return c.JSON(http.StatusOK, recordsRes)
+	res := &external.RecordPageRes{Total: len(records), Records: make([]external.RecordRes, 0)}
+	start, end := page.Bounds(len(records))
+	for _, record := range records[start:end] {
+		res.Records = append(res.Records, *recordMapper(record))
+	}
+	return c.JSON(http.StatusOK, res)
 }
 
 func (s *service) CreateRecord(c echo.Context, domainName string) error {
@@ -173,9 +643,7 @@ func (s *service) CreateRecord(c echo.Context, domainName string) error {
 		return responseClientErr(c, err)
 	}
 
-	if req.Name == "" || req.Type == "" || req.Value == "" {
-		return responseClientErr(c, errors.New("make sure name, type, value are set"))
-	}
+	defer s.zoneLocks.Lock(domainName)()
 
 	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
 	if err != nil {
@@ -185,27 +653,68 @@ func (s *service) CreateRecord(c echo.Context, domainName string) error {
 		return responseNotFound(c, "zone is not found")
 	}
 
-	record := domain.NewRecord(req.Name, string(req.Type), req.Value)
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
 
-	err = zone.AddRecord(record)
+	previousWarnings := zone.Warnings()
+	previousNameServers := zone.NameServers()
+
+	record, err := createRecordFromReq(zone, (*external.RecordReq)(req))
 	if err != nil {
 		return responseClientErr(c, err)
 	}
 
-	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	err = s.checkViews(c.Request().Context(), zone)
+	if errors.Is(err, domain.ErrorUnknownView) {
+		return responseClientErr(c, err)
+	}
 	if err != nil {
 		return responseServerErr(c, err)
 	}
 
-	err = s.bindHelper.UpdateAndReload(c.Request().Context())
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewRecordEvent(domain.EventRecordCreated, zone, record, nil).WithChange(change))
+
+	err = s.zoneRepository.Persist(c.Request().Context(), zone)
 	if err != nil {
 		return responseServerErr(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, recordMapper(record))
+	if warning := s.syncPTR(c.Request().Context(), zone, nil, record, change); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyRecordChanges(c.Request().Context(), zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	warnings = append(warnings, s.zoneSizeWarnings(zone)...)
+
+	if warning := s.publishNameServerChange(c.Request().Context(), zone, previousNameServers); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	recordRes := recordMapper(record)
+	recordRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, recordRes)
 }
 
 func (s *service) DeleteRecord(c echo.Context, domainName string, recordId string) error {
+	defer s.zoneLocks.Lock(domainName)()
+
 	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
 	if err != nil {
 		return responseServerErr(c, err)
@@ -214,27 +723,70 @@ func (s *service) DeleteRecord(c echo.Context, domainName string, recordId strin
 		return responseNotFound(c, "zone is not found")
 	}
 
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
 	record := zone.FindRecordyById(recordId)
 	if record == nil {
 		return responseNotFound(c, "record is not found")
 	}
+	err = record.CheckEditable()
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	previousWarnings := zone.Warnings()
+	previousNameServers := zone.NameServers()
 
 	err = zone.DeleteRecord(record)
 	if err != nil {
 		return responseClientErr(c, err)
 	}
-
-	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	err = zone.CheckNameServers(previousNameServers)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseClientErr(c, err)
 	}
+	primaryMoved := zone.FollowNameServerChange(record, nil)
 
-	err = s.bindHelper.UpdateAndReload(c.Request().Context())
+	_, err = zone.CheckWarnings(previousWarnings)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseClientErr(c, err)
 	}
 
-	return responseOk(c, "OK")
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewRecordEvent(domain.EventRecordDeleted, zone, record, nil).WithChange(change))
+	if primaryMoved {
+		zone.AddEvent(domain.NewZoneEvent(domain.EventZoneUpdated, zone).WithChange(change))
+	}
+
+	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	var warnings []*domain.ValidationWarning
+	if warning := s.syncPTR(c.Request().Context(), zone, record, nil, change); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyRecordChanges(c.Request().Context(), zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := s.publishNameServerChange(c.Request().Context(), zone, previousNameServers); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	return responseOkWithWarnings(c, warnings)
 }
 
 func (s *service) GetRecordById(c echo.Context, domainName string, recordId string) error {
@@ -254,6 +806,37 @@ func (s *service) GetRecordById(c echo.Context, domainName string, recordId stri
 	return c.JSON(http.StatusOK, recordMapper(record))
 }
 
+func (s *service) GetRecordHistory(
+	c echo.Context, domainName string, recordId string, params external.GetRecordHistoryParams,
+) error {
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	filter := domain.AuditLogFilter{Zone: zone.Domain, RecordId: recordId}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	events, err := s.auditLogRepository.GetAuditLogs(c.Request().Context(), filter)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	// The history of a deleted record is kept, a record which never existed has none.
+	if len(events) == 0 && zone.FindRecordyById(recordId) == nil {
+		return responseNotFound(c, "record is not found")
+	}
+
+	historyRes := make([]*external.AuditLogRes, 0)
+	for _, event := range events {
+		historyRes = append(historyRes, auditLogMapper(event))
+	}
+	return c.JSON(http.StatusOK, historyRes)
+}
+
 func (s *service) UpdateRecord(c echo.Context, domainName string, recordId string) error {
 	req := new(external.UpdateRecordJSONRequestBody)
 
@@ -262,6 +845,8 @@ func (s *service) UpdateRecord(c echo.Context, domainName string, recordId strin
 		return responseClientErr(c, err)
 	}
 
+	defer s.zoneLocks.Lock(domainName)()
+
 	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
 	if err != nil {
 		return responseServerErr(c, err)
@@ -270,98 +855,249 @@ func (s *service) UpdateRecord(c echo.Context, domainName string, recordId strin
 		return responseNotFound(c, "zone is not found")
 	}
 
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
 	record := zone.FindRecordyById(recordId)
 	if record == nil {
 		return responseNotFound(c, "record is not found")
 	}
+	previousRecord := *record
+	previousWarnings := zone.Warnings()
+	previousNameServers := zone.NameServers()
 
-	if req.Name != "" {
-		record.Name = req.Name
+	err = updateRecordFromReq(zone, record, (*external.RecordReq)(req))
+	if err != nil {
+		return responseClientErr(c, err)
 	}
-	if req.Type != "" {
-		record.Type = string(req.Type)
+
+	err = s.checkViews(c.Request().Context(), zone)
+	if errors.Is(err, domain.ErrorUnknownView) {
+		return responseClientErr(c, err)
 	}
-	if req.Value != "" {
-		record.Value = req.Value
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	err = zone.CheckNameServers(previousNameServers)
+	if err != nil {
+		return responseClientErr(c, err)
 	}
+	primaryMoved := zone.FollowNameServerChange(&previousRecord, record)
 
-	if !record.IsValid() {
-		return responseClientErr(c, errors.New("record is not valid"))
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
 	}
 
-	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	err = s.checkZoneChange(c.Request().Context(), zone)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseZoneCheckErr(c, err)
 	}
 
-	err = s.bindHelper.UpdateAndReload(c.Request().Context())
+	zone.AddEvent(domain.NewRecordEvent(domain.EventRecordUpdated, zone, record, &previousRecord).WithChange(change))
+	if primaryMoved {
+		zone.AddEvent(domain.NewZoneEvent(domain.EventZoneUpdated, zone).WithChange(change))
+	}
+
+	err = s.zoneRepository.Persist(c.Request().Context(), zone)
 	if err != nil {
 		return responseServerErr(c, err)
 	}
 
-	return c.JSON(http.StatusOK, recordMapper(record))
-}
+	if warning := s.syncPTR(c.Request().Context(), zone, &previousRecord, record, change); warning != nil {
+		warnings = append(warnings, warning)
+	}
 
-func (s *service) GetZones(c echo.Context) error {
-	zones, err := s.zoneRepository.GetAllZones(c.Request().Context())
-	if err != nil {
-		return err
+	s.events.Notify()
+
+	if warning := s.applyRecordChanges(c.Request().Context(), zone); warning != nil {
+		warnings = append(warnings, warning)
 	}
 
-	zonesRes := make([]*external.ZoneRes, 0)
-	for _, zone := range zones {
-		zonesRes = append(zonesRes, zoneMapper(zone))
+	if warning := s.publishNameServerChange(c.Request().Context(), zone, previousNameServers); warning != nil {
+		warnings = append(warnings, warning)
 	}
-	return c.JSON(http.StatusOK, zonesRes)
+
+	recordRes := recordMapper(record)
+	recordRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusOK, recordRes)
 }
 
-func (s *service) CreateZone(c echo.Context) error {
-	req := new(external.CreateZoneJSONRequestBody)
+// BatchRecords applies the operations to the zone in memory first, the zone is only persisted and reloaded once when
+// every operation succeeded.
+func (s *service) BatchRecords(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
 
-	if err := c.Bind(req); err != nil {
+	req := new(external.BatchRecordsJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
 		return responseClientErr(c, err)
 	}
-
-	if req.Domain == "" || req.PrimaryNs == "" || req.MailAddr == "" {
-		return responseClientErr(c, errors.New("make sure domain, primary_ns, and mail_addr are set"))
+	if len(req.Operations) == 0 {
+		return responseClientErr(c, errors.New("operations are required"))
 	}
 
-	zoneExist, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), req.Domain)
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
 	if err != nil {
 		return responseServerErr(c, err)
 	}
-	if zoneExist != nil {
-		return responseClientErr(c, errors.New("zone already exists"))
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
 	}
 
-	zone := domain.NewZone(req.Domain)
-
-	err = zone.RegisterSOA(domain.NewDefaultSOARecord(req.PrimaryNs, req.MailAddr))
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
 	if err != nil {
 		return responseClientErr(c, err)
 	}
 
-	err = zone.AddRecord(domain.NewNSRecord("@", req.PrimaryNs))
+	previousWarnings := zone.Warnings()
+	previousNameServers := zone.NameServers()
+
+	var (
+		previousRecords = make([]*domain.Record, len(req.Operations))
+		records         = make([]*domain.Record, len(req.Operations))
+		primaryMoved    = false
+	)
+	for i, operation := range req.Operations {
+		switch operation.Action {
+		case external.RecordOperationActionCreate, external.RecordOperationActionUpdate,
+			external.RecordOperationActionDelete:
+		default:
+			return responseClientErr(c, fmt.Errorf("operation %v: action is not valid", i))
+		}
+
+		var record *domain.Record
+		if operation.Action != external.RecordOperationActionCreate {
+			if operation.RecordId != nil {
+				record = zone.FindRecordyById(*operation.RecordId)
+			}
+			if record == nil {
+				return responseClientErr(c, fmt.Errorf("operation %v: record is not found", i))
+			}
+			err = record.CheckEditable()
+			if err != nil {
+				return responseClientErr(c, fmt.Errorf("operation %v: %w", i, err))
+			}
+			previousRecord := *record
+			previousRecords[i] = &previousRecord
+		}
+
+		switch operation.Action {
+		case external.RecordOperationActionCreate, external.RecordOperationActionUpdate:
+			if operation.Record == nil {
+				return responseClientErr(c, fmt.Errorf("operation %v: record is required", i))
+			}
+			if record == nil {
+				record, err = createRecordFromReq(zone, operation.Record)
+			} else {
+				err = updateRecordFromReq(zone, record, operation.Record)
+			}
+			records[i] = record
+		case external.RecordOperationActionDelete:
+			err = zone.DeleteRecord(record)
+		}
+		if err != nil {
+			return responseClientErr(c, fmt.Errorf("operation %v: %w", i, err))
+		}
+		if zone.FollowNameServerChange(previousRecords[i], records[i]) {
+			primaryMoved = true
+		}
+	}
+
+	err = s.checkViews(ctx, zone)
+	if errors.Is(err, domain.ErrorUnknownView) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = zone.CheckNameServers(previousNameServers)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	warnings, err := zone.CheckWarnings(previousWarnings)
 	if err != nil {
 		return responseClientErr(c, err)
 	}
 
-	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	err = s.checkZoneChange(c.Request().Context(), zone)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseZoneCheckErr(c, err)
 	}
 
-	err = s.bindHelper.UpdateAndReload(c.Request().Context())
+	for i, operation := range req.Operations {
+		switch operation.Action {
+		case external.RecordOperationActionCreate:
+			zone.AddEvent(domain.NewRecordEvent(domain.EventRecordCreated, zone, records[i], nil).WithChange(change))
+		case external.RecordOperationActionUpdate:
+			zone.AddEvent(
+				domain.NewRecordEvent(domain.EventRecordUpdated, zone, records[i], previousRecords[i]).WithChange(change),
+			)
+		case external.RecordOperationActionDelete:
+			zone.AddEvent(
+				domain.NewRecordEvent(domain.EventRecordDeleted, zone, previousRecords[i], nil).WithChange(change),
+			)
+		}
+	}
+	if primaryMoved {
+		zone.AddEvent(domain.NewZoneEvent(domain.EventZoneUpdated, zone).WithChange(change))
+	}
+
+	err = s.zoneRepository.Persist(ctx, zone)
 	if err != nil {
 		return responseServerErr(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, zoneMapper(zone))
+	for i := range req.Operations {
+		if warning := s.syncPTR(ctx, zone, previousRecords[i], records[i], change); warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyRecordChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	warnings = append(warnings, s.zoneSizeWarnings(zone)...)
+
+	if warning := s.publishNameServerChange(ctx, zone, previousNameServers); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	batchRes := &external.RecordBatchRes{Records: make([]external.RecordRes, 0, len(req.Operations))}
+	for i := range req.Operations {
+		record := records[i]
+		if record == nil {
+			record = previousRecords[i]
+		}
+		batchRes.Records = append(batchRes.Records, *recordMapper(record))
+	}
+	batchRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusOK, batchRes)
 }
 
-func (s *service) DeleteZone(c echo.Context, domainName string) error {
+func (s *service) ImportRecords(c echo.Context, domainName string) error {
 	ctx := c.Request().Context()
 
+	body, err := requestFile(c)
+	if errors.Is(err, errorFileNotSet) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	defer body.Close()
+
+	defer s.zoneLocks.Lock(domainName)()
+
 	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
 	if err != nil {
 		return responseServerErr(c, err)
@@ -370,127 +1106,4919 @@ func (s *service) DeleteZone(c echo.Context, domainName string) error {
 		return responseNotFound(c, "zone is not found")
 	}
 
-	err = s.zoneRepository.Delete(c.Request().Context(), zone)
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseClientErr(c, err)
 	}
 
-	err = s.bindHelper.UpdateAndReload(c.Request().Context())
+	previousWarnings := zone.Warnings()
+
+	added, skipped, err := zone.ImportHostsFile(body)
+	if errors.Is(err, domain.ErrorInvalidHostsFile) {
+		return responseClientErr(c, err)
+	}
 	if err != nil {
 		return responseServerErr(c, err)
 	}
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
 
-	return responseOk(c, "OK")
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	importRes := &external.RecordImportRes{
+		Records: make([]external.RecordRes, 0, len(added)),
+		Skipped: make([]string, 0, len(skipped)),
+	}
+	importRes.Skipped = append(importRes.Skipped, skipped...)
+	if len(added) > 0 {
+		changeWarnings, err := s.persistRecordChanges(ctx, zone, added, nil, change)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		warnings = append(warnings, changeWarnings...)
+	}
+	for _, record := range added {
+		importRes.Records = append(importRes.Records, *recordMapper(record))
+	}
+	importRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, importRes)
 }
 
-func (s *service) GetZoneByDomain(c echo.Context, domainName string) error {
-	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+func (s *service) GetRecordGroups(c echo.Context, params external.GetRecordGroupsParams) error {
+	ctx := c.Request().Context()
+
+	selectors, err := labelSelectors(&params.Label)
 	if err != nil {
 		return responseClientErr(c, err)
 	}
-	if zone == nil {
-		return responseNotFound(c, "zone is not found")
+	if len(selectors) == 0 {
+		return responseClientErr(c, errors.New("label is required"))
 	}
 
-	return c.JSON(http.StatusOK, zoneMapper(zone))
+	var zones []*domain.Zone
+	if params.Domain != nil {
+		zone, err := s.zoneRepository.GetZoneByDomain(ctx, *params.Domain)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		if zone == nil {
+			return responseNotFound(c, "zone is not found")
+		}
+		zones = append(zones, zone)
+	} else {
+		zones, err = s.zoneRepository.GetAllZones(ctx)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+
+	res := make([]external.RecordGroupRes, 0)
+	for _, zone := range zones {
+		if records := zone.FindRecordGroup(selectors); len(records) > 0 {
+			res = append(res, *recordGroupMapper(zone, records))
+		}
+	}
+	return c.JSON(http.StatusOK, res)
 }
 
-func (s *service) UpdateZone(c echo.Context, domainName string) error {
+// BatchRecordGroups changes the records selected by their labels in every zone holding some, the zones being locked in
+// order. The zones are persisted in a single transaction and reloaded once when the change succeeded for every zone.
+func (s *service) BatchRecordGroups(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	req := new(external.UpdateZoneJSONRequestBody)
+	req := new(external.BatchRecordGroupsJSONRequestBody)
 	err := c.Bind(req)
 	if err != nil {
 		return responseClientErr(c, err)
 	}
-
-	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	selectors, err := labelSelectors(&req.Label)
 	if err != nil {
-		return responseServerErr(c, err)
+		return responseClientErr(c, err)
 	}
-	if zone == nil {
-		return responseNotFound(c, "zone is not found")
+	if len(selectors) == 0 {
+		return responseClientErr(c, domain.ErrorInvalidRecordGroupChange)
+	}
+	groupChange := &domain.RecordGroupChange{TTL: req.Ttl, Disabled: req.Disabled}
+	if req.ValueSuffix != nil {
+		groupChange.ValueSuffix = &domain.ValueSuffixChange{From: req.ValueSuffix.From, To: req.ValueSuffix.To}
+	}
+	err = groupChange.Check()
+	if err != nil {
+		return responseClientErr(c, err)
 	}
 
-	if req.Domain != nil && *req.Domain != "" {
-		zone.Domain = *req.Domain
+	allZones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
 	}
-	if req.PrimaryNs != nil && *req.PrimaryNs != "" {
-		zone.SOA.PrimaryNameServer = *req.PrimaryNs
+	var domains []string
+	for _, zone := range allZones {
+		if req.Domains != nil && !containsDomain(*req.Domains, zone.Domain) {
+			continue
+		}
+		if len(zone.FindRecordGroup(selectors)) > 0 {
+			domains = append(domains, zone.Domain)
+		}
 	}
-	if req.MailAddr != nil && *req.MailAddr != "" {
-		zone.SOA.MailAddress = *req.MailAddr
+	// Locked in the order of domain.ZoneLocker, syncPTR locking the reverse zones after the forward ones.
+	domain.SortZoneLockOrder(domains)
+
+	var unlocks []func()
+	unlockAll := func() {
+		for _, unlock := range unlocks {
+			unlock()
+		}
 	}
+	defer unlockAll()
 
-	if !zone.IsValid() {
-		return responseClientErr(c, errors.New("zone input(s) are not valid"))
+	change := changeMetadata(c)
+	var (
+		zones               []*domain.Zone
+		changedRecords      = map[string][]*domain.Record{}
+		previousRecords     = map[string][]*domain.Record{}
+		previousNameServers = map[string][]string{}
+		warnings            []*domain.ValidationWarning
+	)
+	for _, domainName := range domains {
+		unlocks = append(unlocks, s.zoneLocks.Lock(domainName))
+
+		zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		if zone == nil || len(zone.FindRecordGroup(selectors)) == 0 {
+			continue
+		}
+
+		err = zone.CheckChange(change)
+		if err != nil {
+			return responseClientErr(c, fmt.Errorf("%w: %v", err, zone.Domain))
+		}
+		previousWarnings := zone.Warnings()
+		previousNameServers[zone.Id] = zone.NameServers()
+
+		records, previous, err := zone.ChangeRecordGroup(selectors, groupChange)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		if len(records) == 0 {
+			continue
+		}
+		err = zone.CheckNameServers(previousNameServers[zone.Id])
+		if err != nil {
+			return responseClientErr(c, fmt.Errorf("%w: %v", err, zone.Domain))
+		}
+		primaryMoved := false
+		for i, record := range records {
+			if zone.FollowNameServerChange(previous[i], record) {
+				primaryMoved = true
+			}
+		}
+
+		zoneWarnings, err := zone.CheckWarnings(previousWarnings)
+		if err != nil {
+			return responseClientErr(c, fmt.Errorf("%w: %v", err, zone.Domain))
+		}
+		warnings = append(warnings, zoneWarnings...)
+
+		err = s.checkZoneChange(ctx, zone)
+		if err != nil {
+			return responseZoneCheckErr(c, err)
+		}
+
+		for i, record := range records {
+			zone.AddEvent(domain.NewRecordEvent(domain.EventRecordUpdated, zone, record, previous[i]).WithChange(change))
+		}
+		if primaryMoved {
+			zone.AddEvent(domain.NewZoneEvent(domain.EventZoneUpdated, zone).WithChange(change))
+		}
+		zones = append(zones, zone)
+		changedRecords[zone.Id] = records
+		previousRecords[zone.Id] = previous
 	}
 
-	err = s.zoneRepository.Persist(ctx, zone)
+	err = s.zoneRepository.PersistAll(ctx, zones)
 	if err != nil {
 		return responseServerErr(c, err)
 	}
+	// The PTR records are synced in the reverse zones, which may be among the locked ones.
+	unlockAll()
 
-	err = s.bindHelper.UpdateAndReload(ctx)
-	if err != nil {
-		return responseServerErr(c, err)
+	for _, zone := range zones {
+		for i, record := range changedRecords[zone.Id] {
+			if warning := s.syncPTR(ctx, zone, previousRecords[zone.Id][i], record, change); warning != nil {
+				warnings = append(warnings, warning)
+			}
+		}
 	}
 
-	return c.JSON(http.StatusOK, zoneMapper(zone))
-}
+	s.events.Notify()
 
-func responseOk(c echo.Context, message string) error {
-	return c.JSON(http.StatusOK, external.GeneralRes{
-		Code:    http.StatusOK,
-		Message: message,
-	})
-}
-func responseNotFound(c echo.Context, message string) error {
-	return c.JSON(http.StatusNotFound, external.GeneralRes{
-		Code:    http.StatusNotFound,
-		Message: message,
-	})
-}
+	if len(zones) > 0 {
+		if warning := s.applyChanges(ctx, nil); warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
 
-func responseServerErr(c echo.Context, err error) error {
-	return c.JSON(http.StatusInternalServerError, external.GeneralRes{
-		Code:    http.StatusInternalServerError,
-		Message: err.Error(),
-	})
+	batchRes := &external.RecordGroupBatchRes{Groups: make([]external.RecordGroupRes, 0, len(zones))}
+	for _, zone := range zones {
+		if warning := s.publishNameServerChange(ctx, zone, previousNameServers[zone.Id]); warning != nil {
+			warnings = append(warnings, warning)
+		}
+		batchRes.Groups = append(batchRes.Groups, *recordGroupMapper(zone, changedRecords[zone.Id]))
+	}
+	batchRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusOK, batchRes)
 }
 
-func responseClientErr(c echo.Context, err error) error {
-	return c.JSON(http.StatusBadRequest, external.GeneralRes{
-		Code:    http.StatusBadRequest,
-		Message: err.Error(),
-	})
-}
+// createRecordFromReq adds the record of the request to the zone.
+func createRecordFromReq(zone *domain.Zone, req *external.RecordReq) (*domain.Record, error) {
+	if req.Type == "" || req.Value == "" {
+		return nil, errors.New("make sure type, value are set")
+	}
 
-func zoneMapper(zone *domain.Zone) *external.ZoneRes {
-	if zone == nil {
-		return nil
+	record := domain.NewRecord(req.Name, string(req.Type), req.Value)
+	if req.ExternalId != nil {
+		record.ExternalId = strings.TrimSpace(*req.ExternalId)
 	}
-	var records []external.RecordRes
-	for _, record := range zone.Records {
+	if req.Comment != nil {
+		record.Comment = strings.TrimSpace(*req.Comment)
+	}
+	if req.Disabled != nil {
+		record.Disabled = *req.Disabled
+	}
+	if req.Canary != nil {
+		record.Canary = *req.Canary
+	}
+	if req.Ttl != nil {
+		if !domain.IsValidRecordTTL(*req.Ttl) {
+			return nil, domain.ErrorInvalidRecordTTL
+		}
+		record.TTL = *req.Ttl
+	}
+	if req.View != nil {
+		if *req.View != "" && !domain.IsValidViewName(*req.View) {
+			return nil, domain.ErrorInvalidViewName
+		}
+		record.View = *req.View
+	}
+	if req.Labels != nil {
+		err := record.SetLabels(*req.Labels)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if domain.HasRecordPriority(record.Type) {
+		if req.Priority != nil {
+			record.Priority = *req.Priority
+		} else if !record.ExtractPriority() {
+			return nil, errors.New("priority is required for MX and SRV records")
+		}
+		if !zone.IsValidRecord(record) {
+			return nil, errors.New("priority must be between 0 and 65535")
+		}
+	}
+
+	err := zone.CheckRecordValue(record)
+	if err != nil {
+		return nil, err
+	}
+	err = zone.CheckApexRecord(record)
+	if err != nil {
+		return nil, err
+	}
+	err = zone.AddRecord(record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// updateRecordFromReq applies the request to a record of the zone, the fields left out being kept.
+func updateRecordFromReq(zone *domain.Zone, record *domain.Record, req *external.RecordReq) error {
+	err := record.CheckEditable()
+	if err != nil {
+		return err
+	}
+	previousType := record.Type
+
+	if req.Name != "" {
+		err := zone.CheckRecordName(req.Name)
+		if err != nil {
+			return err
+		}
+		record.Name = zone.NormalizeRecordName(req.Name)
+	}
+	if req.Type != "" {
+		record.Type = string(req.Type)
+	}
+	if req.Value != "" {
+		record.Value = req.Value
+	}
+	if req.ExternalId != nil {
+		record.ExternalId = strings.TrimSpace(*req.ExternalId)
+		if other := zone.FindRecordByExternalId(record.ExternalId); record.ExternalId != "" && other != record {
+			return domain.ErrorRecordExternalIdTaken
+		}
+	}
+	if req.Comment != nil {
+		record.Comment = strings.TrimSpace(*req.Comment)
+	}
+	if req.Disabled != nil {
+		record.Disabled = *req.Disabled
+	}
+	if req.Canary != nil {
+		record.Canary = *req.Canary
+	}
+	if req.Ttl != nil {
+		if !domain.IsValidRecordTTL(*req.Ttl) {
+			return domain.ErrorInvalidRecordTTL
+		}
+		record.TTL = *req.Ttl
+	}
+	if req.View != nil {
+		if *req.View != "" && !domain.IsValidViewName(*req.View) {
+			return domain.ErrorInvalidViewName
+		}
+		record.View = *req.View
+	}
+	if req.Labels != nil {
+		err := record.SetLabels(*req.Labels)
+		if err != nil {
+			return err
+		}
+	}
+	switch {
+	case !domain.HasRecordPriority(record.Type):
+		record.Priority = 0
+	case req.Priority != nil:
+		record.Priority = *req.Priority
+	case req.Value != "" && record.ExtractPriority():
+		// The value still heads with the priority, as it used to.
+	case !domain.HasRecordPriority(previousType):
+		return errors.New("priority is required for MX and SRV records")
+	}
+
+	err = zone.CheckRecordValue(record)
+	if err != nil {
+		return err
+	}
+	if !zone.IsValidRecord(record) {
+		return errors.New("record is not valid")
+	}
+	return zone.CheckApexRecord(record)
+}
+
+func (s *service) GetFragments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	fragments, err := s.fragmentRepository.GetAllFragments(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	fragmentsRes := make([]*external.FragmentRes, 0)
+	for _, fragment := range fragments {
+		fragmentsRes = append(fragmentsRes, fragmentMapper(fragment, zones))
+	}
+	return c.JSON(http.StatusOK, fragmentsRes)
+}
+
+func (s *service) UpdateFragment(c echo.Context, name string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateFragmentJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+	if !domain.IsValidFragmentName(name) {
+		return responseClientErr(c, domain.ErrorInvalidFragmentName)
+	}
+
+	fragment := domain.NewRecordFragment(name)
+	var records []*domain.Record
+	for _, recordReq := range req.Records {
+		record := domain.NewRecord(strings.TrimSpace(recordReq.Name), strings.ToUpper(recordReq.Type), recordReq.Value)
+		if recordReq.Priority != nil {
+			record.Priority = *recordReq.Priority
+		} else if domain.HasRecordPriority(record.Type) && !record.ExtractPriority() {
+			return responseClientErr(c, errors.New("priority is required for MX and SRV records"))
+		}
+		records = append(records, record)
+	}
+	err := fragment.SetRecords(records)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	for _, zone := range zones {
+		if !zone.IncludesFragment(fragment.Name) {
+			continue
+		}
+		err = zone.CheckFragment(fragment)
+		if err != nil {
+			return responseClientErr(c, fmt.Errorf("%w: %v", err, zone.Domain))
+		}
+		err = s.bindHelper.CheckZoneWithFragment(ctx, zone, fragment)
+		if err != nil {
+			return responseZoneCheckErr(c, fmt.Errorf("%w: %v", err, zone.Domain))
+		}
+	}
+
+	err = s.fragmentRepository.PersistFragment(ctx, fragment)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	// The zones including the fragment are written again with their next serial.
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, fragmentMapper(fragment, zones))
+}
+
+func (s *service) DeleteFragment(c echo.Context, name string) error {
+	ctx := c.Request().Context()
+
+	fragment, err := s.fragmentRepository.GetFragmentByName(ctx, name)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if fragment == nil {
+		return responseNotFound(c, "fragment is not found")
+	}
+
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if including := fragmentMapper(fragment, zones).Zones; len(including) > 0 {
+		return responseClientErr(c, fmt.Errorf("%w: %v", domain.ErrorFragmentInUse, strings.Join(including, ", ")))
+	}
+
+	err = s.fragmentRepository.DeleteFragment(ctx, fragment)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return responseOk(c, "OK")
+}
+
+func (s *service) GetTsigKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	keys, err := s.tsigKeyRepository.GetAllTSIGKeys(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	keysRes := make([]*external.ManagedTsigKeyRes, 0)
+	for _, key := range keys {
+		keysRes = append(keysRes, tsigKeyMapper(key, zones))
+	}
+	return c.JSON(http.StatusOK, keysRes)
+}
+
+func (s *service) UpdateTsigKey(c echo.Context, name string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateTsigKeyJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+	secret := ""
+	if req.Secret != nil {
+		secret = strings.TrimSpace(*req.Secret)
+	}
+	key, err := domain.NewTSIGKey(name, string(req.Algorithm), secret)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	for _, zone := range zones {
+		if zone.DefinesConflictingKey(key) {
+			return responseClientErr(c, fmt.Errorf("%w: %v (%v)", domain.ErrorTSIGKeyConflict, key.Name, zone.Domain))
+		}
+	}
+
+	err = s.tsigKeyRepository.PersistTSIGKey(ctx, key)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	// A generated secret is only returned here, the secondaries being configured with it once. The secrets sent by the
+	// client are never returned.
+	res := tsigKeyMapper(key, zones)
+	if secret == "" {
+		res.Secret = &key.Secret
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *service) DeleteTsigKey(c echo.Context, name string) error {
+	ctx := c.Request().Context()
+
+	key, err := s.tsigKeyRepository.GetTSIGKeyByName(ctx, name)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if key == nil {
+		return responseNotFound(c, "TSIG key is not found")
+	}
+
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if using := tsigKeyMapper(key, zones).Zones; len(using) > 0 {
+		return responseClientErr(c, fmt.Errorf("%w: %v", domain.ErrorTSIGKeyInUse, strings.Join(using, ", ")))
+	}
+
+	err = s.tsigKeyRepository.DeleteTSIGKey(ctx, key)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+	return responseOk(c, "OK")
+}
+
+// setFragmentsFromReq replaces the fragments the zone includes, each of them having to exist and to expand with the
+// variables of the zone.
+func (s *service) setFragmentsFromReq(ctx context.Context, zone *domain.Zone, names []string) error {
+	err := zone.SetFragments(names)
+	if err != nil {
+		return err
+	}
+	for _, name := range zone.Fragments {
+		fragment, err := s.fragmentRepository.GetFragmentByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if fragment == nil {
+			return fmt.Errorf("%w: %v", domain.ErrorUnknownFragment, name)
+		}
+		err = zone.CheckFragment(fragment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkViews fails with ErrorUnknownView when the zone or one of its records refers to a view which is not defined.
+func (s *service) checkViews(ctx context.Context, zone *domain.Zone) error {
+	names := append(append([]string{}, zone.Views...), zone.RecordViews()...)
+	if len(names) == 0 {
+		return nil
+	}
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if options.FindView(name) == nil {
+			return fmt.Errorf("%w: %v", domain.ErrorUnknownView, name)
+		}
+	}
+	return nil
+}
+
+func (s *service) GetZones(c echo.Context, params external.GetZonesParams) error {
+	filter := domain.ZoneFilter{}
+	if params.ExpiringWithin != nil {
+		if *params.ExpiringWithin < 0 {
+			return responseClientErr(c, errors.New("expiring_within can not be negative"))
+		}
+		filter.ExpiringWithin = time.Duration(*params.ExpiringWithin) * 24 * time.Hour
+	}
+	if params.TechnicalContact != nil {
+		filter.TechnicalContact = strings.TrimSpace(*params.TechnicalContact)
+	}
+	if params.ExternalId != nil {
+		filter.ExternalId = strings.TrimSpace(*params.ExternalId)
+	}
+	if params.Group != nil {
+		filter.Group = strings.TrimSpace(*params.Group)
+	}
+	page, err := pageOf(params.Limit, params.Offset)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	zones, total, err := s.zoneRepository.FindZones(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+
+	res := &external.ZonePageRes{Total: total, Zones: make([]external.ZoneRes, 0)}
+	for _, zone := range zones {
+		res.Zones = append(res.Zones, *zoneMapper(zone))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// pageOf reads the limit and offset query parameters.
+func pageOf(limit *int, offset *int) (domain.Page, error) {
+	page := domain.Page{}
+	if limit != nil {
+		page.Limit = *limit
+	}
+	if offset != nil {
+		page.Offset = *offset
+	}
+	if !page.IsValid() {
+		return page, errors.New("limit and offset can not be negative")
+	}
+	return page, nil
+}
+
+// setAllowTransferFromReq replaces the transfer settings of the zone by the ones of the request, the ones left out
+// being kept.
+func setAllowTransferFromReq(zone *domain.Zone, addresses *[]string, keys *[]external.TsigKeyReq) error {
+	allowTransfer, transferKeys := zone.AllowTransfer, zone.TransferKeys
+	if addresses != nil {
+		allowTransfer = nil
+		for _, address := range *addresses {
+			allowTransfer = append(allowTransfer, strings.TrimSpace(address))
+		}
+	}
+	if keys != nil {
+		transferKeys = nil
+		for _, key := range *keys {
+			transferKey := &domain.TSIGKey{Name: strings.TrimSpace(key.Name)}
+			if key.Algorithm != nil {
+				transferKey.Algorithm = string(*key.Algorithm)
+			}
+			if key.Secret != nil {
+				transferKey.Secret = strings.TrimSpace(*key.Secret)
+			}
+			transferKeys = append(transferKeys, transferKey)
+		}
+	}
+	return zone.SetAllowTransfer(allowTransfer, transferKeys)
+}
+
+// checkTransferKeys fails with domain.ErrorTSIGKeyConflict when another zone, or a managed key, defines a key of the
+// zone differently, and with domain.ErrorUnknownTSIGKey when the zone refers to a managed key which does not exist.
+func (s *service) checkTransferKeys(ctx context.Context, zone *domain.Zone) error {
+	if len(zone.TransferKeys) == 0 {
+		return nil
+	}
+	managedKeys, err := s.tsigKeyRepository.GetAllTSIGKeys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range zone.TransferKeys {
+		if key.IsManaged() && !containsTSIGKey(managedKeys, key.Name) {
+			return fmt.Errorf("%w: %v", domain.ErrorUnknownTSIGKey, key.Name)
+		}
+	}
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return err
+	}
+	return zone.CheckTransferKeys(zones, managedKeys)
+}
+
+func containsTSIGKey(keys []*domain.TSIGKey, name string) bool {
+	for _, key := range keys {
+		if key.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkZoneExternalId fails with domain.ErrorZoneExternalIdTaken when another zone has the external id of the zone.
+func (s *service) checkZoneExternalId(ctx context.Context, zone *domain.Zone) error {
+	if zone.ExternalId == "" {
+		return nil
+	}
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return err
+	}
+	filter := domain.ZoneFilter{ExternalId: zone.ExternalId}
+	for _, other := range zones {
+		if other.Id != zone.Id && filter.Matches(other, time.Now()) {
+			return domain.ErrorZoneExternalIdTaken
+		}
+	}
+	return nil
+}
+
+// similarZoneWarnings warns about the managed domains the domain of a new zone is a near typo of, while
+// warn_similar_zones is set in the settings.
+func (s *service) similarZoneWarnings(ctx context.Context, domainName string) ([]*domain.ValidationWarning, error) {
+	if !s.settings.Settings().WarnSimilarZones {
+		return nil, nil
+	}
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var zoneDomains []string
+	for _, zone := range zones {
+		zoneDomains = append(zoneDomains, zone.Domain)
+	}
+	return domain.SimilarZoneWarnings(domainName, zoneDomains), nil
+}
+
+func (s *service) CreateZone(c echo.Context) error {
+	req := new(external.CreateZoneJSONRequestBody)
+
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Domain == "" || req.PrimaryNs == "" || req.MailAddr == "" {
+		return responseClientErr(c, errors.New("make sure domain, primary_ns, and mail_addr are set"))
+	}
+
+	if domain.IsRpzZoneName(req.Domain) {
+		return responseClientErr(c, errors.New("zone name is reserved"))
+	}
+
+	if domain.IsPublicSuffix(req.Domain) && (req.Force == nil || !*req.Force) {
+		return responseClientErr(c, domain.ErrorPublicSuffix)
+	}
+
+	if !domain.IsValidSOAMailAddress(req.MailAddr) {
+		return responseClientErr(c, errors.New("mail_addr is not valid"))
+	}
+
+	if req.SerialStrategy != nil && !domain.IsValidSerialStrategy(string(*req.SerialStrategy)) {
+		return responseClientErr(c, errors.New("serial_strategy is not valid"))
+	}
+
+	// The zones created without a preset get the default one of the settings, if any.
+	soaPreset := domain.FindSOAPreset(s.settings.Settings().DefaultSOAPreset)
+	if req.SoaPreset != nil {
+		soaPreset = domain.FindSOAPreset(*req.SoaPreset)
+		if soaPreset == nil {
+			return responseClientErr(c, domain.ErrorUnknownSOAPreset)
+		}
+	}
+
+	defer s.zoneLocks.Lock(req.Domain)()
+
+	zoneExist, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), req.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zoneExist != nil {
+		return responseClientErr(c, errors.New("zone already exists"))
+	}
+
+	zone := domain.NewZone(req.Domain)
+	if req.Regulated != nil {
+		zone.Regulated = *req.Regulated
+	}
+	if req.StrictValidation != nil {
+		zone.StrictValidation = *req.StrictValidation
+	}
+	if req.SyncPtr != nil {
+		zone.SyncPTR = *req.SyncPtr
+	}
+	if req.SyncPrimaryNs != nil {
+		zone.SyncPrimaryNS = *req.SyncPrimaryNs
+	}
+	if req.NotifyInterval != nil {
+		if *req.NotifyInterval < 0 {
+			return responseClientErr(c, errors.New("notify_interval can not be negative"))
+		}
+		zone.NotifyInterval = time.Duration(*req.NotifyInterval) * time.Second
+	}
+	if req.Variables != nil {
+		err = zone.SetVariables(*req.Variables)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.AlsoNotify != nil {
+		err = zone.SetAlsoNotify(*req.AlsoNotify)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.Fragments != nil {
+		err = s.setFragmentsFromReq(c.Request().Context(), zone, *req.Fragments)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.DnssecPolicy != nil {
+		if !domain.IsValidDnssecPolicy(*req.DnssecPolicy) {
+			return responseClientErr(c, domain.ErrorInvalidDnssecPolicy)
+		}
+		zone.DnssecPolicy = *req.DnssecPolicy
+	}
+	if req.Views != nil {
+		err = zone.SetViews(*req.Views)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		err = s.checkViews(c.Request().Context(), zone)
+		if errors.Is(err, domain.ErrorUnknownView) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	if req.Group != nil {
+		if !domain.IsValidZoneGroup(*req.Group) {
+			return responseClientErr(c, domain.ErrorInvalidZoneGroup)
+		}
+		zone.Group = *req.Group
+	}
+	if req.AllowTransfer != nil || req.TransferKeys != nil {
+		err = setAllowTransferFromReq(zone, req.AllowTransfer, req.TransferKeys)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		err = s.checkTransferKeys(c.Request().Context(), zone)
+		if errors.Is(err, domain.ErrorTSIGKeyConflict) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	if req.Notes != nil {
+		zone.Notes = *req.Notes
+	}
+	if req.TechnicalContact != nil {
+		zone.TechnicalContact = strings.TrimSpace(*req.TechnicalContact)
+	}
+	if req.ExternalId != nil {
+		zone.ExternalId = strings.TrimSpace(*req.ExternalId)
+		err = s.checkZoneExternalId(c.Request().Context(), zone)
+		if errors.Is(err, domain.ErrorZoneExternalIdTaken) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	if req.ExpiresAt != nil {
+		zone.ExpiresAt, err = domain.ParseZoneExpirationDate(*req.ExpiresAt)
+		if err != nil {
+			return responseClientErr(c, errors.New("expires_at is not a valid YYYY-MM-DD date"))
+		}
+	}
+	if req.Registrar != nil {
+		zone.Registrar = strings.TrimSpace(*req.Registrar)
+		if zone.Registrar != "" && s.settings.Settings().FindRegistrar(zone.Registrar) == nil {
+			return responseClientErr(c, errors.New("registrar account is not configured"))
+		}
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	soa := domain.NewDefaultSOARecord(req.PrimaryNs, req.MailAddr)
+	if req.SerialStrategy != nil {
+		// The zone is new, its first serial comes from the strategy rather than following the default one.
+		soa.SerialStrategy = string(*req.SerialStrategy)
+		soa.Serial = ""
+		soa.UpdateSerial()
+	}
+	if soaPreset != nil {
+		soa.ApplyPreset(soaPreset)
+	}
+
+	err = zone.RegisterSOA(soa)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = zone.AddRecord(domain.NewNSRecord("@", req.PrimaryNs))
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	similarZoneWarnings, err := s.similarZoneWarnings(c.Request().Context(), zone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneCreated, zone).WithChange(change))
+
+	err = s.zoneRepository.Persist(c.Request().Context(), zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	// A new zone only has its NS record, the address of an in-zone name server can only be added afterwards so
+	// the warnings are returned even with strict validation.
+	warnings := append(zone.Warnings(), similarZoneWarnings...)
+	if warning := s.applyChanges(c.Request().Context(), zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(zone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, zoneRes)
+}
+
+func (s *service) ImportZone(c echo.Context, params external.ImportZoneParams) error {
+	body, err := requestFile(c)
+	if errors.Is(err, errorFileNotSet) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	defer body.Close()
+
+	domainName := ""
+	if params.Domain != nil {
+		domainName = strings.TrimSpace(*params.Domain)
+	}
+	zone, err := domain.ParseZoneFile(body, domainName)
+	if errors.Is(err, domain.ErrorInvalidZoneFile) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return s.createImportedZone(c, zone, params.Force != nil && *params.Force)
+}
+
+var errorFileNotSet = errors.New("make sure file is set")
+
+// requestFile returns the file uploaded as the body of the request, or as the file field of a multipart form.
+func requestFile(c echo.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return c.Request().Body, nil
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, errorFileNotSet
+	}
+	return fileHeader.Open()
+}
+
+func (s *service) TransferZone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := external.TransferZoneJSONRequestBody{}
+	err := c.Bind(&req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	domainName, primary := strings.TrimSpace(req.Domain), strings.TrimSpace(req.Primary)
+	if domainName == "" || primary == "" {
+		return responseClientErr(c, errors.New("make sure domain and primary are set"))
+	}
+	if domainName == "@" || strings.Contains(domainName, "*") || !domain.IsValidOwnerName(domainName) {
+		return responseClientErr(c, fmt.Errorf("%v is not a valid domain", domainName))
+	}
+	if !domain.IsValidNotifyTarget(strings.Fields(primary)) {
+		return responseClientErr(c, fmt.Errorf("%v is not a valid primary", primary))
+	}
+
+	var key *domain.TSIGKey
+	if req.TsigKey != nil {
+		key = &domain.TSIGKey{Name: strings.TrimSpace(req.TsigKey.Name)}
+		if req.TsigKey.Algorithm != nil {
+			key.Algorithm = string(*req.TsigKey.Algorithm)
+		}
+		if req.TsigKey.Secret != nil {
+			key.Secret = strings.TrimSpace(*req.TsigKey.Secret)
+		}
+		// A key given by its name only is the managed key of that name.
+		if key.IsManaged() {
+			key, err = s.tsigKeyRepository.GetTSIGKeyByName(ctx, key.Name)
+			if err != nil {
+				return responseServerErr(c, err)
+			}
+			if key == nil {
+				return responseClientErr(c, fmt.Errorf("%w: %v", domain.ErrorUnknownTSIGKey, req.TsigKey.Name))
+			}
+		} else if !key.IsValid() {
+			return responseClientErr(c, domain.ErrorInvalidTSIGKey)
+		}
+	}
+
+	zoneFile, err := s.bindHelper.TransferZone(ctx, primary, domainName, key)
+	if errors.Is(err, domain.ErrorZoneTransferFailed) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	zone, err := domain.ParseZoneFile(strings.NewReader(zoneFile), domainName)
+	if errors.Is(err, domain.ErrorInvalidZoneFile) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return s.createImportedZone(c, zone, req.Force != nil && *req.Force)
+}
+
+// createImportedZone creates a zone read from another DNS server, returning the warnings of its records for the zone
+// to be fixed here. force creates the zones whose domain is a public suffix.
+func (s *service) createImportedZone(c echo.Context, zone *domain.Zone, force bool) error {
+	ctx := c.Request().Context()
+
+	if domain.IsRpzZoneName(zone.Domain) {
+		return responseClientErr(c, errors.New("zone name is reserved"))
+	}
+
+	if domain.IsPublicSuffix(zone.Domain) && !force {
+		return responseClientErr(c, domain.ErrorPublicSuffix)
+	}
+
+	defer s.zoneLocks.Lock(zone.Domain)()
+
+	zoneExist, err := s.zoneRepository.GetZoneByDomain(ctx, zone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zoneExist != nil {
+		return responseClientErr(c, errors.New("zone already exists"))
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	similarZoneWarnings, err := s.similarZoneWarnings(ctx, zone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneCreated, zone).WithChange(change))
+
+	err = s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	warnings := append(zone.Warnings(), similarZoneWarnings...)
+	if warning := s.applyChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(zone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, zoneRes)
+}
+
+// CreateReverseZone creates the reverse zone of a network with the PTR records of the addresses the managed zones
+// point to, delegating the classless zones from the zone of their /24 when it is managed here.
+func (s *service) CreateReverseZone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateReverseZoneJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Cidr == "" || req.PrimaryNs == "" || req.MailAddr == "" {
+		return responseClientErr(c, errors.New("make sure cidr, primary_ns, and mail_addr are set"))
+	}
+	if !domain.IsValidSOAMailAddress(req.MailAddr) {
+		return responseClientErr(c, errors.New("mail_addr is not valid"))
+	}
+	reverseZone, err := domain.NewReverseZone(req.Cidr)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	// The zone delegating a classless zone is changed as well, the zones are locked in the order of domain.ZoneLocker.
+	lockedDomains := []string{reverseZone.Domain}
+	if reverseZone.Parent != "" {
+		lockedDomains = append(lockedDomains, reverseZone.Parent)
+	}
+	domain.SortZoneLockOrder(lockedDomains)
+	for _, lockedDomain := range lockedDomains {
+		defer s.zoneLocks.Lock(lockedDomain)()
+	}
+
+	zoneExist, err := s.zoneRepository.GetZoneByDomain(ctx, reverseZone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zoneExist != nil {
+		return responseClientErr(c, errors.New("zone already exists"))
+	}
+
+	zone := domain.NewZone(reverseZone.Domain)
+	if req.Group != nil {
+		if !domain.IsValidZoneGroup(*req.Group) {
+			return responseClientErr(c, domain.ErrorInvalidZoneGroup)
+		}
+		zone.Group = *req.Group
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	soa := domain.NewDefaultSOARecord(req.PrimaryNs, req.MailAddr)
+	if soaPreset := domain.FindSOAPreset(s.settings.Settings().DefaultSOAPreset); soaPreset != nil {
+		soa.ApplyPreset(soaPreset)
+	}
+	err = zone.RegisterSOA(soa)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	err = zone.AddRecord(domain.NewNSRecord("@", req.PrimaryNs))
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	allZones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	err = addReversePTRs(zone, allZones)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(ctx, zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	var parent *domain.Zone
+	var warnings []*domain.ValidationWarning
+	if reverseZone.Parent != "" {
+		parent, err = s.zoneRepository.GetZoneByDomain(ctx, reverseZone.Parent)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		if parent == nil {
+			warnings = append(warnings, &domain.ValidationWarning{
+				Code: domain.WarningReverseDelegation,
+				Message: fmt.Sprintf("zone %v is not managed here, it has to delegate %v with NS and CNAME records",
+					reverseZone.Parent, reverseZone.Domain),
+			})
+		} else {
+			err = parent.CheckChange(change)
+			if err != nil {
+				return responseClientErr(c, err)
+			}
+			warning, err := delegateReverseZone(parent, reverseZone, req.PrimaryNs, change)
+			if err != nil {
+				return responseClientErr(c, err)
+			}
+			if warning != nil {
+				warnings = append(warnings, warning)
+			}
+			err = s.checkZoneChange(ctx, parent)
+			if err != nil {
+				return responseZoneCheckErr(c, err)
+			}
+		}
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneCreated, zone).WithChange(change))
+
+	err = s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if parent != nil {
+		err = s.zoneRepository.Persist(ctx, parent)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+
+	s.events.Notify()
+
+	warnings = append(zone.Warnings(), warnings...)
+	if warning := s.applyChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(zone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, zoneRes)
+}
+
+// addReversePTRs adds to the reverse zone the PTR records of the addresses the A and AAAA records of the zones point
+// to, an address pointed to by several names keeping the first one.
+func addReversePTRs(reverse *domain.Zone, zones []*domain.Zone) error {
+	added := map[string]bool{}
+	for _, zone := range zones {
+		for _, record := range zone.Records {
+			reverseName, target, ok := zone.PTROf(record)
+			if !ok || added[reverseName] || domain.FindReverseZone([]*domain.Zone{reverse}, reverseName) == nil {
+				continue
+			}
+			_, _, err := reverse.SetPTR(reverseName, target)
+			if err != nil {
+				return err
+			}
+			added[reverseName] = true
+		}
+	}
+	return nil
+}
+
+// delegateReverseZone adds the records delegating the classless reverse zone to the zone of its /24, the names
+// already holding other records being left as they are and returned in a warning.
+func delegateReverseZone(
+	parent *domain.Zone, reverseZone *domain.ReverseZone, nameServer string, change *domain.ChangeMetadata,
+) (*domain.ValidationWarning, error) {
+	var skipped []string
+	for _, record := range reverseZone.DelegationRecords(nameServer) {
+		if len(parent.FindRecordyByCriteria(record.Name, "", "")) > 0 {
+			skipped = append(skipped, record.Name)
+			continue
+		}
+		err := parent.AddRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		parent.AddEvent(domain.NewRecordEvent(domain.EventRecordCreated, parent, record, nil).WithChange(change))
+	}
+	if len(skipped) == 0 {
+		return nil, nil
+	}
+	return &domain.ValidationWarning{
+		Code: domain.WarningReverseDelegation,
+		Message: fmt.Sprintf("zone %v already has records at %v, they are left out of the delegation of %v",
+			parent.Domain, strings.Join(skipped, ", "), reverseZone.Domain),
+	}, nil
+}
+
+func (s *service) DeleteZone(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneDeleted, zone).WithChange(change))
+
+	err = s.zoneRepository.Delete(c.Request().Context(), zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyChanges(c.Request().Context(), nil); warning != nil {
+		return responseOk(c, warning.Message)
+	}
+	return responseOk(c, "OK")
+}
+
+func (s *service) GetSoaPresets(c echo.Context) error {
+	defaultPreset := s.settings.Settings().DefaultSOAPreset
+	presetsRes := make([]*external.SoaPresetRes, 0)
+	for _, preset := range domain.SOAPresets {
+		presetsRes = append(presetsRes, &external.SoaPresetRes{
+			Name:        preset.Name,
+			Description: preset.Description,
+			Refresh:     preset.Refresh,
+			Retry:       preset.Retry,
+			Expire:      preset.Expire,
+			CacheTtl:    preset.CacheTTL,
+			Default:     preset.Name == defaultPreset,
+		})
+	}
+	return c.JSON(http.StatusOK, presetsRes)
+}
+
+func (s *service) GetZoneByDomain(c echo.Context, domainName string) error {
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	return c.JSON(http.StatusOK, zoneMapper(zone))
+}
+
+func (s *service) UpdateZone(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateZoneJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	previousWarnings := zone.Warnings()
+	previousNameServers := zone.NameServers()
+
+	if req.Domain != nil && *req.Domain != "" {
+		zone.Domain = *req.Domain
+	}
+	if req.SyncPrimaryNs != nil {
+		zone.SyncPrimaryNS = *req.SyncPrimaryNs
+	}
+	if req.NotifyInterval != nil {
+		if *req.NotifyInterval < 0 {
+			return responseClientErr(c, errors.New("notify_interval can not be negative"))
+		}
+		zone.NotifyInterval = time.Duration(*req.NotifyInterval) * time.Second
+	}
+	if req.Variables != nil {
+		err = zone.SetVariables(*req.Variables)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.AlsoNotify != nil {
+		err = zone.SetAlsoNotify(*req.AlsoNotify)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.Fragments != nil {
+		err = s.setFragmentsFromReq(c.Request().Context(), zone, *req.Fragments)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+	}
+	if req.DnssecPolicy != nil {
+		if !domain.IsValidDnssecPolicy(*req.DnssecPolicy) {
+			return responseClientErr(c, domain.ErrorInvalidDnssecPolicy)
+		}
+		zone.DnssecPolicy = *req.DnssecPolicy
+	}
+	if req.Views != nil {
+		err = zone.SetViews(*req.Views)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		err = s.checkViews(c.Request().Context(), zone)
+		if errors.Is(err, domain.ErrorUnknownView) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	if req.Group != nil && *req.Group != zone.Group {
+		if !domain.IsValidZoneGroup(*req.Group) {
+			return responseClientErr(c, domain.ErrorInvalidZoneGroup)
+		}
+		// The zone file moves to the folder of the group, the one left behind being reported as unknown.
+		zone.Group = *req.Group
+		zone.FilePath = ""
+	}
+	if req.AllowTransfer != nil || req.TransferKeys != nil {
+		err = setAllowTransferFromReq(zone, req.AllowTransfer, req.TransferKeys)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		err = s.checkTransferKeys(ctx, zone)
+		if errors.Is(err, domain.ErrorTSIGKeyConflict) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	var nameServerRecord, previousNameServerRecord *domain.Record
+	if req.PrimaryNs != nil && *req.PrimaryNs != "" {
+		nameServerRecord, previousNameServerRecord = zone.SetPrimaryNameServer(*req.PrimaryNs)
+	}
+	if req.MailAddr != nil && *req.MailAddr != "" {
+		if !domain.IsValidSOAMailAddress(*req.MailAddr) {
+			return responseClientErr(c, errors.New("mail_addr is not valid"))
+		}
+		zone.SOA.MailAddress = *req.MailAddr
+	}
+	if req.SerialStrategy != nil {
+		if !domain.IsValidSerialStrategy(string(*req.SerialStrategy)) {
+			return responseClientErr(c, errors.New("serial_strategy is not valid"))
+		}
+		zone.SOA.SerialStrategy = string(*req.SerialStrategy)
+	}
+	if req.SoaPreset != nil {
+		soaPreset := domain.FindSOAPreset(*req.SoaPreset)
+		if soaPreset == nil {
+			return responseClientErr(c, domain.ErrorUnknownSOAPreset)
+		}
+		zone.SOA.ApplyPreset(soaPreset)
+	}
+	if req.Regulated != nil {
+		zone.Regulated = *req.Regulated
+	}
+	if req.StrictValidation != nil {
+		zone.StrictValidation = *req.StrictValidation
+	}
+	if req.SyncPtr != nil {
+		zone.SyncPTR = *req.SyncPtr
+	}
+	if req.Notes != nil {
+		zone.Notes = *req.Notes
+	}
+	if req.TechnicalContact != nil {
+		zone.TechnicalContact = strings.TrimSpace(*req.TechnicalContact)
+	}
+	if req.ExternalId != nil {
+		zone.ExternalId = strings.TrimSpace(*req.ExternalId)
+		err = s.checkZoneExternalId(c.Request().Context(), zone)
+		if errors.Is(err, domain.ErrorZoneExternalIdTaken) {
+			return responseClientErr(c, err)
+		}
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+	if req.ExpiresAt != nil {
+		zone.ExpiresAt, err = domain.ParseZoneExpirationDate(*req.ExpiresAt)
+		if err != nil {
+			return responseClientErr(c, errors.New("expires_at is not a valid YYYY-MM-DD date"))
+		}
+	}
+	if req.Registrar != nil {
+		zone.Registrar = strings.TrimSpace(*req.Registrar)
+		if zone.Registrar != "" && s.settings.Settings().FindRegistrar(zone.Registrar) == nil {
+			return responseClientErr(c, errors.New("registrar account is not configured"))
+		}
+	}
+
+	// Regulating a zone is a change of a regulated zone as well.
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if !zone.IsValid() {
+		return responseClientErr(c, errors.New("zone input(s) are not valid"))
+	}
+
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneUpdated, zone).WithChange(change))
+	if nameServerRecord != nil {
+		zone.AddEvent(domain.NewRecordEvent(
+			domain.EventRecordUpdated, zone, nameServerRecord, previousNameServerRecord,
+		).WithChange(change))
+	}
+
+	err = s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	if warning := s.publishNameServerChange(ctx, zone, previousNameServers); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(zone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusOK, zoneRes)
+}
+
+func (s *service) ArchiveZone(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	auditLogs, err := s.auditLogRepository.GetAuditLogs(ctx, domain.AuditLogFilter{Zone: zone.Domain, All: true})
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	archive := domain.NewZoneArchive(zone)
+	bundle, err := domain.NewZoneArchiveBundle(archive, zone, auditLogs).Encode()
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	err = s.archiveRepository.PersistArchive(ctx, archive, bundle)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneArchived, zone).WithChange(change))
+
+	err = s.zoneRepository.Delete(ctx, zone)
+	if err != nil {
+		if deleteErr := s.archiveRepository.DeleteArchive(ctx, archive); deleteErr != nil {
+			log.Println(deleteErr)
+		}
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	// The zone is archived already, named keeps serving it until the next successful reload.
+	s.applyChanges(ctx, nil)
+
+	return c.JSON(http.StatusCreated, zoneArchiveMapper(archive))
+}
+
+func (s *service) CloneZone(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+	req := new(external.CloneZoneJSONRequestBody)
+
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	req.Domain = strings.TrimSpace(req.Domain)
+	if req.Domain == "" {
+		return responseClientErr(c, errors.New("make sure domain is set"))
+	}
+
+	if domain.IsRpzZoneName(req.Domain) {
+		return responseClientErr(c, errors.New("zone name is reserved"))
+	}
+
+	if domain.IsPublicSuffix(req.Domain) && (req.Force == nil || !*req.Force) {
+		return responseClientErr(c, domain.ErrorPublicSuffix)
+	}
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	// The zone being cloned is only read, locking the new domain alone keeps two clones of each other from waiting
+	// for one another.
+	defer s.zoneLocks.Lock(req.Domain)()
+
+	zoneExist, err := s.zoneRepository.GetZoneByDomain(ctx, req.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zoneExist != nil {
+		return responseClientErr(c, errors.New("zone already exists"))
+	}
+
+	clone, err := zone.Clone(req.Domain)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	change := changeMetadata(c)
+	err = clone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), clone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	similarZoneWarnings, err := s.similarZoneWarnings(ctx, clone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	clone.AddEvent(domain.NewZoneEvent(domain.EventZoneCreated, clone).WithChange(change))
+
+	err = s.zoneRepository.Persist(ctx, clone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	// The records are the ones of the zone being cloned, their warnings are returned even with strict validation.
+	warnings := append(clone.Warnings(), similarZoneWarnings...)
+	if warning := s.applyChanges(ctx, clone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(clone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusCreated, zoneRes)
+}
+
+func (s *service) GetDelegations(c echo.Context, domainName string) error {
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	delegationsRes := make([]*external.DelegationRes, 0)
+	for _, delegation := range zone.Delegations() {
+		delegationsRes = append(delegationsRes, delegationMapper(delegation))
+	}
+	return c.JSON(http.StatusOK, delegationsRes)
+}
+
+func (s *service) SetDelegation(c echo.Context, domainName string, name string) error {
+	req := new(external.SetDelegationJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	delegation := &domain.Delegation{Name: name}
+	for _, nameServer := range req.NameServers {
+		delegatedNameServer := &domain.DelegatedNameServer{Host: nameServer.Host}
+		if nameServer.Addresses != nil {
+			delegatedNameServer.Addresses = *nameServer.Addresses
+		}
+		delegation.NameServers = append(delegation.NameServers, delegatedNameServer)
+	}
+	previousWarnings := zone.Warnings()
+
+	added, removed, err := zone.SetDelegation(delegation)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	changeWarnings, err := s.persistRecordChanges(c.Request().Context(), zone, added, removed, change)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	delegationRes := delegationMapper(zone.FindDelegation(name))
+	delegationRes.Warnings = validationWarningsMapper(append(warnings, changeWarnings...))
+	return c.JSON(http.StatusOK, delegationRes)
+}
+
+func (s *service) DeleteDelegation(c echo.Context, domainName string, name string) error {
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if zone.FindDelegation(name) == nil {
+		return responseNotFound(c, "delegation is not found")
+	}
+	previousWarnings := zone.Warnings()
+
+	removed, err := zone.RemoveDelegation(name)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	_, err = zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	warnings, err := s.persistRecordChanges(c.Request().Context(), zone, nil, removed, change)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return responseOkWithWarnings(c, warnings)
+}
+
+func (s *service) GetZoneDnssec(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	status, err := s.bindHelper.DnssecStatus(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dnssecMapper(status))
+}
+
+func (s *service) GetZoneDs(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+	// the DS records of the zones served unsigned, or being unsigned, break their resolution once published
+	if zone.DnssecPolicy != domain.DnssecPolicyDefault ||
+		!s.settings.Settings().IsFeatureEnabled(domain.FeatureDnssec) {
+		return responseNotFound(c, "zone is not signed")
+	}
+
+	keys, err := s.bindHelper.DnssecKeys(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dsMapper(keys))
+}
+
+func (s *service) ExportZone(c echo.Context, domainName string, params external.ExportZoneParams) error {
+	ctx := c.Request().Context()
+
+	format := domain.ExportFormatZoneFile
+	if params.Format != nil {
+		format = string(*params.Format)
+	}
+	if !domain.IsValidExportFormat(format) {
+		return responseClientErr(c, errors.New("format is not valid"))
+	}
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	if format == domain.ExportFormatTinydns || format == domain.ExportFormatUnbound {
+		if zone.SOA == nil || !zone.SOA.IsValid() {
+			return responseClientErr(c, domain.ErrorZoneWithoutSOA)
+		}
+		records, err := s.bindHelper.ServedRecords(ctx, zone)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		fileName, content := "data."+domain.NormalizeDomain(zone.Domain), zone.TinydnsData(records)
+		if format == domain.ExportFormatUnbound {
+			fileName, content = domain.NormalizeDomain(zone.Domain)+".conf", zone.UnboundConfig(records)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%v"`, fileName))
+		return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(content))
+	}
+
+	zoneFile, err := s.bindHelper.ZoneFile(ctx, zone)
+	if errors.Is(err, domain.ErrorZoneWithoutSOA) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="db.%v"`, domain.NormalizeDomain(zone.Domain)))
+	return c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(zoneFile))
+}
+
+func (s *service) FreezeZone(c echo.Context, domainName string) error {
+	req := new(external.FreezeZoneJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	reason := ""
+	if req.Reason != nil {
+		reason = *req.Reason
+	}
+	err = zone.Freeze(reason, time.Now())
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	return s.persistFreeze(c, zone, domain.NewZoneEvent(domain.EventZoneFrozen, zone).WithChange(change))
+}
+
+func (s *service) GetRecordGenerators(c echo.Context, domainName string) error {
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	generatorsRes := make([]*external.RecordGeneratorRes, 0)
+	for _, generator := range zone.Generators {
+		generatorsRes = append(generatorsRes, recordGeneratorMapper(zone, generator))
+	}
+	return c.JSON(http.StatusOK, generatorsRes)
+}
+
+func (s *service) CreateRecordGenerator(c echo.Context, domainName string) error {
+	req := new(external.CreateRecordGeneratorJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	generator := &domain.RecordGenerator{Name: strings.TrimSpace(req.Name), Range: strings.TrimSpace(req.Range)}
+	return s.applyRecordGenerator(c, zone, generator, change, http.StatusCreated)
+}
+
+func (s *service) UpdateRecordGenerator(c echo.Context, domainName string, generatorId string) error {
+	req := new(external.UpdateRecordGeneratorJSONRequestBody)
+	err := c.Bind(req)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if zone.FindRecordGenerator(generatorId) == nil {
+		return responseNotFound(c, "generator is not found")
+	}
+	generator := &domain.RecordGenerator{
+		Id: generatorId, Name: strings.TrimSpace(req.Name), Range: strings.TrimSpace(req.Range),
+	}
+	return s.applyRecordGenerator(c, zone, generator, change, http.StatusOK)
+}
+
+// applyRecordGenerator brings the records of the generator in line, the zone being saved and reloaded once.
+func (s *service) applyRecordGenerator(
+	c echo.Context, zone *domain.Zone, generator *domain.RecordGenerator, change *domain.ChangeMetadata, status int,
+) error {
+	previousWarnings := zone.Warnings()
+
+	added, removed, err := zone.ApplyRecordGenerator(generator)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	warnings, err := zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	changeWarnings, err := s.persistRecordChanges(c.Request().Context(), zone, added, removed, change)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	generatorRes := recordGeneratorMapper(zone, generator)
+	generatorRes.Warnings = validationWarningsMapper(append(warnings, changeWarnings...))
+	return c.JSON(status, generatorRes)
+}
+
+func (s *service) DeleteRecordGenerator(c echo.Context, domainName string, generatorId string) error {
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	generator := zone.FindRecordGenerator(generatorId)
+	if generator == nil {
+		return responseNotFound(c, "generator is not found")
+	}
+	previousWarnings := zone.Warnings()
+
+	removed := zone.RemoveRecordGenerator(generator)
+	_, err = zone.CheckWarnings(previousWarnings)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	warnings, err := s.persistRecordChanges(c.Request().Context(), zone, nil, removed, change)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return responseOkWithWarnings(c, warnings)
+}
+
+// persistRecordChanges saves the zone whose records were added and removed at once, e.g. by a generator, keeping
+// their PTR records in line, and applies it. The warnings of the steps following the save are returned.
+func (s *service) persistRecordChanges(
+	ctx context.Context, zone *domain.Zone, added, removed []*domain.Record, change *domain.ChangeMetadata,
+) ([]*domain.ValidationWarning, error) {
+	for _, record := range added {
+		zone.AddEvent(domain.NewRecordEvent(domain.EventRecordCreated, zone, record, nil).WithChange(change))
+	}
+	for _, record := range removed {
+		zone.AddEvent(domain.NewRecordEvent(domain.EventRecordDeleted, zone, record, nil).WithChange(change))
+	}
+
+	err := s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []*domain.ValidationWarning
+	for _, record := range removed {
+		if warning := s.syncPTR(ctx, zone, record, nil, change); warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+	for _, record := range added {
+		if warning := s.syncPTR(ctx, zone, nil, record, change); warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	s.events.Notify()
+
+	if warning := s.applyRecordChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+	warnings = append(warnings, s.zoneSizeWarnings(zone)...)
+	return warnings, nil
+}
+
+func (s *service) GetZoneStats(c echo.Context, domainName string) error {
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	limits := s.settings.Settings().ZoneLimits
+	size := zoneSize(zone)
+	res := &external.ZoneStatsRes{
+		Records:     size.Records,
+		FileSize:    size.FileSize,
+		MaxRecords:  limits.MaxRecords,
+		MaxFileSize: limits.MaxFileSize,
+		Warnings:    make([]external.ValidationWarning, 0),
+	}
+	if warnings := validationWarningsMapper(limits.Warnings(size)); warnings != nil {
+		res.Warnings = *warnings
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *service) GetZoneReport(c echo.Context, domainName string, params external.GetZoneReportParams) error {
+	ctx := c.Request().Context()
+
+	to := time.Now()
+	if params.To != nil {
+		to = *params.To
+	}
+	from := to.Add(-domain.DefaultReportPeriod)
+	if params.From != nil {
+		from = *params.From
+	}
+	if !from.Before(to) {
+		return responseClientErr(c, errors.New("from must be before to"))
+	}
+	format := domain.ReportFormatMarkdown
+	if params.Format != nil {
+		format = string(*params.Format)
+	}
+	if !domain.IsValidReportFormat(format) {
+		return responseClientErr(c, errors.New("format is not valid"))
+	}
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	events, err := s.auditLogRepository.GetAuditLogs(ctx, domain.AuditLogFilter{
+		Zone: zone.Domain,
+		All:  true,
+		From: from,
+		To:   to,
+	})
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	report := domain.NewZoneReport(zone, from, to)
+	report.SetChanges(events)
+	report.SetQueryStats(s.queryStats.Since(), s.queryStats.Networks())
+	content, err := report.Render(format)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	contentType := "text/markdown; charset=utf-8"
+	if format == domain.ReportFormatHtml {
+		contentType = echo.MIMETextHTMLCharsetUTF8
+	}
+	return c.Blob(http.StatusOK, contentType, content)
+}
+
+func (s *service) ThawZone(c echo.Context, domainName string) error {
+	defer s.zoneLocks.Lock(domainName)()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(c.Request().Context(), domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChangeMetadata(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = zone.Thaw()
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	return s.persistFreeze(c, zone, domain.NewZoneEvent(domain.EventZoneThawed, zone).WithChange(change))
+}
+
+// persistFreeze saves the zone once frozen or thawed, the reload writing its zone file again once thawed.
+func (s *service) persistFreeze(c echo.Context, zone *domain.Zone, event *domain.ChangeEvent) error {
+	ctx := c.Request().Context()
+
+	zone.AddEvent(event)
+	err := s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	var warnings []*domain.ValidationWarning
+	if warning := s.applyChanges(ctx, zone); warning != nil {
+		warnings = append(warnings, warning)
+	}
+
+	zoneRes := zoneMapper(zone)
+	zoneRes.Warnings = validationWarningsMapper(warnings)
+	return c.JSON(http.StatusOK, zoneRes)
+}
+
+func (s *service) GetUnusedRecords(c echo.Context, domainName string, params external.GetUnusedRecordsParams) error {
+	ctx := c.Request().Context()
+
+	days := domain.DefaultUnusedRecordDays
+	if params.Days != nil {
+		days = *params.Days
+	}
+	if days < 1 {
+		return responseClientErr(c, errors.New("days must be at least 1"))
+	}
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	lastQueries, err := s.usageRepository.GetLastQueries(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	res := &external.UnusedRecordsRes{
+		Complete: options.QueryLog && !options.QueryLogSince.IsZero() && !options.QueryLogSince.After(since),
+		Days:     days,
+		Records:  make([]external.UnusedRecordRes, 0),
+	}
+	if options.QueryLog && !options.QueryLogSince.IsZero() {
+		res.TrackedSince = &options.QueryLogSince
+	}
+	for _, record := range zone.UnusedRecords(lastQueries, since) {
+		unused := external.UnusedRecordRes{Record: *recordMapper(record)}
+		if lastQuery, ok := lastQueries[record.Id]; ok {
+			unused.LastQueriedAt = &lastQuery
+		}
+		res.Records = append(res.Records, unused)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *service) PublishZoneDelegation(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+	if zone.Registrar == "" {
+		return responseClientErr(c, errors.New("zone has no registrar account"))
+	}
+
+	err = s.publishDelegation(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+// publishDelegation pushes the name servers of the zone to its registrar account, along with the DS records of the
+// zone once signed, see delegationSigners. The registrars managing the DS records out of their API are left to the
+// user.
+func (s *service) publishDelegation(ctx context.Context, zone *domain.Zone) error {
+	account := s.settings.Settings().FindRegistrar(zone.Registrar)
+	if account == nil {
+		return fmt.Errorf("registrar account %v is not configured", zone.Registrar)
+	}
+	connector, err := external.NewRegistrarConnector(account)
+	if err != nil {
+		return err
+	}
+	err = connector.SetNameServers(ctx, zone.Domain, zone.NameServers())
+	if err != nil {
+		return err
+	}
+
+	signers, publish, err := s.delegationSigners(ctx, zone)
+	if err != nil || !publish {
+		return err
+	}
+	err = connector.SetDelegationSigners(ctx, zone.Domain, signers)
+	if errors.Is(err, domain.ErrorRegistrarUnsupported) {
+		log.Printf("DS records of %v are not published: %v", zone.Domain, err)
+		return nil
+	}
+	return err
+}
+
+// delegationSigners returns the DS records to publish in the parent zone, a SHA-256 digest by key signing key. publish
+// is false when the DS records are left as they are: for the zones which are not signed, and for the signed zones
+// named has not generated keys for yet. The zones being unsigned, their policy being insecure, have them removed.
+func (s *service) delegationSigners(
+	ctx context.Context, zone *domain.Zone,
+) (signers []*domain.DelegationSigner, publish bool, err error) {
+	if !zone.IsSigned() || !s.settings.Settings().IsFeatureEnabled(domain.FeatureDnssec) {
+		return nil, false, nil
+	}
+	if zone.DnssecPolicy == domain.DnssecPolicyInsecure {
+		return nil, true, nil
+	}
+
+	keys, err := s.bindHelper.DnssecKeys(ctx, zone)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, key := range keys {
+		for _, ds := range key.DS {
+			if ds.DigestType != domain.DSDigestSHA256 {
+				continue
+			}
+			signers = append(signers, &domain.DelegationSigner{
+				KeyTag:     ds.KeyTag,
+				Algorithm:  ds.Algorithm,
+				DigestType: ds.DigestType,
+				Digest:     ds.Digest,
+				Flags:      key.Flags,
+				PublicKey:  key.PublicKey,
+			})
+		}
+	}
+	return signers, len(signers) > 0, nil
+}
+
+// checkZoneChange checks the zone files of a changed zone before it is persisted, failing with
+// domain.ErrorZoneCheckFailed when named would refuse to load them. Every change of the records of a zone is checked.
+func (s *service) checkZoneChange(ctx context.Context, zone *domain.Zone) error {
+	return s.bindHelper.CheckZone(ctx, zone)
+}
+
+// applyChanges reloads named once the changes are saved. A failure leaves the zones pending, they are applied by the
+// next successful reload, so it is returned as a warning for the client not to retry the change.
+func (s *service) applyChanges(ctx context.Context, zone *domain.Zone) *domain.ValidationWarning {
+	return appliedWarning(zone, s.leftOutError(zone, s.bindHelper.UpdateAndReload(ctx)))
+}
+
+// applyRecordChanges reloads the zone alone once the changes of its records are saved, see applyChanges. Every zone is
+// reloaded when the zone syncs its PTR records, the reverse zones having changed as well.
+func (s *service) applyRecordChanges(ctx context.Context, zone *domain.Zone) *domain.ValidationWarning {
+	if zone.SyncPTR {
+		return s.applyChanges(ctx, zone)
+	}
+	return appliedWarning(zone, s.leftOutError(zone, s.bindHelper.UpdateAndReloadZone(ctx, zone.Domain)))
+}
+
+// leftOutError returns err, or why the zone was left out of the reload which succeeded, its zone file failing to load.
+func (s *service) leftOutError(zone *domain.Zone, err error) error {
+	if err != nil || zone == nil {
+		return err
+	}
+	return s.bindHelper.LastReload().LeftOutError(zone.Domain)
+}
+
+// zoneSize returns the size of the zone, its zone file being the one last written.
+func zoneSize(zone *domain.Zone) *domain.ZoneSize {
+	size := &domain.ZoneSize{Records: len(zone.Records)}
+	if info, err := os.Stat(zone.FilePath); err == nil {
+		size.FileSize = info.Size()
+	}
+	return size
+}
+
+// zoneSizeWarnings warns about the zone once a change grew it close to the zone limits of the settings.
+func (s *service) zoneSizeWarnings(zone *domain.Zone) []*domain.ValidationWarning {
+	limits := s.settings.Settings().ZoneLimits
+	return limits.Warnings(zoneSize(zone))
+}
+
+// appliedWarning marks the zone applied once err, the outcome of the reload, is nil, returning it as a warning
+// otherwise.
+func appliedWarning(zone *domain.Zone, err error) *domain.ValidationWarning {
+	if err == nil {
+		// The changes deferred by the notify window of the zone are applied once the window is over.
+		if zone != nil && !zone.PublishDeferred(time.Now()) {
+			zone.AppliedRevision = zone.Revision
+		}
+		return nil
+	}
+	log.Println(err)
+	return &domain.ValidationWarning{
+		Code:    domain.WarningNotApplied,
+		Message: fmt.Sprintf("change is saved but not applied yet: %v", err),
+	}
+}
+
+// publishNameServerChange publishes the delegation when a change of the zone changed its name servers. The zone has
+// been changed already, a failure is returned as a warning to retry the publication later.
+func (s *service) publishNameServerChange(
+	ctx context.Context, zone *domain.Zone, previousNameServers []string,
+) *domain.ValidationWarning {
+	if zone.Registrar == "" || strings.Join(previousNameServers, " ") == strings.Join(zone.NameServers(), " ") {
+		return nil
+	}
+	err := s.publishDelegation(ctx, zone)
+	if err == nil {
+		return nil
+	}
+	log.Println(err)
+	return &domain.ValidationWarning{
+		Code:    domain.WarningRegistrarPublishFailed,
+		Message: fmt.Sprintf("name servers could not be published at the registrar: %v", err),
+	}
+}
+
+// syncPTR mirrors the change of an A or AAAA record of a zone syncing its PTR records in the reverse zones managed
+// here, previous being the record before the change, nil when created, and record nil when deleted. The zone has been
+// changed already, a failure is returned as a warning.
+func (s *service) syncPTR(
+	ctx context.Context, zone *domain.Zone, previous, record *domain.Record, change *domain.ChangeMetadata,
+) *domain.ValidationWarning {
+	if !zone.SyncPTR {
+		return nil
+	}
+	previousReverseName, previousTarget, hadPTR := zone.PTROf(previous)
+	reverseName, target, hasPTR := zone.PTROf(record)
+	if !hadPTR && !hasPTR || hadPTR && hasPTR && previousReverseName == reverseName && previousTarget == target {
+		return nil
+	}
+
+	err := s.updatePTR(ctx, zone.Domain, previousReverseName, previousTarget, reverseName, target, change)
+	if err == nil {
+		return nil
+	}
+	log.Println(err)
+	return &domain.ValidationWarning{
+		Code:    domain.WarningPTRSyncFailed,
+		Message: fmt.Sprintf("PTR records could not be kept in line: %v", err),
+	}
+}
+
+// updatePTR removes the PTR record of previousReverseName pointing to previousTarget and points the one of
+// reverseName to target, in the reverse zones managed here. The lock of forwardDomain is held by the caller, the
+// reverse zones are locked after it in the order of domain.ZoneLocker and read again before they are changed.
+func (s *service) updatePTR(
+	ctx context.Context, forwardDomain, previousReverseName, previousTarget, reverseName, target string,
+	change *domain.ChangeMetadata,
+) error {
+	allZones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return err
+	}
+	var reverseDomains []string
+	for _, name := range []string{previousReverseName, reverseName} {
+		reverse := domain.FindReverseZone(allZones, name)
+		if reverse != nil && (len(reverseDomains) == 0 || reverseDomains[0] != reverse.Domain) {
+			reverseDomains = append(reverseDomains, reverse.Domain)
+		}
+	}
+	domain.SortZoneLockOrder(reverseDomains)
+
+	var zones []*domain.Zone
+	for _, reverseDomain := range reverseDomains {
+		if domain.NormalizeDomain(reverseDomain) != domain.NormalizeDomain(forwardDomain) {
+			defer s.zoneLocks.Lock(reverseDomain)()
+		}
+		reverse, err := s.zoneRepository.GetZoneByDomain(ctx, reverseDomain)
+		if err != nil {
+			return err
+		}
+		if reverse != nil {
+			zones = append(zones, reverse)
+		}
+	}
+	changedZones := map[string]*domain.Zone{}
+
+	if reverse := domain.FindReverseZone(zones, previousReverseName); reverse != nil {
+		err = reverse.CheckChange(change)
+		if err != nil {
+			return err
+		}
+		if removed := reverse.RemovePTR(previousReverseName, previousTarget); removed != nil {
+			reverse.AddEvent(domain.NewRecordEvent(domain.EventRecordDeleted, reverse, removed, nil).WithChange(change))
+			changedZones[reverse.Id] = reverse
+		}
+	}
+
+	if reverse := domain.FindReverseZone(zones, reverseName); reverse != nil {
+		err = reverse.CheckChange(change)
+		if err != nil {
+			return err
+		}
+		updated, previousRecord, err := reverse.SetPTR(reverseName, target)
+		if err != nil {
+			return err
+		}
+		if updated != nil {
+			eventType := domain.EventRecordCreated
+			if previousRecord != nil {
+				eventType = domain.EventRecordUpdated
+			}
+			reverse.AddEvent(domain.NewRecordEvent(eventType, reverse, updated, previousRecord).WithChange(change))
+			changedZones[reverse.Id] = reverse
+		}
+	}
+
+	for _, reverse := range changedZones {
+		err = s.checkZoneChange(ctx, reverse)
+		if err != nil {
+			return err
+		}
+	}
+	for _, reverse := range changedZones {
+		err = s.zoneRepository.Persist(ctx, reverse)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) GetZoneRegistration(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	zone, err := s.zoneRepository.GetZoneByDomain(ctx, domainName)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zone == nil {
+		return responseNotFound(c, "zone is not found")
+	}
+
+	registration, err := s.registrations.Lookup(ctx, zone.Domain)
+	if errors.Is(err, domain.ErrorRegistrationNotFound) {
+		return responseNotFound(c, err.Error())
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	comparison := domain.CompareNameServers(registration.NameServers, zone)
+	return c.JSON(http.StatusOK, registrationMapper(registration, comparison))
+}
+
+func (s *service) GetZoneArchives(c echo.Context) error {
+	archives, err := s.archiveRepository.GetAllArchives(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	archivesRes := make([]*external.ZoneArchiveRes, 0)
+	for _, archive := range archives {
+		archivesRes = append(archivesRes, zoneArchiveMapper(archive))
+	}
+	return c.JSON(http.StatusOK, archivesRes)
+}
+
+func (s *service) GetZoneArchiveBundle(c echo.Context, archiveId string) error {
+	ctx := c.Request().Context()
+
+	archive, err := s.archiveRepository.GetArchiveById(ctx, archiveId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if archive == nil {
+		return responseNotFound(c, "archive is not found")
+	}
+
+	bundle, err := s.archiveRepository.GetBundle(ctx, archive)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%v-%v.json.gz"`,
+		archive.Domain, archive.ArchivedAt.UTC().Format("20060102150405")))
+	return c.Blob(http.StatusOK, "application/gzip", bundle)
+}
+
+func (s *service) RestoreZoneArchive(c echo.Context, archiveId string) error {
+	ctx := c.Request().Context()
+
+	archive, err := s.archiveRepository.GetArchiveById(ctx, archiveId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if archive == nil {
+		return responseNotFound(c, "archive is not found")
+	}
+
+	encoded, err := s.archiveRepository.GetBundle(ctx, archive)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle, err := domain.DecodeZoneArchiveBundle(bytes.NewReader(encoded))
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	zone := bundle.Zone
+
+	defer s.zoneLocks.Lock(zone.Domain)()
+
+	zoneExist, err := s.zoneRepository.GetZoneByDomain(ctx, zone.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if zoneExist != nil {
+		return responseClientErr(c, errors.New("zone already exists"))
+	}
+
+	change := changeMetadata(c)
+	err = zone.CheckChange(change)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	// A key defined differently since the zone was archived would make named.conf invalid.
+	err = s.checkTransferKeys(ctx, zone)
+	if errors.Is(err, domain.ErrorTSIGKeyConflict) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.checkZoneChange(c.Request().Context(), zone)
+	if err != nil {
+		return responseZoneCheckErr(c, err)
+	}
+
+	// The bind folder may have moved since the zone was archived.
+	zone.FilePath = ""
+	zone.AddEvent(domain.NewZoneEvent(domain.EventZoneRestored, zone).WithChange(change))
+
+	err = s.zoneRepository.Persist(ctx, zone)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	s.events.Notify()
+
+	warning := s.applyChanges(ctx, zone)
+	zoneRes := zoneMapper(zone)
+	if warning != nil {
+		zoneRes.Warnings = validationWarningsMapper([]*domain.ValidationWarning{warning})
+	}
+	return c.JSON(http.StatusCreated, zoneRes)
+}
+
+func (s *service) DeleteZoneArchive(c echo.Context, archiveId string) error {
+	ctx := c.Request().Context()
+
+	archive, err := s.archiveRepository.GetArchiveById(ctx, archiveId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if archive == nil {
+		return responseNotFound(c, "archive is not found")
+	}
+
+	err = s.archiveRepository.DeleteArchive(ctx, archive)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) GetAuditLogs(c echo.Context, params external.GetAuditLogsParams) error {
+	filter := domain.AuditLogFilter{}
+	if params.Zone != nil {
+		filter.Zone = domain.NormalizeDomain(*params.Zone)
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+
+	events, err := s.auditLogRepository.GetAuditLogs(c.Request().Context(), filter)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	auditLogsRes := make([]*external.AuditLogRes, 0)
+	for _, event := range events {
+		auditLogsRes = append(auditLogsRes, auditLogMapper(event))
+	}
+	return c.JSON(http.StatusOK, auditLogsRes)
+}
+
+func (s *service) GetAuditExporters(c echo.Context) error {
+	exporters, err := s.auditLogRepository.GetAllExporters(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	exportersRes := make([]*external.AuditExporterRes, 0)
+	for _, exporter := range exporters {
+		exportersRes = append(exportersRes, auditExporterMapper(exporter))
+	}
+	return c.JSON(http.StatusOK, exportersRes)
+}
+
+func (s *service) CreateAuditExporter(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateAuditExporterJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Type == "" || req.Address == "" {
+		return responseClientErr(c, errors.New("make sure type and address are set"))
+	}
+
+	exporter := domain.NewAuditExporter(string(req.Type), req.Address)
+	if req.Protocol != nil {
+		exporter.Protocol = string(*req.Protocol)
+	}
+	if req.Enabled != nil {
+		exporter.Enabled = *req.Enabled
+	}
+	if !exporter.IsValid() {
+		return responseClientErr(c, errors.New("exporter is not valid"))
+	}
+
+	err := s.auditLogRepository.PersistExporter(ctx, exporter)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, auditExporterMapper(exporter))
+}
+
+func (s *service) UpdateAuditExporter(c echo.Context, exporterId string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateAuditExporterJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	exporter, err := s.auditLogRepository.GetExporterById(ctx, exporterId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if exporter == nil {
+		return responseNotFound(c, "exporter is not found")
+	}
+
+	updated := domain.NewAuditExporter(string(req.Type), req.Address)
+	updated.Id = exporter.Id
+	if req.Protocol != nil {
+		updated.Protocol = string(*req.Protocol)
+	}
+	if req.Enabled != nil {
+		updated.Enabled = *req.Enabled
+	}
+	if !updated.IsValid() {
+		return responseClientErr(c, errors.New("exporter is not valid"))
+	}
+
+	err = s.auditLogRepository.PersistExporter(ctx, updated)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, auditExporterMapper(updated))
+}
+
+func (s *service) DeleteAuditExporter(c echo.Context, exporterId string) error {
+	ctx := c.Request().Context()
+
+	exporter, err := s.auditLogRepository.GetExporterById(ctx, exporterId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if exporter == nil {
+		return responseNotFound(c, "exporter is not found")
+	}
+
+	err = s.auditLogRepository.DeleteExporter(ctx, exporter)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) GetWebhooks(c echo.Context) error {
+	webhooks, err := s.webhookRepository.GetAllWebhooks(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	webhooksRes := make([]*external.WebhookRes, 0)
+	for _, webhook := range webhooks {
+		webhooksRes = append(webhooksRes, webhookMapper(webhook))
+	}
+	return c.JSON(http.StatusOK, webhooksRes)
+}
+
+func (s *service) CreateWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateWebhookJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Url == "" {
+		return responseClientErr(c, errors.New("make sure url is set"))
+	}
+
+	webhook := domain.NewWebhook(req.Url)
+	applyWebhookReq(webhook, external.WebhookReq(*req))
+	if !webhook.IsValid() {
+		return responseClientErr(c, errors.New("webhook is not valid"))
+	}
+
+	err := s.webhookRepository.PersistWebhook(ctx, webhook)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, webhookMapper(webhook))
+}
+
+func (s *service) UpdateWebhook(c echo.Context, webhookId string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateWebhookJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	webhook, err := s.webhookRepository.GetWebhookById(ctx, webhookId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if webhook == nil {
+		return responseNotFound(c, "webhook is not found")
+	}
+
+	applyWebhookReq(webhook, external.WebhookReq(*req))
+	if !webhook.IsValid() {
+		return responseClientErr(c, errors.New("webhook is not valid"))
+	}
+
+	err = s.webhookRepository.PersistWebhook(ctx, webhook)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, webhookMapper(webhook))
+}
+
+func (s *service) DeleteWebhook(c echo.Context, webhookId string) error {
+	ctx := c.Request().Context()
+
+	webhook, err := s.webhookRepository.GetWebhookById(ctx, webhookId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if webhook == nil {
+		return responseNotFound(c, "webhook is not found")
+	}
+
+	err = s.webhookRepository.DeleteWebhook(ctx, webhook)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+// applyWebhookReq copies the fields set in a webhook request, an empty url leaves the current one.
+func applyWebhookReq(webhook *domain.Webhook, req external.WebhookReq) {
+	if req.Url != "" {
+		webhook.Url = req.Url
+	}
+	if req.Events != nil {
+		webhook.Events = *req.Events
+	}
+	if req.Zones != nil {
+		webhook.Zones = nil
+		for _, zoneDomain := range *req.Zones {
+			webhook.Zones = append(webhook.Zones, domain.NormalizeDomain(zoneDomain))
+		}
+	}
+	if req.RecordTypes != nil {
+		webhook.RecordTypes = nil
+		for _, recordType := range *req.RecordTypes {
+			webhook.RecordTypes = append(webhook.RecordTypes, strings.ToUpper(recordType))
+		}
+	}
+	if req.PayloadTemplate != nil {
+		webhook.PayloadTemplate = *req.PayloadTemplate
+	}
+	if req.ContentType != nil {
+		webhook.ContentType = *req.ContentType
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+}
+
+func (s *service) GetRpzFeeds(c echo.Context) error {
+	feeds, err := s.rpzRepository.GetAllFeeds(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	feedsRes := make([]*external.RpzFeedRes, 0)
+	for _, feed := range feeds {
+		feedsRes = append(feedsRes, rpzFeedMapper(feed))
+	}
+	return c.JSON(http.StatusOK, feedsRes)
+}
+
+func (s *service) CreateRpzFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateRpzFeedJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Name == "" || req.Url == "" {
+		return responseClientErr(c, errors.New("make sure name and url are set"))
+	}
+
+	feed := domain.NewRpzFeed(req.Name, req.Url, domain.DefaultRpzFeedRefreshInterval)
+	if req.Profile != nil {
+		feed.Profile = *req.Profile
+	}
+	if req.RefreshInterval != nil {
+		feed.RefreshInterval = time.Duration(*req.RefreshInterval) * time.Second
+	}
+	if req.Enabled != nil {
+		feed.Enabled = *req.Enabled
+	}
+	if !feed.IsValid() {
+		return responseClientErr(c, errors.New("feed is not valid"))
+	}
+
+	err := s.rpzRepository.PersistFeed(ctx, feed)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	if feed.Enabled {
+		go func(feed domain.RpzFeed) {
+			err := s.rpzFeedUpdater.Refresh(context.Background(), &feed)
+			if err != nil {
+				log.Println(err)
+			}
+		}(*feed)
+	}
+
+	return c.JSON(http.StatusCreated, rpzFeedMapper(feed))
+}
+
+func (s *service) UpdateRpzFeed(c echo.Context, feedId string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateRpzFeedJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	feed, err := s.rpzRepository.GetFeedById(ctx, feedId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if feed == nil {
+		return responseNotFound(c, "feed is not found")
+	}
+
+	if req.Name != "" {
+		feed.Name = req.Name
+	}
+	if req.Url != "" && req.Url != feed.Url {
+		feed.Url = req.Url
+		feed.LastRefreshed, feed.LastAttempted = time.Time{}, time.Time{}
+	}
+	if req.Profile != nil {
+		feed.Profile = *req.Profile
+	}
+	if req.RefreshInterval != nil {
+		feed.RefreshInterval = time.Duration(*req.RefreshInterval) * time.Second
+	}
+	if req.Enabled != nil {
+		feed.Enabled = *req.Enabled
+	}
+	if !feed.IsValid() {
+		return responseClientErr(c, errors.New("feed is not valid"))
+	}
+
+	err = s.rpzRepository.PersistFeed(ctx, feed)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rpzFeedMapper(feed))
+}
+
+func (s *service) DeleteRpzFeed(c echo.Context, feedId string) error {
+	ctx := c.Request().Context()
+
+	feed, err := s.rpzRepository.GetFeedById(ctx, feedId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if feed == nil {
+		return responseNotFound(c, "feed is not found")
+	}
+
+	err = s.rpzRepository.DeleteFeed(ctx, feed)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.rpzFeedUpdater.Remove(ctx, feed)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) RefreshRpzFeed(c echo.Context, feedId string) error {
+	ctx := c.Request().Context()
+
+	feed, err := s.rpzRepository.GetFeedById(ctx, feedId)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if feed == nil {
+		return responseNotFound(c, "feed is not found")
+	}
+
+	err = s.rpzFeedUpdater.Refresh(ctx, feed)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rpzFeedMapper(feed))
+}
+
+func (s *service) GetRpzProfiles(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	feeds, err := s.rpzRepository.GetAllFeeds(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	storedProfiles, err := s.rpzRepository.GetProfiles(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	profilesRes := make([]*external.RpzProfileRes, 0)
+	for _, profile := range domain.MergeRpzProfiles(storedProfiles, feeds) {
+		profilesRes = append(profilesRes, rpzProfileMapper(profile, feeds))
+	}
+	return c.JSON(http.StatusOK, profilesRes)
+}
+
+func (s *service) UpdateRpzProfile(c echo.Context, name string) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateRpzProfileJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	feeds, err := s.rpzRepository.GetAllFeeds(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	storedProfiles, err := s.rpzRepository.GetProfiles(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	profile := domain.FindRpzProfile(domain.MergeRpzProfiles(storedProfiles, feeds), name)
+	if profile == nil {
+		return responseNotFound(c, "profile is not found")
+	}
+	profile.Enabled = req.Enabled
+	if req.Views != nil {
+		err = profile.SetViews(*req.Views)
+		if err != nil {
+			return responseClientErr(c, err)
+		}
+		options, err := s.serverRepository.GetOptions(ctx)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		for _, viewName := range profile.Views {
+			if options.FindView(viewName) == nil {
+				return responseClientErr(c, fmt.Errorf("%w: %v", domain.ErrorUnknownView, viewName))
+			}
+		}
+	}
+
+	err = s.rpzRepository.PersistProfile(ctx, profile)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	hasFeed := false
+	for _, feed := range feeds {
+		if feed.Profile == profile.Name {
+			hasFeed = true
+			break
+		}
+	}
+	if profile.Enabled && !hasFeed {
+		for i, feedUrl := range profile.DefaultFeedUrls {
+			feed := domain.NewRpzFeed(fmt.Sprintf("%v-%v", profile.Name, i+1), feedUrl,
+				domain.DefaultRpzFeedRefreshInterval)
+			feed.Profile = profile.Name
+			err = s.rpzRepository.PersistFeed(ctx, feed)
+			if err != nil {
+				return responseServerErr(c, err)
+			}
+			feeds = append(feeds, feed)
+
+			go func(feed domain.RpzFeed) {
+				err := s.rpzFeedUpdater.Refresh(context.Background(), &feed)
+				if err != nil {
+					log.Println(err)
+				}
+			}(*feed)
+		}
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rpzProfileMapper(profile, feeds))
+}
+
+func (s *service) GetRpzAllowlist(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rpzAllowlistMapper(options))
+}
+
+func (s *service) UpdateRpzAllowlist(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateRpzAllowlistJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = options.SetRpzAllowlist(req.Domains)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rpzAllowlistMapper(options))
+}
+
+func (s *service) GetBlackhole(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, blackholeMapper(options))
+}
+
+func (s *service) UpdateBlackhole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateBlackholeJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	presets, networks := options.BlackholePresets, options.BlackholeNetworks
+	if req.Presets != nil {
+		presets = *req.Presets
+	}
+	if req.Networks != nil {
+		networks = *req.Networks
+	}
+
+	err = options.SetBlackhole(presets, networks)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, blackholeMapper(options))
+}
+
+func (s *service) GetBlackholePresets(c echo.Context) error {
+	presetsRes := make([]*external.BlackholePresetRes, 0)
+	for _, preset := range domain.BlackholePresets {
+		presetsRes = append(presetsRes, &external.BlackholePresetRes{
+			Name:        preset.Name,
+			Description: preset.Description,
+			Networks:    preset.Networks,
+		})
+	}
+	return c.JSON(http.StatusOK, presetsRes)
+}
+
+func (s *service) FlushCache(c echo.Context) error {
+	req := new(external.FlushCacheJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	name := ""
+	if req.Name != nil {
+		name = *req.Name
+	}
+	tree := req.Tree != nil && *req.Tree
+	if name == "" && tree {
+		return responseClientErr(c, errors.New("name is required to flush a tree"))
+	}
+
+	err := s.bindHelper.FlushCache(c.Request().Context(), name, tree)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) DumpCache(c echo.Context) error {
+	dump, err := s.bindHelper.DumpCache(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.Blob(http.StatusOK, "text/plain; charset=UTF-8", dump)
+}
+
+func (s *service) ExportRecoveryBundle(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.ExportRecoveryBundleJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+	if len(req.Passphrase) < domain.MinRecoveryPassphraseLength {
+		return responseClientErr(c, domain.ErrorShortRecoveryPassphrase)
+	}
+
+	var err error
+	bundle := domain.NewRecoveryBundle(s.migration.SchemaVersion())
+	bundle.Options, err = s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle.Zones, err = s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle.Fragments, err = s.fragmentRepository.GetAllFragments(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle.TSIGKeys, err = s.tsigKeyRepository.GetAllTSIGKeys(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle.RpzFeeds, err = s.rpzRepository.GetAllFeeds(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	bundle.RpzProfiles, err = s.rpzRepository.GetProfiles(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	encoded, err := bundle.Encode(req.Passphrase)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="recovery-%v.bundle"`, bundle.ExportedAt.Format("20060102-150405")))
+	return c.Blob(http.StatusOK, "application/octet-stream", encoded)
+}
+
+func (s *service) GetConsistencyReport(c echo.Context) error {
+	return c.JSON(http.StatusOK, consistencyReportMapper(s.consistencyReport))
+}
+
+func (s *service) GetClusterInstances(c echo.Context) error {
+	instances, err := s.cluster.Instances(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	instancesRes := make([]*external.InstanceRes, 0)
+	for _, instance := range instances {
+		instancesRes = append(instancesRes, &external.InstanceRes{
+			Id:            instance.Id,
+			Hostname:      instance.Hostname,
+			Version:       instance.Version,
+			SchemaVersion: instance.SchemaVersion,
+			Role:          external.InstanceResRole(instance.Role),
+			SyncStatus:    external.InstanceResSyncStatus(instance.SyncStatus),
+			Self:          instance.Id == s.cluster.InstanceId(),
+			StartedAt:     instance.StartedAt,
+			LastSeenAt:    instance.LastSeenAt,
+		})
+	}
+	return c.JSON(http.StatusOK, instancesRes)
+}
+
+func (s *service) GetFailoverStatus(c echo.Context) error {
+	return c.JSON(http.StatusOK, failoverMapper(s.failover.Status()))
+}
+
+func (s *service) PromoteFailover(c echo.Context) error {
+	err := s.failover.Promote(c.Request().Context())
+	if errors.Is(err, domain.ErrorFailoverNotStandby) {
+		return responseClientErr(c, err)
+	}
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return c.JSON(http.StatusOK, failoverMapper(s.failover.Status()))
+}
+
+func (s *service) GetSerialConsistency(c echo.Context) error {
+	consistency := s.serials.Consistency()
+	if consistency == nil {
+		return responseNotFound(c, "serial check is not configured")
+	}
+	return c.JSON(http.StatusOK, serialConsistencyMapper(consistency))
+}
+
+func (s *service) GetFaults(c echo.Context) error {
+	if s.faults == nil {
+		return responseNotFound(c, domain.ErrorChaosModeDisabled.Error())
+	}
+	return c.JSON(http.StatusOK, faultsMapper(s.faults.Faults()))
+}
+
+func (s *service) InjectFault(c echo.Context) error {
+	if s.faults == nil {
+		return responseNotFound(c, domain.ErrorChaosModeDisabled.Error())
+	}
+
+	req := new(external.InjectFaultJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	fault := &domain.Fault{Type: string(req.Type)}
+	if req.DelayMs != nil {
+		fault.Delay = time.Duration(*req.DelayMs) * time.Millisecond
+	}
+	if req.Count != nil {
+		fault.Remaining = *req.Count
+	}
+	if !fault.IsValid() {
+		return responseClientErr(c, domain.ErrorInvalidFault)
+	}
+
+	err := s.faults.Inject(c.Request().Context(), fault)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return c.JSON(http.StatusOK, faultsMapper(s.faults.Faults()))
+}
+
+func (s *service) ClearFaults(c echo.Context) error {
+	if s.faults == nil {
+		return responseNotFound(c, domain.ErrorChaosModeDisabled.Error())
+	}
+	s.faults.Clear()
+	return responseOk(c, "injected faults are cleared")
+}
+
+func (s *service) GetForwarders(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, s.forwardersMapper(options.Forwarders))
+}
+
+func (s *service) UpdateForwarders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateForwardersJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = options.SetForwarders(req.Forwarders)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, s.forwardersMapper(options.Forwarders))
+}
+
+func (s *service) GetViews(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, viewsMapper(options.Views))
+}
+
+// UpdateViews replaces the views, the views removed having to serve no zone nor record on their own.
+func (s *service) UpdateViews(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateViewsJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	previousViews := options.Views
+
+	var views []*domain.View
+	for _, view := range req.Views {
+		views = append(views, &domain.View{Name: view.Name, MatchClients: view.MatchClients})
+	}
+	err = options.SetViews(views)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	zones, err := s.zoneRepository.GetAllZones(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	rpzProfiles, err := s.rpzRepository.GetProfiles(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	for _, view := range previousViews {
+		if options.FindView(view.Name) != nil {
+			continue
+		}
+		for _, zone := range zones {
+			if zone.UsesView(view.Name) {
+				return responseClientErr(c, fmt.Errorf("%w: %v (%v)", domain.ErrorViewInUse, view.Name, zone.Domain))
+			}
+		}
+		for _, profile := range rpzProfiles {
+			if profile.UsesView(view.Name) {
+				return responseClientErr(c, fmt.Errorf("%w: %v (response policy profile %v)", domain.ErrorViewInUse,
+					view.Name, profile.Name))
+			}
+		}
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, viewsMapper(options.Views))
+}
+
+func (s *service) GetQueryLog(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, queryLogMapper(options))
+}
+
+func (s *service) UpdateQueryLog(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateQueryLogJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	if req.Enabled && !options.QueryLog {
+		options.QueryLogSince = time.Now()
+	}
+	if !req.Enabled {
+		options.QueryLogSince = time.Time{}
+	}
+	options.QueryLog = req.Enabled
+	if req.ClientSubnetStats != nil {
+		options.ClientSubnetStats = *req.ClientSubnetStats
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, queryLogMapper(options))
+}
+
+func (s *service) GetNetworkStats(c echo.Context) error {
+	networks := s.queryStats.Networks()
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Queries > networks[j].Queries
+	})
+
+	statsRes := &external.NetworkStatsRes{
+		Since:    s.queryStats.Since(),
+		Networks: make([]external.NetworkStats, 0),
+	}
+	for _, network := range networks {
+		statsRes.Networks = append(statsRes.Networks, *networkStatsMapper(network))
+	}
+	return c.JSON(http.StatusOK, statsRes)
+}
+
+func (s *service) GetInsights(c echo.Context, params external.GetInsightsParams) error {
+	ctx := c.Request().Context()
+
+	limit := domain.DefaultMissingNamesLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 {
+		return responseClientErr(c, errors.New("limit must be at least 1"))
+	}
+	domainName := ""
+	if params.Domain != nil {
+		domainName = domain.NormalizeDomain(*params.Domain)
+	}
+
+	var missingNames []*domain.MissingName
+	for _, missing := range s.queryStats.MissingNames() {
+		if domainName == "" || missing.Zone == domainName {
+			missingNames = append(missingNames, missing)
+		}
+	}
+	sort.Slice(missingNames, func(i, j int) bool {
+		if missingNames[i].Queries != missingNames[j].Queries {
+			return missingNames[i].Queries > missingNames[j].Queries
+		}
+		return missingNames[i].LastQueriedAt.After(missingNames[j].LastQueriedAt)
+	})
+	if len(missingNames) > limit {
+		missingNames = missingNames[:limit]
+	}
+
+	res := &external.InsightsRes{
+		Since:        s.queryStats.Since(),
+		MissingNames: make([]external.MissingNameRes, 0),
+	}
+	zones := map[string]*domain.Zone{}
+	for _, missing := range missingNames {
+		zone, ok := zones[missing.Zone]
+		if !ok {
+			var err error
+			zone, err = s.zoneRepository.GetZoneByDomain(ctx, missing.Zone)
+			if err != nil {
+				return responseServerErr(c, err)
+			}
+			zones[missing.Zone] = zone
+		}
+		var suggestions []*domain.Record
+		if zone != nil {
+			suggestions = zone.SuggestRecords(missing)
+		}
+		res.MissingNames = append(res.MissingNames, *missingNameMapper(missing, suggestions))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *service) GetInventory(c echo.Context, params external.GetInventoryParams) error {
+	ctx := c.Request().Context()
+
+	selectors, err := labelSelectors(params.Label)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	var zones []*domain.Zone
+	if params.Domain != nil {
+		zone, err := s.zoneRepository.GetZoneByDomain(ctx, *params.Domain)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		if zone == nil {
+			return responseNotFound(c, "zone is not found")
+		}
+		zones = append(zones, zone)
+	} else {
+		zones, err = s.zoneRepository.GetAllZones(ctx)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+
+	var hosts []*domain.InventoryHost
+	for _, zone := range zones {
+		hosts = append(hosts, zone.InventoryHosts(selectors)...)
+	}
+	return c.JSON(http.StatusOK, domain.AnsibleInventory(hosts))
+}
+
+func (s *service) GetScrapeTargets(c echo.Context, params external.GetScrapeTargetsParams) error {
+	ctx := c.Request().Context()
+
+	selectors, err := labelSelectors(params.Label)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	var zones []*domain.Zone
+	if params.Domain != nil {
+		zone, err := s.zoneRepository.GetZoneByDomain(ctx, *params.Domain)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+		if zone == nil {
+			return responseNotFound(c, "zone is not found")
+		}
+		zones = append(zones, zone)
+	} else {
+		zones, err = s.zoneRepository.GetAllZones(ctx)
+		if err != nil {
+			return responseServerErr(c, err)
+		}
+	}
+
+	res := make([]external.ScrapeTargetGroupRes, 0)
+	for _, zone := range zones {
+		for _, target := range zone.ScrapeTargets(selectors) {
+			res = append(res, external.ScrapeTargetGroupRes{Targets: []string{target.Target}, Labels: target.Labels})
+		}
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// labelSelectors parses the label query parameter, selecting the records having every label.
+func labelSelectors(labels *[]string) ([]*domain.LabelSelector, error) {
+	if labels == nil {
+		return nil, nil
+	}
+	var selectors []*domain.LabelSelector
+	for _, label := range *labels {
+		selector, err := domain.ParseLabelSelector(label)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// containsDomain tells whether the domains given by the client hold the domain, the domains being normalized.
+func containsDomain(domains []string, domainName string) bool {
+	for _, name := range domains {
+		if domain.NormalizeDomain(name) == domain.NormalizeDomain(domainName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *service) GetRecursion(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, recursionMapper(options))
+}
+
+func (s *service) UpdateRecursion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateRecursionJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	allowRecursion := options.AllowRecursion
+	if req.AllowRecursion != nil {
+		allowRecursion = *req.AllowRecursion
+	}
+	allowOpenResolver := false
+	if req.AllowOpenResolver != nil {
+		allowOpenResolver = *req.AllowOpenResolver
+	}
+
+	err = options.SetRecursion(string(req.Mode), allowRecursion, allowOpenResolver)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, recursionMapper(options))
+}
+
+func (s *service) GetDefaultZones(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, defaultZonesMapper(options))
+}
+
+func (s *service) UpdateDefaultZones(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateDefaultZonesJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	content := ""
+	if req.Content != nil {
+		content = *req.Content
+	}
+	err = options.SetDefaultZones(string(req.Mode), content)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, defaultZonesMapper(options))
+}
+
+func (s *service) GetRootHints(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rootHintsMapper(options))
+}
+
+func (s *service) UpdateRootHints(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.UpdateRootHintsJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	content, rootHintsUrl, interval := "", options.RootHintsUrl, options.RootHintsRefreshInterval
+	if req.Content != nil {
+		content = *req.Content
+	}
+	if req.Url != nil {
+		rootHintsUrl = strings.TrimSpace(*req.Url)
+	}
+	if req.RefreshInterval != nil {
+		interval = time.Duration(*req.RefreshInterval) * time.Second
+	}
+	err = options.SetRootHints(string(req.Mode), content, rootHintsUrl, interval)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	if options.IsRootHintsRefreshDue(time.Now()) {
+		go func() {
+			err := s.rootHintsUpdater.Refresh(context.Background())
+			if err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+
+	return c.JSON(http.StatusOK, rootHintsMapper(options))
+}
+
+func (s *service) RefreshRootHints(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if options.RootHints != domain.RootHintsRefresh {
+		return responseClientErr(c, errors.New("root hints are not refreshed"))
+	}
+
+	err = s.rootHintsUpdater.Refresh(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	options, err = s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return c.JSON(http.StatusOK, rootHintsMapper(options))
+}
+
+func (s *service) GetValidationExceptions(c echo.Context) error {
+	options, err := s.serverRepository.GetOptions(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	exceptionsRes := make([]*external.ValidationExceptionRes, 0)
+	for _, domainName := range options.ValidateExcept {
+		exceptionsRes = append(exceptionsRes, &external.ValidationExceptionRes{Domain: domainName})
+	}
+	return c.JSON(http.StatusOK, exceptionsRes)
+}
+
+func (s *service) CreateValidationException(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateValidationExceptionJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Domain == "" {
+		return responseClientErr(c, errors.New("make sure domain is set"))
+	}
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = options.AddValidationException(req.Domain)
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, &external.ValidationExceptionRes{Domain: domain.NormalizeDomain(req.Domain)})
+}
+
+func (s *service) DeleteValidationException(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	options, err := s.serverRepository.GetOptions(ctx)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = options.DeleteValidationException(domainName)
+	if err != nil {
+		return responseNotFound(c, err.Error())
+	}
+
+	err = s.serverRepository.PersistOptions(ctx, options)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.bindHelper.UpdateAndReload(ctx)
+	if err != nil {
+		return responseReloadErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) GetNegativeTrustAnchors(c echo.Context) error {
+	ntas, err := s.serverRepository.GetAllNegativeTrustAnchors(c.Request().Context())
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	ntasRes := make([]*external.NegativeTrustAnchorRes, 0)
+	for _, nta := range ntas {
+		if nta.IsExpired() {
+			continue
+		}
+		ntasRes = append(ntasRes, negativeTrustAnchorMapper(nta))
+	}
+	return c.JSON(http.StatusOK, ntasRes)
+}
+
+func (s *service) CreateNegativeTrustAnchor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	req := new(external.CreateNegativeTrustAnchorJSONRequestBody)
+	if err := c.Bind(req); err != nil {
+		return responseClientErr(c, err)
+	}
+
+	if req.Domain == "" {
+		return responseClientErr(c, errors.New("make sure domain is set"))
+	}
+
+	lifetime := domain.DefaultNegativeTrustAnchorLifetime
+	if req.Lifetime != nil {
+		lifetime = time.Duration(*req.Lifetime) * time.Second
+	}
+	if lifetime <= 0 || lifetime > domain.MaxNegativeTrustAnchorLifetime {
+		return responseClientErr(c, errors.New("lifetime must be between 1 second and 1 week"))
+	}
+
+	nta := domain.NewNegativeTrustAnchor(req.Domain, lifetime)
+	if !nta.IsValid() {
+		return responseClientErr(c, errors.New("negative trust anchor is not valid"))
+	}
+
+	existing, err := s.serverRepository.GetNegativeTrustAnchorByDomain(ctx, nta.Domain)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if existing != nil {
+		nta.Id = existing.Id
+	}
+
+	// The anchor is only stored once named holds it, so that a refused anchor is not re-applied on restart.
+	err = s.bindHelper.AddNegativeTrustAnchor(ctx, nta)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.serverRepository.PersistNegativeTrustAnchor(ctx, nta)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, negativeTrustAnchorMapper(nta))
+}
+
+func (s *service) DeleteNegativeTrustAnchor(c echo.Context, domainName string) error {
+	ctx := c.Request().Context()
+
+	nta, err := s.serverRepository.GetNegativeTrustAnchorByDomain(ctx, domain.NormalizeDomain(domainName))
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	if nta == nil || nta.IsExpired() {
+		return responseNotFound(c, "negative trust anchor is not found")
+	}
+
+	err = s.bindHelper.RemoveNegativeTrustAnchor(ctx, nta)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	err = s.serverRepository.DeleteNegativeTrustAnchor(ctx, nta)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+
+	return responseOk(c, "OK")
+}
+
+func (s *service) ReloadConfig(c echo.Context) error {
+	settings, err := s.settings.Reload(c.Request().Context())
+	if err != nil {
+		return responseClientErr(c, err)
+	}
+	return c.JSON(http.StatusOK, settingsMapper(settings))
+}
+
+func (s *service) GetFeatures(c echo.Context) error {
+	settings := s.settings.Settings()
+	featuresRes := make([]*external.FeatureRes, 0)
+	for _, feature := range domain.Features {
+		featuresRes = append(featuresRes, featureMapper(feature, settings))
+	}
+	return c.JSON(http.StatusOK, featuresRes)
+}
+
+func (s *service) GetLocales(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.localizer.Languages())
+}
+
+func (s *service) GetLocaleCatalog(c echo.Context, language string) error {
+	catalog := s.localizer.Catalog(strings.ToLower(language))
+	if catalog == nil {
+		return responseNotFound(c, "language is not found")
+	}
+	return c.JSON(http.StatusOK, catalog)
+}
+
+// changeMetadata reads the change metadata sent along with a mutation, nil when none is sent.
+func changeMetadata(c echo.Context) *domain.ChangeMetadata {
+	header := c.Request().Header
+	return domain.NewChangeMetadata(
+		header.Get("X-Change-Ticket"), header.Get("X-Change-Reason"), header.Get("X-Change-Requested-By"))
+}
+
+// statusPingTimeout bounds how long the status page waits for named.
+const statusPingTimeout = 3 * time.Second
+
+// getStatusPage serves the health of the service without any zone data, responding 503 while it is degraded so
+// that monitors can watch it as well.
+func (s *service) getStatusPage(c echo.Context) error {
+	settings := s.settings.Settings().StatusPage
+	if !settings.Enabled {
+		return responseNotFound(c, "status page is not enabled")
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), statusPingTimeout)
+	defer cancel()
+	status := domain.NewServiceStatus(settings, s.bindHelper.Ping(ctx), s.bindHelper.LastReload())
+	status.AddNamedCrash(s.bindHelper.LastCrash())
+	status.AddSerialCheck(s.serials.Consistency())
+	content, err := status.RenderHtml()
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	code := http.StatusOK
+	if !status.Healthy() {
+		code = http.StatusServiceUnavailable
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Blob(code, echo.MIMETextHTMLCharsetUTF8, content)
+}
+
+func serveAsset(c echo.Context, name string, contentType string) error {
+	content, err := dnsservermanager.Assets.ReadFile(name)
+	if err != nil {
+		return responseServerErr(c, err)
+	}
+	return c.Blob(http.StatusOK, contentType, content)
+}
+
+// localize translates a message to the language negotiated for the request.
+func localize(c echo.Context, message string) string {
+	if translate, ok := c.Get(translateContextKey).(func(string) string); ok {
+		return translate(message)
+	}
+	return message
+}
+
+func responseOk(c echo.Context, message string) error {
+	return c.JSON(http.StatusOK, external.GeneralRes{
+		Code:    http.StatusOK,
+		Message: localize(c, message),
+	})
+}
+
+// responseOkWithWarnings responds to the deletions of records, the message being the one of the warning the change is
+// not applied with, if any, and the warnings being returned along.
+func responseOkWithWarnings(c echo.Context, warnings []*domain.ValidationWarning) error {
+	message := "OK"
+	for _, warning := range warnings {
+		if warning.Code == domain.WarningNotApplied {
+			message = warning.Message
+			break
+		}
+	}
+	return c.JSON(http.StatusOK, external.GeneralRes{
+		Code:     http.StatusOK,
+		Message:  localize(c, message),
+		Warnings: validationWarningsMapper(warnings),
+	})
+}
+
+func responseNotFound(c echo.Context, message string) error {
+	return c.JSON(http.StatusNotFound, external.GeneralRes{
+		Code:    http.StatusNotFound,
+		Message: localize(c, message),
+	})
+}
+
+func responseServerErr(c echo.Context, err error) error {
+	return c.JSON(http.StatusInternalServerError, external.GeneralRes{
+		Code:    http.StatusInternalServerError,
+		Message: localize(c, err.Error()),
+	})
+}
+
+// responseZoneCheckErr responds with the error of a zone check, the zone files named-checkzone rejects being made of
+// the request.
+func responseZoneCheckErr(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrorZoneCheckFailed) {
+		return responseClientErr(c, err)
+	}
+	return responseServerErr(c, err)
+}
+
+// responseReloadErr responds with the error of a failed reload, the configs named-checkconf rejects being made of the
+// request.
+func responseReloadErr(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrorConfigCheckFailed) {
+		return responseClientErr(c, err)
+	}
+	return responseServerErr(c, err)
+}
+
+func responseUnavailable(c echo.Context, err error) error {
+	return c.JSON(http.StatusServiceUnavailable, external.GeneralRes{
+		Code:    http.StatusServiceUnavailable,
+		Message: localize(c, err.Error()),
+	})
+}
+
+func responseClientErr(c echo.Context, err error) error {
+	res := external.GeneralRes{
+		Code:    http.StatusBadRequest,
+		Message: localize(c, err.Error()),
+	}
+	if errorCode := domain.ErrorCode(err); errorCode != "" {
+		res.ErrorCode = &errorCode
+	}
+	return c.JSON(http.StatusBadRequest, res)
+}
+
+func zoneMapper(zone *domain.Zone) *external.ZoneRes {
+	if zone == nil {
+		return nil
+	}
+	var records []external.RecordRes
+	for _, record := range zone.Records {
 		records = append(records, *recordMapper(record))
 	}
-	return &external.ZoneRes{
-		Domain:  zone.Domain,
-		Id:      zone.Id,
-		Records: records,
-		Soa:     *soaMapper(zone.SOA),
+	res := &external.ZoneRes{
+		AppliedRevision:  zone.AppliedRevision,
+		Domain:           zone.Domain,
+		Id:               zone.Id,
+		Notes:            zone.Notes,
+		NotifyInterval:   int(zone.NotifyInterval / time.Second),
+		AllowTransfer:    make([]string, 0),
+		TransferKeys:     make([]external.TsigKeyRes, 0),
+		AlsoNotify:       make([]string, 0),
+		Fragments:        make([]string, 0),
+		Views:            make([]string, 0),
+		DnssecPolicy:     zone.DnssecPolicy,
+		Group:            zone.Group,
+		Variables:        zone.Variables,
+		Records:          records,
+		Registrar:        zone.Registrar,
+		Regulated:        zone.Regulated,
+		Revision:         zone.Revision,
+		Soa:              *soaMapper(zone.SOA),
+		Status:           external.ZoneResStatus(zone.Status()),
+		StrictValidation: zone.StrictValidation,
+		SyncPtr:          zone.SyncPTR,
+		SyncPrimaryNs:    zone.SyncPrimaryNS,
+		ExternalId:       zone.ExternalId,
+		TechnicalContact: zone.TechnicalContact,
+	}
+	if !zone.ExpiresAt.IsZero() {
+		expiresAt := zone.ExpiresAt.Format(domain.ZoneExpirationDateLayout)
+		res.ExpiresAt = &expiresAt
+	}
+	if zone.IsFrozen() {
+		frozenAt, freezeReason := zone.FrozenAt, zone.FreezeReason
+		res.FrozenAt, res.FreezeReason = &frozenAt, &freezeReason
+	}
+	if res.Variables == nil {
+		res.Variables = map[string]string{}
 	}
+	res.AllowTransfer = append(res.AllowTransfer, zone.AllowTransfer...)
+	res.AlsoNotify = append(res.AlsoNotify, zone.AlsoNotify...)
+	res.Fragments = append(res.Fragments, zone.Fragments...)
+	res.Views = append(res.Views, zone.Views...)
+	for _, key := range zone.TransferKeys {
+		res.TransferKeys = append(res.TransferKeys, external.TsigKeyRes{Name: key.Name, Algorithm: key.Algorithm})
+	}
+	return res
 }
 
 func recordMapper(record *domain.Record) *external.RecordRes {
 	if record == nil {
 		return nil
 	}
-	return &external.RecordRes{
-		Id:    record.Id,
-		Name:  record.Name,
-		Type:  external.RecordResType(record.Type),
-		Value: record.Value,
+	res := &external.RecordRes{
+		Id:         record.Id,
+		Name:       record.Name,
+		Type:       external.RecordResType(record.Type),
+		Value:      record.Value,
+		Ttl:        record.TTL,
+		View:       record.View,
+		ExternalId: record.ExternalId,
+		Comment:    record.Comment,
+		Disabled:   record.Disabled,
+		Canary:     record.Canary,
+		Labels:     record.Labels,
+	}
+	if res.Labels == nil {
+		res.Labels = map[string]string{}
+	}
+	if domain.HasRecordPriority(record.Type) {
+		priority := record.Priority
+		res.Priority = &priority
+	}
+	if record.Generator != nil {
+		generatorId := record.Generator.Id
+		res.GeneratorId = &generatorId
+	}
+	return res
+}
+
+func recordGroupMapper(zone *domain.Zone, records []*domain.Record) *external.RecordGroupRes {
+	res := &external.RecordGroupRes{Domain: zone.Domain, Records: make([]external.RecordRes, 0, len(records))}
+	for _, record := range records {
+		res.Records = append(res.Records, *recordMapper(record))
+	}
+	return res
+}
+
+// tsigKeyMapper leaves the secret out.
+func tsigKeyMapper(key *domain.TSIGKey, zones []*domain.Zone) *external.ManagedTsigKeyRes {
+	res := &external.ManagedTsigKeyRes{
+		Name:      key.Name,
+		Algorithm: key.Algorithm,
+		Zones:     make([]string, 0),
+	}
+	for _, zone := range zones {
+		if zone.UsesManagedKey(key.Name) {
+			res.Zones = append(res.Zones, zone.Domain)
+		}
+	}
+	return res
+}
+
+func fragmentMapper(fragment *domain.RecordFragment, zones []*domain.Zone) *external.FragmentRes {
+	res := &external.FragmentRes{
+		Name:    fragment.Name,
+		Records: make([]external.FragmentRecord, 0),
+		Zones:   make([]string, 0),
+	}
+	for _, record := range fragment.Records {
+		recordRes := external.FragmentRecord{Name: record.Name, Type: record.Type, Value: record.Value}
+		if domain.HasRecordPriority(record.Type) {
+			priority := record.Priority
+			recordRes.Priority = &priority
+		}
+		res.Records = append(res.Records, recordRes)
+	}
+	for _, zone := range zones {
+		if zone.IncludesFragment(fragment.Name) {
+			res.Zones = append(res.Zones, zone.Domain)
+		}
+	}
+	return res
+}
+
+func recordGeneratorMapper(zone *domain.Zone, generator *domain.RecordGenerator) *external.RecordGeneratorRes {
+	return &external.RecordGeneratorRes{
+		Id:          generator.Id,
+		Name:        generator.Name,
+		Range:       generator.Range,
+		RecordCount: len(zone.GeneratedRecords(generator)),
+	}
+}
+
+func delegationMapper(delegation *domain.Delegation) *external.DelegationRes {
+	delegationRes := &external.DelegationRes{Name: delegation.Name, NameServers: make([]external.DelegatedNameServer, 0)}
+	for _, nameServer := range delegation.NameServers {
+		nameServerRes := external.DelegatedNameServer{Host: nameServer.Host}
+		if len(nameServer.Addresses) > 0 {
+			addresses := nameServer.Addresses
+			nameServerRes.Addresses = &addresses
+		}
+		delegationRes.NameServers = append(delegationRes.NameServers, nameServerRes)
+	}
+	return delegationRes
+}
+
+// validationWarningsMapper returns nil without warnings so the field is left out of the response.
+func validationWarningsMapper(warnings []*domain.ValidationWarning) *[]external.ValidationWarning {
+	if len(warnings) == 0 {
+		return nil
+	}
+	var warningsRes []external.ValidationWarning
+	for _, warning := range warnings {
+		warningsRes = append(warningsRes, external.ValidationWarning{Code: warning.Code, Message: warning.Message})
+	}
+	return &warningsRes
+}
+
+func consistencyReportMapper(report *domain.ConsistencyReport) *external.ConsistencyReportRes {
+	res := &external.ConsistencyReportRes{Time: report.Time, Actions: []external.ConsistencyAction{}}
+	for _, action := range report.Actions {
+		actionRes := external.ConsistencyAction{
+			Action: external.ConsistencyActionAction(action.Action),
+			Path:   action.Path,
+		}
+		if action.Zone != "" {
+			actionRes.Zone = &action.Zone
+		}
+		if action.Message != "" {
+			actionRes.Message = &action.Message
+		}
+		res.Actions = append(res.Actions, actionRes)
+	}
+	return res
+}
+
+func (s *service) forwardersMapper(forwarders []string) []*external.ForwarderRes {
+	statuses := map[string]*domain.ForwarderStatus{}
+	for _, status := range s.forwarders.Statuses() {
+		statuses[status.Address] = status
+	}
+
+	forwardersRes := make([]*external.ForwarderRes, 0)
+	for _, forwarder := range forwarders {
+		res := &external.ForwarderRes{Address: forwarder, Healthy: true}
+		if status, ok := statuses[forwarder]; ok {
+			latencyMs := float64(status.Latency) / float64(time.Millisecond)
+			res.Healthy = status.Healthy
+			res.ConsecutiveFailures = status.ConsecutiveFailures
+			res.LastChecked = &status.LastChecked
+			res.LatencyMs = &latencyMs
+			if status.LastError != "" {
+				res.LastError = &status.LastError
+			}
+		}
+		forwardersRes = append(forwardersRes, res)
+	}
+	return forwardersRes
+}
+
+func viewsMapper(views []*domain.View) []external.ViewRes {
+	res := make([]external.ViewRes, 0, len(views))
+	for _, view := range views {
+		res = append(res, external.ViewRes{Name: view.Name, MatchClients: view.MatchClients})
+	}
+	return res
+}
+
+func failoverMapper(status *domain.FailoverStatus) *external.FailoverRes {
+	res := &external.FailoverRes{
+		PrimaryHealthy:      status.PrimaryHealthy,
+		ConsecutiveFailures: status.ConsecutiveFailures,
+		Promoted:            status.Promoted,
+	}
+	if status.Role != "" {
+		res.Role = &status.Role
+	}
+	if !status.LastChecked.IsZero() {
+		res.LastChecked = &status.LastChecked
+	}
+	if status.LastError != "" {
+		res.LastError = &status.LastError
+	}
+	if !status.PromotedAt.IsZero() {
+		res.PromotedAt = &status.PromotedAt
+	}
+	return res
+}
+
+func serialConsistencyMapper(consistency *domain.SerialConsistency) *external.SerialConsistencyRes {
+	res := &external.SerialConsistencyRes{
+		AlertAfter: int(consistency.AlertAfter / time.Second),
+		Nodes:      make([]string, 0),
+		Zones:      make([]external.ZoneSerialsRes, 0),
+	}
+	if !consistency.CheckedAt.IsZero() {
+		res.CheckedAt = &consistency.CheckedAt
+	}
+	res.Nodes = append(res.Nodes, consistency.Nodes...)
+	for _, zone := range consistency.Zones {
+		zoneRes := external.ZoneSerialsRes{Domain: zone.Zone, Nodes: make([]external.NodeSerialRes, 0)}
+		if zone.Serial != "" {
+			zoneRes.Serial = &zone.Serial
+		}
+		for _, node := range zone.Nodes {
+			nodeRes := external.NodeSerialRes{Node: node.Node, InSync: node.InSync, Alerting: node.Alerting}
+			if node.Serial != "" {
+				nodeRes.Serial = &node.Serial
+			}
+			if node.Error != "" {
+				nodeRes.Error = &node.Error
+			}
+			if !node.DivergedSince.IsZero() {
+				nodeRes.DivergedSince = &node.DivergedSince
+			}
+			zoneRes.Nodes = append(zoneRes.Nodes, nodeRes)
+		}
+		res.Zones = append(res.Zones, zoneRes)
+	}
+	return res
+}
+
+func faultsMapper(faults []*domain.Fault) []*external.FaultRes {
+	faultsRes := make([]*external.FaultRes, 0)
+	for _, fault := range faults {
+		res := &external.FaultRes{Type: external.FaultResType(fault.Type), InjectedAt: fault.InjectedAt}
+		if fault.Delay > 0 {
+			delayMs := int(fault.Delay / time.Millisecond)
+			res.DelayMs = &delayMs
+		}
+		if fault.Remaining > 0 {
+			remaining := fault.Remaining
+			res.Remaining = &remaining
+		}
+		faultsRes = append(faultsRes, res)
+	}
+	return faultsRes
+}
+
+func zoneArchiveMapper(archive *domain.ZoneArchive) *external.ZoneArchiveRes {
+	return &external.ZoneArchiveRes{
+		Id:          archive.Id,
+		Domain:      archive.Domain,
+		ArchivedAt:  archive.ArchivedAt,
+		RecordCount: archive.RecordCount,
+		Size:        archive.Size,
+	}
+}
+
+func dnssecMapper(status *domain.DnssecStatus) *external.DnssecRes {
+	res := &external.DnssecRes{
+		Policy:  status.Policy,
+		Signing: status.Signing,
+		Keys:    make([]external.DnssecKeyRes, 0),
+		Status:  status.Status,
+	}
+	for _, key := range status.Keys {
+		keyRes := external.DnssecKeyRes{
+			Tag:       key.Tag,
+			Algorithm: key.Algorithm,
+			Flags:     key.Flags,
+			Dnskey:    key.DNSKEY,
+		}
+		if ds := key.FindDS(domain.DSDigestSHA256); ds != nil {
+			record := ds.String()
+			keyRes.Ds = &record
+		}
+		if key.View != "" {
+			view := key.View
+			keyRes.View = &view
+		}
+		res.Keys = append(res.Keys, keyRes)
+	}
+	return res
+}
+
+func dsMapper(keys []*domain.DnssecKey) *external.DsRes {
+	res := &external.DsRes{
+		Ds:      make([]external.DsRecordRes, 0),
+		Dnskeys: make([]string, 0),
+	}
+	for _, key := range keys {
+		res.Dnskeys = append(res.Dnskeys, key.DNSKEY)
+		for _, ds := range key.DS {
+			res.Ds = append(res.Ds, external.DsRecordRes{
+				KeyTag:     ds.KeyTag,
+				Algorithm:  ds.Algorithm,
+				DigestType: ds.DigestType,
+				Digest:     ds.Digest,
+				Record:     ds.String(),
+			})
+		}
+	}
+	return res
+}
+
+func registrationMapper(
+	registration *domain.Registration, comparison *domain.NameServerComparison,
+) *external.RegistrationRes {
+	res := &external.RegistrationRes{
+		Domain:            registration.Domain,
+		MissingAtRegistry: make([]string, 0),
+		NameServers:       make([]string, 0),
+		NameServersMatch:  comparison.Matches(),
+		Registrar:         registration.Registrar,
+		UnknownToZone:     make([]string, 0),
+		ZoneNameServers:   make([]string, 0),
+	}
+	if !registration.ExpiresAt.IsZero() {
+		res.ExpiresAt = &registration.ExpiresAt
+	}
+	res.MissingAtRegistry = append(res.MissingAtRegistry, comparison.MissingAtRegistry...)
+	res.NameServers = append(res.NameServers, comparison.Registry...)
+	res.UnknownToZone = append(res.UnknownToZone, comparison.UnknownToZone...)
+	res.ZoneNameServers = append(res.ZoneNameServers, comparison.Zone...)
+	return res
+}
+
+func auditLogMapper(event *domain.ChangeEvent) *external.AuditLogRes {
+	if event == nil {
+		return nil
+	}
+	res := &external.AuditLogRes{
+		Id:             event.Id,
+		Type:           event.Type,
+		Time:           event.Time,
+		Zone:           event.Zone,
+		Record:         recordMapper(event.Record),
+		PreviousRecord: recordMapper(event.PreviousRecord),
+	}
+	if event.Detail != "" {
+		res.Detail = &event.Detail
+	}
+	if event.Change != nil {
+		res.Change = &external.ChangeMetadata{
+			TicketId:    event.Change.TicketId,
+			Reason:      event.Change.Reason,
+			RequestedBy: event.Change.RequestedBy,
+		}
+	}
+	return res
+}
+
+func auditExporterMapper(exporter *domain.AuditExporter) *external.AuditExporterRes {
+	if exporter == nil {
+		return nil
+	}
+	res := &external.AuditExporterRes{
+		Id:      exporter.Id,
+		Type:    external.AuditExporterResType(exporter.Type),
+		Address: exporter.Address,
+		Enabled: exporter.Enabled,
+	}
+	if exporter.Protocol != "" {
+		protocol := external.AuditExporterResProtocol(exporter.Protocol)
+		res.Protocol = &protocol
+	}
+	return res
+}
+
+func webhookMapper(webhook *domain.Webhook) *external.WebhookRes {
+	if webhook == nil {
+		return nil
+	}
+	res := &external.WebhookRes{
+		Id:              webhook.Id,
+		Url:             webhook.Url,
+		Events:          webhook.Events,
+		Zones:           webhook.Zones,
+		RecordTypes:     webhook.RecordTypes,
+		PayloadTemplate: webhook.PayloadTemplate,
+		ContentType:     webhook.ContentType,
+		Enabled:         webhook.Enabled,
+	}
+	if res.Events == nil {
+		res.Events = make([]string, 0)
+	}
+	if res.Zones == nil {
+		res.Zones = make([]string, 0)
+	}
+	if res.RecordTypes == nil {
+		res.RecordTypes = make([]string, 0)
+	}
+	return res
+}
+
+func settingsMapper(settings *domain.Settings) *external.SettingsRes {
+	return &external.SettingsRes{
+		LogLevel: external.SettingsResLogLevel(settings.LogLevel),
+		RateLimit: external.RateLimit{
+			Burst:             settings.RateLimit.Burst,
+			RequestsPerSecond: settings.RateLimit.RequestsPerSecond,
+		},
+		WebhookCount: len(settings.Webhooks),
+	}
+}
+
+func featureMapper(feature *domain.Feature, settings *domain.Settings) *external.FeatureRes {
+	return &external.FeatureRes{
+		Name:        feature.Name,
+		Description: feature.Description,
+		Enabled:     settings.IsFeatureEnabled(feature.Name),
+		Available:   feature.Available,
+	}
+}
+
+func rpzFeedMapper(feed *domain.RpzFeed) *external.RpzFeedRes {
+	if feed == nil {
+		return nil
+	}
+	res := &external.RpzFeedRes{
+		Id:              feed.Id,
+		Name:            feed.Name,
+		Profile:         feed.Profile,
+		Url:             feed.Url,
+		RefreshInterval: int(feed.RefreshInterval.Seconds()),
+		Enabled:         feed.Enabled,
+		DomainCount:     feed.DomainCount,
+	}
+	if !feed.LastRefreshed.IsZero() {
+		res.LastRefreshed = &feed.LastRefreshed
+	}
+	if feed.LastError != "" {
+		res.LastError = &feed.LastError
+	}
+	return res
+}
+
+func rpzProfileMapper(profile *domain.RpzProfile, feeds []*domain.RpzFeed) *external.RpzProfileRes {
+	if profile == nil {
+		return nil
+	}
+	res := &external.RpzProfileRes{
+		Name:        profile.Name,
+		Description: profile.Description,
+		Enabled:     profile.Enabled,
+		Views:       make([]string, 0),
+	}
+	res.Views = append(res.Views, profile.Views...)
+	for _, feed := range feeds {
+		if feed.Profile == profile.Name {
+			res.FeedCount++
+		}
+	}
+	return res
+}
+
+func rpzAllowlistMapper(options *domain.ServerOptions) *external.RpzAllowlist {
+	if options == nil {
+		return nil
+	}
+	domains := options.RpzAllowlist
+	if domains == nil {
+		domains = make([]string, 0)
+	}
+	return &external.RpzAllowlist{Domains: domains}
+}
+
+func blackholeMapper(options *domain.ServerOptions) *external.BlackholeRes {
+	if options == nil {
+		return nil
+	}
+	res := &external.BlackholeRes{
+		Presets:  options.BlackholePresets,
+		Networks: options.BlackholeNetworks,
+	}
+	if res.Presets == nil {
+		res.Presets = make([]string, 0)
+	}
+	if res.Networks == nil {
+		res.Networks = make([]string, 0)
+	}
+	return res
+}
+
+func queryLogMapper(options *domain.ServerOptions) *external.QueryLogRes {
+	if options == nil {
+		return nil
+	}
+	return &external.QueryLogRes{
+		Enabled:           options.QueryLog,
+		ClientSubnetStats: options.ClientSubnetStats,
+	}
+}
+
+func networkStatsMapper(stats *domain.NetworkQueryStats) *external.NetworkStats {
+	if stats == nil {
+		return nil
+	}
+	source := external.NetworkStatsSourceClient
+	if stats.FromClientSubnet {
+		source = external.NetworkStatsSourceEcs
+	}
+	zones := make([]external.ZoneQueryCount, 0)
+	for zoneDomain, queries := range stats.Zones {
+		zones = append(zones, external.ZoneQueryCount{Domain: zoneDomain, Queries: queries})
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Queries > zones[j].Queries
+	})
+	return &external.NetworkStats{
+		Network: stats.Network,
+		Source:  source,
+		Queries: stats.Queries,
+		Zones:   zones,
+	}
+}
+
+func missingNameMapper(missing *domain.MissingName, suggestions []*domain.Record) *external.MissingNameRes {
+	if missing == nil {
+		return nil
+	}
+	res := &external.MissingNameRes{
+		Domain:        missing.Zone,
+		Name:          missing.Name,
+		Queries:       missing.Queries,
+		Types:         missing.Types,
+		LastQueriedAt: missing.LastQueriedAt,
+		Suggestions:   make([]external.RecordReq, 0),
+	}
+	for _, record := range suggestions {
+		res.Suggestions = append(res.Suggestions, external.RecordReq{
+			Name:  record.Name,
+			Type:  external.RecordReqType(record.Type),
+			Value: record.Value,
+		})
+	}
+	return res
+}
+
+func recursionMapper(options *domain.ServerOptions) *external.RecursionRes {
+	if options == nil {
+		return nil
+	}
+	allowRecursion := options.AllowRecursion
+	if allowRecursion == nil {
+		allowRecursion = make([]string, 0)
+	}
+	return &external.RecursionRes{
+		Mode:              external.RecursionResMode(options.RecursionMode),
+		AllowRecursion:    allowRecursion,
+		AllowOpenResolver: options.AllowOpenResolver,
+	}
+}
+
+func defaultZonesMapper(options *domain.ServerOptions) *external.DefaultZonesRes {
+	if options == nil {
+		return nil
+	}
+	res := &external.DefaultZonesRes{Mode: external.DefaultZonesResMode(options.DefaultZones)}
+	if options.DefaultZonesContent != "" {
+		res.Content = &options.DefaultZonesContent
+	}
+	return res
+}
+
+func rootHintsMapper(options *domain.ServerOptions) *external.RootHintsRes {
+	if options == nil {
+		return nil
+	}
+	res := &external.RootHintsRes{
+		Mode:            external.RootHintsResMode(options.RootHints),
+		Url:             options.RootHintsUrl,
+		RefreshInterval: int(options.RootHintsRefreshInterval.Seconds()),
+	}
+	if options.RootHintsContent != "" {
+		res.Content = &options.RootHintsContent
+	}
+	if !options.RootHintsRefreshedAt.IsZero() {
+		res.LastRefreshed = &options.RootHintsRefreshedAt
+	}
+	if options.RootHintsLastError != "" {
+		res.LastError = &options.RootHintsLastError
+	}
+	return res
+}
+
+func negativeTrustAnchorMapper(nta *domain.NegativeTrustAnchor) *external.NegativeTrustAnchorRes {
+	if nta == nil {
+		return nil
+	}
+	return &external.NegativeTrustAnchorRes{
+		Id:        nta.Id,
+		Domain:    nta.Domain,
+		ExpiresAt: nta.ExpiresAt,
 	}
 }
 
@@ -506,6 +6034,7 @@ func soaMapper(soa *domain.SOARecord) *external.SoaRes {
 		Refresh:           soa.Refresh,
 		Retry:             soa.Retry,
 		Serial:            soa.Serial,
+		SerialStrategy:    external.SoaResSerialStrategy(soa.SerialStrategy),
 		Expire:            soa.Expire,
 		CacheTtl:          soa.CacheTTL,
 	}
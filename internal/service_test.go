@@ -0,0 +1,233 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/anantadwi13/dns-server-manager/internal/domain"
+	"github.com/anantadwi13/dns-server-manager/internal/external"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDNSServer accepts every zone, CheckZone taking checkDelay to widen the window the zones stay locked in. rndc
+// is not running, the negative trust anchors being refused.
+type testDNSServer struct {
+	domain.DNSServer
+	checkDelay time.Duration
+}
+
+func (d *testDNSServer) CheckZone(ctx context.Context, zone *domain.Zone) error {
+	time.Sleep(d.checkDelay)
+	return nil
+}
+
+func (d *testDNSServer) UpdateAndReload(ctx context.Context) error {
+	return nil
+}
+
+func (d *testDNSServer) UpdateAndReloadZone(ctx context.Context, domainName string) error {
+	return nil
+}
+
+func (d *testDNSServer) LastReload() *domain.ReloadResult {
+	return nil
+}
+
+func (d *testDNSServer) AddNegativeTrustAnchor(ctx context.Context, nta *domain.NegativeTrustAnchor) error {
+	return errors.New("rndc: connection refused")
+}
+
+type testEventPublisher struct {
+	domain.EventPublisher
+}
+
+func (p *testEventPublisher) Notify() {}
+
+type testSettingsProvider struct {
+	domain.SettingsProvider
+}
+
+func (p *testSettingsProvider) Settings() *domain.Settings {
+	return &domain.Settings{}
+}
+
+func newTestService(t *testing.T, checkDelay time.Duration) *service {
+	dir := t.TempDir()
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	err = external.NewSqliteMigration(db).Migrate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &service{
+		config:           config,
+		db:               db,
+		readDb:           db,
+		zoneRepository:   external.NewSqliteZoneRepository(config, db, db),
+		serverRepository: external.NewSqliteServerRepository(db),
+		zoneLocks:        external.NewZoneLocker(),
+		bindHelper:       &testDNSServer{checkDelay: checkDelay},
+		events:           &testEventPublisher{},
+		settings:         &testSettingsProvider{},
+	}
+}
+
+func persistTestZone(t *testing.T, s *service, domainName string, syncPTR bool, records ...*domain.Record) {
+	zone := domain.NewZone(domainName)
+	zone.SyncPTR = syncPTR
+	err := zone.RegisterSOA(domain.NewDefaultSOARecord("ns1.example.com.", "admin.example.com."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		err = zone.AddRecord(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = s.zoneRepository.Persist(context.Background(), zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func serveTestRequest(handler func(c echo.Context) error, method, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	err := handler(echo.New().NewContext(req, rec))
+	if err != nil {
+		rec.Code = http.StatusInternalServerError
+	}
+	return rec
+}
+
+func TestBatchRecordGroupsAndPTRSyncLockInTheSameOrder(t *testing.T) {
+	s := newTestService(t, 20*time.Millisecond)
+	forward := domain.NewRecord("www", "A", "192.0.2.1")
+	forward.Labels = map[string]string{"env": "prod"}
+	persistTestZone(t, s, "example.com", true, forward)
+	reverse := domain.NewRecord("1", "PTR", "www.example.com.")
+	reverse.Labels = map[string]string{"env": "prod"}
+	persistTestZone(t, s, "2.0.192.in-addr.arpa", false, reverse)
+
+	for i := 0; i < 3; i++ {
+		// The batch changes both zones while the new record syncs its PTR record in the reverse zone.
+		batchRes := make(chan *httptest.ResponseRecorder, 1)
+		createRes := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			batchRes <- serveTestRequest(s.BatchRecordGroups, http.MethodPatch,
+				fmt.Sprintf(`{"label": ["env=prod"], "ttl": %v}`, 300+i))
+		}()
+		go func() {
+			createRes <- serveTestRequest(func(c echo.Context) error {
+				return s.CreateRecord(c, "example.com")
+			}, http.MethodPost, fmt.Sprintf(`{"name": "host-%v", "type": "A", "value": "192.0.2.%v"}`, i, 10+i))
+		}()
+
+		timeout := time.After(10 * time.Second)
+		for _, res := range []chan *httptest.ResponseRecorder{batchRes, createRes} {
+			select {
+			case rec := <-res:
+				if rec.Code >= http.StatusBadRequest {
+					t.Fatalf("request failed with %v: %v", rec.Code, rec.Body.String())
+				}
+			case <-timeout:
+				t.Fatal("the zone locks deadlocked")
+			}
+		}
+	}
+
+	zone, err := s.zoneRepository.GetZoneByDomain(context.Background(), "2.0.192.in-addr.arpa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zone.Records) != 4 {
+		t.Fatalf("expected the PTR record and the 3 synced ones, got %v records", len(zone.Records))
+	}
+}
+
+func restoreTestBundle(t *testing.T, config domain.Config, zones ...*domain.Zone) error {
+	bundle := domain.NewRecoveryBundle(external.NewSqliteMigration(nil).SchemaVersion())
+	bundle.Options = domain.NewDefaultServerOptions()
+	bundle.Zones = zones
+	encoded, err := bundle.Encode("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return RestoreRecoveryBundle(context.Background(), config, bytes.NewReader(encoded), "correct horse battery staple")
+}
+
+func TestRestoreRecoveryBundleIsSkippedOnceRestored(t *testing.T) {
+	dir := t.TempDir()
+	config := domain.NewConfig(filepath.Join(dir, "bind"), filepath.Join(dir, "data"), "test.db", "", nil, false)
+
+	// The second zone fails its check, a CNAME record having other data next to it.
+	broken := domain.NewZone("example.org")
+	err := broken.RegisterSOA(domain.NewDefaultSOARecord("ns1.example.org.", "admin.example.org."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken.Records = append(broken.Records, domain.NewRecord("www", "CNAME", "example.com."),
+		domain.NewRecord("www", "A", "192.0.2.1"))
+	err = restoreTestBundle(t, config, domain.NewZone("example.com"), broken)
+	if err == nil {
+		t.Fatal("expected the restore to fail")
+	}
+	for _, path := range []string{config.DBPath(), config.DBPath() + ".restoring"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %v not to exist once the restore failed", path)
+		}
+	}
+
+	err = restoreTestBundle(t, config, domain.NewZone("example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = restoreTestBundle(t, config, domain.NewZone("example.org"))
+	if !errors.Is(err, domain.ErrorRecoveryTargetNotEmpty) {
+		t.Fatalf("expected the second restore to be skipped, got %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", config.DBPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	zones, err := external.NewSqliteZoneRepository(config, db, db).GetAllZones(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0].Domain != "example.com" {
+		t.Fatalf("expected the zone of the first bundle only, got %v zones", len(zones))
+	}
+}
+
+func TestCreateNegativeTrustAnchorIsNotStoredOnceRefused(t *testing.T) {
+	s := newTestService(t, 0)
+	rec := serveTestRequest(s.CreateNegativeTrustAnchor, http.MethodPost, `{"domain":"example.com"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected the anchor to be refused, got %v", rec.Code)
+	}
+	ntas, err := s.serverRepository.GetAllNegativeTrustAnchors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ntas) != 0 {
+		t.Fatalf("expected the refused anchor not to be stored, got %v anchors", len(ntas))
+	}
+}